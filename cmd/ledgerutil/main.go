@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/internal/ledgerutil"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func main() {
+	kingpin.Version("0.0.1")
+
+	exit, err := executeForArgs(os.Args[1:], os.Stdout)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	}
+	os.Exit(exit)
+}
+
+func executeForArgs(args []string, stdout io.Writer) (exit int, err error) {
+	//
+	// command line flags
+	//
+	app := kingpin.New("ledgerutil", "Offline inspection of peer and orderer block stores and of peer ledger snapshots. The peer or the orderer must be stopped for inspecting the block store.")
+	blockStoreDir := app.Flag("block-store", "Path to the block store directory that contains the 'chains' and 'index' directories, i.e., <peer.fileSystemPath>/ledgersData/chains for a peer or <FileLedger.Location> for an orderer. Required by all the commands except compare").String()
+	channelID := app.Flag("channel-id", "Channel ID. Required by all the commands except compare").Short('c').String()
+	outputFile := app.Flag("output", "Path to the file to write the output to. The output is written to stdout if not specified").Short('o').String()
+
+	info := app.Command("info", "Show the block files and the block index information of the channel ledger")
+
+	blocks := app.Command("blocks", "Dump a range of blocks, read sequentially from the block files, as JSON")
+	blocksStart := blocks.Flag("start", "Number of the first block to dump").Default("0").Uint64()
+	blocksEnd := blocks.Flag("end", "Number of the last block to dump. Defaults to the last block present in the block files").String()
+	blocksFull := blocks.Flag("full", "Include the fully decoded blocks in the output").Bool()
+
+	block := app.Command("block", "Look up a block by number or by header hash via the block index")
+	blockNum := block.Flag("number", "Block number").Short('n').String()
+	blockHash := block.Flag("hash", "Hex encoded block header hash").String()
+	blockFull := block.Flag("full", "Include the fully decoded block in the output").Bool()
+
+	tx := app.Command("tx", "Look up a transaction by transaction ID via the block index")
+	txID := tx.Flag("tx-id", "Transaction ID").Required().String()
+	txFull := tx.Flag("full", "Include the fully decoded transaction envelope in the output").Bool()
+
+	verify := app.Command("verify", "Verify the block hash chain and the consistency of the block index with the block files")
+
+	compare := app.Command("compare", "Compare the public state and the private state hashes in two snapshots of a channel, taken at the same height on two peers, and report the keys that differ")
+	snapshotDir1 := compare.Arg("snapshot1", "Path to the first snapshot directory").Required().String()
+	snapshotDir2 := compare.Arg("snapshot2", "Path to the second snapshot directory").Required().String()
+
+	command, err := app.Parse(args)
+	if err != nil {
+		return 1, err
+	}
+
+	//
+	// flag validation
+	//
+	if command != compare.FullCommand() {
+		if *blockStoreDir == "" {
+			return 1, fmt.Errorf("required flag --block-store not provided")
+		}
+		if *channelID == "" {
+			return 1, fmt.Errorf("required flag --channel-id not provided")
+		}
+	}
+	if command == block.FullCommand() && (*blockHash == "") == (*blockNum == "") {
+		return 1, fmt.Errorf("exactly one of --number or --hash must be specified")
+	}
+	var (
+		blockHashBytes []byte
+		blockNumber    uint64
+		endBlockNumber uint64
+	)
+	if *blockHash != "" {
+		if blockHashBytes, err = hex.DecodeString(*blockHash); err != nil {
+			return 1, fmt.Errorf("decoding block hash: %s", err)
+		}
+	}
+	if *blockNum != "" {
+		if blockNumber, err = strconv.ParseUint(*blockNum, 10, 64); err != nil {
+			return 1, fmt.Errorf("parsing block number: %s", err)
+		}
+	}
+	if *blocksEnd != "" {
+		if endBlockNumber, err = strconv.ParseUint(*blocksEnd, 10, 64); err != nil {
+			return 1, fmt.Errorf("parsing end block number: %s", err)
+		}
+	}
+
+	out := stdout
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			return 1, fmt.Errorf("creating output file: %s", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if command == compare.FullCommand() {
+		identical, err := ledgerutil.Compare(*snapshotDir1, *snapshotDir2, out)
+		if err != nil {
+			return 1, err
+		}
+		if !identical {
+			return 1, fmt.Errorf("snapshots [%s] and [%s] differ", *snapshotDir1, *snapshotDir2)
+		}
+		return 0, nil
+	}
+
+	inspector, err := blkstorage.OpenInspector(*blockStoreDir, *channelID)
+	if err != nil {
+		return 1, err
+	}
+	defer inspector.Close()
+
+	//
+	// call the underlying implementations
+	//
+	switch command {
+	case info.FullCommand():
+		err = ledgerutil.Info(inspector, *channelID, out)
+	case blocks.FullCommand():
+		if *blocksEnd == "" {
+			_, lastBlockNum, noBlockFiles, err := inspector.BlockfilesInfo()
+			if err != nil {
+				return 1, err
+			}
+			if noBlockFiles {
+				return 1, fmt.Errorf("no blocks present in the block files of channel [%s]", *channelID)
+			}
+			endBlockNumber = lastBlockNum
+		}
+		err = ledgerutil.DumpBlocks(inspector, *blocksStart, endBlockNumber, *blocksFull, out)
+	case block.FullCommand():
+		if blockHashBytes != nil {
+			err = ledgerutil.LookupBlockByHash(inspector, blockHashBytes, *blockFull, out)
+			break
+		}
+		err = ledgerutil.LookupBlockByNumber(inspector, blockNumber, *blockFull, out)
+	case tx.FullCommand():
+		err = ledgerutil.LookupTx(inspector, *txID, *txFull, out)
+	case verify.FullCommand():
+		var clean bool
+		clean, err = ledgerutil.Verify(inspector, *channelID, out)
+		if err == nil && !clean {
+			return 1, fmt.Errorf("verification of channel [%s] found issues", *channelID)
+		}
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/internal/ledgerutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteForArgs(t *testing.T) {
+	blockStoreDir, err := ioutil.TempDir("", "ledgerutil")
+	require.NoError(t, err)
+	defer os.RemoveAll(blockStoreDir)
+
+	// an orderer blockstore indexes only the block numbers
+	provider, err := blkstorage.NewProvider(
+		blkstorage.NewConf(blockStoreDir, 0),
+		&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+		&disabled.Provider{},
+	)
+	require.NoError(t, err)
+	store, err := provider.Open("testchannel")
+	require.NoError(t, err)
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, b := range blocks {
+		require.NoError(t, store.AddBlock(b))
+	}
+	provider.Close()
+
+	commonArgs := []string{"--block-store", blockStoreDir, "--channel-id", "testchannel"}
+
+	t.Run("info", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		exit, err := executeForArgs(append(commonArgs, "info"), out)
+		require.NoError(t, err)
+		require.Equal(t, 0, exit)
+		info := &ledgerutil.LedgerInfo{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), info))
+		require.Equal(t, uint64(4), *info.LastBlockNum)
+		require.Equal(t, []string{"BlockNum"}, info.Index.IndexedAttrs)
+	})
+
+	t.Run("blocks", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		exit, err := executeForArgs(append(commonArgs, "blocks", "--start", "2"), out)
+		require.NoError(t, err)
+		require.Equal(t, 0, exit)
+		summaries := []*ledgerutil.BlockSummary{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &summaries))
+		require.Len(t, summaries, 3)
+		require.Equal(t, uint64(4), summaries[2].Number)
+	})
+
+	t.Run("blocks-to-output-file", func(t *testing.T) {
+		outputFile := filepath.Join(blockStoreDir, "blocks.json")
+		exit, err := executeForArgs(append(commonArgs, "--output", outputFile, "blocks", "--end", "1"), &bytes.Buffer{})
+		require.NoError(t, err)
+		require.Equal(t, 0, exit)
+		b, err := ioutil.ReadFile(outputFile)
+		require.NoError(t, err)
+		summaries := []*ledgerutil.BlockSummary{}
+		require.NoError(t, json.Unmarshal(b, &summaries))
+		require.Len(t, summaries, 2)
+	})
+
+	t.Run("block-by-number", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		exit, err := executeForArgs(append(commonArgs, "block", "--number", "3"), out)
+		require.NoError(t, err)
+		require.Equal(t, 0, exit)
+		summary := &ledgerutil.BlockSummary{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), summary))
+		require.Equal(t, hex.EncodeToString(protoutil.BlockHeaderHash(blocks[3].Header)), summary.Hash)
+	})
+
+	t.Run("block-by-hash-not-indexed", func(t *testing.T) {
+		hash := hex.EncodeToString(protoutil.BlockHeaderHash(blocks[3].Header))
+		exit, err := executeForArgs(append(commonArgs, "block", "--hash", hash), &bytes.Buffer{})
+		require.EqualError(t, err, "failed to locate block with hash ["+hash+"] in the block index: attribute not indexed")
+		require.Equal(t, 1, exit)
+	})
+
+	t.Run("block-flags-missing", func(t *testing.T) {
+		exit, err := executeForArgs(append(commonArgs, "block"), &bytes.Buffer{})
+		require.EqualError(t, err, "exactly one of --number or --hash must be specified")
+		require.Equal(t, 1, exit)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		exit, err := executeForArgs(append(commonArgs, "verify"), out)
+		require.NoError(t, err)
+		require.Equal(t, 0, exit)
+		report := &ledgerutil.VerificationReport{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), report))
+		require.Equal(t, uint64(5), report.BlocksVerified)
+	})
+
+	t.Run("non-existing-channel", func(t *testing.T) {
+		exit, err := executeForArgs([]string{"--block-store", blockStoreDir, "--channel-id", "missing", "info"}, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read the block files directory for ledger [missing]")
+		require.Equal(t, 1, exit)
+	})
+
+	t.Run("missing-required-flag", func(t *testing.T) {
+		exit, err := executeForArgs([]string{"--channel-id", "testchannel", "info"}, &bytes.Buffer{})
+		require.EqualError(t, err, "required flag --block-store not provided")
+		require.Equal(t, 1, exit)
+	})
+
+	t.Run("compare-missing-snapshot", func(t *testing.T) {
+		exit, err := executeForArgs([]string{"compare", blockStoreDir, "non-existing-dir"}, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the metadata of the snapshot")
+		require.Equal(t, 1, exit)
+	})
+}
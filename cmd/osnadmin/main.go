@@ -11,16 +11,25 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/cmd/common/signer"
 	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/internal/osnadmin/migration"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/protoutil"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -37,6 +46,12 @@ func main() {
 }
 
 func executeForArgs(args []string) (output string, exit int, err error) {
+	return execute(args, os.Stdout)
+}
+
+// execute runs the command, and returns its output. The events of the commands that stream them
+// are written to the given stream as they are received.
+func execute(args []string, stream io.Writer) (output string, exit int, err error) {
 	//
 	// command line flags
 	//
@@ -52,25 +67,51 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 	joinChannelID := join.Flag("channel-id", "Channel ID").Short('c').Required().String()
 	configBlockPath := join.Flag("config-block", "Path to the file containing an up-to-date config block for the channel").Short('b').Required().String()
 
+	bulkJoin := channel.Command("bulk-join", "Join an Ordering Service Node (OSN) to several channels at once. The channels that do not yet exist are created.")
+	bulkJoinConfigBlockPaths := bulkJoin.Flag("config-block", "Path to a file containing an up-to-date config block for a channel (may be repeated)").Short('b').Strings()
+	bulkJoinConfigBlockDir := bulkJoin.Flag("config-block-dir", "Path to a directory containing an up-to-date config block for each channel, one file per channel").String()
+
 	list := channel.Command("list", "List channel information for an Ordering Service Node (OSN). If the channel-id flag is set, more detailed information will be provided for that channel.")
 	listChannelID := list.Flag("channel-id", "Channel ID").Short('c').String()
 
+	watch := channel.Command("watch", "Watch the status and height of a channel an Ordering Service Node (OSN) is a member of, until the channel is active. Each change is printed as it happens.")
+	watchChannelID := watch.Flag("channel-id", "Channel ID").Short('c').Required().String()
+
 	remove := channel.Command("remove", "Remove an Ordering Service Node (OSN) from a channel.")
 	removeChannelID := remove.Flag("channel-id", "Channel ID").Short('c').Required().String()
 
+	transferLeader := channel.Command("transfer-leader", "Transfer the leadership of a channel to another consenter, e.g. before restarting the Ordering Service Node (OSN) that leads it. Supported by the etcdraft consensus type only.")
+	transferLeaderChannelID := transferLeader.Flag("channel-id", "Channel ID").Short('c').Required().String()
+	transferLeaderTo := transferLeader.Flag("to", "Consenter ID of the OSN to transfer the leadership to").Required().Uint64()
+	transferLeaderLeaders := transferLeader.Flag("leader-address", "Admin endpoint of another consenter of the channel, as <consenter ID>=<address>. The request is sent again to the leader of the channel when the OSN neither leads the channel nor is the transferee (may be repeated)").StringMap()
+
+	migrate := app.Command("migration", "Migration of all the channels of an Ordering Service Node (OSN) from the kafka to the etcdraft consensus type, through maintenance mode. The stage of each channel is derived from its latest config, hence a failed migration is resumed by running it again.")
+	serviceAddress := migrate.Flag("service-address", "Ordering service endpoint (Broadcast and Deliver) of the OSN, reached with the same TLS configuration as the admin endpoint").Required().String()
+	mspID := migrate.Flag("msp-id", "MSP ID of the orderer organization admin that signs the config updates").Required().String()
+	signCert := migrate.Flag("sign-cert", "Path to file containing the PEM-encoded X509 certificate of the orderer organization admin").Required().String()
+	signKey := migrate.Flag("sign-key", "Path to file containing the PEM-encoded private key of the orderer organization admin").Required().String()
+
+	migrationStatus := migrate.Command("status", "Show the migration stage of each channel and the next step of the migration.")
+
+	migrationRun := migrate.Command("run", "Advance all the channels to the next stage of the migration: maintenance mode, then etcdraft in maintenance mode once the OSNs are backed up, then etcdraft once the OSNs are restarted.")
+	raftMetadataPath := migrationRun.Flag("raft-metadata", "Path to the file containing the JSON-encoded etcdraft metadata (etcdraft.ConfigMetadata) the channels are switched to. Required to switch the channels to etcdraft").String()
+	migrationTimeout := migrationRun.Flag("timeout", "Time to wait for each config update to be committed").Default("30s").Duration()
+
 	command := kingpin.MustParse(app.Parse(args))
 
 	//
 	// flag validation
 	//
 	var (
+		scheme        string
 		osnURL        string
 		caCertPool    *x509.CertPool
 		tlsClientCert tls.Certificate
 	)
 	// TLS enabled
 	if *caFile != "" {
-		osnURL = fmt.Sprintf("https://%s", *orderer)
+		scheme = "https"
+		osnURL = fmt.Sprintf("%s://%s", scheme, *orderer)
 		var err error
 		caCertPool = x509.NewCertPool()
 		caFilePEM, err := ioutil.ReadFile(*caFile)
@@ -87,7 +128,8 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 			return "", 1, fmt.Errorf("loading client cert/key pair: %s", err)
 		}
 	} else { // TLS disabled
-		osnURL = fmt.Sprintf("http://%s", *orderer)
+		scheme = "http"
+		osnURL = fmt.Sprintf("%s://%s", scheme, *orderer)
 	}
 
 	var marshaledConfigBlock []byte
@@ -103,6 +145,52 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 		}
 	}
 
+	if command == migrationStatus.FullCommand() || command == migrationRun.FullCommand() {
+		migrator, err := newMigrator(*serviceAddress, *caFile, *clientCert, *clientKey, osnURL, caCertPool, tlsClientCert,
+			signer.Config{MSPID: *mspID, IdentityPath: *signCert, KeyPath: *signKey})
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+
+		var report *migration.Report
+		if command == migrationStatus.FullCommand() {
+			report, err = migrator.Status()
+		} else {
+			migrator.Timeout = *migrationTimeout
+			if *raftMetadataPath != "" {
+				migrator.RaftMetadata, err = readRaftMetadata(*raftMetadataPath)
+			}
+			if err == nil {
+				report, err = migrator.Run()
+			}
+		}
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		return migrationOutput(report), 0, nil
+	}
+
+	var marshaledConfigBlocks [][]byte
+	if command == bulkJoin.FullCommand() {
+		marshaledConfigBlocks, err = readConfigBlocks(*bulkJoinConfigBlockPaths, *bulkJoinConfigBlockDir)
+		if err != nil {
+			return "", 1, err
+		}
+	}
+
+	leaderURLs := map[uint64]string{}
+	for id, address := range *transferLeaderLeaders {
+		consenterID, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			return "", 1, fmt.Errorf("parsing consenter ID of leader address %s: %s", address, err)
+		}
+		leaderURLs[consenterID] = fmt.Sprintf("%s://%s", scheme, address)
+	}
+
+	if command == watch.FullCommand() {
+		return watchChannel(osnURL, *watchChannelID, caCertPool, tlsClientCert, stream)
+	}
+
 	//
 	// call the underlying implementations
 	//
@@ -111,6 +199,8 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 	switch command {
 	case join.FullCommand():
 		resp, err = osnadmin.Join(osnURL, marshaledConfigBlock, caCertPool, tlsClientCert)
+	case bulkJoin.FullCommand():
+		resp, err = osnadmin.BulkJoin(osnURL, marshaledConfigBlocks, caCertPool, tlsClientCert)
 	case list.FullCommand():
 		if *listChannelID != "" {
 			resp, err = osnadmin.ListSingleChannel(osnURL, *listChannelID, caCertPool, tlsClientCert)
@@ -119,6 +209,8 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 		resp, err = osnadmin.ListAllChannels(osnURL, caCertPool, tlsClientCert)
 	case remove.FullCommand():
 		resp, err = osnadmin.Remove(osnURL, *removeChannelID, caCertPool, tlsClientCert)
+	case transferLeader.FullCommand():
+		resp, err = osnadmin.TransferLeader(osnURL, *transferLeaderChannelID, *transferLeaderTo, leaderURLs, caCertPool, tlsClientCert)
 	}
 	if err != nil {
 		return errorOutput(err), 1, nil
@@ -158,6 +250,156 @@ func errorOutput(err error) string {
 	return fmt.Sprintf("Error: %s\n", err)
 }
 
+func readConfigBlocks(paths []string, dir string) ([][]byte, error) {
+	if dir != "" {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("reading config block directory: %s", err)
+		}
+		for _, f := range files {
+			if f.Mode().IsRegular() {
+				paths = append(paths, filepath.Join(dir, f.Name()))
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no config blocks provided, either --config-block or --config-block-dir must be specified")
+	}
+
+	var blocks [][]byte
+	for _, path := range paths {
+		blockBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading config block: %s", err)
+		}
+		if _, err := protoutil.UnmarshalBlock(blockBytes); err != nil {
+			return nil, fmt.Errorf("unmarshaling config block %s: %s", path, err)
+		}
+		blocks = append(blocks, blockBytes)
+	}
+
+	return blocks, nil
+}
+
+// watchChannel writes the channel info to the stream upon every change, until the channel is active or fails, and
+// returns the last channel info. The event stream is re-established whenever the OSN ends or cuts it before the
+// channel is active, e.g. upon the write timeout of the admin endpoint, which cuts the stream without notice.
+func watchChannel(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, stream io.Writer) (string, int, error) {
+	var last []byte
+	for {
+		resp, err := osnadmin.Watch(osnURL, channelID, caCertPool, tlsClientCert)
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, err := readBodyBytes(resp.Body)
+			if err != nil {
+				return errorOutput(err), 1, nil
+			}
+			return responseOutput(resp.StatusCode, bodyBytes), 0, nil
+		}
+
+		received, done := false, false
+		err = osnadmin.ReadEvents(resp.Body, func(event osnadmin.Event) error {
+			received = true
+			switch event.Type {
+			case "channel":
+				info := types.ChannelInfo{}
+				if err := json.Unmarshal([]byte(event.Data), &info); err != nil {
+					return fmt.Errorf("decoding channel event: %s", err)
+				}
+				// The current info is sent again when the stream is re-established.
+				if string(last) != event.Data+"\n" {
+					fmt.Fprintln(stream, event.Data)
+				}
+				last = []byte(event.Data + "\n")
+				done = info.Status == types.StatusActive || info.Status == types.StatusFailed
+			case "error":
+				errResp := types.ErrorResponse{}
+				if err := json.Unmarshal([]byte(event.Data), &errResp); err != nil {
+					return fmt.Errorf("decoding error event: %s", err)
+				}
+				return errors.New(errResp.Error)
+			}
+			return nil
+		})
+		resp.Body.Close()
+		if err != nil && !done && (errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)) {
+			err = nil
+		}
+		if err != nil {
+			return errorOutput(err), 1, nil
+		}
+		if done {
+			return responseOutput(http.StatusOK, last), 0, nil
+		}
+		if !received {
+			return errorOutput(errors.New("event stream ended without any event")), 1, nil
+		}
+	}
+}
+
+func newMigrator(serviceAddress, caFile, clientCert, clientKey, osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate, signerConfig signer.Config) (*migration.Migrator, error) {
+	sign, err := signer.NewSigner(signerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("loading signing identity: %s", err)
+	}
+
+	clientConfig := comm.ClientConfig{
+		Timeout: 5 * time.Second,
+	}
+	// TLS enabled
+	if caFile != "" {
+		clientConfig.SecOpts.UseTLS = true
+		caFilePEM, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading orderer CA certificate: %s", err)
+		}
+		clientConfig.SecOpts.ServerRootCAs = [][]byte{caFilePEM}
+		if clientConfig.SecOpts.Certificate, err = ioutil.ReadFile(clientCert); err != nil {
+			return nil, fmt.Errorf("reading client cert: %s", err)
+		}
+		if clientConfig.SecOpts.Key, err = ioutil.ReadFile(clientKey); err != nil {
+			return nil, fmt.Errorf("reading client key: %s", err)
+		}
+	}
+
+	orderer, err := migration.NewOrderer(serviceAddress, clientConfig, sign)
+	if err != nil {
+		return nil, err
+	}
+
+	return &migration.Migrator{
+		Orderer:      orderer,
+		Admin:        migration.NewAdmin(osnURL, caCertPool, tlsClientCert),
+		Signer:       sign,
+		PollInterval: time.Second,
+	}, nil
+}
+
+func readRaftMetadata(path string) (*etcdraft.ConfigMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading etcdraft metadata: %s", err)
+	}
+	defer f.Close()
+
+	metadata := &etcdraft.ConfigMetadata{}
+	if err := protolator.DeepUnmarshalJSON(f, metadata); err != nil {
+		return nil, fmt.Errorf("decoding etcdraft metadata: %s", err)
+	}
+	if len(metadata.Consenters) == 0 {
+		return nil, fmt.Errorf("etcdraft metadata has no consenters")
+	}
+
+	return metadata, nil
+}
+
+func migrationOutput(report *migration.Report) string {
+	output, _ := json.MarshalIndent(report, "", "\t")
+	return string(output)
+}
+
 func validateBlockChannelID(blockBytes []byte, channelID string) error {
 	block := &common.Block{}
 	err := proto.Unmarshal(blockBytes, block)
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package main
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -14,10 +15,12 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -178,6 +181,53 @@ var _ = Describe("osnadmin", func() {
 			checkOutput(output, exit, err, 200, expectedOutput)
 		})
 
+		It("uses the channel participation API to list the details of the cluster of a single channel", func() {
+			lastSeen := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+			cluster := &types.ClusterInfo{
+				ID:             1,
+				Leader:         2,
+				Term:           3,
+				CommittedIndex: 12,
+				AppliedIndex:   11,
+				PendingConfigChange: &types.ConfigChange{
+					Type: types.ConfigChangeRemoveNode,
+					ID:   3,
+				},
+				Consenters: []types.ConsenterInfo{
+					{ID: 1, Host: "orderer1", Port: 7050, Active: true},
+					{ID: 2, Host: "orderer2", Port: 7050, Active: true, LastSeen: &lastSeen},
+					{ID: 3, Host: "orderer3", Port: 7050},
+				},
+			}
+			mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{
+				Name:              "asparagus",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            987,
+				Cluster:           cluster,
+			}, nil)
+
+			args := []string{
+				"channel",
+				"list",
+				"--orderer-address", ordererURL,
+				"--channel-id", "asparagus",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ChannelInfo{
+				Name:              "asparagus",
+				URL:               "/participation/v1/channels/asparagus",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            987,
+				Cluster:           cluster,
+			}
+			checkOutput(output, exit, err, 200, expectedOutput)
+		})
+
 		Context("when the channel does not exist", func() {
 			BeforeEach(func() {
 				mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, errors.New("eat-your-peas"))
@@ -318,6 +368,396 @@ var _ = Describe("osnadmin", func() {
 		})
 	})
 
+	Describe("TransferLeader", func() {
+		BeforeEach(func() {
+			mockChannelManagement.TransferLeadershipReturns(2, nil)
+		})
+
+		It("uses the channel participation API to transfer the leadership of a channel", func() {
+			args := []string{
+				"channel",
+				"transfer-leader",
+				"--orderer-address", ordererURL,
+				"--channel-id", channelID,
+				"--to", "2",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.LeadershipTransferResponse{
+				Name:   channelID,
+				Leader: 2,
+			}
+			checkOutput(output, exit, err, 200, expectedOutput)
+
+			Expect(mockChannelManagement.TransferLeadershipCallCount()).To(Equal(1))
+			actualChannelID, transferee := mockChannelManagement.TransferLeadershipArgsForCall(0)
+			Expect(actualChannelID).To(Equal(channelID))
+			Expect(transferee).To(Equal(uint64(2)))
+		})
+
+		Context("when the leadership transfer fails", func() {
+			BeforeEach(func() {
+				mockChannelManagement.TransferLeadershipReturns(0, errors.New("timed out waiting for leadership to be transferred to 2"))
+			})
+
+			It("returns 400 bad request", func() {
+				args := []string{
+					"channel",
+					"transfer-leader",
+					"--orderer-address", ordererURL,
+					"--channel-id", channelID,
+					"--to", "2",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ErrorResponse{
+					Error: "cannot transfer leadership: timed out waiting for leadership to be transferred to 2",
+				}
+				checkOutput(output, exit, err, 400, expectedOutput)
+			})
+		})
+
+		Context("when the OSN is not the leader", func() {
+			BeforeEach(func() {
+				mockChannelManagement.TransferLeadershipReturnsOnCall(0, 0, &types.NotLeaderError{Leader: 1})
+			})
+
+			It("returns 421 misdirected request and the leader", func() {
+				args := []string{
+					"channel",
+					"transfer-leader",
+					"--orderer-address", ordererURL,
+					"--channel-id", channelID,
+					"--to", "2",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.NotLeaderResponse{
+					Error:  "cannot transfer leadership: not leader, leader is 1",
+					Leader: 1,
+				}
+				checkOutput(output, exit, err, 421, expectedOutput)
+				Expect(mockChannelManagement.TransferLeadershipCallCount()).To(Equal(1))
+			})
+
+			It("sends the request again to the leader when its address is known", func() {
+				args := []string{
+					"channel",
+					"transfer-leader",
+					"--orderer-address", ordererURL,
+					"--channel-id", channelID,
+					"--to", "2",
+					"--leader-address", "1=" + ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.LeadershipTransferResponse{
+					Name:   channelID,
+					Leader: 2,
+				}
+				checkOutput(output, exit, err, 200, expectedOutput)
+				Expect(mockChannelManagement.TransferLeadershipCallCount()).To(Equal(2))
+			})
+
+			It("returns an error when the consenter ID of a leader address is invalid", func() {
+				args := []string{
+					"channel",
+					"transfer-leader",
+					"--orderer-address", ordererURL,
+					"--channel-id", channelID,
+					"--to", "2",
+					"--leader-address", "one=" + ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				Expect(err).To(MatchError(ContainSubstring("parsing consenter ID of leader address")))
+				Expect(exit).To(Equal(1))
+				Expect(output).To(BeEmpty())
+			})
+		})
+
+		Context("when TLS is disabled", func() {
+			BeforeEach(func() {
+				tlsConfig = nil
+			})
+
+			It("uses the channel participation API to transfer the leadership of a channel", func() {
+				args := []string{
+					"channel",
+					"transfer-leader",
+					"--orderer-address", ordererURL,
+					"--channel-id", channelID,
+					"--to", "2",
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.LeadershipTransferResponse{
+					Name:   channelID,
+					Leader: 2,
+				}
+				checkOutput(output, exit, err, 200, expectedOutput)
+			})
+		})
+	})
+
+	Describe("BulkJoin", func() {
+		var blockDir string
+
+		BeforeEach(func() {
+			blockDir = filepath.Join(tempDir, "blocks")
+			err := os.Mkdir(blockDir, 0755)
+			Expect(err).NotTo(HaveOccurred())
+			for _, name := range []string{"apple", "banana"} {
+				blockBytes, err := proto.Marshal(blockWithGroups(map[string]*cb.ConfigGroup{"Application": {}}, name))
+				Expect(err).NotTo(HaveOccurred())
+				err = ioutil.WriteFile(filepath.Join(blockDir, name+".block"), blockBytes, 0644)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			mockChannelManagement.JoinChannelStub = func(channelID string, _ *cb.Block, _ bool) (types.ChannelInfo, error) {
+				if channelID == "banana" {
+					return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+				}
+				return types.ChannelInfo{
+					Name:              channelID,
+					ConsensusRelation: "follower",
+					Status:            "onboarding",
+				}, nil
+			}
+		})
+
+		expectedOutput := types.BulkJoinResponse{
+			Channels: []types.BulkJoinResult{
+				{
+					Name:       "apple",
+					StatusCode: 201,
+					Info: &types.ChannelInfo{
+						Name:              "apple",
+						URL:               "/participation/v1/channels/apple",
+						ConsensusRelation: "follower",
+						Status:            "onboarding",
+					},
+				},
+				{
+					Name:       "banana",
+					StatusCode: 405,
+					Error:      "cannot join: channel already exists",
+				},
+			},
+		}
+
+		It("uses the channel participation API to join the channels of the config blocks", func() {
+			args := []string{
+				"channel",
+				"bulk-join",
+				"--orderer-address", ordererURL,
+				"--config-block", filepath.Join(blockDir, "apple.block"),
+				"--config-block", filepath.Join(blockDir, "banana.block"),
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkOutput(output, exit, err, 200, expectedOutput)
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(2))
+		})
+
+		It("uses the channel participation API to join the channels of the config blocks in a directory", func() {
+			args := []string{
+				"channel",
+				"bulk-join",
+				"--orderer-address", ordererURL,
+				"--config-block-dir", blockDir,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			checkOutput(output, exit, err, 200, expectedOutput)
+			Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(2))
+		})
+
+		Context("when no config blocks are provided", func() {
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"bulk-join",
+					"--orderer-address", ordererURL,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "no config blocks provided, either --config-block or --config-block-dir must be specified")
+			})
+		})
+
+		Context("when a config block is not a block", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(filepath.Join(blockDir, "cherry.block"), []byte("not-a-block"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"bulk-join",
+					"--orderer-address", ordererURL,
+					"--config-block-dir", blockDir,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				checkFlagError(output, exit, err, "unmarshaling config block "+filepath.Join(blockDir, "cherry.block"))
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Watch", func() {
+		var stream *bytes.Buffer
+
+		BeforeEach(func() {
+			stream = &bytes.Buffer{}
+			infos := []types.ChannelInfo{
+				{Name: "apple", ConsensusRelation: "follower", Status: "onboarding", Height: 3},
+				{Name: "apple", ConsensusRelation: "consenter", Status: "active", Height: 9},
+			}
+			mockChannelManagement.ChannelInfoStub = func(string) (types.ChannelInfo, error) {
+				return infos[mockChannelManagement.ChannelInfoCallCount()-1], nil
+			}
+		})
+
+		It("uses the channel participation API to watch a channel until it is active", func() {
+			args := []string{
+				"channel",
+				"watch",
+				"--orderer-address", ordererURL,
+				"--channel-id", "apple",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := execute(args, stream)
+			expectedOutput := types.ChannelInfo{
+				Name:              "apple",
+				URL:               "/participation/v1/channels/apple",
+				ConsensusRelation: "consenter",
+				Status:            "active",
+				Height:            9,
+			}
+			checkOutput(output, exit, err, 200, expectedOutput)
+			Expect(stream.String()).To(Equal(
+				`{"name":"apple","url":"/participation/v1/channels/apple","consensusRelation":"follower","status":"onboarding","height":3}` + "\n" +
+					`{"name":"apple","url":"/participation/v1/channels/apple","consensusRelation":"consenter","status":"active","height":9}` + "\n",
+			))
+		})
+
+		Context("when the channel is removed", func() {
+			BeforeEach(func() {
+				mockChannelManagement.ChannelInfoStub = nil
+				mockChannelManagement.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "apple", ConsensusRelation: "follower", Status: "onboarding", Height: 3}, nil)
+				mockChannelManagement.ChannelInfoReturnsOnCall(1, types.ChannelInfo{}, types.ErrChannelNotExist)
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				args := []string{
+					"channel",
+					"watch",
+					"--orderer-address", ordererURL,
+					"--channel-id", "apple",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := execute(args, stream)
+				checkCLIError(output, exit, err, "channel does not exist")
+				Expect(stream.String()).To(ContainSubstring(`"status":"onboarding"`))
+			})
+		})
+
+		Context("when the OSN cuts the event stream", func() {
+			var watches int
+
+			BeforeEach(func() {
+				watches = 0
+				h := testServer.Config.Handler
+				testServer.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					watches++
+					if watches > 1 {
+						h.ServeHTTP(w, r)
+						return
+					}
+					// The write timeout of the admin endpoint cuts the stream in the middle of an event.
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, "event: channel\n")
+					fmt.Fprint(w, `data: {"name":"apple","url":"/participation/v1/channels/apple","consensusRelation":"follower","status":"onboarding","height":3}`+"\n\n")
+					fmt.Fprint(w, "event: channel\ndata: {\"name\":\"apple\",")
+					w.(http.Flusher).Flush()
+					panic(http.ErrAbortHandler)
+				})
+			})
+
+			It("re-establishes the stream until the channel is active", func() {
+				args := []string{
+					"channel",
+					"watch",
+					"--orderer-address", ordererURL,
+					"--channel-id", "apple",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := execute(args, stream)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "consenter",
+					Status:            "active",
+					Height:            9,
+				}
+				checkOutput(output, exit, err, 200, expectedOutput)
+				Expect(watches).To(Equal(2))
+				Expect(stream.String()).To(Equal(
+					`{"name":"apple","url":"/participation/v1/channels/apple","consensusRelation":"follower","status":"onboarding","height":3}` + "\n" +
+						`{"name":"apple","url":"/participation/v1/channels/apple","consensusRelation":"consenter","status":"active","height":9}` + "\n",
+				))
+			})
+		})
+
+		Context("when the channel does not exist", func() {
+			BeforeEach(func() {
+				mockChannelManagement.ChannelInfoStub = nil
+				mockChannelManagement.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+			})
+
+			It("returns 404 not found", func() {
+				args := []string{
+					"channel",
+					"watch",
+					"--orderer-address", ordererURL,
+					"--channel-id", "apple",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := execute(args, stream)
+				checkOutput(output, exit, err, 404, types.ErrorResponse{Error: "channel does not exist"})
+				Expect(stream.String()).To(BeEmpty())
+			})
+		})
+	})
+
 	Describe("Join", func() {
 		var blockPath string
 
@@ -497,6 +937,69 @@ var _ = Describe("osnadmin", func() {
 		})
 	})
 
+	Describe("Migration", func() {
+		var (
+			raftMetadataPath string
+			signCert         string
+		)
+
+		BeforeEach(func() {
+			raftMetadataPath = filepath.Join(tempDir, "metadata.json")
+			signCert = clientCert
+		})
+
+		migrationRunArgs := func() []string {
+			return []string{
+				"migration",
+				"--orderer-address", ordererURL,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+				"--service-address", "127.0.0.1:0",
+				"--msp-id", "OrdererMSP",
+				"--sign-cert", signCert,
+				"--sign-key", clientKey,
+				"run",
+				"--raft-metadata", raftMetadataPath,
+			}
+		}
+
+		Context("when the signing identity cannot be loaded", func() {
+			BeforeEach(func() {
+				signCert = filepath.Join(tempDir, "does-not-exist.pem")
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				output, exit, err := executeForArgs(migrationRunArgs())
+				checkCLIErrorRegExp(output, exit, err, "loading signing identity: open .*does-not-exist.pem: no such file or directory")
+			})
+		})
+
+		Context("when the etcdraft metadata is not valid JSON", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(raftMetadataPath, []byte("not-json"), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				output, exit, err := executeForArgs(migrationRunArgs())
+				checkCLIErrorRegExp(output, exit, err, "decoding etcdraft metadata: .*")
+			})
+		})
+
+		Context("when the etcdraft metadata has no consenters", func() {
+			BeforeEach(func() {
+				err := ioutil.WriteFile(raftMetadataPath, []byte(`{"options": {"tick_interval": "500ms"}}`), 0644)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("returns with exit code 1 and prints the error", func() {
+				output, exit, err := executeForArgs(migrationRunArgs())
+				checkCLIError(output, exit, err, "etcdraft metadata has no consenters")
+			})
+		})
+	})
+
 	Describe("Flags", func() {
 		It("accepts short versions of the --orderer-address, --channel-id, and --config-block flags", func() {
 			configBlock := blockWithGroups(
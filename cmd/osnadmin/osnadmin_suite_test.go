@@ -22,6 +22,7 @@ type channelManagement interface {
 	ChannelInfo(channelID string) (types.ChannelInfo, error)
 	JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
 	RemoveChannel(channelID string) error
+	TransferLeadership(channelID string, transferee uint64) (uint64, error)
 }
 
 func TestOsnadmin(t *testing.T) {
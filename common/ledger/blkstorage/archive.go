@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	archivingInfoFile     = "archivedBlocks.info"
+	archivingInfoTempFile = "archivedBlocksTemp.info"
+)
+
+// ErrNotFoundInArchive is returned by an ArchiveStore when no content is stored under the requested key
+var ErrNotFoundInArchive = errors.New("not found in the archive")
+
+// ArchiveStore is the backend to which the completed block files are moved off the local disk.
+// The keys are of the form "<ledgerID>/<block file name>"
+type ArchiveStore interface {
+	// Put stores the content of the file at the given local path under the given key,
+	// replacing the content already stored under the key, if any
+	Put(key, localPath string) error
+	// Get writes the content stored under the given key to w. ErrNotFoundInArchive is returned,
+	// possibly wrapped, if no content is stored under the key
+	Get(key string, w io.Writer) error
+}
+
+// archiver moves the completed block files of a ledger to the archive store in the background
+// and serves the archived block files from a read-through cache
+type archiver struct {
+	ledgerID   string
+	rootDir    string
+	store      ArchiveStore
+	localFiles int
+	cache      *archiveCache
+
+	// lock guards firstLocalFileNum, such that a block file is not deleted from the local disk
+	// between the decision to read it locally and opening it
+	lock              sync.RWMutex
+	firstLocalFileNum int
+
+	trigger chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newArchiver(ledgerID, rootDir string, conf *ArchiveConf, firstFileNum int) (*archiver, error) {
+	archivingInfo, err := loadArchivingInfo(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	firstLocalFileNum := firstFileNum
+	if archivingInfo != nil && int(archivingInfo.FirstLocalFileNum) > firstLocalFileNum {
+		firstLocalFileNum = int(archivingInfo.FirstLocalFileNum)
+	}
+	// the block files may not have been deleted if a crash took place after these were archived
+	for fileNum := firstFileNum; fileNum < firstLocalFileNum; fileNum++ {
+		if err := os.Remove(deriveBlockfilePath(rootDir, fileNum)); err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "error while deleting the archived block file [%d]", fileNum)
+		}
+	}
+
+	cache, err := newArchiveCache(filepath.Join(conf.CacheDir, ledgerID), ledgerID, conf.Store, conf.CacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &archiver{
+		ledgerID:          ledgerID,
+		rootDir:           rootDir,
+		store:             conf.Store,
+		localFiles:        conf.LocalFiles,
+		cache:             cache,
+		firstLocalFileNum: firstLocalFileNum,
+		trigger:           make(chan struct{}, 1),
+		stop:              make(chan struct{}),
+		stopped:           make(chan struct{}),
+	}, nil
+}
+
+// start launches the background archiving. The function latestFileNum returns the number of the
+// block file being appended, the block files below this number are completed
+func (a *archiver) start(latestFileNum func() int) {
+	go func() {
+		defer close(a.stopped)
+		for {
+			if err := a.archiveCompletedFiles(latestFileNum()); err != nil {
+				logger.Errorf("Error while archiving the block files of the ledger [%s], archiving will be retried when the next block file is completed: %s", a.ledgerID, err)
+			}
+			select {
+			case <-a.trigger:
+			case <-a.stop:
+				return
+			}
+		}
+	}()
+	a.notify()
+}
+
+// notify signals the completion of a block file
+func (a *archiver) notify() {
+	select {
+	case a.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (a *archiver) close() {
+	close(a.stop)
+	<-a.stopped
+}
+
+func (a *archiver) archiveCompletedFiles(latestFileNum int) error {
+	for fileNum := a.getFirstLocalFileNum(); fileNum < latestFileNum-a.localFiles; fileNum++ {
+		select {
+		case <-a.stop:
+			return nil
+		default:
+		}
+		if err := a.archiveFile(fileNum); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveFile moves a block file to the archive store. The archiving information is recorded
+// before the block file is deleted from the local disk and hence, a crash in between leaves
+// behind a block file that is deleted when the ledger is opened next
+func (a *archiver) archiveFile(fileNum int) error {
+	filePath := deriveBlockfilePath(a.rootDir, fileNum)
+	if err := a.store.Put(archiveKey(a.ledgerID, fileNum), filePath); err != nil {
+		return errors.WithMessagef(err, "error while archiving the block file [%s]", filePath)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if err := saveArchivingInfo(a.rootDir, &ArchivingInfo{FirstLocalFileNum: uint64(fileNum + 1)}); err != nil {
+		return err
+	}
+	a.firstLocalFileNum = fileNum + 1
+	if err := os.Remove(filePath); err != nil {
+		return errors.Wrapf(err, "error while deleting the archived block file [%s]", filePath)
+	}
+	logger.Infof("Archived the block file [%s]", filePath)
+	return fileutil.SyncDir(a.rootDir)
+}
+
+func (a *archiver) getFirstLocalFileNum() int {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	return a.firstLocalFileNum
+}
+
+// openBlockfile opens a block file from the local disk or, if the block file has been archived, from the cache
+func (a *archiver) openBlockfile(fileNum int) (*os.File, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	if fileNum >= a.firstLocalFileNum {
+		return localBlockfileOpener(a.rootDir)(fileNum)
+	}
+	return a.cache.open(fileNum)
+}
+
+func archiveKey(ledgerID string, fileNum int) string {
+	return ledgerID + "/" + blockfilePrefix + fmt.Sprintf("%06d", fileNum)
+}
+
+func saveArchivingInfo(rootDir string, archivingInfo *ArchivingInfo) error {
+	b, err := proto.Marshal(archivingInfo)
+	if err != nil {
+		return errors.Wrap(err, "error while marshalling archivingInfo")
+	}
+	if err := fileutil.CreateAndSyncFileAtomically(rootDir, archivingInfoTempFile, archivingInfoFile, b, 0644); err != nil {
+		return err
+	}
+	return fileutil.SyncDir(rootDir)
+}
+
+func loadArchivingInfo(rootDir string) (*ArchivingInfo, error) {
+	b, err := ioutil.ReadFile(filepath.Join(rootDir, archivingInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading archivingInfo file")
+	}
+	archivingInfo := &ArchivingInfo{}
+	if err := proto.Unmarshal(b, archivingInfo); err != nil {
+		return nil, errors.Wrapf(err, "error while unmarshalling archivingInfo")
+	}
+	return archivingInfo, nil
+}
+
+// GetLedgersWithArchivedBlocks returns the ledgers whose block files have been moved to an archive store
+func GetLedgersWithArchivedBlocks(blockStorageDir string) ([]string, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	ledgerIDs, err := fileutil.ListSubdirs(conf.getChainsDir())
+	if err != nil {
+		return nil, err
+	}
+	archivedLedgers := []string{}
+	for _, ledgerID := range ledgerIDs {
+		archivingInfo, err := loadArchivingInfo(conf.getLedgerBlockDir(ledgerID))
+		if err != nil {
+			return nil, err
+		}
+		if archivingInfo != nil {
+			archivedLedgers = append(archivedLedgers, ledgerID)
+		}
+	}
+	return archivedLedgers, nil
+}
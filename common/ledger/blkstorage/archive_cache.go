@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"container/list"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const fetchingFilePrefix = "fetching_"
+
+// archiveCache is a read-through cache of the archived block files of a ledger. It retains up to maxFiles
+// block files on the local disk and evicts the least recently opened block file when a block file is fetched
+// beyond this limit. An evicted block file remains readable through the handles opened before its eviction
+type archiveCache struct {
+	dir      string
+	ledgerID string
+	store    ArchiveStore
+	maxFiles int
+
+	lock    sync.Mutex
+	lru     *list.List // of the cached file numbers, the most recently opened at the front
+	entries map[int]*list.Element
+}
+
+// newArchiveCache creates a cache in the given directory. The block files cached previously in the directory are
+// discarded, as these may be partial
+func newArchiveCache(dir, ledgerID string, store ArchiveStore, maxFiles int) (*archiveCache, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, errors.Wrapf(err, "error while clearing the archive cache dir [%s]", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error while creating the archive cache dir [%s]", dir)
+	}
+	return &archiveCache{
+		dir:      dir,
+		ledgerID: ledgerID,
+		store:    store,
+		maxFiles: maxFiles,
+		lru:      list.New(),
+		entries:  map[int]*list.Element{},
+	}, nil
+}
+
+func (c *archiveCache) open(fileNum int) (*os.File, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.entries[fileNum]; ok {
+		c.lru.MoveToFront(e)
+	} else {
+		if err := c.fetch(fileNum); err != nil {
+			return nil, err
+		}
+		c.entries[fileNum] = c.lru.PushFront(fileNum)
+		c.evict()
+	}
+	return localBlockfileOpener(c.dir)(fileNum)
+}
+
+func (c *archiveCache) fetch(fileNum int) error {
+	filePath := deriveBlockfilePath(c.dir, fileNum)
+	fetchingFilePath := filepath.Join(c.dir, fetchingFilePrefix+filepath.Base(filePath))
+	file, err := os.Create(fetchingFilePath)
+	if err != nil {
+		return errors.Wrapf(err, "error while creating the file [%s]", fetchingFilePath)
+	}
+	defer os.Remove(fetchingFilePath)
+	err = c.store.Get(archiveKey(c.ledgerID, fileNum), file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = errors.Wrapf(closeErr, "error while closing the file [%s]", fetchingFilePath)
+	}
+	if err != nil {
+		return errors.WithMessagef(err, "error while fetching the block file [%d] of the ledger [%s] from the archive", fileNum, c.ledgerID)
+	}
+	logger.Debugf("Fetched the block file [%d] of the ledger [%s] from the archive", fileNum, c.ledgerID)
+	return errors.Wrapf(os.Rename(fetchingFilePath, filePath), "error while renaming the file [%s]", fetchingFilePath)
+}
+
+func (c *archiveCache) evict() {
+	for c.lru.Len() > c.maxFiles {
+		e := c.lru.Back()
+		fileNum := c.lru.Remove(e).(int)
+		delete(c.entries, fileNum)
+		if err := os.Remove(deriveBlockfilePath(c.dir, fileNum)); err != nil {
+			logger.Warnf("Error while evicting the block file [%d] of the ledger [%s] from the archive cache: %s", fileNum, c.ledgerID, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/pkg/errors"
+)
+
+// LocalDirArchiveStore is an ArchiveStore that keeps the archived block files in a directory, typically
+// a mount of a cheaper or a network attached storage
+type LocalDirArchiveStore struct {
+	dir string
+}
+
+// NewLocalDirArchiveStore constructs a LocalDirArchiveStore that keeps the archived block files in the given directory
+func NewLocalDirArchiveStore(dir string) (*LocalDirArchiveStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "error while creating the archive dir [%s]", dir)
+	}
+	return &LocalDirArchiveStore{dir: dir}, nil
+}
+
+// Put implements the function in the interface `ArchiveStore`
+func (s *LocalDirArchiveStore) Put(key, localPath string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return errors.Wrapf(err, "error while opening the file [%s]", localPath)
+	}
+	defer src.Close()
+
+	filePath := s.filePath(key)
+	dir, name := filepath.Split(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "error while creating the dir [%s]", dir)
+	}
+	tempFilePath := filepath.Join(dir, name+".tmp")
+	dst, err := os.Create(tempFilePath)
+	if err != nil {
+		return errors.Wrapf(err, "error while creating the file [%s]", tempFilePath)
+	}
+	defer os.Remove(tempFilePath)
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return errors.Wrapf(err, "error while copying the file [%s] to [%s]", localPath, tempFilePath)
+	}
+	if err := dst.Sync(); err != nil {
+		dst.Close()
+		return errors.Wrapf(err, "error while syncing the file [%s]", tempFilePath)
+	}
+	if err := dst.Close(); err != nil {
+		return errors.Wrapf(err, "error while closing the file [%s]", tempFilePath)
+	}
+	if err := os.Rename(tempFilePath, filePath); err != nil {
+		return errors.Wrapf(err, "error while renaming the file [%s] to [%s]", tempFilePath, filePath)
+	}
+	return fileutil.SyncDir(dir)
+}
+
+// Get implements the function in the interface `ArchiveStore`
+func (s *LocalDirArchiveStore) Get(key string, w io.Writer) error {
+	filePath := s.filePath(key)
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return errors.WithMessagef(ErrNotFoundInArchive, "key [%s]", key)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error while opening the file [%s]", filePath)
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return errors.Wrapf(err, "error while reading the file [%s]", filePath)
+}
+
+func (s *LocalDirArchiveStore) filePath(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
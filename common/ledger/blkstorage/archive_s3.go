@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultS3Region       = "us-east-1"
+	defaultS3Timeout      = 5 * time.Minute
+	s3SigningAlgorithm    = "AWS4-HMAC-SHA256"
+	s3EmptyPayloadHash    = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3MaxErrorMessageSize = 1024
+)
+
+// S3ArchiveConfig configures an S3ArchiveStore
+type S3ArchiveConfig struct {
+	// Endpoint is the URL of the S3 compatible service, e.g., "https://s3.us-east-1.amazonaws.com" or "http://minio:9000".
+	// The objects are addressed in the path style, i.e., as "<Endpoint>/<Bucket>/<key>"
+	Endpoint string
+	// Region is the region used for signing the requests. It defaults to "us-east-1"
+	Region string
+	// Bucket is the bucket in which the block files are archived. The bucket is expected to exist
+	Bucket string
+	// Prefix is prepended to the keys of the archived block files
+	Prefix string
+	// AccessKeyID and SecretAccessKey are the credentials used for signing the requests
+	AccessKeyID     string
+	SecretAccessKey string
+	// Timeout is the timeout for transferring a block file. It defaults to five minutes
+	Timeout time.Duration
+}
+
+// S3ArchiveStore is an ArchiveStore that keeps the archived block files as objects in a bucket of a service
+// that implements the Amazon S3 API, such as Amazon S3 or MinIO. The requests are signed with the AWS
+// Signature Version 4
+type S3ArchiveStore struct {
+	endpoint *url.URL
+	conf     S3ArchiveConfig
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3ArchiveStore constructs an S3ArchiveStore
+func NewS3ArchiveStore(conf *S3ArchiveConfig) (*S3ArchiveStore, error) {
+	endpoint, err := url.Parse(conf.Endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid S3 endpoint [%s]", conf.Endpoint)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, errors.Errorf("invalid S3 endpoint [%s], expected an http or https URL", conf.Endpoint)
+	}
+	if conf.Bucket == "" {
+		return nil, errors.New("S3 bucket is not specified")
+	}
+	c := *conf
+	if c.Region == "" {
+		c.Region = defaultS3Region
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultS3Timeout
+	}
+	return &S3ArchiveStore{
+		endpoint: endpoint,
+		conf:     c,
+		client:   &http.Client{Timeout: c.Timeout},
+		now:      time.Now,
+	}, nil
+}
+
+// Put implements the function in the interface `ArchiveStore`
+func (s *S3ArchiveStore) Put(key, localPath string) error {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return errors.Wrapf(err, "error while opening the file [%s]", localPath)
+	}
+	defer f.Close()
+	// the payload is hashed for signing the request and is read again for sending the request
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return errors.Wrapf(err, "error while reading the file [%s]", localPath)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return errors.Wrapf(err, "error while seeking the file [%s]", localPath)
+	}
+
+	req, err := s.newRequest(http.MethodPut, s.objectPath(key), ioutil.NopCloser(f))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	resp, err := s.do(req, hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s.statusError(req, resp)
+	}
+	return nil
+}
+
+// Get implements the function in the interface `ArchiveStore`
+func (s *S3ArchiveStore) Get(key string, w io.Writer) error {
+	req, err := s.newRequest(http.MethodGet, s.objectPath(key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		_, err := io.Copy(w, resp.Body)
+		return errors.Wrapf(err, "error while reading the object [%s] from the S3 service", key)
+	case http.StatusNotFound:
+		return errors.WithMessagef(ErrNotFoundInArchive, "key [%s]", key)
+	default:
+		return s.statusError(req, resp)
+	}
+}
+
+func (s *S3ArchiveStore) objectPath(key string) string {
+	return "/" + s.conf.Bucket + "/" + s.conf.Prefix + key
+}
+
+func (s *S3ArchiveStore) newRequest(method, path string, body io.ReadCloser) (*http.Request, error) {
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + path
+	u.RawPath = s3EscapePath(u.Path)
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while creating the request for [%s]", path)
+	}
+	return req, nil
+}
+
+func (s *S3ArchiveStore) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	s.sign(req, payloadHash)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while sending the request [%s %s] to the S3 service", req.Method, req.URL.Path)
+	}
+	return resp, nil
+}
+
+func (s *S3ArchiveStore) statusError(req *http.Request, resp *http.Response) error {
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, s3MaxErrorMessageSize))
+	return errors.Errorf("unexpected status [%s] from the S3 service for the request [%s %s]: %s",
+		resp.Status, req.Method, req.URL.Path, strings.TrimSpace(string(msg)))
+}
+
+// sign adds the authorization header to the request as per the AWS Signature Version 4. The headers
+// present in the request, along with the host, are signed
+func (s *S3ArchiveStore) sign(req *http.Request, payloadHash string) {
+	t := s.now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headerValues := map[string]string{"host": host}
+	for name, values := range req.Header {
+		headerValues[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	headerNames := make([]string, 0, len(headerValues))
+	for name := range headerValues {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headerValues[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + s.conf.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalRequestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.conf.SecretAccessKey), date)
+	for _, data := range []string{s.conf.Region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, data)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", s3SigningAlgorithm+
+		" Credential="+s.conf.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3EscapePath escapes all the characters in the path other than the unreserved characters
+// and the path separators, as expected by the S3 service for computing the signature
+func s3EscapePath(path string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&0xF])
+		}
+	}
+	return b.String()
+}
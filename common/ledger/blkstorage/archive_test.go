@@ -0,0 +1,255 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveBlockFiles(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	archiveDir := filepath.Join(path, "archive")
+	store, err := NewLocalDirArchiveStore(archiveDir)
+	require.NoError(t, err)
+	conf := NewConfWithArchive(path, 0, NoCompression, &ArchiveConf{Store: store, CacheSize: 1})
+	ledgerDir := conf.getLedgerBlockDir("testLedger")
+	cacheDir := filepath.Join(path, ArchiveCacheDir, "testLedger")
+	blocks := testutil.ConstructTestBlocks(t, 40)
+
+	env := newTestEnv(t, conf)
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	// block files: [0-10], [11-20], [21-30], [31-39]
+	for i, b := range blocks {
+		require.NoError(t, blkfileMgr.addBlock(b))
+		if i != 0 && i%10 == 0 {
+			blkfileMgr.moveToNextFile()
+		}
+	}
+
+	// the latest block file and the most recent completed block file are retained locally
+	require.Eventually(t, func() bool {
+		exists, _, err := fileutil.FileExists(deriveBlockfilePath(ledgerDir, 1))
+		return err == nil && !exists
+	}, 10*time.Second, 10*time.Millisecond)
+	for fileNum, local := range []bool{false, false, true, true} {
+		exists, _, err := fileutil.FileExists(deriveBlockfilePath(ledgerDir, fileNum))
+		require.NoError(t, err)
+		require.Equal(t, local, exists, "block file [%d]", fileNum)
+		exists, _, err = fileutil.FileExists(filepath.Join(archiveDir, filepath.FromSlash(archiveKey("testLedger", fileNum))))
+		require.NoError(t, err)
+		require.Equal(t, !local, exists, "block file [%d]", fileNum)
+	}
+	archivedLedgers, err := GetLedgersWithArchivedBlocks(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"testLedger"}, archivedLedgers)
+
+	verifyBlocksWithArchive(t, blkfileMgrWrapper, blocks)
+	cachedFiles, err := ioutil.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, cachedFiles, 1)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	t.Run("reopen", func(t *testing.T) {
+		env := newTestEnv(t, conf)
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		verifyBlocksWithArchive(t, blkfileMgrWrapper, blocks)
+	})
+
+	t.Run("rebuild the index from the archived files", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(conf.getIndexDir()))
+		env := newTestEnv(t, conf)
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		verifyBlocksWithArchive(t, blkfileMgrWrapper, blocks)
+	})
+
+	t.Run("block file left behind after archiving", func(t *testing.T) {
+		f, err := os.Create(deriveBlockfilePath(ledgerDir, 0))
+		require.NoError(t, err)
+		require.NoError(t, store.Get(archiveKey("testLedger", 0), f))
+		require.NoError(t, f.Close())
+
+		env := newTestEnv(t, conf)
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		exists, _, err := fileutil.FileExists(deriveBlockfilePath(ledgerDir, 0))
+		require.NoError(t, err)
+		require.False(t, exists)
+		verifyBlocksWithArchive(t, blkfileMgrWrapper, blocks)
+	})
+
+	t.Run("archived block file missing from the archive", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(archiveDir, filepath.FromSlash(archiveKey("testLedger", 0)))))
+		env := newTestEnv(t, conf)
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		_, err := blkfileMgrWrapper.blockfileMgr.retrieveBlockByNumber(5)
+		require.Equal(t, ErrNotFoundInArchive, errors.Cause(err))
+		blkfileMgrWrapper.testGetBlockByNumber(blocks[11:], 11, nil)
+	})
+}
+
+func TestArchiveStoreFailure(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	store := &failingArchiveStore{}
+	conf := NewConfWithArchive(path, 0, NoCompression, &ArchiveConf{Store: store, LocalFiles: 1})
+	blocks := testutil.ConstructTestBlocks(t, 20)
+
+	env := newTestEnv(t, conf)
+	defer env.provider.Close()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	for i, b := range blocks {
+		require.NoError(t, blkfileMgr.addBlock(b))
+		if i%5 == 4 {
+			blkfileMgr.moveToNextFile()
+		}
+	}
+
+	require.Equal(t, 4, blkfileMgr.latestFileNumber())
+	require.Error(t, blkfileMgr.archiver.archiveCompletedFiles(blkfileMgr.latestFileNumber()))
+	require.Equal(t, 0, blkfileMgr.archiver.getFirstLocalFileNum())
+	archivingInfo, err := loadArchivingInfo(conf.getLedgerBlockDir("testLedger"))
+	require.NoError(t, err)
+	require.Nil(t, archivingInfo)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+}
+
+func TestArchiveCache(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	store, err := NewLocalDirArchiveStore(filepath.Join(path, "archive"))
+	require.NoError(t, err)
+	for fileNum := 0; fileNum < 3; fileNum++ {
+		srcPath := filepath.Join(path, "src")
+		require.NoError(t, ioutil.WriteFile(srcPath, []byte{byte(fileNum)}, 0644))
+		require.NoError(t, store.Put(archiveKey("testLedger", fileNum), srcPath))
+	}
+
+	cacheDir := filepath.Join(path, "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "stale"), []byte("stale"), 0644))
+	cache, err := newArchiveCache(cacheDir, "testLedger", store, 2)
+	require.NoError(t, err)
+	requireCachedFiles := func(fileNums ...int) {
+		files, err := ioutil.ReadDir(cacheDir)
+		require.NoError(t, err)
+		names := []string{}
+		for _, f := range files {
+			names = append(names, f.Name())
+		}
+		expectedNames := []string{}
+		for _, fileNum := range fileNums {
+			expectedNames = append(expectedNames, filepath.Base(deriveBlockfilePath(cacheDir, fileNum)))
+		}
+		require.ElementsMatch(t, expectedNames, names)
+	}
+	requireCachedFiles()
+
+	readFile := func(fileNum int) []byte {
+		f, err := cache.open(fileNum)
+		require.NoError(t, err)
+		defer f.Close()
+		b, err := ioutil.ReadAll(f)
+		require.NoError(t, err)
+		return b
+	}
+	require.Equal(t, []byte{0}, readFile(0))
+	require.Equal(t, []byte{1}, readFile(1))
+	requireCachedFiles(0, 1)
+	require.Equal(t, []byte{0}, readFile(0))
+	require.Equal(t, []byte{2}, readFile(2))
+	requireCachedFiles(0, 2)
+
+	// a file opened before its eviction remains readable
+	f, err := cache.open(0)
+	require.NoError(t, err)
+	defer f.Close()
+	require.Equal(t, []byte{1}, readFile(1))
+	require.Equal(t, []byte{2}, readFile(2))
+	requireCachedFiles(1, 2)
+	b, err := ioutil.ReadAll(f)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0}, b)
+
+	_, err = cache.open(3)
+	require.Equal(t, ErrNotFoundInArchive, errors.Cause(err))
+	requireCachedFiles(1, 2)
+}
+
+func TestLocalDirArchiveStore(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	store, err := NewLocalDirArchiveStore(filepath.Join(path, "archive"))
+	require.NoError(t, err)
+
+	srcPath := filepath.Join(path, "src")
+	require.NoError(t, ioutil.WriteFile(srcPath, []byte("content-1"), 0644))
+	require.NoError(t, store.Put("ledger1/file1", srcPath))
+	require.NoError(t, ioutil.WriteFile(srcPath, []byte("content-2"), 0644))
+	require.NoError(t, store.Put("ledger1/file1", srcPath))
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, store.Get("ledger1/file1", buf))
+	require.Equal(t, "content-2", buf.String())
+
+	err = store.Get("ledger1/file2", buf)
+	require.EqualError(t, err, "key [ledger1/file2]: not found in the archive")
+	require.Equal(t, ErrNotFoundInArchive, errors.Cause(err))
+
+	err = store.Put("ledger1/file2", filepath.Join(path, "missing"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error while opening the file")
+}
+
+func verifyBlocksWithArchive(t *testing.T, blkfileMgrWrapper *testBlockfileMgrWrapper, blocks []*common.Block) {
+	require.Equal(t, uint64(len(blocks)), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+	blkfileMgrWrapper.testGetBlockByHash(blocks, nil)
+	blkfileMgrWrapper.testGetBlockByTxID(blocks, nil)
+	testBlockfileMgrBlockIterator(t, blkfileMgrWrapper.blockfileMgr, 0, len(blocks)-1, blocks)
+	for _, block := range blocks {
+		for _, txEnvelopeBytes := range block.Data.Data {
+			txID, err := protoutil.GetOrComputeTxIDFromEnvelope(txEnvelopeBytes)
+			require.NoError(t, err)
+			blkfileMgrWrapper.testGetTransactionByTxID(txID, txEnvelopeBytes, nil)
+		}
+	}
+}
+
+type failingArchiveStore struct{}
+
+func (s *failingArchiveStore) Put(key, localPath string) error {
+	return errors.New("archive-unavailable")
+}
+
+func (s *failingArchiveStore) Get(key string, w io.Writer) error {
+	return errors.New("archive-unavailable")
+}
@@ -59,7 +59,7 @@ func BootstrapBlockstoreFromSnapshot(t *testing.T, ledgerName string, blocks []*
 		require.NoError(t, originalBlkStore.AddBlock(block))
 	}
 
-	_, err = originalBlkStore.ExportTxIds(snapshotDir, testNewHashFunc)
+	_, err = originalBlkStore.ExportTxIds(snapshotDir, testNewHashFunc, nil)
 	require.NoError(t, err)
 
 	lastBlockInSnapshot := blocks[len(blocks)-1]
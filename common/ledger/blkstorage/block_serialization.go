@@ -7,16 +7,33 @@ SPDX-License-Identifier: Apache-2.0
 package blkstorage
 
 import (
+	"bytes"
+
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
+// compressedBlockMarker prefixes the bytes of a compressed block in the block files and is followed by
+// a byte that identifies the compression algorithm. The marker reads, as an uncompressed block, as a
+// header of the block number 1 with an empty data hash and an empty previous hash, which never appears
+// in a block file because the previous hash of any block other than the genesis block is not empty.
+// This allows the compressed and the uncompressed blocks to be mixed in the same block file
+var compressedBlockMarker = []byte{0x01, 0x00, 0x00}
+
+const (
+	snappyCodec = byte(0x01)
+)
+
 type serializedBlockInfo struct {
 	blockHeader *common.BlockHeader
 	txOffsets   []*txindexInfo
 	metadata    *common.BlockMetadata
+	// compressed indicates that the block is stored compressed, in which case the txOffsets
+	// are relative to the decompressed bytes of the block
+	compressed bool
 }
 
 //The order of the transactions must be maintained for history
@@ -43,9 +60,48 @@ func serializeBlock(block *common.Block) ([]byte, *serializedBlockInfo, error) {
 	return buf.Bytes(), info, nil
 }
 
-func deserializeBlock(serializedBlockBytes []byte) (*common.Block, error) {
+// compressBlockBytes returns the bytes to be stored in the block files for a serialized block
+func compressBlockBytes(serializedBlockBytes []byte, compression Compression) ([]byte, error) {
+	switch compression {
+	case NoCompression, "":
+		return serializedBlockBytes, nil
+	case SnappyCompression:
+		compressed := make([]byte, len(compressedBlockMarker)+1, len(compressedBlockMarker)+1+snappy.MaxEncodedLen(len(serializedBlockBytes)))
+		copy(compressed, compressedBlockMarker)
+		compressed[len(compressedBlockMarker)] = snappyCodec
+		return append(compressed, snappy.Encode(nil, serializedBlockBytes)...), nil
+	default:
+		return nil, errors.Errorf("unsupported block compression [%s]", compression)
+	}
+}
+
+// decompressBlockBytes returns the serialized block for the bytes stored in the block files and whether
+// the stored bytes were compressed
+func decompressBlockBytes(storedBlockBytes []byte) ([]byte, bool, error) {
+	if !bytes.HasPrefix(storedBlockBytes, compressedBlockMarker) {
+		return storedBlockBytes, false, nil
+	}
+	if len(storedBlockBytes) == len(compressedBlockMarker) {
+		return nil, false, errors.New("error decoding the compressed block, the compression algorithm is missing")
+	}
+	switch codec := storedBlockBytes[len(compressedBlockMarker)]; codec {
+	case snappyCodec:
+		serializedBlockBytes, err := snappy.Decode(nil, storedBlockBytes[len(compressedBlockMarker)+1:])
+		if err != nil {
+			return nil, false, errors.Wrap(err, "error decompressing the block")
+		}
+		return serializedBlockBytes, true, nil
+	default:
+		return nil, false, errors.Errorf("error decoding the compressed block, unknown compression algorithm [%d]", codec)
+	}
+}
+
+func deserializeBlock(storedBlockBytes []byte) (*common.Block, error) {
+	serializedBlockBytes, _, err := decompressBlockBytes(storedBlockBytes)
+	if err != nil {
+		return nil, err
+	}
 	block := &common.Block{}
-	var err error
 	b := newBuffer(serializedBlockBytes)
 	if block.Header, err = extractHeader(b); err != nil {
 		return nil, err
@@ -59,9 +115,12 @@ func deserializeBlock(serializedBlockBytes []byte) (*common.Block, error) {
 	return block, nil
 }
 
-func extractSerializedBlockInfo(serializedBlockBytes []byte) (*serializedBlockInfo, error) {
-	info := &serializedBlockInfo{}
-	var err error
+func extractSerializedBlockInfo(storedBlockBytes []byte) (*serializedBlockInfo, error) {
+	serializedBlockBytes, compressed, err := decompressBlockBytes(storedBlockBytes)
+	if err != nil {
+		return nil, err
+	}
+	info := &serializedBlockInfo{compressed: compressed}
 	b := newBuffer(serializedBlockBytes)
 	info.blockHeader, err = extractHeader(b)
 	if err != nil {
@@ -42,6 +42,72 @@ func TestBlockSerialization(t *testing.T) {
 	require.Equal(t, block, deserializedBlock)
 }
 
+func TestBlockCompression(t *testing.T) {
+	block := testutil.ConstructTestBlock(t, 1, 10, 100)
+	bb, info, err := serializeBlock(block)
+	require.NoError(t, err)
+
+	t.Run("no compression", func(t *testing.T) {
+		stored, err := compressBlockBytes(bb, NoCompression)
+		require.NoError(t, err)
+		require.Equal(t, bb, stored)
+		decompressed, compressed, err := decompressBlockBytes(stored)
+		require.NoError(t, err)
+		require.False(t, compressed)
+		require.Equal(t, bb, decompressed)
+	})
+
+	t.Run("snappy compression", func(t *testing.T) {
+		stored, err := compressBlockBytes(bb, SnappyCompression)
+		require.NoError(t, err)
+		require.NotEqual(t, bb, stored)
+		decompressed, compressed, err := decompressBlockBytes(stored)
+		require.NoError(t, err)
+		require.True(t, compressed)
+		require.Equal(t, bb, decompressed)
+
+		deserializedBlock, err := deserializeBlock(stored)
+		require.NoError(t, err)
+		require.Equal(t, block, deserializedBlock)
+
+		infoFromStored, err := extractSerializedBlockInfo(stored)
+		require.NoError(t, err)
+		require.True(t, infoFromStored.compressed)
+		infoFromStored.compressed = false
+		require.Equal(t, info, infoFromStored)
+	})
+
+	t.Run("unsupported compression", func(t *testing.T) {
+		_, err := compressBlockBytes(bb, Compression("zstd"))
+		require.EqualError(t, err, "unsupported block compression [zstd]")
+	})
+
+	t.Run("malformed compressed block", func(t *testing.T) {
+		_, _, err := decompressBlockBytes(compressedBlockMarker)
+		require.EqualError(t, err, "error decoding the compressed block, the compression algorithm is missing")
+
+		_, _, err = decompressBlockBytes(append(append([]byte{}, compressedBlockMarker...), 0x05, 0x01))
+		require.EqualError(t, err, "error decoding the compressed block, unknown compression algorithm [5]")
+
+		_, err = deserializeBlock(append(append([]byte{}, compressedBlockMarker...), snappyCodec, 0xff))
+		require.Contains(t, err.Error(), "error decompressing the block")
+	})
+}
+
+func TestParseCompression(t *testing.T) {
+	for name, expected := range map[string]Compression{
+		"":       NoCompression,
+		"none":   NoCompression,
+		"snappy": SnappyCompression,
+	} {
+		c, err := ParseCompression(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, c)
+	}
+	_, err := ParseCompression("gzip")
+	require.EqualError(t, err, "unsupported block compression [gzip], supported values are [none, snappy]")
+}
+
 func TestSerializedBlockInfo(t *testing.T) {
 	c := &testutilTxIDComputator{
 		t:               t,
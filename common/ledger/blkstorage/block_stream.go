@@ -34,12 +34,15 @@ type blockfileStream struct {
 // it starts from a given file offset and continues with the next
 // file segment until the end of the last segment (`endFileNum`)
 type blockStream struct {
-	rootDir           string
+	openBlockfile     blockfileOpener
 	currentFileNum    int
 	endFileNum        int
 	currentFileStream *blockfileStream
 }
 
+// blockfileOpener opens the block file with the given number for reading
+type blockfileOpener func(fileNum int) (*os.File, error)
+
 // blockPlacementInfo captures the information related
 // to block's placement in the file.
 type blockPlacementInfo struct {
@@ -52,15 +55,31 @@ type blockPlacementInfo struct {
 // blockfileStream functions
 ////////////////////////////////////
 func newBlockfileStream(rootDir string, fileNum int, startOffset int64) (*blockfileStream, error) {
-	filePath := deriveBlockfilePath(rootDir, fileNum)
-	logger.Debugf("newBlockfileStream(): filePath=[%s], startOffset=[%d]", filePath, startOffset)
-	var file *os.File
-	var err error
-	if file, err = os.OpenFile(filePath, os.O_RDONLY, 0600); err != nil {
-		return nil, errors.Wrapf(err, "error opening block file %s", filePath)
+	return openBlockfileStream(localBlockfileOpener(rootDir), fileNum, startOffset)
+}
+
+// localBlockfileOpener opens the block files present in the given directory
+func localBlockfileOpener(rootDir string) blockfileOpener {
+	return func(fileNum int) (*os.File, error) {
+		filePath := deriveBlockfilePath(rootDir, fileNum)
+		file, err := os.OpenFile(filePath, os.O_RDONLY, 0600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error opening block file %s", filePath)
+		}
+		return file, nil
+	}
+}
+
+func openBlockfileStream(openBlockfile blockfileOpener, fileNum int, startOffset int64) (*blockfileStream, error) {
+	file, err := openBlockfile(fileNum)
+	if err != nil {
+		return nil, err
 	}
+	filePath := file.Name()
+	logger.Debugf("openBlockfileStream(): filePath=[%s], startOffset=[%d]", filePath, startOffset)
 	var newPosition int64
 	if newPosition, err = file.Seek(startOffset, 0); err != nil {
+		file.Close()
 		return nil, errors.Wrapf(err, "error seeking block file [%s] to startOffset [%d]", filePath, startOffset)
 	}
 	if newPosition != startOffset {
@@ -146,11 +165,15 @@ func (s *blockfileStream) close() error {
 // blockStream functions
 ////////////////////////////////////
 func newBlockStream(rootDir string, startFileNum int, startOffset int64, endFileNum int) (*blockStream, error) {
-	startFileStream, err := newBlockfileStream(rootDir, startFileNum, startOffset)
+	return openBlockStream(localBlockfileOpener(rootDir), startFileNum, startOffset, endFileNum)
+}
+
+func openBlockStream(openBlockfile blockfileOpener, startFileNum int, startOffset int64, endFileNum int) (*blockStream, error) {
+	startFileStream, err := openBlockfileStream(openBlockfile, startFileNum, startOffset)
 	if err != nil {
 		return nil, err
 	}
-	return &blockStream{rootDir, startFileNum, endFileNum, startFileStream}, nil
+	return &blockStream{openBlockfile, startFileNum, endFileNum, startFileStream}, nil
 }
 
 func (s *blockStream) moveToNextBlockfileStream() error {
@@ -159,7 +182,7 @@ func (s *blockStream) moveToNextBlockfileStream() error {
 		return err
 	}
 	s.currentFileNum++
-	if s.currentFileStream, err = newBlockfileStream(s.rootDir, s.currentFileNum, 0); err != nil {
+	if s.currentFileStream, err = openBlockfileStream(s.openBlockfile, s.currentFileNum, 0); err != nil {
 		return err
 	}
 	return nil
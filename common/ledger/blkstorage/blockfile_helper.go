@@ -94,7 +94,15 @@ func binarySearchFileNumForBlock(rootDir string, blockNum uint64) (int, error) {
 		return -1, err
 	}
 
+	pruningInfo, err := loadPruningInfo(rootDir)
+	if err != nil {
+		return -1, err
+	}
+
 	beginFile := 0
+	if pruningInfo != nil {
+		beginFile = int(pruningInfo.FirstFileNum)
+	}
 	endFile := blkfilesInfo.latestFileNumber
 
 	for endFile != beginFile {
@@ -116,7 +124,11 @@ func binarySearchFileNumForBlock(rootDir string, blockNum uint64) (int, error) {
 }
 
 func retrieveFirstBlockNumFromFile(rootDir string, fileNum int) (uint64, error) {
-	s, err := newBlockfileStream(rootDir, fileNum, 0)
+	return firstBlockNumFromFile(localBlockfileOpener(rootDir), fileNum)
+}
+
+func firstBlockNumFromFile(openBlockfile blockfileOpener, fileNum int) (uint64, error) {
+	s, err := openBlockfileStream(openBlockfile, fileNum, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"fmt"
 	"math"
+	"os"
 	"sync"
 	"sync/atomic"
 
@@ -40,6 +41,8 @@ type blockfileMgr struct {
 	index                     *blockIndex
 	blockfilesInfo            *blockfilesInfo
 	bootstrappingSnapshotInfo *BootstrappingSnapshotInfo
+	pruningInfo               *PruningInfo
+	archiver                  *archiver
 	blkfilesInfoCond          *sync.Cond
 	currentFileWriter         *blockfileWriter
 	bcInfo                    atomic.Value
@@ -132,6 +135,18 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 		return nil, err
 	}
 	mgr.bootstrappingSnapshotInfo = bsi
+	if mgr.pruningInfo, err = loadPruningInfo(rootDir); err != nil {
+		return nil, err
+	}
+	if conf.archive != nil {
+		firstFileNum := 0
+		if mgr.pruningInfo != nil {
+			firstFileNum = int(mgr.pruningInfo.FirstFileNum)
+		}
+		if mgr.archiver, err = newArchiver(id, rootDir, conf.archive, firstFileNum); err != nil {
+			return nil, err
+		}
+	}
 	mgr.currentFileWriter = currentFileWriter
 	mgr.blkfilesInfoCond = sync.NewCond(&sync.Mutex{})
 
@@ -160,6 +175,9 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *IndexConfig, indexStore
 		bcInfo.PreviousBlockHash = lastBlockHeader.PreviousHash
 	}
 	mgr.bcInfo.Store(bcInfo)
+	if mgr.archiver != nil {
+		mgr.archiver.start(mgr.latestFileNumber)
+	}
 	return mgr, nil
 }
 
@@ -249,6 +267,9 @@ func deriveBlockfilePath(rootDir string, suffixNum int) string {
 }
 
 func (mgr *blockfileMgr) close() {
+	if mgr.archiver != nil {
+		mgr.archiver.close()
+	}
 	mgr.currentFileWriter.close()
 }
 
@@ -271,6 +292,9 @@ func (mgr *blockfileMgr) moveToNextFile() {
 	}
 	mgr.currentFileWriter = nextFileWriter
 	mgr.updateBlockfilesInfo(blkfilesInfo)
+	if mgr.archiver != nil {
+		mgr.archiver.notify()
+	}
 }
 
 func (mgr *blockfileMgr) addBlock(block *common.Block) error {
@@ -296,6 +320,12 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	if err != nil {
 		return errors.WithMessage(err, "error serializing block")
 	}
+	compressed := mgr.conf.compression != "" && mgr.conf.compression != NoCompression
+	if compressed {
+		if blockBytes, err = compressBlockBytes(blockBytes, mgr.conf.compression); err != nil {
+			return errors.WithMessage(err, "error compressing block")
+		}
+	}
 	blockHash := protoutil.BlockHeaderHash(block.Header)
 	//Get the location / offset where each transaction starts in the block and where the block ends
 	txOffsets := info.txOffsets
@@ -344,14 +374,17 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//Index block file location pointer updated with file suffex and offset for the new block
 	blockFLP := &fileLocPointer{fileSuffixNum: newBlkfilesInfo.latestFileNumber}
 	blockFLP.offset = currentOffset
-	// shift the txoffset because we prepend length of bytes before block bytes
-	for _, txOffset := range txOffsets {
-		txOffset.loc.offset += len(blockBytesEncodedLen)
+	// shift the txoffset because we prepend length of bytes before block bytes. The txoffsets
+	// of a compressed block remain relative to the decompressed block bytes
+	if !compressed {
+		for _, txOffset := range txOffsets {
+			txOffset.loc.offset += len(blockBytesEncodedLen)
+		}
 	}
 	//save the index in the database
 	if err = mgr.index.indexBlock(&blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata, compressed: compressed}); err != nil {
 		return err
 	}
 
@@ -381,6 +414,15 @@ func (mgr *blockfileMgr) syncIndex() error {
 		)
 	}
 
+	if mgr.pruningInfo != nil && nextIndexableBlock < mgr.pruningInfo.FirstBlockNum {
+		// The pruning requires the index to be synced up to the first block retained and hence, this condition
+		// can happen only if the index is dropped/corrupted afterward
+		return errors.Errorf(
+			"cannot sync index with block files. blocks have been pruned from the blockstore and first available block=[%d]",
+			mgr.pruningInfo.FirstBlockNum,
+		)
+	}
+
 	if mgr.blockfilesInfo.noBlockFiles {
 		logger.Debug("No block files present. This happens when there has not been any blocks added to the ledger yet")
 		return nil
@@ -395,8 +437,11 @@ func (mgr *blockfileMgr) syncIndex() error {
 	startOffset := 0
 	skipFirstBlock := false
 	endFileNum := mgr.blockfilesInfo.latestFileNumber
+	if mgr.pruningInfo != nil {
+		startFileNum = int(mgr.pruningInfo.FirstFileNum)
+	}
 
-	firstAvailableBlkNum, err := retrieveFirstBlockNumFromFile(mgr.rootDir, 0)
+	firstAvailableBlkNum, err := firstBlockNumFromFile(mgr.openBlockfile, startFileNum)
 	if err != nil {
 		return err
 	}
@@ -416,7 +461,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 
 	//open a blockstream to the file location that was stored in the index
 	var stream *blockStream
-	if stream, err = newBlockStream(mgr.rootDir, startFileNum, int64(startOffset), endFileNum); err != nil {
+	if stream, err = openBlockStream(mgr.openBlockfile, startFileNum, int64(startOffset), endFileNum); err != nil {
 		return err
 	}
 	var blockBytes []byte
@@ -449,10 +494,13 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 
 		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
-		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
+		//therefore just shift by the difference between blockBytesOffset and blockStartOffset.
+		//The txOffsets of a compressed block remain relative to the decompressed block bytes
+		if !info.compressed {
+			numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+			for _, offset := range info.txOffsets {
+				offset.loc.offset += numBytesToShift
+			}
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
@@ -462,6 +510,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
+		blockIdxInfo.compressed = info.compressed
 
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
@@ -475,6 +524,20 @@ func (mgr *blockfileMgr) syncIndex() error {
 	return nil
 }
 
+func (mgr *blockfileMgr) latestFileNumber() int {
+	mgr.blkfilesInfoCond.L.Lock()
+	defer mgr.blkfilesInfoCond.L.Unlock()
+	return mgr.blockfilesInfo.latestFileNumber
+}
+
+// openBlockfile opens a block file for reading. An archived block file is fetched from the archive store
+func (mgr *blockfileMgr) openBlockfile(fileNum int) (*os.File, error) {
+	if mgr.archiver != nil {
+		return mgr.archiver.openBlockfile(fileNum)
+	}
+	return localBlockfileOpener(mgr.rootDir)(fileNum)
+}
+
 func (mgr *blockfileMgr) getBlockchainInfo() *common.BlockchainInfo {
 	return mgr.bcInfo.Load().(*common.BlockchainInfo)
 }
@@ -516,10 +579,7 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 	if blockNum < mgr.firstPossibleBlockNumberInBlockFiles() {
-		return nil, errors.Errorf(
-			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
-			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
-		)
+		return nil, mgr.blockNotAvailableErr(blockNum)
 	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
@@ -531,10 +591,8 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error) {
 	logger.Debugf("retrieveBlockByTxID() - txID = [%s]", txID)
 	loc, err := mgr.index.getBlockLocByTxID(txID)
-	if err == errNilValue {
-		return nil, errors.Errorf(
-			"details for the TXID [%s] not available. Ledger bootstrapped from a snapshot. First available block = [%d]",
-			txID, mgr.firstPossibleBlockNumberInBlockFiles())
+	if err == errNilValue || err == errPrunedValue {
+		return nil, mgr.txNotAvailableErr(txID, err)
 	}
 	if err != nil {
 		return nil, err
@@ -556,10 +614,7 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
 	if blockNum < mgr.firstPossibleBlockNumberInBlockFiles() {
-		return nil, errors.Errorf(
-			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
-			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
-		)
+		return nil, mgr.blockNotAvailableErr(blockNum)
 	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
@@ -578,10 +633,7 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 
 func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
 	if startNum < mgr.firstPossibleBlockNumberInBlockFiles() {
-		return nil, errors.Errorf(
-			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
-			startNum, mgr.firstPossibleBlockNumberInBlockFiles(),
-		)
+		return nil, mgr.blockNotAvailableErr(startNum)
 	}
 	return newBlockItr(mgr, startNum), nil
 }
@@ -593,10 +645,8 @@ func (mgr *blockfileMgr) txIDExists(txID string) (bool, error) {
 func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByID() - txId = [%s]", txID)
 	loc, err := mgr.index.getTxLoc(txID)
-	if err == errNilValue {
-		return nil, errors.Errorf(
-			"details for the TXID [%s] not available. Ledger bootstrapped from a snapshot. First available block = [%d]",
-			txID, mgr.firstPossibleBlockNumberInBlockFiles())
+	if err == errNilValue || err == errPrunedValue {
+		return nil, mgr.txNotAvailableErr(txID, err)
 	}
 	if err != nil {
 		return nil, err
@@ -607,10 +657,7 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
 	if blockNum < mgr.firstPossibleBlockNumberInBlockFiles() {
-		return nil, errors.Errorf(
-			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
-			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
-		)
+		return nil, mgr.blockNotAvailableErr(blockNum)
 	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
@@ -633,17 +680,50 @@ func (mgr *blockfileMgr) fetchBlock(lp *fileLocPointer) (*common.Block, error) {
 
 func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.Envelope, error) {
 	logger.Debugf("Entering fetchTransactionEnvelope() %v\n", lp)
+	return fetchTransactionEnvelope(mgr.openBlockfile, lp)
+}
+
+// fetchTransactionEnvelope reads the transaction envelope at the given location. The envelope of a
+// transaction in a compressed block is extracted from the decompressed bytes of the block
+func fetchTransactionEnvelope(openBlockfile blockfileOpener, lp *fileLocPointer) (*common.Envelope, error) {
 	var err error
 	var txEnvelopeBytes []byte
-	if txEnvelopeBytes, err = mgr.fetchRawBytes(lp); err != nil {
-		return nil, err
+	if lp.txLocInBlock == nil {
+		if txEnvelopeBytes, err = fetchRawBytes(openBlockfile, lp); err != nil {
+			return nil, err
+		}
+	} else {
+		if txEnvelopeBytes, err = fetchTxBytesFromCompressedBlock(openBlockfile, lp); err != nil {
+			return nil, err
+		}
 	}
 	_, n := proto.DecodeVarint(txEnvelopeBytes)
 	return protoutil.GetEnvelopeFromBlock(txEnvelopeBytes[n:])
 }
 
+func fetchTxBytesFromCompressedBlock(openBlockfile blockfileOpener, lp *fileLocPointer) ([]byte, error) {
+	stream, err := openBlockfileStream(openBlockfile, lp.fileSuffixNum, int64(lp.offset))
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+	storedBlockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return nil, err
+	}
+	serializedBlockBytes, _, err := decompressBlockBytes(storedBlockBytes)
+	if err != nil {
+		return nil, err
+	}
+	start, end := lp.txLocInBlock.offset, lp.txLocInBlock.offset+lp.txLocInBlock.bytesLength
+	if start < 0 || end > len(serializedBlockBytes) || start > end {
+		return nil, errors.Errorf("transaction location [%s] is beyond the bounds of the block at [%s]", lp.txLocInBlock, &lp.locPointer)
+	}
+	return serializedBlockBytes[start:end], nil
+}
+
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
-	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
+	stream, err := openBlockfileStream(mgr.openBlockfile, lp.fileSuffixNum, int64(lp.offset))
 	if err != nil {
 		return nil, err
 	}
@@ -655,9 +735,8 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
-func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
-	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
-	reader, err := newBlockfileReader(filePath)
+func fetchRawBytes(openBlockfile blockfileOpener, lp *fileLocPointer) ([]byte, error) {
+	reader, err := newBlockfileReader(openBlockfile, lp.fileSuffixNum)
 	if err != nil {
 		return nil, err
 	}
@@ -696,14 +775,48 @@ func (mgr *blockfileMgr) saveBlkfilesInfo(i *blockfilesInfo, sync bool) error {
 }
 
 func (mgr *blockfileMgr) firstPossibleBlockNumberInBlockFiles() uint64 {
-	if mgr.bootstrappingSnapshotInfo == nil {
-		return 0
+	firstBlockNum := uint64(0)
+	if mgr.bootstrappingSnapshotInfo != nil {
+		firstBlockNum = mgr.bootstrappingSnapshotInfo.LastBlockNum + 1
+	}
+	if mgr.pruningInfo != nil && mgr.pruningInfo.FirstBlockNum > firstBlockNum {
+		firstBlockNum = mgr.pruningInfo.FirstBlockNum
 	}
-	return mgr.bootstrappingSnapshotInfo.LastBlockNum + 1
+	return firstBlockNum
 }
 
 func (mgr *blockfileMgr) bootstrappedFromSnapshot() bool {
-	return mgr.firstPossibleBlockNumberInBlockFiles() > 0
+	return mgr.bootstrappingSnapshotInfo != nil
+}
+
+// blockNotAvailableErr returns the error for a block below the first block available in the block files.
+// The error for a block that has been pruned wraps ErrBlockPruned
+func (mgr *blockfileMgr) blockNotAvailableErr(blockNum uint64) error {
+	if mgr.bootstrappingSnapshotInfo != nil && blockNum <= mgr.bootstrappingSnapshotInfo.LastBlockNum {
+		return errors.Errorf(
+			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
+			blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
+		)
+	}
+	return errors.WithMessagef(ErrBlockPruned,
+		"cannot serve block [%d]. First available block = [%d]",
+		blockNum, mgr.firstPossibleBlockNumberInBlockFiles(),
+	)
+}
+
+// txNotAvailableErr returns the error for a transaction whose details are not present in the block index,
+// as indicated by the given error from the block index
+func (mgr *blockfileMgr) txNotAvailableErr(txID string, err error) error {
+	if err == errPrunedValue {
+		return errors.WithMessagef(ErrBlockPruned,
+			"details for the TXID [%s] not available. First available block = [%d]",
+			txID, mgr.firstPossibleBlockNumberInBlockFiles(),
+		)
+	}
+	return errors.Errorf(
+		"details for the TXID [%s] not available. Ledger bootstrapped from a snapshot. First available block = [%d]",
+		txID, mgr.firstPossibleBlockNumberInBlockFiles(),
+	)
 }
 
 // scanForLastCompleteBlock scan a given block file and detects the last offset in the file
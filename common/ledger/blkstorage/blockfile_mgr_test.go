@@ -370,6 +370,60 @@ func TestBlockfileMgrGetTxByBlockNumTranNum(t *testing.T) {
 	}
 }
 
+func TestBlockfileMgrWithCompression(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	blocks := testutil.ConstructTestBlocks(t, 15)
+
+	// the first few blocks are stored uncompressed
+	blkfileMgrWrapper.addBlocks(blocks[:5])
+	// compressed blocks are appended to the same block file
+	blkfileMgr.conf.compression = SnappyCompression
+	blkfileMgrWrapper.addBlocks(blocks[5:10])
+	// the remaining compressed blocks are not indexed, so that the index sync gets exercised upon restart
+	originalIndexStore := blkfileMgr.index.db
+	blkfileMgr.index.db = env.provider.leveldbProvider.GetDBHandle("someRandomPlace")
+	blkfileMgrWrapper.addBlocks(blocks[10:])
+	blkfileMgr.index.db = originalIndexStore
+	require.Equal(t, 0, blkfileMgr.blockfilesInfo.latestFileNumber)
+	blkfileMgrWrapper.close()
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgr = blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.testGetBlockByHash(blocks, nil)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+	testBlockfileMgrBlockIterator(t, blkfileMgr, 0, 14, blocks)
+
+	for blockIndex, blk := range blocks {
+		for tranIndex, txEnvelopeBytes := range blk.Data.Data {
+			txEnvelope, err := protoutil.GetEnvelopeFromBlock(txEnvelopeBytes)
+			require.NoError(t, err)
+			txID, err := protoutil.GetOrComputeTxIDFromEnvelope(txEnvelopeBytes)
+			require.NoError(t, err)
+
+			txEnvelopeFromFileMgr, err := blkfileMgr.retrieveTransactionByID(txID)
+			require.NoError(t, err, "Error while retrieving tx from blkfileMgr")
+			require.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+
+			txEnvelopeFromFileMgr, err = blkfileMgr.retrieveTransactionByBlockNumTranNum(uint64(blockIndex), uint64(tranIndex))
+			require.NoError(t, err, "Error while retrieving tx from blkfileMgr")
+			require.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+
+			blockFromFileMgr, err := blkfileMgr.retrieveBlockByTxID(txID)
+			require.NoError(t, err)
+			require.Equal(t, blk, blockFromFileMgr)
+		}
+	}
+
+	header, err := blkfileMgr.retrieveBlockHeaderByNumber(12)
+	require.NoError(t, err)
+	require.Equal(t, blocks[12].Header, header)
+}
+
 func TestBlockfileMgrRestart(t *testing.T) {
 	env := newTestEnv(t, NewConf(testPath(), 0))
 	defer env.Cleanup()
@@ -38,15 +38,17 @@ var (
 	indexSavePointKey              = []byte(indexSavePointKeyStr)
 	errIndexSavePointKeyNotPresent = errors.New("NoBlockIndexed")
 	errNilValue                    = errors.New("")
+	errPrunedValue                 = errors.New("PrunedValue")
 	importTxIDsBatchSize           = uint64(10000) // txID is 64 bytes, so batch size roughly translates to 640KB
 )
 
 type blockIdxInfo struct {
-	blockNum  uint64
-	blockHash []byte
-	flp       *fileLocPointer
-	txOffsets []*txindexInfo
-	metadata  *common.BlockMetadata
+	blockNum   uint64
+	blockHash  []byte
+	flp        *fileLocPointer
+	txOffsets  []*txindexInfo
+	metadata   *common.BlockMetadata
+	compressed bool
 }
 
 type blockIndex struct {
@@ -110,7 +112,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index3 Used to find a transaction by its transaction id
 	if index.isAttributeIndexed(IndexableAttrTxID) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLocationPointer(txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to txid-index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -136,7 +138,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if index.isAttributeIndexed(IndexableAttrBlockNumTranNum) {
 		for i, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLocationPointer(txoffset.loc)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, i, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -196,6 +198,9 @@ func (index *blockIndex) getTxLoc(txID string) (*fileLocPointer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if v.Pruned {
+		return nil, errPrunedValue
+	}
 	txFLP := &fileLocPointer{}
 	if err = txFLP.unmarshal(v.TxLocation); err != nil {
 		return nil, err
@@ -208,6 +213,9 @@ func (index *blockIndex) getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	if err != nil {
 		return nil, err
 	}
+	if v.Pruned {
+		return nil, errPrunedValue
+	}
 	blkFLP := &fileLocPointer{}
 	if err = blkFLP.unmarshal(v.BlkLocation); err != nil {
 		return nil, err
@@ -286,7 +294,7 @@ func (index *blockIndex) getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint
 	return txFLP, nil
 }
 
-func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
+func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewHashFunc, progress *snapshot.Progress) (map[string][]byte, error) {
 	if !index.isAttributeIndexed(IndexableAttrTxID) {
 		return nil, ErrAttrNotIndexed
 	}
@@ -314,7 +322,7 @@ func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewH
 		}
 		previousTxID = txID
 		if numTxIDs == 0 { // first iteration, create the data file
-			dataFile, err = snapshot.CreateFile(filepath.Join(dir, snapshotDataFileName), snapshotFileFormat, newHashFunc)
+			dataFile, err = snapshot.CreateFileWithProgress(filepath.Join(dir, snapshotDataFileName), snapshotFileFormat, newHashFunc, progress)
 			if err != nil {
 				return nil, err
 			}
@@ -324,6 +332,7 @@ func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewH
 			return nil, err
 		}
 		numTxIDs++
+		progress.AddRecords(1)
 	}
 
 	if dataFile == nil {
@@ -336,7 +345,7 @@ func (index *blockIndex) exportUniqueTxIDs(dir string, newHashFunc snapshot.NewH
 	}
 
 	// create the metadata file
-	metadataFile, err := snapshot.CreateFile(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat, newHashFunc)
+	metadataFile, err := snapshot.CreateFileWithProgress(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat, newHashFunc, progress)
 	if err != nil {
 		return nil, err
 	}
@@ -486,6 +495,9 @@ func (lp *locPointer) String() string {
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	// txLocInBlock is set for a transaction in a compressed block. In this case, the locPointer
+	// points to the block and txLocInBlock points to the transaction within the decompressed block bytes
+	txLocInBlock *locPointer
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -495,6 +507,20 @@ func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *
 	return flp
 }
 
+// txLocationPointer returns the location of a transaction in the block, given the location
+// of the transaction relative to the beginning of the block
+func (blockIdxInfo *blockIdxInfo) txLocationPointer(relativeLP *locPointer) *fileLocPointer {
+	flp := blockIdxInfo.flp
+	if !blockIdxInfo.compressed {
+		return newFileLocationPointer(flp.fileSuffixNum, flp.offset, relativeLP)
+	}
+	return &fileLocPointer{
+		fileSuffixNum: flp.fileSuffixNum,
+		locPointer:    locPointer{offset: flp.offset},
+		txLocInBlock:  &locPointer{offset: relativeLP.offset, bytesLength: relativeLP.bytesLength},
+	}
+}
+
 func (flp *fileLocPointer) marshal() ([]byte, error) {
 	buffer := proto.NewBuffer([]byte{})
 	e := buffer.EncodeVarint(uint64(flp.fileSuffixNum))
@@ -509,11 +535,19 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
 	}
+	if flp.txLocInBlock != nil {
+		if e = buffer.EncodeVarint(uint64(flp.txLocInBlock.offset)); e != nil {
+			return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
+		}
+		if e = buffer.EncodeVarint(uint64(flp.txLocInBlock.bytesLength)); e != nil {
+			return nil, errors.Wrapf(e, "unexpected error while marshaling fileLocPointer [%s]", flp)
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
 func (flp *fileLocPointer) unmarshal(b []byte) error {
-	buffer := proto.NewBuffer(b)
+	buffer := newBuffer(b)
 	i, e := buffer.DecodeVarint()
 	if e != nil {
 		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
@@ -530,10 +564,30 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
 	}
 	flp.bytesLength = int(i)
+
+	// the location of a transaction in a compressed block carries the location within the block
+	if buffer.GetBytesConsumed() == len(b) {
+		return nil
+	}
+	txLocInBlock := &locPointer{}
+	i, e = buffer.DecodeVarint()
+	if e != nil {
+		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
+	}
+	txLocInBlock.offset = int(i)
+	i, e = buffer.DecodeVarint()
+	if e != nil {
+		return errors.Wrapf(e, "unexpected error while unmarshaling bytes [%#v] into fileLocPointer", b)
+	}
+	txLocInBlock.bytesLength = int(i)
+	flp.txLocInBlock = txLocInBlock
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
+	if flp.txLocInBlock != nil {
+		return fmt.Sprintf("fileSuffixNum=%d, %s, txLocInBlock=[%s]", flp.fileSuffixNum, flp.locPointer.String(), flp.txLocInBlock.String())
+	}
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
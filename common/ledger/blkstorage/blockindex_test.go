@@ -217,6 +217,26 @@ func containsAttr(indexItems []IndexableAttr, attr IndexableAttr) bool {
 	return false
 }
 
+func TestFileLocPointerMarshalUnmarshal(t *testing.T) {
+	for _, flp := range []*fileLocPointer{
+		{fileSuffixNum: 2, locPointer: locPointer{offset: 100, bytesLength: 20}},
+		{fileSuffixNum: 2, locPointer: locPointer{offset: 100}, txLocInBlock: &locPointer{offset: 300, bytesLength: 40}},
+	} {
+		b, err := flp.marshal()
+		require.NoError(t, err)
+		unmarshaled := &fileLocPointer{}
+		require.NoError(t, unmarshaled.unmarshal(b))
+		require.Equal(t, flp, unmarshaled)
+	}
+
+	flp := &fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 100}, txLocInBlock: &locPointer{offset: 300, bytesLength: 40}}
+	b, err := flp.marshal()
+	require.NoError(t, err)
+	err = (&fileLocPointer{}).unmarshal(b[:len(b)-1])
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected error while unmarshaling bytes")
+}
+
 func TestTxIDKeyEncodingDecoding(t *testing.T) {
 	testcases := []struct {
 		txid   string
@@ -284,7 +304,7 @@ func TestExportUniqueTxIDs(t *testing.T) {
 	defer os.RemoveAll(testSnapshotDir)
 
 	// empty store generates no output
-	fileHashes, err := blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	fileHashes, err := blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.NoError(t, err)
 	require.Empty(t, fileHashes)
 	files, err := ioutil.ReadDir(testSnapshotDir)
@@ -296,7 +316,7 @@ func TestExportUniqueTxIDs(t *testing.T) {
 	blkfileMgr.addBlock(gb)
 	configTxID, err := protoutil.GetOrComputeTxIDFromEnvelope(gb.Data.Data[0])
 	require.NoError(t, err)
-	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.NoError(t, err)
 	verifyExportedTxIDs(t, testSnapshotDir, fileHashes, configTxID)
 	os.Remove(filepath.Join(testSnapshotDir, snapshotDataFileName))
@@ -314,7 +334,7 @@ func TestExportUniqueTxIDs(t *testing.T) {
 	)
 	err = blkfileMgr.addBlock(block1)
 	require.NoError(t, err)
-	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.NoError(t, err)
 	verifyExportedTxIDs(t, testSnapshotDir, fileHashes, "txid-1", "txid-2", "txid-3", configTxID) //"txid-1" appears once, Txids appear in radix sort order
 	os.Remove(filepath.Join(testSnapshotDir, snapshotDataFileName))
@@ -332,9 +352,18 @@ func TestExportUniqueTxIDs(t *testing.T) {
 	blkfileMgr.addBlock(block2)
 	require.NoError(t, err)
 
-	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	progress := &snapshot.Progress{}
+	fileHashes, err = blkfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, progress)
 	require.NoError(t, err)
 	verifyExportedTxIDs(t, testSnapshotDir, fileHashes, "txid-1", "txid-2", "txid-3", "txid-4", "txid-0000000", configTxID) // "txid-1", and "txid-3 appears once and Txids appear in radix sort order
+	require.Equal(t, uint64(6), progress.Records())
+	var exportedBytes int64
+	for _, fileName := range []string{snapshotDataFileName, snapshotMetadataFileName} {
+		fileInfo, err := os.Stat(filepath.Join(testSnapshotDir, fileName))
+		require.NoError(t, err)
+		exportedBytes += fileInfo.Size()
+	}
+	require.Equal(t, uint64(exportedBytes), progress.Bytes())
 }
 
 func TestExportUniqueTxIDsWhenTxIDsNotIndexed(t *testing.T) {
@@ -348,7 +377,7 @@ func TestExportUniqueTxIDsWhenTxIDsNotIndexed(t *testing.T) {
 
 	testSnapshotDir := testPath()
 	defer os.RemoveAll(testSnapshotDir)
-	_, err := blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	_, err := blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.Equal(t, err, ErrAttrNotIndexed)
 }
 
@@ -371,7 +400,7 @@ func TestExportUniqueTxIDsErrorCases(t *testing.T) {
 	dataFilePath := filepath.Join(testSnapshotDir, snapshotDataFileName)
 	_, err := os.Create(dataFilePath)
 	require.NoError(t, err)
-	_, err = blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	_, err = blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.Contains(t, err.Error(), "error while creating the snapshot file: "+dataFilePath)
 	os.RemoveAll(testSnapshotDir)
 
@@ -381,21 +410,21 @@ func TestExportUniqueTxIDsErrorCases(t *testing.T) {
 	metadataFilePath := filepath.Join(testSnapshotDir, snapshotMetadataFileName)
 	_, err = os.Create(metadataFilePath)
 	require.NoError(t, err)
-	_, err = blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	_, err = blkfileMgrWrapper.blockfileMgr.index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.Contains(t, err.Error(), "error while creating the snapshot file: "+metadataFilePath)
 	os.RemoveAll(testSnapshotDir)
 
 	// error while retrieving the txid key
 	require.NoError(t, os.MkdirAll(testSnapshotDir, 0700))
 	index.db.Put([]byte{txIDIdxKeyPrefix}, []byte("some junk value"), true)
-	_, err = index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	_, err = index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.EqualError(t, err, "invalid txIDKey {74}: number of consumed bytes from DecodeVarint is invalid, expected 1, but got 0")
 	os.RemoveAll(testSnapshotDir)
 
 	// error while reading from leveldb
 	require.NoError(t, os.MkdirAll(testSnapshotDir, 0700))
 	env.provider.leveldbProvider.Close()
-	_, err = index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc)
+	_, err = index.exportUniqueTxIDs(testSnapshotDir, testNewHashFunc, nil)
 	require.EqualError(t, err, "internal leveldb error while obtaining db iterator: leveldb: closed")
 	os.RemoveAll(testSnapshotDir)
 }
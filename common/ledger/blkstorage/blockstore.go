@@ -100,9 +100,10 @@ func (store *BlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 // ExportTxIds creates two files in the specified dir and returns a map that contains
 // the mapping between the names of the files and their hashes.
 // Technically, the TxIDs appear in the sort order of radix-sort/shortlex. However,
-// since practically all the TxIDs are of same length, so the sort order would be the lexical sort order
-func (store *BlockStore) ExportTxIds(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
-	return store.fileMgr.index.exportUniqueTxIDs(dir, newHashFunc)
+// since practically all the TxIDs are of same length, so the sort order would be the lexical sort order.
+// The number of the exported TxIDs and the bytes written are accumulated in the progress, if not nil
+func (store *BlockStore) ExportTxIds(dir string, newHashFunc snapshot.NewHashFunc, progress *snapshot.Progress) (map[string][]byte, error) {
+	return store.fileMgr.index.exportUniqueTxIDs(dir, newHashFunc, progress)
 }
 
 // Shutdown shuts down the block store
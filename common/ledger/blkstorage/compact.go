@@ -0,0 +1,269 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// compactingFilePrefix prefixes the name of a block file while it is being rewritten. The prefix differs
+// from the prefix of the block files so that a leftover of an interrupted compaction is not taken for a block file
+const compactingFilePrefix = "compacting_"
+
+// CompactBlockFiles rewrites the block files of all the ledgers such that each block is stored with the given
+// compression, and updates the block index for the new placement of the blocks. The block files that already
+// store all of their blocks as desired are not rewritten. This function is expected to be invoked while the
+// peer is offline. A block file is replaced atomically, however, the block index is updated after the block file
+// is replaced and hence, if this function is interrupted, it should be invoked again before the peer is started
+func CompactBlockFiles(blockStorageDir string, compression Compression, indexConfig *IndexConfig) error {
+	if _, err := compressBlockBytes(nil, compression); err != nil {
+		return err
+	}
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	chainsDir := conf.getChainsDir()
+	chainsDirExists, err := pathExists(chainsDir)
+	if err != nil {
+		return err
+	}
+	if !chainsDirExists {
+		logger.Infof("Dir [%s] missing... exiting", chainsDir)
+		return nil
+	}
+	ledgerIDs, err := fileutil.ListSubdirs(chainsDir)
+	if err != nil {
+		return err
+	}
+
+	dbProvider, err := leveldbhelper.NewProvider(
+		&leveldbhelper.Conf{
+			DBPath:         conf.getIndexDir(),
+			ExpectedFormat: dataFormatVersion(indexConfig),
+		},
+	)
+	if err != nil {
+		return err
+	}
+	defer dbProvider.Close()
+
+	for _, ledgerID := range ledgerIDs {
+		indexDB := dbProvider.GetDBHandle(ledgerID)
+		index, err := newBlockIndex(indexConfig, indexDB)
+		if err != nil {
+			return err
+		}
+		c := &compactor{
+			ledgerID:    ledgerID,
+			ledgerDir:   conf.getLedgerBlockDir(ledgerID),
+			mgr:         &blockfileMgr{db: indexDB},
+			index:       index,
+			compression: compression,
+		}
+		if err := c.compact(); err != nil {
+			return errors.WithMessagef(err, "error while compacting the block files of the ledger [%s]", ledgerID)
+		}
+	}
+	return nil
+}
+
+type compactor struct {
+	ledgerID    string
+	ledgerDir   string
+	mgr         *blockfileMgr
+	index       *blockIndex
+	compression Compression
+}
+
+func (c *compactor) compact() error {
+	blkfilesInfo, err := c.mgr.loadBlkfilesInfo()
+	if err != nil {
+		return err
+	}
+	if blkfilesInfo == nil || blkfilesInfo.noBlockFiles {
+		logger.Infof("No blocks found for the ledger [%s]... skipping", c.ledgerID)
+		return nil
+	}
+	logger.Infof("Compacting the block files of the ledger [%s] with compression [%s]", c.ledgerID, c.compression)
+	// indexing the rewritten blocks moves the savepoint of the index, which is restored once all the files are compacted
+	lastBlockIndexed, err := c.index.getLastBlockIndexed()
+	if err != nil && err != errIndexSavePointKeyNotPresent {
+		return err
+	}
+	indexSavePointPresent := err == nil
+
+	for fileNum := 0; fileNum <= blkfilesInfo.latestFileNumber; fileNum++ {
+		untilBlockNum := uint64(math.MaxUint64)
+		if fileNum == blkfilesInfo.latestFileNumber {
+			// a partially written block, if any, beyond the last persisted block is dropped
+			untilBlockNum = blkfilesInfo.lastPersistedBlock
+		}
+		newSize, err := c.compactFile(fileNum, untilBlockNum)
+		if err != nil {
+			return err
+		}
+		if fileNum != blkfilesInfo.latestFileNumber || newSize == blkfilesInfo.latestFileSize {
+			continue
+		}
+		blkfilesInfo.latestFileSize = newSize
+		if err := c.mgr.saveBlkfilesInfo(blkfilesInfo, true); err != nil {
+			return err
+		}
+	}
+	if indexSavePointPresent {
+		if err := c.index.db.Put(indexSavePointKey, encodeBlockNum(lastBlockIndexed), true); err != nil {
+			return err
+		}
+	}
+	logger.Infof("Compacted the block files of the ledger [%s]", c.ledgerID)
+	return nil
+}
+
+// compactFile rewrites a block file, if any of its blocks is not stored with the desired compression, and
+// indexes the blocks for their new placement. The blocks are read up to the block untilBlockNum and the
+// size of the file for the blocks read is returned
+func (c *compactor) compactFile(fileNum int, untilBlockNum uint64) (int, error) {
+	filePath := deriveBlockfilePath(c.ledgerDir, fileNum)
+	exists, _, err := fileutil.FileExists(filePath)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+	stream, err := newBlockfileStream(c.ledgerDir, fileNum, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+
+	compactingFilePath := filepath.Join(c.ledgerDir, compactingFilePrefix+filepath.Base(filePath))
+	if err := os.RemoveAll(compactingFilePath); err != nil {
+		return 0, errors.Wrapf(err, "error removing the file [%s]", compactingFilePath)
+	}
+	writer, err := newBlockfileWriter(compactingFilePath)
+	if err != nil {
+		return 0, err
+	}
+	defer writer.close()
+
+	var blockIdxInfos []*blockIdxInfo
+	rewrite := false
+	newSize := 0
+	compressed := c.compression != NoCompression && c.compression != ""
+	for {
+		storedBlockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return 0, err
+		}
+		if storedBlockBytes == nil {
+			break
+		}
+		serializedBlockBytes, wasCompressed, err := decompressBlockBytes(storedBlockBytes)
+		if err != nil {
+			return 0, err
+		}
+		info, err := extractSerializedBlockInfo(serializedBlockBytes)
+		if err != nil {
+			return 0, err
+		}
+		blockBytes, err := compressBlockBytes(serializedBlockBytes, c.compression)
+		if err != nil {
+			return 0, err
+		}
+		rewrite = rewrite || wasCompressed != compressed
+
+		blockBytesEncodedLen := proto.EncodeVarint(uint64(len(blockBytes)))
+		if err := writer.append(blockBytesEncodedLen, false); err != nil {
+			return 0, err
+		}
+		if err := writer.append(blockBytes, false); err != nil {
+			return 0, err
+		}
+		if !compressed {
+			for _, txOffset := range info.txOffsets {
+				txOffset.loc.offset += len(blockBytesEncodedLen)
+			}
+		}
+		blockIdxInfos = append(blockIdxInfos, &blockIdxInfo{
+			blockNum:   info.blockHeader.Number,
+			blockHash:  protoutil.BlockHeaderHash(info.blockHeader),
+			flp:        &fileLocPointer{fileSuffixNum: fileNum, locPointer: locPointer{offset: newSize}},
+			txOffsets:  info.txOffsets,
+			metadata:   info.metadata,
+			compressed: compressed,
+		})
+		newSize += len(blockBytesEncodedLen) + len(blockBytes)
+		if info.blockHeader.Number >= untilBlockNum {
+			break
+		}
+	}
+
+	if !rewrite {
+		if err := os.Remove(compactingFilePath); err != nil {
+			return 0, errors.Wrapf(err, "error removing the file [%s]", compactingFilePath)
+		}
+		// the file may have been rewritten by an interrupted compaction that did not update the index
+		upToDate, err := c.isIndexUpToDate(blockIdxInfos)
+		if err != nil || upToDate {
+			logger.Infof("Block file [%s] is already compacted", filePath)
+			return newSize, err
+		}
+		logger.Infof("Block file [%s] is already compacted, updating the block index", filePath)
+		return newSize, c.indexBlocks(blockIdxInfos)
+	}
+	if err := writer.file.Sync(); err != nil {
+		return 0, errors.Wrapf(err, "error syncing the file [%s]", compactingFilePath)
+	}
+	if err := os.Rename(compactingFilePath, filePath); err != nil {
+		return 0, errors.Wrapf(err, "error replacing the file [%s]", filePath)
+	}
+	if err := fileutil.SyncDir(c.ledgerDir); err != nil {
+		return 0, err
+	}
+	if err := c.indexBlocks(blockIdxInfos); err != nil {
+		return 0, err
+	}
+	logger.Infof("Compacted block file [%s], [%d] blocks", filePath, len(blockIdxInfos))
+	return newSize, nil
+}
+
+func (c *compactor) indexBlocks(blockIdxInfos []*blockIdxInfo) error {
+	for _, blockIdxInfo := range blockIdxInfos {
+		if err := c.index.indexBlock(blockIdxInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isIndexUpToDate checks whether the block index points to the given placement of the blocks. The index is
+// assumed to be outdated if the block numbers are not indexed, as the placement cannot be verified
+func (c *compactor) isIndexUpToDate(blockIdxInfos []*blockIdxInfo) (bool, error) {
+	if !c.index.isAttributeIndexed(IndexableAttrBlockNum) {
+		return len(blockIdxInfos) == 0, nil
+	}
+	for _, blockIdxInfo := range blockIdxInfos {
+		flp, err := c.index.getBlockLocByBlockNum(blockIdxInfo.blockNum)
+		if err == ErrNotFoundInIndex {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if flp.fileSuffixNum != blockIdxInfo.flp.fileSuffixNum || flp.offset != blockIdxInfo.flp.offset {
+			return false, nil
+		}
+	}
+	return true, nil
+}
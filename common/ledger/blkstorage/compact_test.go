@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactBlockFiles(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	blocks := testutil.ConstructTestBlocks(t, 40)
+	indexConfig := &IndexConfig{AttrsToIndex: attrsToIndex}
+
+	env := newTestEnv(t, NewConf(path, 0))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	for i, b := range blocks[:30] {
+		require.NoError(t, blkfileMgr.addBlock(b))
+		if i != 0 && i%10 == 0 {
+			blkfileMgr.moveToNextFile()
+		}
+	}
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	t.Run("compress", func(t *testing.T) {
+		require.NoError(t, CompactBlockFiles(path, SnappyCompression, indexConfig))
+		verifyBlockFilesCompression(t, path, "testLedger", true)
+		verifyBlockStoreAfterCompaction(t, path, blocks[:30])
+	})
+
+	t.Run("compact again", func(t *testing.T) {
+		sizes := blockFileSizes(t, path, "testLedger")
+		require.NoError(t, CompactBlockFiles(path, SnappyCompression, indexConfig))
+		require.Equal(t, sizes, blockFileSizes(t, path, "testLedger"))
+		verifyBlockStoreAfterCompaction(t, path, blocks[:30])
+	})
+
+	t.Run("decompress", func(t *testing.T) {
+		require.NoError(t, CompactBlockFiles(path, NoCompression, indexConfig))
+		verifyBlockFilesCompression(t, path, "testLedger", false)
+		verifyBlockStoreAfterCompaction(t, path, blocks[:30])
+	})
+
+	t.Run("rerun after interruption", func(t *testing.T) {
+		// simulate a compaction interrupted after the block files were replaced by restoring a copy of the index
+		indexDir := (&Conf{blockStorageDir: path}).getIndexDir()
+		indexBackupDir := filepath.Join(path, "index-backup")
+		copyDir(t, indexDir, indexBackupDir)
+		require.NoError(t, CompactBlockFiles(path, SnappyCompression, indexConfig))
+		require.NoError(t, os.RemoveAll(indexDir))
+		require.NoError(t, os.Rename(indexBackupDir, indexDir))
+
+		require.NoError(t, CompactBlockFiles(path, SnappyCompression, indexConfig))
+		verifyBlockFilesCompression(t, path, "testLedger", true)
+		verifyBlockStoreAfterCompaction(t, path, blocks[:30])
+	})
+
+	t.Run("append after compaction", func(t *testing.T) {
+		env := newTestEnv(t, NewConf(path, 0))
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		blkfileMgrWrapper.addBlocks(blocks[30:])
+		blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+		blkfileMgrWrapper.testGetBlockByTxID(blocks, nil)
+	})
+
+	t.Run("unsupported compression", func(t *testing.T) {
+		require.EqualError(t, CompactBlockFiles(path, Compression("zstd"), indexConfig), "unsupported block compression [zstd]")
+	})
+}
+
+func verifyBlockStoreAfterCompaction(t *testing.T, path string, blocks []*common.Block) {
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.provider.Close()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+
+	require.Equal(t, uint64(len(blocks)), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0, nil)
+	blkfileMgrWrapper.testGetBlockByHash(blocks, nil)
+	blkfileMgrWrapper.testGetBlockByTxID(blocks, nil)
+	testBlockfileMgrBlockIterator(t, blkfileMgrWrapper.blockfileMgr, 0, len(blocks)-1, blocks)
+	for _, block := range blocks {
+		for _, txEnvelopeBytes := range block.Data.Data {
+			txID, err := protoutil.GetOrComputeTxIDFromEnvelope(txEnvelopeBytes)
+			require.NoError(t, err)
+			blkfileMgrWrapper.testGetTransactionByTxID(txID, txEnvelopeBytes, nil)
+		}
+	}
+}
+
+func verifyBlockFilesCompression(t *testing.T, path, ledgerID string, expectCompressed bool) {
+	ledgerDir := (&Conf{blockStorageDir: path}).getLedgerBlockDir(ledgerID)
+	files, err := ioutil.ReadDir(ledgerDir)
+	require.NoError(t, err)
+	for fileNum, f := range files {
+		require.Equal(t, blockfilePrefix+fmt.Sprintf("%06d", fileNum), f.Name())
+		stream, err := newBlockfileStream(ledgerDir, fileNum, 0)
+		require.NoError(t, err)
+		for {
+			storedBlockBytes, err := stream.nextBlockBytes()
+			require.NoError(t, err)
+			if storedBlockBytes == nil {
+				break
+			}
+			_, compressed, err := decompressBlockBytes(storedBlockBytes)
+			require.NoError(t, err)
+			require.Equal(t, expectCompressed, compressed)
+		}
+		stream.close()
+	}
+}
+
+func blockFileSizes(t *testing.T, path, ledgerID string) map[string]int64 {
+	files, err := ioutil.ReadDir((&Conf{blockStorageDir: path}).getLedgerBlockDir(ledgerID))
+	require.NoError(t, err)
+	sizes := map[string]int64{}
+	for _, f := range files {
+		sizes[f.Name()] = f.Size()
+	}
+	return sizes
+}
+
+func copyDir(t *testing.T, srcDir, destDir string) {
+	require.NoError(t, os.MkdirAll(destDir, 0755))
+	files, err := ioutil.ReadDir(srcDir)
+	require.NoError(t, err)
+	for _, f := range files {
+		b, err := ioutil.ReadFile(filepath.Join(srcDir, f.Name()))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(destDir, f.Name()), b, 0644))
+	}
+}
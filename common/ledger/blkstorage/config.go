@@ -6,29 +6,102 @@ SPDX-License-Identifier: Apache-2.0
 
 package blkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
 	ChainsDir = "chains"
 	// IndexDir is the name of the directory containing all block indexes across ledgers.
-	IndexDir                = "index"
+	IndexDir = "index"
+	// ArchiveCacheDir is the name of the default directory for caching the block files fetched from the archive.
+	ArchiveCacheDir         = "archivecache"
 	defaultMaxBlockfileSize = 64 * 1024 * 1024 // bytes
+	defaultArchiveCacheSize = 4                // block files
+)
+
+// Compression is the algorithm used for compressing the blocks that are appended to the block files
+type Compression string
+
+const (
+	// NoCompression stores the blocks uncompressed
+	NoCompression Compression = "none"
+	// SnappyCompression compresses each block with snappy
+	SnappyCompression Compression = "snappy"
 )
 
+// ParseCompression returns the Compression for the given name. An empty name is treated as "none"
+func ParseCompression(name string) (Compression, error) {
+	switch Compression(name) {
+	case "", NoCompression:
+		return NoCompression, nil
+	case SnappyCompression:
+		return SnappyCompression, nil
+	default:
+		return "", errors.Errorf("unsupported block compression [%s], supported values are [%s, %s]", name, NoCompression, SnappyCompression)
+	}
+}
+
+// ArchiveConf configures the archiving of the completed block files. The block files other than the most recent
+// ones are moved to the archive store in the background and are fetched on demand, through a local cache, when
+// the blocks present in these files are retrieved
+type ArchiveConf struct {
+	// Store is the archive store to which the block files are moved
+	Store ArchiveStore
+	// LocalFiles is the number of the most recent completed block files that are retained on the local disk,
+	// in addition to the block file being appended. At least one completed block file is retained
+	LocalFiles int
+	// CacheDir is the directory in which the block files fetched from the archive store are cached.
+	// It defaults to a directory next to the block files
+	CacheDir string
+	// CacheSize is the maximum number of block files cached per ledger
+	CacheSize int
+}
+
 // Conf encapsulates all the configurations for `BlockStore`
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	compression      Compression
+	archive          *ArchiveConf
 }
 
 // NewConf constructs new `Conf`.
 // blockStorageDir is the top level folder under which `BlockStore` manages its data
 func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
+	return NewConfWithCompression(blockStorageDir, maxBlockfileSize, NoCompression)
+}
+
+// NewConfWithCompression constructs new `Conf` that compresses the blocks appended to the block files
+// with the given algorithm. The blocks that are already present in the block files are read regardless
+// of whether they were compressed and with which algorithm
+func NewConfWithCompression(blockStorageDir string, maxBlockfileSize int, compression Compression) *Conf {
+	return NewConfWithArchive(blockStorageDir, maxBlockfileSize, compression, nil)
+}
+
+// NewConfWithArchive constructs new `Conf` that, in addition to the compression, moves the completed block files
+// to an archive store. A nil archiveConf leaves all the block files on the local disk
+func NewConfWithArchive(blockStorageDir string, maxBlockfileSize int, compression Compression, archiveConf *ArchiveConf) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	if archiveConf != nil {
+		c := *archiveConf
+		if c.LocalFiles < 1 {
+			c.LocalFiles = 1
+		}
+		if c.CacheDir == "" {
+			c.CacheDir = filepath.Join(blockStorageDir, ArchiveCacheDir)
+		}
+		if c.CacheSize <= 0 {
+			c.CacheSize = defaultArchiveCacheSize
+		}
+		archiveConf = &c
+	}
+	return &Conf{blockStorageDir, maxBlockfileSize, compression, archiveConf}
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -0,0 +1,427 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// Inspector provides a read-only view of the block files and the block index of a ledger.
+// It is intended to be used by offline tools against a blockstore that is not opened
+// by a peer or an orderer process. Unlike the `BlockStore`, the Inspector never
+// attempts to sync the index with the block files or to record the blockfiles info,
+// so that the on-disk state can be investigated as is.
+type Inspector struct {
+	ledgerID                  string
+	rootDir                   string
+	dbProvider                *leveldbhelper.Provider
+	index                     *blockIndex
+	bootstrappingSnapshotInfo *BootstrappingSnapshotInfo
+	pruningInfo               *PruningInfo
+}
+
+// BlockLocation captures the placement of a block in the block files.
+// Offset points to the beginning of the length-prefixed block bytes
+type BlockLocation struct {
+	FileNum int
+	Offset  int64
+}
+
+// TxLocation captures the placement of a transaction envelope in the block files. For a transaction
+// in a compressed block, Offset points to the beginning of the length-prefixed block bytes and InBlock
+// captures the placement of the envelope within the decompressed block bytes
+type TxLocation struct {
+	FileNum int
+	Offset  int64
+	Length  int
+	InBlock *TxLocationInBlock
+}
+
+// TxLocationInBlock captures the placement of a transaction envelope within the decompressed bytes of a block
+type TxLocationInBlock struct {
+	Offset int64
+	Length int
+}
+
+// TxIndexEntry represents an entry in the txID index. For a ledger bootstrapped from a snapshot,
+// the txIDs present in the snapshot do not carry the locations and the validation code. The txIDs
+// of the pruned blocks carry the validation code but not the locations
+type TxIndexEntry struct {
+	TxID           string
+	BlockNum       uint64
+	TxNum          uint64
+	ValidationCode peer.TxValidationCode
+	Pruned         bool
+	BlockLocation  *BlockLocation
+	TxLocation     *TxLocation
+}
+
+// IndexInfo captures the summary of the block index for a ledger
+type IndexInfo struct {
+	Format                string
+	IndexedAttrs          []IndexableAttr
+	LastBlockIndexed      uint64
+	LastBlockIndexedFound bool
+}
+
+// OpenInspector opens the block files and the block index for the given ledger for read-only access.
+// The blockStorageDir is the directory that contains the `chains` and the `index` directories, i.e.,
+// `<peer.fileSystemPath>/ledgersData/chains` for a peer and `<FileLedger.Location>` for an orderer.
+// The index format of a peer ledger and of an orderer ledger differ, the format found on the disk
+// decides the set of attributes that can be queried
+func OpenInspector(blockStorageDir, ledgerID string) (*Inspector, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	rootDir := conf.getLedgerBlockDir(ledgerID)
+	if _, err := os.Stat(rootDir); err != nil {
+		return nil, errors.Wrapf(err, "failed to read the block files directory for ledger [%s]", ledgerID)
+	}
+
+	format, err := leveldbhelper.ReadDataFormat(conf.getIndexDir())
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read the format of the block index for ledger [%s]", ledgerID)
+	}
+	// a peer maintains all the indexes whereas an orderer maintains only the block number index
+	indexConfig := &IndexConfig{AttrsToIndex: []IndexableAttr{IndexableAttrBlockNum}}
+	if format == dataformat.CurrentFormat {
+		indexConfig.AttrsToIndex = []IndexableAttr{
+			IndexableAttrBlockHash,
+			IndexableAttrBlockNum,
+			IndexableAttrTxID,
+			IndexableAttrBlockNumTranNum,
+		}
+	}
+	dbProvider, err := leveldbhelper.NewReadOnlyProvider(
+		&leveldbhelper.Conf{
+			DBPath:         conf.getIndexDir(),
+			ExpectedFormat: format,
+		},
+	)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to open block index for ledger [%s]", ledgerID)
+	}
+
+	index, err := newBlockIndex(indexConfig, dbProvider.GetDBHandle(ledgerID))
+	if err != nil {
+		dbProvider.Close()
+		return nil, err
+	}
+	bsi, err := loadBootstrappingSnapshotInfo(rootDir)
+	if err != nil {
+		dbProvider.Close()
+		return nil, err
+	}
+	pruningInfo, err := loadPruningInfo(rootDir)
+	if err != nil {
+		dbProvider.Close()
+		return nil, err
+	}
+	return &Inspector{
+		ledgerID:                  ledgerID,
+		rootDir:                   rootDir,
+		dbProvider:                dbProvider,
+		index:                     index,
+		bootstrappingSnapshotInfo: bsi,
+		pruningInfo:               pruningInfo,
+	}, nil
+}
+
+// Close releases the block index
+func (i *Inspector) Close() {
+	i.dbProvider.Close()
+}
+
+// BootstrappingSnapshotInfo returns the information about the snapshot from which the ledger
+// was bootstrapped, if any
+func (i *Inspector) BootstrappingSnapshotInfo() *BootstrappingSnapshotInfo {
+	return i.bootstrappingSnapshotInfo
+}
+
+// PruningInfo returns the information about the blocks pruned from the block files, if any
+func (i *Inspector) PruningInfo() *PruningInfo {
+	return i.pruningInfo
+}
+
+// FirstBlockNum returns the number of the first block that is expected to be present in the block files
+func (i *Inspector) FirstBlockNum() uint64 {
+	firstBlockNum := uint64(0)
+	if i.bootstrappingSnapshotInfo != nil {
+		firstBlockNum = i.bootstrappingSnapshotInfo.LastBlockNum + 1
+	}
+	if i.pruningInfo != nil && i.pruningInfo.FirstBlockNum > firstBlockNum {
+		firstBlockNum = i.pruningInfo.FirstBlockNum
+	}
+	return firstBlockNum
+}
+
+// BlockfilesInfo scans the block files and returns the number of the latest block file,
+// the number of the last complete block and whether any block is present in the block files
+func (i *Inspector) BlockfilesInfo() (latestFileNum int, lastBlockNum uint64, noBlockFiles bool, err error) {
+	info, err := constructBlockfilesInfo(i.rootDir)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return info.latestFileNumber, info.lastPersistedBlock, info.noBlockFiles, nil
+}
+
+// IndexInfo returns the summary of the block index for the ledger
+func (i *Inspector) IndexInfo() (*IndexInfo, error) {
+	format, err := i.dbProvider.GetDataFormat()
+	if err != nil {
+		return nil, err
+	}
+	info := &IndexInfo{Format: format}
+	for _, a := range []IndexableAttr{
+		IndexableAttrBlockHash,
+		IndexableAttrBlockNum,
+		IndexableAttrTxID,
+		IndexableAttrBlockNumTranNum,
+	} {
+		if i.index.isAttributeIndexed(a) {
+			info.IndexedAttrs = append(info.IndexedAttrs, a)
+		}
+	}
+	lastBlockIndexed, err := i.index.getLastBlockIndexed()
+	switch err {
+	case nil:
+		info.LastBlockIndexed = lastBlockIndexed
+		info.LastBlockIndexedFound = true
+	case errIndexSavePointKeyNotPresent:
+	default:
+		return nil, err
+	}
+	return info, nil
+}
+
+// IsAttributeIndexed returns true if the given attribute is maintained in the block index of the ledger
+func (i *Inspector) IsAttributeIndexed(attr IndexableAttr) bool {
+	return i.index.isAttributeIndexed(attr)
+}
+
+// BlockLocationByNumber returns the location of the block as recorded in the block index
+func (i *Inspector) BlockLocationByNumber(blockNum uint64) (*BlockLocation, error) {
+	flp, err := i.index.getBlockLocByBlockNum(blockNum)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockLocation{FileNum: flp.fileSuffixNum, Offset: int64(flp.offset)}, nil
+}
+
+// BlockLocationByHash returns the location of the block as recorded in the block index
+func (i *Inspector) BlockLocationByHash(blockHash []byte) (*BlockLocation, error) {
+	flp, err := i.index.getBlockLocByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockLocation{FileNum: flp.fileSuffixNum, Offset: int64(flp.offset)}, nil
+}
+
+// TxIndexEntries returns all the entries present in the txID index for the given txID.
+// More than one entry is returned if the same txID appeared in multiple transactions,
+// for instance, in the case of transactions marked as duplicate
+func (i *Inspector) TxIndexEntries(txID string) ([]*TxIndexEntry, error) {
+	if !i.index.isAttributeIndexed(IndexableAttrTxID) {
+		return nil, ErrAttrNotIndexed
+	}
+	rangeScan := constructTxIDRangeScan(txID)
+	itr, err := i.index.db.GetIterator(rangeScan.startKey, rangeScan.stopKey)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
+	}
+	defer itr.Release()
+
+	var entries []*TxIndexEntry
+	for itr.Next() {
+		entry, err := decodeTxIndexEntry(txID, rangeScan.startKey, itr.Key(), itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrapf(err, "error while trying to retrieve transaction info by TXID [%s]", txID)
+	}
+	return entries, nil
+}
+
+// TxLocationByBlockNumTranNum returns the location of the transaction as recorded in the block index
+func (i *Inspector) TxLocationByBlockNumTranNum(blockNum, tranNum uint64) (*TxLocation, error) {
+	flp, err := i.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
+	if err != nil {
+		return nil, err
+	}
+	return &TxLocation{FileNum: flp.fileSuffixNum, Offset: int64(flp.offset), Length: flp.bytesLength}, nil
+}
+
+// RetrieveBlockAt reads the block present at the given location in the block files
+func (i *Inspector) RetrieveBlockAt(loc *BlockLocation) (*common.Block, error) {
+	stream, err := newBlockfileStream(i.rootDir, loc.FileNum, loc.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return nil, err
+	}
+	if blockBytes == nil {
+		return nil, errors.Errorf("no block present at location [fileNum=%d, offset=%d]", loc.FileNum, loc.Offset)
+	}
+	return deserializeBlock(blockBytes)
+}
+
+// RetrieveTxAt reads the transaction envelope present at the given location in the block files
+func (i *Inspector) RetrieveTxAt(loc *TxLocation) (*common.Envelope, error) {
+	flp := &fileLocPointer{
+		fileSuffixNum: loc.FileNum,
+		locPointer:    locPointer{offset: int(loc.Offset), bytesLength: loc.Length},
+	}
+	if loc.InBlock != nil {
+		flp.txLocInBlock = &locPointer{offset: int(loc.InBlock.Offset), bytesLength: loc.InBlock.Length}
+	}
+	return fetchTransactionEnvelope(localBlockfileOpener(i.rootDir), flp)
+}
+
+// RetrieveBlocks returns an iterator that reads the blocks sequentially from the block files,
+// starting at the block with number startNum. The iterator does not consult the block index
+// and hence can be used even when the block index is missing or corrupted
+func (i *Inspector) RetrieveBlocks(startNum uint64) (*BlockfilesItr, error) {
+	if startNum < i.FirstBlockNum() {
+		if i.bootstrappingSnapshotInfo == nil || startNum > i.bootstrappingSnapshotInfo.LastBlockNum {
+			return nil, errors.WithMessagef(ErrBlockPruned,
+				"cannot serve block [%d]. First available block = [%d]",
+				startNum, i.FirstBlockNum(),
+			)
+		}
+		return nil, errors.Errorf(
+			"cannot serve block [%d]. The ledger is bootstrapped from a snapshot. First available block = [%d]",
+			startNum, i.FirstBlockNum(),
+		)
+	}
+	latestFileNum, lastBlockNum, noBlockFiles, err := i.BlockfilesInfo()
+	if err != nil {
+		return nil, err
+	}
+	if noBlockFiles || startNum > lastBlockNum {
+		return &BlockfilesItr{}, nil
+	}
+	startFileNum, err := binarySearchFileNumForBlock(i.rootDir, startNum)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := newBlockStream(i.rootDir, startFileNum, 0, latestFileNum)
+	if err != nil {
+		return nil, err
+	}
+	return &BlockfilesItr{
+		stream:       stream,
+		startNum:     startNum,
+		lastBlockNum: lastBlockNum,
+	}, nil
+}
+
+// BlockfilesItr iterates over the blocks present in the block files
+type BlockfilesItr struct {
+	stream       *blockStream
+	startNum     uint64
+	lastBlockNum uint64
+	done         bool
+}
+
+// Next returns the next block along with its location in the block files.
+// A nil block is returned when the iterator is exhausted
+func (itr *BlockfilesItr) Next() (*common.Block, *BlockLocation, error) {
+	if itr.stream == nil || itr.done {
+		return nil, nil, nil
+	}
+	for {
+		blockBytes, placementInfo, err := itr.stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return nil, nil, err
+		}
+		if blockBytes == nil {
+			itr.done = true
+			return nil, nil, nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if info.blockHeader.Number < itr.startNum {
+			continue
+		}
+		block, err := deserializeBlock(blockBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if block.Header.Number >= itr.lastBlockNum {
+			// a partially written block, if any, after the last complete block is not returned
+			itr.done = true
+		}
+		return block, &BlockLocation{FileNum: placementInfo.fileNum, Offset: placementInfo.blockStartOffset}, nil
+	}
+}
+
+// Close releases the underlying block files
+func (itr *BlockfilesItr) Close() {
+	if itr.stream != nil {
+		itr.stream.close()
+	}
+}
+
+func decodeTxIndexEntry(txID string, txIDKeyPrefix, key, val []byte) (*TxIndexEntry, error) {
+	entry := &TxIndexEntry{TxID: txID}
+	remainingBytes := key[len(txIDKeyPrefix):]
+	blkNum, n, err := util.DecodeOrderPreservingVarUint64(remainingBytes)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid txIDKey {%x}", key)
+	}
+	txNum, _, err := util.DecodeOrderPreservingVarUint64(remainingBytes[n:])
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid txIDKey {%x}", key)
+	}
+	entry.BlockNum = blkNum
+	entry.TxNum = txNum
+
+	if len(val) == 0 {
+		// entries imported from a snapshot do not carry a value
+		entry.ValidationCode = peer.TxValidationCode(-1)
+		return entry, nil
+	}
+	indexVal := &TxIDIndexValue{}
+	if err := proto.Unmarshal(val, indexVal); err != nil {
+		return nil, errors.Wrapf(err, "unexpected error while unmarshaling bytes [%#v] into TxIDIndexValProto", val)
+	}
+	entry.ValidationCode = peer.TxValidationCode(indexVal.TxValidationCode)
+	if indexVal.Pruned {
+		entry.Pruned = true
+		return entry, nil
+	}
+
+	blkFLP := &fileLocPointer{}
+	if err := blkFLP.unmarshal(indexVal.BlkLocation); err != nil {
+		return nil, err
+	}
+	entry.BlockLocation = &BlockLocation{FileNum: blkFLP.fileSuffixNum, Offset: int64(blkFLP.offset)}
+
+	txFLP := &fileLocPointer{}
+	if err := txFLP.unmarshal(indexVal.TxLocation); err != nil {
+		return nil, err
+	}
+	entry.TxLocation = &TxLocation{FileNum: txFLP.fileSuffixNum, Offset: int64(txFLP.offset), Length: txFLP.bytesLength}
+	if txFLP.txLocInBlock != nil {
+		entry.TxLocation.InBlock = &TxLocationInBlock{Offset: int64(txFLP.txLocInBlock.offset), Length: txFLP.txLocInBlock.bytesLength}
+	}
+	return entry, nil
+}
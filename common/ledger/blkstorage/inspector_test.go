@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspector(t *testing.T) {
+	path := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	txflags.ValidationFlags(blocks[5].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).
+		SetFlag(0, peer.TxValidationCode_MVCC_READ_CONFLICT)
+
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	for i, b := range blocks {
+		require.NoError(t, blkfileMgr.addBlock(b))
+		if i != 0 && i%10 == 0 {
+			blkfileMgr.moveToNextFile()
+		}
+	}
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	inspector, err := OpenInspector(path, "testLedger")
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	t.Run("blockfiles-and-index-info", func(t *testing.T) {
+		latestFileNum, lastBlockNum, noBlockFiles, err := inspector.BlockfilesInfo()
+		require.NoError(t, err)
+		require.Equal(t, 2, latestFileNum)
+		require.Equal(t, uint64(29), lastBlockNum)
+		require.False(t, noBlockFiles)
+
+		indexInfo, err := inspector.IndexInfo()
+		require.NoError(t, err)
+		require.Equal(t,
+			&IndexInfo{
+				Format:                "2.0",
+				IndexedAttrs:          attrsToIndex,
+				LastBlockIndexed:      29,
+				LastBlockIndexedFound: true,
+			},
+			indexInfo,
+		)
+		require.Nil(t, inspector.BootstrappingSnapshotInfo())
+		require.Equal(t, uint64(0), inspector.FirstBlockNum())
+	})
+
+	t.Run("retrieve-blocks-from-files", func(t *testing.T) {
+		itr, err := inspector.RetrieveBlocks(15)
+		require.NoError(t, err)
+		defer itr.Close()
+		for i := 15; i < 30; i++ {
+			block, loc, err := itr.Next()
+			require.NoError(t, err)
+			require.Equal(t, blocks[i], block)
+
+			indexedLoc, err := inspector.BlockLocationByNumber(uint64(i))
+			require.NoError(t, err)
+			require.Equal(t, indexedLoc, loc)
+		}
+		block, loc, err := itr.Next()
+		require.NoError(t, err)
+		require.Nil(t, block)
+		require.Nil(t, loc)
+	})
+
+	t.Run("retrieve-block-by-hash", func(t *testing.T) {
+		loc, err := inspector.BlockLocationByHash(protoutil.BlockHeaderHash(blocks[12].Header))
+		require.NoError(t, err)
+		block, err := inspector.RetrieveBlockAt(loc)
+		require.NoError(t, err)
+		require.Equal(t, blocks[12], block)
+
+		_, err = inspector.BlockLocationByHash([]byte("non-existing-hash"))
+		require.Equal(t, ErrNotFoundInIndex, err)
+	})
+
+	t.Run("tx-index-entries", func(t *testing.T) {
+		txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[5].Data.Data[0])
+		require.NoError(t, err)
+		entries, err := inspector.TxIndexEntries(txID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, txID, entries[0].TxID)
+		require.Equal(t, uint64(5), entries[0].BlockNum)
+		require.Equal(t, uint64(0), entries[0].TxNum)
+		require.Equal(t, peer.TxValidationCode_MVCC_READ_CONFLICT, entries[0].ValidationCode)
+
+		block, err := inspector.RetrieveBlockAt(entries[0].BlockLocation)
+		require.NoError(t, err)
+		require.Equal(t, blocks[5], block)
+
+		txEnv, err := inspector.RetrieveTxAt(entries[0].TxLocation)
+		require.NoError(t, err)
+		expectedTxEnv, err := protoutil.GetEnvelopeFromBlock(blocks[5].Data.Data[0])
+		require.NoError(t, err)
+		require.True(t, proto.Equal(expectedTxEnv, txEnv))
+
+		txLoc, err := inspector.TxLocationByBlockNumTranNum(5, 0)
+		require.NoError(t, err)
+		require.Equal(t, entries[0].TxLocation, txLoc)
+
+		entries, err = inspector.TxIndexEntries("non-existing-txid")
+		require.NoError(t, err)
+		require.Len(t, entries, 0)
+	})
+}
+
+func TestInspectorOrdererIndex(t *testing.T) {
+	path := testPath()
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	env := newTestEnvSelectiveIndexing(t, NewConf(path, 0), []IndexableAttr{IndexableAttrBlockNum}, &disabled.Provider{})
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	inspector, err := OpenInspector(path, "testLedger")
+	require.NoError(t, err)
+	defer inspector.Close()
+
+	indexInfo, err := inspector.IndexInfo()
+	require.NoError(t, err)
+	require.Equal(t, []IndexableAttr{IndexableAttrBlockNum}, indexInfo.IndexedAttrs)
+	require.False(t, inspector.IsAttributeIndexed(IndexableAttrTxID))
+
+	_, err = inspector.TxIndexEntries("txid")
+	require.Equal(t, ErrAttrNotIndexed, err)
+
+	loc, err := inspector.BlockLocationByNumber(3)
+	require.NoError(t, err)
+	block, err := inspector.RetrieveBlockAt(loc)
+	require.NoError(t, err)
+	require.Equal(t, blocks[3], block)
+}
+
+func TestInspectorBootstrappedFromSnapshot(t *testing.T) {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	bsi := &SnapshotInfo{
+		LastBlockNum:      4,
+		LastBlockHash:     protoutil.BlockHeaderHash(blocks[4].Header),
+		PreviousBlockHash: protoutil.BlockHeaderHash(blocks[3].Header),
+	}
+	snapshotDir := testPath()
+	defer os.RemoveAll(snapshotDir)
+	originalStore, err := env.provider.Open("original")
+	require.NoError(t, err)
+	for _, b := range blocks[:5] {
+		require.NoError(t, originalStore.AddBlock(b))
+	}
+	_, err = originalStore.ExportTxIds(snapshotDir, testNewHashFunc, nil)
+	require.NoError(t, err)
+	require.NoError(t, env.provider.ImportFromSnapshot("testLedger", snapshotDir, bsi))
+	store, err := env.provider.Open("testLedger")
+	require.NoError(t, err)
+	for _, b := range blocks[5:] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	env.provider.Close()
+
+	inspector, err := OpenInspector(path, "testLedger")
+	require.NoError(t, err)
+	defer inspector.Close()
+	require.Equal(t, uint64(5), inspector.FirstBlockNum())
+	require.Equal(t, bsi.LastBlockHash, inspector.BootstrappingSnapshotInfo().LastBlockHash)
+
+	_, err = inspector.RetrieveBlocks(2)
+	require.EqualError(t, err, "cannot serve block [2]. The ledger is bootstrapped from a snapshot. First available block = [5]")
+
+	itr, err := inspector.RetrieveBlocks(5)
+	require.NoError(t, err)
+	defer itr.Close()
+	block, _, err := itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, blocks[5], block)
+
+	txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[2].Data.Data[0])
+	require.NoError(t, err)
+	entries, err := inspector.TxIndexEntries(txID)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, peer.TxValidationCode(-1), entries[0].ValidationCode)
+	require.Nil(t, entries[0].BlockLocation)
+}
+
+func TestOpenInspectorNonExistingLedger(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	_, err := OpenInspector(path, "non-existing-ledger")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read the block files directory for ledger [non-existing-ledger]")
+}
+
+func TestOpenInspectorReadOnly(t *testing.T) {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	require.NoError(t, blkfileMgrWrapper.blockfileMgr.addBlock(testutil.ConstructTestBlocks(t, 1)[0]))
+
+	_, err := OpenInspector(path, "testLedger")
+	require.Error(t, err, "the block index is held open by the block store")
+	require.Contains(t, err.Error(), "make sure no process is using it")
+
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+	indexDir := (&Conf{blockStorageDir: path}).getIndexDir()
+	require.NoError(t, os.RemoveAll(indexDir))
+
+	_, err = OpenInspector(path, "testLedger")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to read the format of the block index for ledger [testLedger]")
+	_, err = os.Stat(indexDir)
+	require.True(t, os.IsNotExist(err), "the missing block index is not created")
+}
@@ -0,0 +1,297 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	pruningInfoFile     = "prunedBlocks.info"
+	pruningInfoTempFile = "prunedBlocksTemp.info"
+)
+
+// ErrBlockPruned is returned when the requested block, or a transaction in the requested block,
+// has been pruned from the block store
+var ErrBlockPruned = errors.New("the block has been pruned")
+
+type pruner struct {
+	ledgerID      string
+	ledgerDir     string
+	index         *blockIndex
+	belowBlockNum uint64
+}
+
+// PruneBlocks deletes the block files of a ledger that contain only the blocks below the given block number, along
+// with the entries in the block index for these blocks. The latest block file is never deleted and hence, the blocks
+// below the given block number that share a block file with the subsequent blocks are retained. The txIDs of the pruned
+// transactions are retained in the block index, without their locations, so that the duplicate txIDs continue to be
+// detected. The number of the first block that remains available is returned.
+// This function is expected to be invoked while the peer is offline. The pruning information is recorded before
+// any block file is deleted and hence, if this function is interrupted, it should be invoked again
+func PruneBlocks(blockStorageDir, ledgerID string, belowBlockNum uint64, indexConfig *IndexConfig) (uint64, error) {
+	conf := &Conf{blockStorageDir: blockStorageDir}
+	ledgerDir := conf.getLedgerBlockDir(ledgerID)
+	if err := validateLedgerID(ledgerDir, ledgerID); err != nil {
+		return 0, err
+	}
+	dbProvider, err := leveldbhelper.NewProvider(
+		&leveldbhelper.Conf{
+			DBPath:         conf.getIndexDir(),
+			ExpectedFormat: dataFormatVersion(indexConfig),
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer dbProvider.Close()
+
+	indexDB := dbProvider.GetDBHandle(ledgerID)
+	index, err := newBlockIndex(indexConfig, indexDB)
+	if err != nil {
+		return 0, err
+	}
+	p := &pruner{
+		ledgerID:      ledgerID,
+		ledgerDir:     ledgerDir,
+		index:         index,
+		belowBlockNum: belowBlockNum,
+	}
+	return p.prune(&blockfileMgr{db: indexDB})
+}
+
+func (p *pruner) prune(mgr *blockfileMgr) (uint64, error) {
+	pruningInfo, err := loadPruningInfo(p.ledgerDir)
+	if err != nil {
+		return 0, err
+	}
+	firstAvailableBlockNum := uint64(0)
+	if pruningInfo != nil {
+		firstAvailableBlockNum = pruningInfo.FirstBlockNum
+	}
+
+	blkfilesInfo, err := mgr.loadBlkfilesInfo()
+	if err != nil {
+		return 0, err
+	}
+	if blkfilesInfo == nil || blkfilesInfo.noBlockFiles {
+		logger.Infof("No blocks found for the ledger [%s]... skipping", p.ledgerID)
+		return firstAvailableBlockNum, nil
+	}
+	lastBlockIndexed, err := p.index.getLastBlockIndexed()
+	if err != nil && err != errIndexSavePointKeyNotPresent {
+		return 0, err
+	}
+	if err == errIndexSavePointKeyNotPresent || lastBlockIndexed+1 < p.belowBlockNum {
+		return 0, errors.Errorf(
+			"cannot prune the blocks below block [%d] as the block index is behind, start the peer to sync the block index before pruning",
+			p.belowBlockNum,
+		)
+	}
+
+	filesToPrune, firstFileNum, firstBlockNum, err := p.filesToPrune(blkfilesInfo.latestFileNumber)
+	if err != nil {
+		return 0, err
+	}
+	if len(filesToPrune) == 0 {
+		logger.Infof("No block file of the ledger [%s] contains only the blocks below block [%d]... skipping", p.ledgerID, p.belowBlockNum)
+		return firstAvailableBlockNum, nil
+	}
+	if firstBlockNum > firstAvailableBlockNum {
+		if err := savePruningInfo(p.ledgerDir, &PruningInfo{FirstBlockNum: firstBlockNum, FirstFileNum: uint64(firstFileNum)}); err != nil {
+			return 0, err
+		}
+		firstAvailableBlockNum = firstBlockNum
+	}
+
+	for _, fileNum := range filesToPrune {
+		if err := p.pruneIndexEntries(fileNum); err != nil {
+			return 0, err
+		}
+		filePath := deriveBlockfilePath(p.ledgerDir, fileNum)
+		if err := os.Remove(filePath); err != nil {
+			return 0, errors.Wrapf(err, "error while deleting the block file [%s]", filePath)
+		}
+		logger.Infof("Deleted the block file [%s]", filePath)
+	}
+	if err := fileutil.SyncDir(p.ledgerDir); err != nil {
+		return 0, err
+	}
+	logger.Infof("Pruned the blocks of the ledger [%s], first available block = [%d]", p.ledgerID, firstAvailableBlockNum)
+	return firstAvailableBlockNum, nil
+}
+
+// filesToPrune returns the block files that contain only the blocks below the block belowBlockNum,
+// along with the number of the first block file to be retained and the number of the first block in it.
+// The block files that are already deleted by a previous pruning are skipped
+func (p *pruner) filesToPrune(latestFileNum int) ([]int, int, uint64, error) {
+	var filesToPrune []int
+	var firstBlockNum uint64
+	for fileNum := 0; fileNum < latestFileNum; fileNum++ {
+		exists, _, err := fileutil.FileExists(deriveBlockfilePath(p.ledgerDir, fileNum))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if !exists {
+			continue
+		}
+		// all the blocks in a block file are below the first block in the next block file
+		nextFileFirstBlockNum, found, err := firstBlockNumInFile(p.ledgerDir, fileNum+1)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if !found || nextFileFirstBlockNum > p.belowBlockNum {
+			break
+		}
+		filesToPrune = append(filesToPrune, fileNum)
+		firstBlockNum = nextFileFirstBlockNum
+	}
+	if len(filesToPrune) == 0 {
+		return nil, 0, 0, nil
+	}
+	return filesToPrune, filesToPrune[len(filesToPrune)-1] + 1, firstBlockNum, nil
+}
+
+// pruneIndexEntries deletes the entries from the block index for the blocks in the given block file.
+// The entries for the txIDs are retained without the locations of the transactions
+func (p *pruner) pruneIndexEntries(fileNum int) error {
+	stream, err := newBlockfileStream(p.ledgerDir, fileNum, 0)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	batch := p.index.db.NewUpdateBatch()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			break
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		blockNum := info.blockHeader.Number
+		batch.Delete(constructBlockHashKey(protoutil.BlockHeaderHash(info.blockHeader)))
+		batch.Delete(constructBlockNumKey(blockNum))
+		for txNum, txOffset := range info.txOffsets {
+			batch.Delete(constructBlockNumTranNumKey(blockNum, uint64(txNum)))
+			if err := p.pruneTxIDEntry(batch, constructTxIDKey(txOffset.txID, blockNum, uint64(txNum))); err != nil {
+				return err
+			}
+		}
+	}
+	return p.index.db.WriteBatch(batch, true)
+}
+
+func (p *pruner) pruneTxIDEntry(batch *leveldbhelper.UpdateBatch, txIDKey []byte) error {
+	valBytes, err := p.index.db.Get(txIDKey)
+	if err != nil {
+		return err
+	}
+	if valBytes == nil {
+		return nil
+	}
+	val := &TxIDIndexValue{}
+	if err := proto.Unmarshal(valBytes, val); err != nil {
+		return errors.Wrapf(err, "unexpected error while unmarshaling bytes [%#v] into TxIDIndexValProto", valBytes)
+	}
+	prunedValBytes, err := proto.Marshal(&TxIDIndexValue{TxValidationCode: val.TxValidationCode, Pruned: true})
+	if err != nil {
+		return errors.Wrap(err, "unexpected error while marshaling TxIDIndexValProto message")
+	}
+	batch.Put(txIDKey, prunedValBytes)
+	return nil
+}
+
+// firstBlockNumInFile returns the number of the first block in the given block file and whether the block file
+// contains any block
+func firstBlockNumInFile(rootDir string, fileNum int) (uint64, bool, error) {
+	exists, _, err := fileutil.FileExists(deriveBlockfilePath(rootDir, fileNum))
+	if err != nil || !exists {
+		return 0, false, err
+	}
+	stream, err := newBlockfileStream(rootDir, fileNum, 0)
+	if err != nil {
+		return 0, false, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil || blockBytes == nil {
+		return 0, false, err
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.blockHeader.Number, true, nil
+}
+
+func savePruningInfo(rootDir string, pruningInfo *PruningInfo) error {
+	b, err := proto.Marshal(pruningInfo)
+	if err != nil {
+		return errors.Wrap(err, "error while marshalling pruningInfo")
+	}
+	if err := fileutil.CreateAndSyncFileAtomically(rootDir, pruningInfoTempFile, pruningInfoFile, b, 0644); err != nil {
+		return err
+	}
+	return fileutil.SyncDir(rootDir)
+}
+
+func loadPruningInfo(rootDir string) (*PruningInfo, error) {
+	b, err := ioutil.ReadFile(filepath.Join(rootDir, pruningInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading pruningInfo file")
+	}
+	pruningInfo := &PruningInfo{}
+	if err := proto.Unmarshal(b, pruningInfo); err != nil {
+		return nil, errors.Wrapf(err, "error while unmarshalling pruningInfo")
+	}
+	return pruningInfo, nil
+}
+
+// IsPruned returns whether the blocks of the given ledger have been pruned
+func IsPruned(blockStorageDir, ledgerID string) (bool, error) {
+	pruningInfo, err := loadPruningInfo((&Conf{blockStorageDir: blockStorageDir}).getLedgerBlockDir(ledgerID))
+	if err != nil {
+		return false, err
+	}
+	return pruningInfo != nil, nil
+}
+
+// GetLedgersWithPrunedBlocks returns the ledgers whose blocks have been pruned
+func GetLedgersWithPrunedBlocks(blockStorageDir string) ([]string, error) {
+	ledgerIDs, err := fileutil.ListSubdirs((&Conf{blockStorageDir: blockStorageDir}).getChainsDir())
+	if err != nil {
+		return nil, err
+	}
+	prunedLedgers := []string{}
+	for _, ledgerID := range ledgerIDs {
+		pruned, err := IsPruned(blockStorageDir, ledgerID)
+		if err != nil {
+			return nil, err
+		}
+		if pruned {
+			prunedLedgers = append(prunedLedgers, ledgerID)
+		}
+	}
+	return prunedLedgers, nil
+}
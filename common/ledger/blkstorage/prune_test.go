@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneBlocks(t *testing.T) {
+	path := testPath()
+	defer os.RemoveAll(path)
+	blocks := testutil.ConstructTestBlocks(t, 40)
+	indexConfig := &IndexConfig{AttrsToIndex: attrsToIndex}
+	ledgerDir := (&Conf{blockStorageDir: path}).getLedgerBlockDir("testLedger")
+
+	env := newTestEnv(t, NewConf(path, 0))
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgr := blkfileMgrWrapper.blockfileMgr
+	// block files: [0-10], [11-20], [21-29]
+	for i, b := range blocks[:30] {
+		require.NoError(t, blkfileMgr.addBlock(b))
+		if i != 0 && i%10 == 0 {
+			blkfileMgr.moveToNextFile()
+		}
+	}
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	t.Run("prune below the first block", func(t *testing.T) {
+		firstBlockNum, err := PruneBlocks(path, "testLedger", 5, indexConfig)
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), firstBlockNum)
+		pruned, err := IsPruned(path, "testLedger")
+		require.NoError(t, err)
+		require.False(t, pruned)
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		firstBlockNum, err := PruneBlocks(path, "testLedger", 15, indexConfig)
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), firstBlockNum)
+
+		exists, _, err := fileutil.FileExists(deriveBlockfilePath(ledgerDir, 0))
+		require.NoError(t, err)
+		require.False(t, exists)
+		prunedLedgers, err := GetLedgersWithPrunedBlocks(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{"testLedger"}, prunedLedgers)
+
+		env := newTestEnv(t, NewConf(path, 0))
+		defer env.provider.Close()
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+		defer blkfileMgrWrapper.close()
+		blkfileMgr := blkfileMgrWrapper.blockfileMgr
+
+		require.Equal(t, uint64(30), blkfileMgr.getBlockchainInfo().Height)
+		blkfileMgrWrapper.testGetBlockByNumber(blocks[11:30], 11, nil)
+		testBlockfileMgrBlockIterator(t, blkfileMgr, 11, 29, blocks[11:30])
+
+		_, err = blkfileMgr.retrieveBlockByNumber(5)
+		require.EqualError(t, err, "cannot serve block [5]. First available block = [11]: the block has been pruned")
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		_, err = blkfileMgr.retrieveBlockHeaderByNumber(5)
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		_, err = blkfileMgr.retrieveBlocks(10)
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		_, err = blkfileMgr.retrieveTransactionByBlockNumTranNum(5, 0)
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		_, err = blkfileMgr.retrieveBlockByHash(protoutil.BlockHeaderHash(blocks[5].Header))
+		require.Equal(t, ErrNotFoundInIndex, err)
+
+		txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[5].Data.Data[0])
+		require.NoError(t, err)
+		exists, err = blkfileMgr.txIDExists(txID)
+		require.NoError(t, err)
+		require.True(t, exists)
+		_, err = blkfileMgr.retrieveTransactionByID(txID)
+		require.EqualError(t, err, "details for the TXID ["+txID+"] not available. First available block = [11]: the block has been pruned")
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		_, err = blkfileMgr.retrieveBlockByTxID(txID)
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		validationCode, err := blkfileMgr.retrieveTxValidationCodeByTxID(txID)
+		require.NoError(t, err)
+		require.Equal(t, txflags.ValidationFlags(blocks[5].Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER]).Flag(0), validationCode)
+
+		blkfileMgrWrapper.addBlocks(blocks[30:])
+		blkfileMgrWrapper.testGetBlockByNumber(blocks[11:], 11, nil)
+	})
+
+	t.Run("prune again", func(t *testing.T) {
+		firstBlockNum, err := PruneBlocks(path, "testLedger", 15, indexConfig)
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), firstBlockNum)
+
+		// the latest block file is never deleted
+		firstBlockNum, err = PruneBlocks(path, "testLedger", 40, indexConfig)
+		require.NoError(t, err)
+		require.Equal(t, uint64(21), firstBlockNum)
+
+		inspector, err := OpenInspector(path, "testLedger")
+		require.NoError(t, err)
+		defer inspector.Close()
+		require.Equal(t, uint64(21), inspector.FirstBlockNum())
+		require.Equal(t, &PruningInfo{FirstBlockNum: 21, FirstFileNum: 2}, inspector.PruningInfo())
+		_, err = inspector.RetrieveBlocks(20)
+		require.Equal(t, ErrBlockPruned, errors.Cause(err))
+		txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[15].Data.Data[0])
+		require.NoError(t, err)
+		entries, err := inspector.TxIndexEntries(txID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.True(t, entries[0].Pruned)
+		require.Nil(t, entries[0].BlockLocation)
+	})
+
+	t.Run("index behind the blocks to prune", func(t *testing.T) {
+		_, err := PruneBlocks(path, "testLedger", 45, indexConfig)
+		require.EqualError(t, err, "cannot prune the blocks below block [45] as the block index is behind, start the peer to sync the block index before pruning")
+	})
+
+	t.Run("index dropped after pruning", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll((&Conf{blockStorageDir: path}).getIndexDir()))
+		env := newTestEnv(t, NewConf(path, 0))
+		defer env.provider.Close()
+		_, err := env.provider.Open("testLedger")
+		require.EqualError(t, err, "cannot sync index with block files. blocks have been pruned from the blockstore and first available block=[21]")
+	})
+}
@@ -106,7 +106,7 @@ func TestImportFromSnapshot(t *testing.T) {
 			PreviousBlockHash: protoutil.BlockHeaderHash(prevBlock.Header),
 		}, bcInfo)
 
-		_, err = originalBlockStore.ExportTxIds(snapshotDir, testNewHashFunc)
+		_, err = originalBlockStore.ExportTxIds(snapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 		lastBlockInSnapshot := blocksBeforeSnapshot[len(blocksBeforeSnapshot)-1]
 
@@ -248,7 +248,7 @@ func TestImportFromSnapshot(t *testing.T) {
 			require.NoError(t, bootstrappedBlockStore.AddBlock(b))
 		}
 
-		fileHashes, err := bootstrappedBlockStore.ExportTxIds(anotherSnapshotDir, testNewHashFunc)
+		fileHashes, err := bootstrappedBlockStore.ExportTxIds(anotherSnapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 		expectedTxIDs := []string{}
 		for _, b := range append(blocksDetailsBeforeSnapshot, blocksDetailsAfterSnapshot...) {
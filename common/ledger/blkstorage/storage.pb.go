@@ -21,9 +21,12 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type TxIDIndexValue struct {
-	BlkLocation          []byte   `protobuf:"bytes,1,opt,name=blk_location,json=blkLocation,proto3" json:"blk_location,omitempty"`
-	TxLocation           []byte   `protobuf:"bytes,2,opt,name=tx_location,json=txLocation,proto3" json:"tx_location,omitempty"`
-	TxValidationCode     int32    `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,proto3" json:"tx_validation_code,omitempty"`
+	BlkLocation      []byte `protobuf:"bytes,1,opt,name=blk_location,json=blkLocation,proto3" json:"blk_location,omitempty"`
+	TxLocation       []byte `protobuf:"bytes,2,opt,name=tx_location,json=txLocation,proto3" json:"tx_location,omitempty"`
+	TxValidationCode int32  `protobuf:"varint,3,opt,name=tx_validation_code,json=txValidationCode,proto3" json:"tx_validation_code,omitempty"`
+	// pruned is set when the block that contains the transaction has been pruned, in which
+	// case the locations are not present
+	Pruned               bool     `protobuf:"varint,4,opt,name=pruned,proto3" json:"pruned,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -75,6 +78,13 @@ func (m *TxIDIndexValue) GetTxValidationCode() int32 {
 	return 0
 }
 
+func (m *TxIDIndexValue) GetPruned() bool {
+	if m != nil {
+		return m.Pruned
+	}
+	return false
+}
+
 type BootstrappingSnapshotInfo struct {
 	LastBlockNum         uint64   `protobuf:"varint,1,opt,name=lastBlockNum,proto3" json:"lastBlockNum,omitempty"`
 	LastBlockHash        []byte   `protobuf:"bytes,2,opt,name=lastBlockHash,proto3" json:"lastBlockHash,omitempty"`
@@ -130,30 +140,124 @@ func (m *BootstrappingSnapshotInfo) GetPreviousBlockHash() []byte {
 	return nil
 }
 
+type PruningInfo struct {
+	FirstBlockNum        uint64   `protobuf:"varint,1,opt,name=firstBlockNum,proto3" json:"firstBlockNum,omitempty"`
+	FirstFileNum         uint64   `protobuf:"varint,2,opt,name=firstFileNum,proto3" json:"firstFileNum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PruningInfo) Reset()         { *m = PruningInfo{} }
+func (m *PruningInfo) String() string { return proto.CompactTextString(m) }
+func (*PruningInfo) ProtoMessage()    {}
+func (*PruningInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{2}
+}
+
+func (m *PruningInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PruningInfo.Unmarshal(m, b)
+}
+func (m *PruningInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PruningInfo.Marshal(b, m, deterministic)
+}
+func (m *PruningInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PruningInfo.Merge(m, src)
+}
+func (m *PruningInfo) XXX_Size() int {
+	return xxx_messageInfo_PruningInfo.Size(m)
+}
+func (m *PruningInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PruningInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PruningInfo proto.InternalMessageInfo
+
+func (m *PruningInfo) GetFirstBlockNum() uint64 {
+	if m != nil {
+		return m.FirstBlockNum
+	}
+	return 0
+}
+
+func (m *PruningInfo) GetFirstFileNum() uint64 {
+	if m != nil {
+		return m.FirstFileNum
+	}
+	return 0
+}
+
+type ArchivingInfo struct {
+	// firstLocalFileNum is the number of the first block file that is present on the local disk. The block
+	// files below this number have been moved to the archive, unless these have been pruned
+	FirstLocalFileNum    uint64   `protobuf:"varint,1,opt,name=firstLocalFileNum,proto3" json:"firstLocalFileNum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ArchivingInfo) Reset()         { *m = ArchivingInfo{} }
+func (m *ArchivingInfo) String() string { return proto.CompactTextString(m) }
+func (*ArchivingInfo) ProtoMessage()    {}
+func (*ArchivingInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{3}
+}
+
+func (m *ArchivingInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ArchivingInfo.Unmarshal(m, b)
+}
+func (m *ArchivingInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ArchivingInfo.Marshal(b, m, deterministic)
+}
+func (m *ArchivingInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ArchivingInfo.Merge(m, src)
+}
+func (m *ArchivingInfo) XXX_Size() int {
+	return xxx_messageInfo_ArchivingInfo.Size(m)
+}
+func (m *ArchivingInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ArchivingInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ArchivingInfo proto.InternalMessageInfo
+
+func (m *ArchivingInfo) GetFirstLocalFileNum() uint64 {
+	if m != nil {
+		return m.FirstLocalFileNum
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*TxIDIndexValue)(nil), "msgs.txIDIndexValue")
 	proto.RegisterType((*BootstrappingSnapshotInfo)(nil), "msgs.bootstrappingSnapshotInfo")
+	proto.RegisterType((*PruningInfo)(nil), "msgs.pruningInfo")
+	proto.RegisterType((*ArchivingInfo)(nil), "msgs.archivingInfo")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 271 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x41, 0x4b, 0xf3, 0x40,
-	0x10, 0x86, 0xc9, 0xd7, 0x7e, 0x1e, 0xa6, 0xa9, 0xe8, 0x9e, 0xea, 0xc9, 0x1a, 0x3c, 0xf4, 0x50,
-	0x9a, 0x83, 0x20, 0x9e, 0xab, 0x07, 0x03, 0xe2, 0x21, 0x82, 0x07, 0x2f, 0x61, 0x77, 0xb3, 0x4d,
-	0x96, 0x6c, 0x76, 0x96, 0xdd, 0x49, 0x89, 0x57, 0xff, 0x82, 0x7f, 0x58, 0x0c, 0xa1, 0xa5, 0x78,
-	0x9c, 0xe7, 0x7d, 0x60, 0x5e, 0x5e, 0x98, 0x07, 0x42, 0xcf, 0x2b, 0xb5, 0x71, 0x1e, 0x09, 0xd9,
-	0xb4, 0x0d, 0x55, 0x48, 0xbe, 0x22, 0x38, 0xa7, 0x3e, 0x7b, 0xca, 0x6c, 0xa9, 0xfa, 0x77, 0x6e,
-	0x3a, 0xc5, 0x6e, 0x20, 0x16, 0xa6, 0x29, 0x0c, 0x4a, 0x4e, 0x1a, 0xed, 0x22, 0x5a, 0x46, 0xab,
-	0x38, 0x9f, 0x09, 0xd3, 0xbc, 0x8c, 0x88, 0x5d, 0xc3, 0x8c, 0xfa, 0xa3, 0xf1, 0x6f, 0x30, 0x80,
-	0xfa, 0x83, 0xb0, 0x06, 0x46, 0x7d, 0xb1, 0xe7, 0x46, 0x97, 0x03, 0x28, 0x24, 0x96, 0x6a, 0x31,
-	0x59, 0x46, 0xab, 0xff, 0xf9, 0x05, 0xfd, 0x3e, 0x1a, 0x83, 0x47, 0x2c, 0x55, 0xf2, 0x1d, 0xc1,
-	0x95, 0x40, 0xa4, 0x40, 0x9e, 0x3b, 0xa7, 0x6d, 0xf5, 0x66, 0xb9, 0x0b, 0x35, 0x52, 0x66, 0x77,
-	0xc8, 0x12, 0x88, 0x0d, 0x0f, 0xb4, 0x35, 0x28, 0x9b, 0xd7, 0xae, 0x1d, 0xfa, 0x4c, 0xf3, 0x13,
-	0xc6, 0x6e, 0x61, 0x7e, 0xb8, 0x9f, 0x79, 0xa8, 0xc7, 0x4a, 0xa7, 0x90, 0xad, 0xe1, 0xd2, 0x79,
-	0xb5, 0xd7, 0xd8, 0x85, 0xa3, 0x39, 0x19, 0xcc, 0xbf, 0xc1, 0xf6, 0xe1, 0xe3, 0xbe, 0xd2, 0x54,
-	0x77, 0x62, 0x23, 0xb1, 0x4d, 0xeb, 0x4f, 0xa7, 0xbc, 0x51, 0x65, 0xa5, 0x7c, 0xba, 0xe3, 0xc2,
-	0x6b, 0x99, 0x4a, 0x6c, 0x5b, 0xb4, 0xe9, 0x08, 0x85, 0x69, 0xc6, 0x81, 0xc5, 0xd9, 0xb0, 0xf0,
-	0xdd, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd8, 0x44, 0x15, 0xf9, 0x72, 0x01, 0x00, 0x00,
+	// 336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x92, 0x31, 0x4b, 0xc3, 0x40,
+	0x14, 0x80, 0x49, 0x5b, 0x8b, 0xbc, 0x36, 0x62, 0x6f, 0x90, 0x3a, 0x59, 0x43, 0x87, 0x0e, 0xa5,
+	0x19, 0x04, 0x71, 0x71, 0xa9, 0x22, 0x16, 0xc4, 0x21, 0x42, 0x05, 0x97, 0x72, 0x49, 0xae, 0xc9,
+	0x91, 0xcb, 0xbd, 0x70, 0x77, 0x29, 0xf1, 0x77, 0xb8, 0xf9, 0x6b, 0x25, 0xd7, 0xb4, 0xb5, 0x74,
+	0xbc, 0xef, 0x7d, 0x79, 0x7c, 0x84, 0x07, 0xae, 0x36, 0xa8, 0x68, 0xc2, 0x66, 0x85, 0x42, 0x83,
+	0xa4, 0x93, 0xeb, 0x44, 0x7b, 0xbf, 0x0e, 0x5c, 0x98, 0x6a, 0xf1, 0xbc, 0x90, 0x31, 0xab, 0x96,
+	0x54, 0x94, 0x8c, 0xdc, 0x42, 0x3f, 0x14, 0xd9, 0x4a, 0x60, 0x44, 0x0d, 0x47, 0x39, 0x74, 0x46,
+	0xce, 0xa4, 0x1f, 0xf4, 0x42, 0x91, 0xbd, 0x35, 0x88, 0xdc, 0x40, 0xcf, 0x54, 0x07, 0xa3, 0x65,
+	0x0d, 0x30, 0xd5, 0x5e, 0x98, 0x02, 0x31, 0xd5, 0x6a, 0x43, 0x05, 0x8f, 0x2d, 0x58, 0x45, 0x18,
+	0xb3, 0x61, 0x7b, 0xe4, 0x4c, 0xce, 0x82, 0x4b, 0x53, 0x2d, 0xf7, 0x83, 0x27, 0x8c, 0x19, 0xb9,
+	0x82, 0x6e, 0xa1, 0x4a, 0xc9, 0xe2, 0x61, 0x67, 0xe4, 0x4c, 0xce, 0x83, 0xe6, 0xe5, 0xfd, 0x38,
+	0x70, 0x1d, 0x22, 0x1a, 0x6d, 0x14, 0x2d, 0x0a, 0x2e, 0x93, 0x0f, 0x49, 0x0b, 0x9d, 0xa2, 0x59,
+	0xc8, 0x35, 0x12, 0x0f, 0xfa, 0x82, 0x6a, 0x33, 0x17, 0x18, 0x65, 0xef, 0x65, 0x6e, 0x3b, 0x3b,
+	0xc1, 0x11, 0x23, 0x63, 0x70, 0xf7, 0xef, 0x57, 0xaa, 0xd3, 0x26, 0xf5, 0x18, 0x92, 0x29, 0x0c,
+	0x0a, 0xc5, 0x36, 0x1c, 0x4b, 0x7d, 0x30, 0xdb, 0xd6, 0x3c, 0x1d, 0x78, 0x9f, 0xd0, 0xab, 0xfb,
+	0xb8, 0x4c, 0x6c, 0xc6, 0x18, 0xdc, 0x35, 0x57, 0x27, 0x1d, 0xc7, 0xb0, 0x8e, 0xb5, 0xe0, 0x85,
+	0x0b, 0x56, 0x4b, 0xad, 0x6d, 0xec, 0x7f, 0xe6, 0x3d, 0x82, 0x4b, 0x55, 0x94, 0xf2, 0xcd, 0x6e,
+	0xf5, 0x14, 0x06, 0x56, 0xa8, 0x7f, 0xab, 0xd8, 0x7d, 0xb9, 0x5d, 0x7f, 0x3a, 0x98, 0x3f, 0x7c,
+	0xdd, 0x27, 0xdc, 0xa4, 0x65, 0x38, 0x8b, 0x30, 0xf7, 0xd3, 0xef, 0x82, 0x29, 0xc1, 0xe2, 0x84,
+	0x29, 0x7f, 0x4d, 0x43, 0xc5, 0x23, 0x3f, 0xc2, 0x3c, 0x47, 0xe9, 0x37, 0x30, 0x14, 0x59, 0x73,
+	0x10, 0x61, 0xd7, 0x5e, 0xc4, 0xdd, 0xdf, 0x00, 0x64, 0xfc, 0x2a, 0x65, 0x22, 0x02, 0x00, 0x00,
 }
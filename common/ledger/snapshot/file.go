@@ -13,6 +13,7 @@ import (
 	"hash"
 	"io"
 	"os"
+	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -20,6 +21,43 @@ import (
 
 type NewHashFunc func() (hash.Hash, error)
 
+// Progress accumulates the number of the records exported and the bytes written to the snapshot files
+// during an export. It can be read while the export is in progress. A nil Progress ignores the updates
+type Progress struct {
+	records uint64
+	bytes   uint64
+}
+
+// AddRecords adds to the number of the records exported
+func (p *Progress) AddRecords(n uint64) {
+	if p == nil {
+		return
+	}
+	atomic.AddUint64(&p.records, n)
+}
+
+// Records returns the number of the records exported
+func (p *Progress) Records() uint64 {
+	if p == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&p.records)
+}
+
+// Bytes returns the number of the bytes written to the snapshot files
+func (p *Progress) Bytes() uint64 {
+	if p == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&p.bytes)
+}
+
+// Write implements io.Writer and counts the bytes written
+func (p *Progress) Write(b []byte) (int, error) {
+	atomic.AddUint64(&p.bytes, uint64(len(b)))
+	return len(b), nil
+}
+
 // FileWriter creates a new file for ledger snapshot. This is expected to be used by various
 // components of ledger, such as blockstorage and statedb for exporting the relevant snapshot data
 type FileWriter struct {
@@ -34,6 +72,12 @@ type FileWriter struct {
 // This function returns an error if the file already exists. The `dataformat` is the first byte
 // written to the file. The function newHash is used to construct an hash.Hash for computing the hash-sum of the data stream
 func CreateFile(filePath string, dataformat byte, newHashFunc NewHashFunc) (*FileWriter, error) {
+	return CreateFileWithProgress(filePath, dataformat, newHashFunc, nil)
+}
+
+// CreateFileWithProgress creates a new file for exporting the ledger snapshot data, same as the function `CreateFile`,
+// and in addition, counts the bytes written to the file in the supplied progress, if not nil
+func CreateFileWithProgress(filePath string, dataformat byte, newHashFunc NewHashFunc, progress *Progress) (*FileWriter, error) {
 	hashImpl, err := newHashFunc()
 	if err != nil {
 		return nil, err
@@ -46,6 +90,9 @@ func CreateFile(filePath string, dataformat byte, newHashFunc NewHashFunc) (*Fil
 	}
 	bufWriter := bufio.NewWriter(file)
 	multiWriter := io.MultiWriter(bufWriter, hashImpl)
+	if progress != nil {
+		multiWriter = io.MultiWriter(bufWriter, hashImpl, progress)
+	}
 	if _, err := multiWriter.Write([]byte{dataformat}); err != nil {
 		file.Close()
 		return nil, errors.Wrapf(err, "error while writing data format to the snapshot file: %s", filePath)
@@ -100,6 +100,33 @@ func TestFileCreateAndRead(t *testing.T) {
 	require.Equal(t, []byte{}, b)
 }
 
+func TestFileCreateWithProgress(t *testing.T) {
+	testDir := testPath(t)
+	defer os.RemoveAll(testDir)
+
+	progress := &Progress{}
+	fileWriter, err := CreateFileWithProgress(path.Join(testDir, "dataFile"), byte(5), testNewHashFunc, progress)
+	require.NoError(t, err)
+	defer fileWriter.Close()
+	require.NoError(t, fileWriter.EncodeString("hi"))
+	progress.AddRecords(1)
+	require.NoError(t, fileWriter.EncodeUVarint(300))
+	progress.AddRecords(1)
+	_, err = fileWriter.Done()
+	require.NoError(t, err)
+
+	fileInfo, err := os.Stat(path.Join(testDir, "dataFile"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(fileInfo.Size()), progress.Bytes())
+	require.Equal(t, uint64(6), progress.Bytes())
+	require.Equal(t, uint64(2), progress.Records())
+
+	var nilProgress *Progress
+	nilProgress.AddRecords(1)
+	require.Equal(t, uint64(0), nilProgress.Records())
+	require.Equal(t, uint64(0), nilProgress.Bytes())
+}
+
 func TestFileCreateAndLargeValue(t *testing.T) {
 	testDir := testPath(t)
 	defer os.RemoveAll(testDir)
@@ -8,6 +8,7 @@ package leveldbhelper
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"syscall"
 
@@ -77,6 +78,28 @@ func (dbInst *DB) Open() {
 	dbInst.dbState = opened
 }
 
+// openReadOnly opens the underlying db for reading only, e.g. to inspect the db of a process which is
+// stopped. Unlike Open, it neither creates the db if it is missing nor panics, and fails if another process
+// holds the db open.
+func (dbInst *DB) openReadOnly() error {
+	dbInst.mutex.Lock()
+	defer dbInst.mutex.Unlock()
+	if dbInst.dbState == opened {
+		return nil
+	}
+	dbPath := dbInst.conf.DBPath
+	if _, err := os.Stat(dbPath); err != nil {
+		return errors.Wrapf(err, "failed to read the leveldb directory [%s]", dbPath)
+	}
+	db, err := leveldb.OpenFile(dbPath, &opt.Options{ReadOnly: true, ErrorIfMissing: true})
+	if err != nil {
+		return errors.Wrapf(err, "error opening leveldb at [%s] for reading, make sure no process is using it", dbPath)
+	}
+	dbInst.db = db
+	dbInst.dbState = opened
+	return nil
+}
+
 // IsEmpty returns whether or not a database is empty
 func (dbInst *DB) IsEmpty() (bool, error) {
 	dbInst.mutex.RLock()
@@ -114,6 +114,54 @@ func openDBAndCheckFormat(conf *Conf) (d *DB, e error) {
 	return db, nil
 }
 
+// NewReadOnlyProvider constructs a Provider on an existing db opened for reading only, and checks that its
+// format is the ExpectedFormat. Unlike `NewProvider`, it returns an error if the db is missing or is held open
+// by another process, and never sets the format
+func NewReadOnlyProvider(conf *Conf) (*Provider, error) {
+	db := CreateDB(conf)
+	if err := db.openReadOnly(); err != nil {
+		return nil, err
+	}
+	internalDB := &DBHandle{
+		db:     db,
+		dbName: internalDBName,
+	}
+	formatVersion, err := internalDB.Get(formatVersionKey)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if !bytes.Equal(formatVersion, []byte(conf.ExpectedFormat)) {
+		db.Close()
+		return nil, &dataformat.ErrFormatMismatch{
+			ExpectedFormat: conf.ExpectedFormat,
+			Format:         string(formatVersion),
+			DBInfo:         fmt.Sprintf("leveldb at [%s]", conf.DBPath),
+		}
+	}
+	return &Provider{
+		db:        db,
+		dbHandles: make(map[string]*DBHandle),
+	}, nil
+}
+
+// ReadDataFormat returns the format of the data recorded in the existing db at the given path, which is opened
+// for reading only. Unlike `NewProvider`, this function neither checks the format against an expected format nor
+// sets the format in an empty db, and returns an error if the db is missing or is held open by another process
+func ReadDataFormat(dbPath string) (string, error) {
+	db := CreateDB(&Conf{DBPath: dbPath})
+	if err := db.openReadOnly(); err != nil {
+		return "", err
+	}
+	defer db.Close()
+	internalDB := &DBHandle{
+		db:     db,
+		dbName: internalDBName,
+	}
+	f, err := internalDB.Get(formatVersionKey)
+	return string(f), err
+}
+
 // GetDataFormat returns the format of the data
 func (p *Provider) GetDataFormat() (string, error) {
 	f, err := p.GetDBHandle(internalDBName).Get(formatVersionKey)
@@ -344,6 +344,71 @@ func TestFormatCheck(t *testing.T) {
 	}
 }
 
+func TestReadDataFormat(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	_, err := ReadDataFormat(testDBPath)
+	require.EqualError(t, err, fmt.Sprintf("failed to read the leveldb directory [%s]: stat %s: no such file or directory", testDBPath, testDBPath))
+	_, err = os.Stat(testDBPath)
+	require.True(t, os.IsNotExist(err), "the missing db is not created")
+
+	p, err := NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: ""})
+	require.NoError(t, err)
+	p.Close()
+	f, err := ReadDataFormat(testDBPath)
+	require.NoError(t, err)
+	require.Equal(t, "", f)
+
+	// reading the format of an empty db does not set the format
+	p, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: ""})
+	require.NoError(t, err)
+	empty, err := p.db.IsEmpty()
+	require.NoError(t, err)
+	require.True(t, empty)
+
+	_, err = ReadDataFormat(testDBPath)
+	require.Error(t, err, "the db is held open by the provider")
+	require.Contains(t, err.Error(), "make sure no process is using it")
+	p.Close()
+
+	p, err = NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	p.Close()
+	f, err = ReadDataFormat(testDBPath)
+	require.NoError(t, err)
+	require.Equal(t, "2.0", f)
+}
+
+func TestNewReadOnlyProvider(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	_, err := NewReadOnlyProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.Error(t, err)
+	_, err = os.Stat(testDBPath)
+	require.True(t, os.IsNotExist(err), "the missing db is not created")
+
+	p, err := NewProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	require.NoError(t, p.GetDBHandle("db1").Put([]byte("key"), []byte("value"), true))
+
+	_, err = NewReadOnlyProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.Error(t, err, "the db is held open by the provider")
+	p.Close()
+
+	_, err = NewReadOnlyProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "1.x"})
+	require.IsType(t, &dataformat.ErrFormatMismatch{}, err)
+
+	p, err = NewReadOnlyProvider(&Conf{DBPath: testDBPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	defer p.Close()
+	value, err := p.GetDBHandle("db1").Get([]byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("value"), value)
+	require.Error(t, p.GetDBHandle("db1").Put([]byte("key"), []byte("other value"), true), "the db is read-only")
+}
+
 func TestClose(t *testing.T) {
 	env := newTestProviderEnv(t, testDBPath)
 	defer env.cleanup()
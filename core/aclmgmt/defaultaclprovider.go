@@ -64,6 +64,7 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.pResourcePolicyMap[resources.Snapshot_submitrequest] = mgmt.Admins
 	d.pResourcePolicyMap[resources.Snapshot_cancelrequest] = mgmt.Admins
 	d.pResourcePolicyMap[resources.Snapshot_listpending] = mgmt.Admins
+	d.pResourcePolicyMap[resources.Snapshot_status] = mgmt.Admins
 
 	//-------------- LSCC --------------
 	//p resources (implemented by the chaincode currently)
@@ -108,6 +109,7 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	//Event resources
 	d.cResourcePolicyMap[resources.Event_Block] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Event_FilteredBlock] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Event_ChaincodeEvents] = CHANNELREADERS
 
 	return d
 }
@@ -68,6 +68,9 @@ func TestPolicyBase(t *testing.T) {
 	require.NoError(t, err)
 	err = pprov.CheckACL("pol", env)
 	require.NoError(t, err)
+
+	err = pprov.CheckACL("pol", []*protoutil.SignedData{{Data: []byte("msg"), Identity: []byte("Alice"), Signature: []byte("sig")}})
+	require.NoError(t, err)
 }
 
 func TestPolicyBad(t *testing.T) {
@@ -27,6 +27,7 @@ const (
 	Snapshot_submitrequest = "snapshot/submitrequest"
 	Snapshot_cancelrequest = "snapshot/cancelrequest"
 	Snapshot_listpending   = "snapshot/listpending"
+	Snapshot_status        = "snapshot/status"
 
 	//Lscc resources
 	Lscc_Install                   = "lscc/Install"
@@ -59,6 +60,7 @@ const (
 	Peer_ChaincodeToChaincode = "peer/ChaincodeToChaincode"
 
 	//Events
-	Event_Block         = "event/Block"
-	Event_FilteredBlock = "event/FilteredBlock"
+	Event_Block           = "event/Block"
+	Event_FilteredBlock   = "event/FilteredBlock"
+	Event_ChaincodeEvents = "event/ChaincodeEvents"
 )
@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/shimext"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -876,21 +878,41 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 	iterID := h.UUIDGenerator.New()
 	namespaceID := txContext.NamespaceID
 
-	getHistoryForKey := &pb.GetHistoryForKey{}
+	// shimext.GetHistoryForKey is wire compatible with pb.GetHistoryForKey and additionally carries the optional metadata
+	getHistoryForKey := &shimext.GetHistoryForKey{}
 	err := proto.Unmarshal(msg.Payload, getHistoryForKey)
 	if err != nil {
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
-	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKey(namespaceID, getHistoryForKey.Key)
+	metadata, err := getHistoryQueryMetadataFromBytes(getHistoryForKey.Metadata)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
 
+	var historyIter commonledger.ResultsIterator
+	isPaginated := false
 	totalReturnLimit := h.calculateTotalReturnLimit(nil)
+	if metadata != nil {
+		isPaginated = metadata.PageSize > 0
+		totalReturnLimit = h.calculateTotalReturnLimit(&pb.QueryMetadata{PageSize: metadata.PageSize})
+		options, err := historyQueryOptions(metadata)
+		if err != nil {
+			return nil, err
+		}
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(namespaceID, getHistoryForKey.Key, options)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	} else {
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKey(namespaceID, getHistoryForKey.Key)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
 
 	txContext.InitializeQueryContext(iterID, historyIter)
-	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, false, totalReturnLimit)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
 	if err != nil {
 		txContext.CleanupQueryContext(iterID)
 		return nil, errors.WithStack(err)
@@ -906,6 +928,42 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 	return &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: payloadBytes, Txid: msg.Txid, ChannelId: msg.ChannelId}, nil
 }
 
+func getHistoryQueryMetadataFromBytes(metadataBytes []byte) (*shimext.HistoryQueryMetadata, error) {
+	if metadataBytes != nil {
+		metadata := &shimext.HistoryQueryMetadata{}
+		err := proto.Unmarshal(metadataBytes, metadata)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal failed")
+		}
+		return metadata, nil
+	}
+	return nil, nil
+}
+
+func historyQueryOptions(metadata *shimext.HistoryQueryMetadata) (*ledger.HistoryQueryOptions, error) {
+	options := &ledger.HistoryQueryOptions{
+		StartBlock: metadata.StartBlock,
+		EndBlock:   metadata.EndBlock,
+		PageSize:   metadata.PageSize,
+		Bookmark:   metadata.Bookmark,
+	}
+	if metadata.StartTime != nil {
+		startTime, err := ptypes.Timestamp(metadata.StartTime)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid start time")
+		}
+		options.StartTime = startTime
+	}
+	if metadata.EndTime != nil {
+		endTime, err := ptypes.Timestamp(metadata.EndTime)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid end time")
+		}
+		options.EndTime = endTime
+	}
+	return options, nil
+}
+
 func isCollectionSet(collection string) bool {
 	return collection != ""
 }
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger"
+	ledgera "github.com/hyperledger/fabric/core/ledger"
 )
 
 type HistoryQueryExecutor struct {
@@ -22,6 +23,36 @@ type HistoryQueryExecutor struct {
 		result1 ledger.ResultsIterator
 		result2 error
 	}
+	GetHistoryForKeyWithOptionsStub        func(string, string, *ledgera.HistoryQueryOptions) (ledger.QueryResultsIterator, error)
+	getHistoryForKeyWithOptionsMutex       sync.RWMutex
+	getHistoryForKeyWithOptionsArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}
+	getHistoryForKeyWithOptionsReturns struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
+	getHistoryForKeyWithOptionsReturnsOnCall map[int]struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}
+	GetPrivateDataHashHistoryStub        func(string, string, string) (ledger.ResultsIterator, error)
+	getPrivateDataHashHistoryMutex       sync.RWMutex
+	getPrivateDataHashHistoryArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	getPrivateDataHashHistoryReturns struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
+	getPrivateDataHashHistoryReturnsOnCall map[int]struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,11 +121,145 @@ func (fake *HistoryQueryExecutor) GetHistoryForKeyReturnsOnCall(i int, result1 l
 	}{result1, result2}
 }
 
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptions(arg1 string, arg2 string, arg3 *ledgera.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithOptionsReturnsOnCall[len(fake.getHistoryForKeyWithOptionsArgsForCall)]
+	fake.getHistoryForKeyWithOptionsArgsForCall = append(fake.getHistoryForKeyWithOptionsArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 *ledgera.HistoryQueryOptions
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetHistoryForKeyWithOptions", []interface{}{arg1, arg2, arg3})
+	fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	if fake.GetHistoryForKeyWithOptionsStub != nil {
+		return fake.GetHistoryForKeyWithOptionsStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getHistoryForKeyWithOptionsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCallCount() int {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithOptionsArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsCalls(stub func(string, string, *ledgera.HistoryQueryOptions) (ledger.QueryResultsIterator, error)) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsArgsForCall(i int) (string, string, *ledgera.HistoryQueryOptions) {
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithOptionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturns(result1 ledger.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	fake.getHistoryForKeyWithOptionsReturns = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetHistoryForKeyWithOptionsReturnsOnCall(i int, result1 ledger.QueryResultsIterator, result2 error) {
+	fake.getHistoryForKeyWithOptionsMutex.Lock()
+	defer fake.getHistoryForKeyWithOptionsMutex.Unlock()
+	fake.GetHistoryForKeyWithOptionsStub = nil
+	if fake.getHistoryForKeyWithOptionsReturnsOnCall == nil {
+		fake.getHistoryForKeyWithOptionsReturnsOnCall = make(map[int]struct {
+			result1 ledger.QueryResultsIterator
+			result2 error
+		})
+	}
+	fake.getHistoryForKeyWithOptionsReturnsOnCall[i] = struct {
+		result1 ledger.QueryResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistory(arg1 string, arg2 string, arg3 string) (ledger.ResultsIterator, error) {
+	fake.getPrivateDataHashHistoryMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataHashHistoryReturnsOnCall[len(fake.getPrivateDataHashHistoryArgsForCall)]
+	fake.getPrivateDataHashHistoryArgsForCall = append(fake.getPrivateDataHashHistoryArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetPrivateDataHashHistory", []interface{}{arg1, arg2, arg3})
+	fake.getPrivateDataHashHistoryMutex.Unlock()
+	if fake.GetPrivateDataHashHistoryStub != nil {
+		return fake.GetPrivateDataHashHistoryStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPrivateDataHashHistoryReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistoryCallCount() int {
+	fake.getPrivateDataHashHistoryMutex.RLock()
+	defer fake.getPrivateDataHashHistoryMutex.RUnlock()
+	return len(fake.getPrivateDataHashHistoryArgsForCall)
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistoryCalls(stub func(string, string, string) (ledger.ResultsIterator, error)) {
+	fake.getPrivateDataHashHistoryMutex.Lock()
+	defer fake.getPrivateDataHashHistoryMutex.Unlock()
+	fake.GetPrivateDataHashHistoryStub = stub
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistoryArgsForCall(i int) (string, string, string) {
+	fake.getPrivateDataHashHistoryMutex.RLock()
+	defer fake.getPrivateDataHashHistoryMutex.RUnlock()
+	argsForCall := fake.getPrivateDataHashHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistoryReturns(result1 ledger.ResultsIterator, result2 error) {
+	fake.getPrivateDataHashHistoryMutex.Lock()
+	defer fake.getPrivateDataHashHistoryMutex.Unlock()
+	fake.GetPrivateDataHashHistoryStub = nil
+	fake.getPrivateDataHashHistoryReturns = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HistoryQueryExecutor) GetPrivateDataHashHistoryReturnsOnCall(i int, result1 ledger.ResultsIterator, result2 error) {
+	fake.getPrivateDataHashHistoryMutex.Lock()
+	defer fake.getPrivateDataHashHistoryMutex.Unlock()
+	fake.GetPrivateDataHashHistoryStub = nil
+	if fake.getPrivateDataHashHistoryReturnsOnCall == nil {
+		fake.getPrivateDataHashHistoryReturnsOnCall = make(map[int]struct {
+			result1 ledger.ResultsIterator
+			result2 error
+		})
+	}
+	fake.getPrivateDataHashHistoryReturnsOnCall[i] = struct {
+		result1 ledger.ResultsIterator
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HistoryQueryExecutor) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithOptionsMutex.RLock()
+	defer fake.getHistoryForKeyWithOptionsMutex.RUnlock()
+	fake.getPrivateDataHashHistoryMutex.RLock()
+	defer fake.getPrivateDataHashHistoryMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
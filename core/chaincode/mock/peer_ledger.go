@@ -256,6 +256,16 @@ type PeerLedger struct {
 		result1 []uint64
 		result2 error
 	}
+	SnapshotGenerationProgressStub        func() *ledger.SnapshotProgress
+	snapshotGenerationProgressMutex       sync.RWMutex
+	snapshotGenerationProgressArgsForCall []struct {
+	}
+	snapshotGenerationProgressReturns struct {
+		result1 *ledger.SnapshotProgress
+	}
+	snapshotGenerationProgressReturnsOnCall map[int]struct {
+		result1 *ledger.SnapshotProgress
+	}
 	SubmitSnapshotRequestStub        func(uint64) error
 	submitSnapshotRequestMutex       sync.RWMutex
 	submitSnapshotRequestArgsForCall []struct {
@@ -1464,6 +1474,58 @@ func (fake *PeerLedger) PendingSnapshotRequestsReturnsOnCall(i int, result1 []ui
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) SnapshotGenerationProgress() *ledger.SnapshotProgress {
+	fake.snapshotGenerationProgressMutex.Lock()
+	ret, specificReturn := fake.snapshotGenerationProgressReturnsOnCall[len(fake.snapshotGenerationProgressArgsForCall)]
+	fake.snapshotGenerationProgressArgsForCall = append(fake.snapshotGenerationProgressArgsForCall, struct {
+	}{})
+	fake.recordInvocation("SnapshotGenerationProgress", []interface{}{})
+	fake.snapshotGenerationProgressMutex.Unlock()
+	if fake.SnapshotGenerationProgressStub != nil {
+		return fake.SnapshotGenerationProgressStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.snapshotGenerationProgressReturns
+	return fakeReturns.result1
+}
+
+func (fake *PeerLedger) SnapshotGenerationProgressCallCount() int {
+	fake.snapshotGenerationProgressMutex.RLock()
+	defer fake.snapshotGenerationProgressMutex.RUnlock()
+	return len(fake.snapshotGenerationProgressArgsForCall)
+}
+
+func (fake *PeerLedger) SnapshotGenerationProgressCalls(stub func() *ledger.SnapshotProgress) {
+	fake.snapshotGenerationProgressMutex.Lock()
+	defer fake.snapshotGenerationProgressMutex.Unlock()
+	fake.SnapshotGenerationProgressStub = stub
+}
+
+func (fake *PeerLedger) SnapshotGenerationProgressReturns(result1 *ledger.SnapshotProgress) {
+	fake.snapshotGenerationProgressMutex.Lock()
+	defer fake.snapshotGenerationProgressMutex.Unlock()
+	fake.SnapshotGenerationProgressStub = nil
+	fake.snapshotGenerationProgressReturns = struct {
+		result1 *ledger.SnapshotProgress
+	}{result1}
+}
+
+func (fake *PeerLedger) SnapshotGenerationProgressReturnsOnCall(i int, result1 *ledger.SnapshotProgress) {
+	fake.snapshotGenerationProgressMutex.Lock()
+	defer fake.snapshotGenerationProgressMutex.Unlock()
+	fake.SnapshotGenerationProgressStub = nil
+	if fake.snapshotGenerationProgressReturnsOnCall == nil {
+		fake.snapshotGenerationProgressReturnsOnCall = make(map[int]struct {
+			result1 *ledger.SnapshotProgress
+		})
+	}
+	fake.snapshotGenerationProgressReturnsOnCall[i] = struct {
+		result1 *ledger.SnapshotProgress
+	}{result1}
+}
+
 func (fake *PeerLedger) SubmitSnapshotRequest(arg1 uint64) error {
 	fake.submitSnapshotRequestMutex.Lock()
 	ret, specificReturn := fake.submitSnapshotRequestReturnsOnCall[len(fake.submitSnapshotRequestArgsForCall)]
@@ -1630,6 +1692,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.newTxSimulatorMutex.RUnlock()
 	fake.pendingSnapshotRequestsMutex.RLock()
 	defer fake.pendingSnapshotRequestsMutex.RUnlock()
+	fake.snapshotGenerationProgressMutex.RLock()
+	defer fake.snapshotGenerationProgressMutex.RUnlock()
 	fake.submitSnapshotRequestMutex.RLock()
 	defer fake.submitSnapshotRequestMutex.RUnlock()
 	fake.txIDExistsMutex.RLock()
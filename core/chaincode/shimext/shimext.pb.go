@@ -0,0 +1,184 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: shimext.proto
+
+package shimext
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// GetHistoryForKey is wire compatible with the message protos.GetHistoryForKey of the chaincode
+// shim protocol and extends it with an optional metadata. A shim that is not aware of the metadata
+// sends only the key, in which case the full history of the key is returned
+type GetHistoryForKey struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
+func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
+func (*GetHistoryForKey) ProtoMessage()    {}
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9f25af60ec6738da, []int{0}
+}
+
+func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
+}
+func (m *GetHistoryForKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetHistoryForKey.Marshal(b, m, deterministic)
+}
+func (m *GetHistoryForKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetHistoryForKey.Merge(m, src)
+}
+func (m *GetHistoryForKey) XXX_Size() int {
+	return xxx_messageInfo_GetHistoryForKey.Size(m)
+}
+func (m *GetHistoryForKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetHistoryForKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetHistoryForKey proto.InternalMessageInfo
+
+func (m *GetHistoryForKey) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetHistoryForKey) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// HistoryQueryMetadata restricts the modifications of a key returned by a history query to the
+// ones committed in a range of blocks and made by transactions whose timestamps are in a range of time.
+// The results are returned newest first and are paginated if a page size is specified
+type HistoryQueryMetadata struct {
+	StartBlock           uint64               `protobuf:"varint,1,opt,name=start_block,json=startBlock,proto3" json:"start_block,omitempty"`
+	EndBlock             uint64               `protobuf:"varint,2,opt,name=end_block,json=endBlock,proto3" json:"end_block,omitempty"`
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	PageSize             int32                `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Bookmark             string               `protobuf:"bytes,6,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryQueryMetadata) Reset()         { *m = HistoryQueryMetadata{} }
+func (m *HistoryQueryMetadata) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryMetadata) ProtoMessage()    {}
+func (*HistoryQueryMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9f25af60ec6738da, []int{1}
+}
+
+func (m *HistoryQueryMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryMetadata.Unmarshal(m, b)
+}
+func (m *HistoryQueryMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryMetadata.Marshal(b, m, deterministic)
+}
+func (m *HistoryQueryMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryMetadata.Merge(m, src)
+}
+func (m *HistoryQueryMetadata) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryMetadata.Size(m)
+}
+func (m *HistoryQueryMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryMetadata proto.InternalMessageInfo
+
+func (m *HistoryQueryMetadata) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*GetHistoryForKey)(nil), "shimext.GetHistoryForKey")
+	proto.RegisterType((*HistoryQueryMetadata)(nil), "shimext.HistoryQueryMetadata")
+}
+
+func init() { proto.RegisterFile("shimext.proto", fileDescriptor_9f25af60ec6738da) }
+
+var fileDescriptor_9f25af60ec6738da = []byte{
+	// 303 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x51, 0xbb, 0x6b, 0xf3, 0x30,
+	0x10, 0xc7, 0x79, 0x5b, 0xf9, 0x3e, 0x08, 0xa6, 0x83, 0x49, 0x86, 0x98, 0x4c, 0x9e, 0x2c, 0xe8,
+	0x0b, 0xba, 0x95, 0x0c, 0x6d, 0xa1, 0x74, 0xa8, 0xdb, 0xa9, 0x4b, 0x90, 0xa5, 0x8b, 0x2d, 0x1c,
+	0xf9, 0x8c, 0xac, 0x40, 0x9d, 0x7f, 0xbe, 0x45, 0xb2, 0x93, 0xb5, 0x9b, 0x7e, 0xaf, 0xe3, 0x7e,
+	0x27, 0xf2, 0xbf, 0x29, 0xa4, 0x82, 0x6f, 0x93, 0xd4, 0x1a, 0x0d, 0x06, 0xd3, 0x1e, 0x2e, 0xd7,
+	0x39, 0x62, 0x7e, 0x00, 0xea, 0xe8, 0xec, 0xb8, 0xa7, 0x46, 0x2a, 0x68, 0x0c, 0x53, 0x75, 0xe7,
+	0xdc, 0x3c, 0x92, 0xc5, 0x33, 0x98, 0x17, 0xd9, 0x18, 0xd4, 0xed, 0x13, 0xea, 0x57, 0x68, 0x83,
+	0x05, 0x19, 0x96, 0xd0, 0x86, 0x5e, 0xe4, 0xc5, 0x7e, 0x6a, 0x9f, 0xc1, 0x92, 0xcc, 0x14, 0x18,
+	0x26, 0x98, 0x61, 0xe1, 0x20, 0xf2, 0xe2, 0x7f, 0xe9, 0x05, 0x6f, 0x7e, 0x3c, 0x72, 0xd5, 0xe7,
+	0xdf, 0x8f, 0xa0, 0xdb, 0xb7, 0x5e, 0x08, 0xd6, 0x64, 0xde, 0x18, 0xa6, 0xcd, 0x2e, 0x3b, 0x20,
+	0x2f, 0xdd, 0xb8, 0x51, 0x4a, 0x1c, 0xb5, 0xb5, 0x4c, 0xb0, 0x22, 0x3e, 0x54, 0xa2, 0x97, 0x07,
+	0x4e, 0x9e, 0x41, 0x25, 0x3a, 0xf1, 0x81, 0x74, 0xd6, 0x9d, 0xdd, 0x38, 0x1c, 0x46, 0x5e, 0x3c,
+	0xbf, 0x5e, 0x26, 0x5d, 0x9d, 0xe4, 0x5c, 0x27, 0xf9, 0x3c, 0xd7, 0x49, 0x7d, 0xe7, 0xb6, 0x38,
+	0xb8, 0x23, 0x76, 0x4c, 0x17, 0x1c, 0xfd, 0x19, 0x9c, 0x42, 0x25, 0x5c, 0x6c, 0x45, 0xfc, 0x9a,
+	0xe5, 0xb0, 0x6b, 0xe4, 0x09, 0xc2, 0x71, 0xe4, 0xc5, 0xe3, 0x74, 0x66, 0x89, 0x0f, 0x79, 0x02,
+	0x7b, 0x81, 0x0c, 0xb1, 0x54, 0x4c, 0x97, 0xe1, 0xc4, 0x1d, 0xe6, 0x82, 0xb7, 0xf7, 0x5f, 0xb7,
+	0xb9, 0x34, 0xc5, 0x31, 0x4b, 0x38, 0x2a, 0x5a, 0xb4, 0x35, 0xe8, 0x03, 0x88, 0x1c, 0x34, 0xdd,
+	0xb3, 0x4c, 0x4b, 0x4e, 0x39, 0x6a, 0xa0, 0xbc, 0x60, 0xb2, 0xe2, 0x28, 0x80, 0xf6, 0x9f, 0x93,
+	0x4d, 0xdc, 0x36, 0x37, 0xbf, 0x03, 0x00, 0x36, 0xc3, 0x1e, 0xd7, 0xbd, 0x01, 0x00, 0x00,
+}
@@ -48,6 +48,7 @@ type PeerLedgerSupport interface {
 // chain information
 type LedgerCommitter struct {
 	PeerLedgerSupport
+	notifier *CommitNotifier
 }
 
 // NewLedgerCommitter is a factory function to create an instance of the committer
@@ -56,6 +57,13 @@ func NewLedgerCommitter(ledger PeerLedgerSupport) *LedgerCommitter {
 	return &LedgerCommitter{PeerLedgerSupport: ledger}
 }
 
+// WithCommitNotifier sets the notifier of the transactions of the committed
+// blocks.
+func (lc *LedgerCommitter) WithCommitNotifier(notifier *CommitNotifier) *LedgerCommitter {
+	lc.notifier = notifier
+	return lc
+}
+
 // CommitLegacy commits blocks atomically with private data
 func (lc *LedgerCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData, commitOpts *ledger.CommitOptions) error {
 	// Committing new block
@@ -63,6 +71,10 @@ func (lc *LedgerCommitter) CommitLegacy(blockAndPvtData *ledger.BlockAndPvtData,
 		return err
 	}
 
+	if lc.notifier != nil {
+		lc.notifier.Notify(blockAndPvtData.Block)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// TxStatus is the status of a committed transaction.
+type TxStatus struct {
+	TxID           string
+	ValidationCode peer.TxValidationCode
+	BlockNumber    uint64
+}
+
+// CommitNotifier notifies the status of the transactions committed on a
+// channel to the clients waiting for them, without reading the ledger. A client
+// must register before looking the transactions up in the ledger, so that a
+// transaction committed in between is notified.
+type CommitNotifier struct {
+	mutex   sync.Mutex
+	waiters map[string]map[*commitWaiter]struct{}
+}
+
+type commitWaiter struct {
+	txIDs    map[string]struct{}
+	statuses chan *TxStatus
+}
+
+// NewCommitNotifier creates a CommitNotifier without waiters.
+func NewCommitNotifier() *CommitNotifier {
+	return &CommitNotifier{
+		waiters: map[string]map[*commitWaiter]struct{}{},
+	}
+}
+
+// Register returns a channel which receives the status of each of the
+// transactions once it is committed, and a function to call once the
+// statuses are no longer needed. If a TxID is committed more than once, only
+// its first commit is notified.
+func (n *CommitNotifier) Register(txIDs ...string) (<-chan *TxStatus, func()) {
+	w := &commitWaiter{txIDs: map[string]struct{}{}}
+	for _, txID := range txIDs {
+		w.txIDs[txID] = struct{}{}
+	}
+	// There is at most one status for each TxID, so the notifications never block.
+	w.statuses = make(chan *TxStatus, len(w.txIDs))
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for txID := range w.txIDs {
+		if n.waiters[txID] == nil {
+			n.waiters[txID] = map[*commitWaiter]struct{}{}
+		}
+		n.waiters[txID][w] = struct{}{}
+	}
+
+	return w.statuses, func() { n.deregister(w) }
+}
+
+// Notify sends the status of the transactions of a committed block to the
+// clients waiting for them.
+func (n *CommitNotifier) Notify(block *common.Block) {
+	if !n.hasWaiters() {
+		return
+	}
+
+	statuses := BlockTxStatuses(block)
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for _, status := range statuses {
+		for w := range n.waiters[status.TxID] {
+			w.statuses <- status
+			n.remove(w, status.TxID)
+		}
+	}
+}
+
+// BlockTxStatuses returns the status of the transactions of a block which
+// have a TxID, in the order of the block. A transaction without a validation
+// flag is NOT_VALIDATED.
+func BlockTxStatuses(block *common.Block) []*TxStatus {
+	flags := txflags.ValidationFlags(block.GetMetadata().GetMetadata()[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	var statuses []*TxStatus
+	for i, envBytes := range block.GetData().GetData() {
+		env, err := protoutil.UnmarshalEnvelope(envBytes)
+		if err != nil {
+			continue
+		}
+		chdr, err := protoutil.ChannelHeader(env)
+		if err != nil || chdr.TxId == "" {
+			continue
+		}
+		status := &TxStatus{
+			TxID:           chdr.TxId,
+			ValidationCode: peer.TxValidationCode_NOT_VALIDATED,
+			BlockNumber:    block.GetHeader().GetNumber(),
+		}
+		if i < len(flags) {
+			status.ValidationCode = flags.Flag(i)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// FindTxStatus returns the status of the first transaction of a block with
+// the TxID, or nil if the block does not contain it.
+func FindTxStatus(block *common.Block, txID string) *TxStatus {
+	for _, status := range BlockTxStatuses(block) {
+		if status.TxID == txID {
+			return status
+		}
+	}
+	return nil
+}
+
+func (n *CommitNotifier) hasWaiters() bool {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return len(n.waiters) > 0
+}
+
+func (n *CommitNotifier) deregister(w *commitWaiter) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	for txID := range w.txIDs {
+		n.remove(w, txID)
+	}
+}
+
+func (n *CommitNotifier) remove(w *commitWaiter, txID string) {
+	delete(w.txIDs, txID)
+	delete(n.waiters[txID], w)
+	if len(n.waiters[txID]) == 0 {
+		delete(n.waiters, txID)
+	}
+}
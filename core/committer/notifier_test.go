@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package committer
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	ledger2 "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func committedBlock(number uint64, validationCodes map[string]peer.TxValidationCode, txIDs ...string) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	flags := txflags.New(len(txIDs))
+	for i, txID := range txIDs {
+		env := &common.Envelope{
+			Payload: protoutil.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{TxId: txID}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+		flags.SetFlag(i, validationCodes[txID])
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func TestCommitNotifier(t *testing.T) {
+	notifier := NewCommitNotifier()
+	notifier.Notify(committedBlock(1, nil, "tx1"))
+
+	statuses, done := notifier.Register("tx1", "tx2", "tx2")
+	defer done()
+	otherStatuses, otherDone := notifier.Register("tx2", "tx3")
+
+	notifier.Notify(committedBlock(2, map[string]peer.TxValidationCode{"tx2": peer.TxValidationCode_MVCC_READ_CONFLICT}, "tx0", "tx2"))
+	require.Equal(t, &TxStatus{TxID: "tx2", ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 2}, <-statuses)
+	require.Equal(t, &TxStatus{TxID: "tx2", ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 2}, <-otherStatuses)
+
+	// Only the first commit of a TxID is notified.
+	notifier.Notify(committedBlock(3, map[string]peer.TxValidationCode{"tx2": peer.TxValidationCode_DUPLICATE_TXID}, "tx1", "tx2"))
+	require.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, BlockNumber: 3}, <-statuses)
+	require.Empty(t, statuses)
+	require.Empty(t, otherStatuses)
+
+	otherDone()
+	notifier.Notify(committedBlock(4, nil, "tx3"))
+	require.Empty(t, otherStatuses)
+	require.Empty(t, notifier.waiters, "all the waiters are notified or deregistered")
+}
+
+func TestFindTxStatus(t *testing.T) {
+	block := committedBlock(5, map[string]peer.TxValidationCode{"tx2": peer.TxValidationCode_MVCC_READ_CONFLICT}, "tx1", "tx2", "tx2")
+	require.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, BlockNumber: 5}, FindTxStatus(block, "tx1"))
+	require.Equal(t, &TxStatus{TxID: "tx2", ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 5}, FindTxStatus(block, "tx2"))
+	require.Nil(t, FindTxStatus(block, "tx3"))
+
+	// a transaction without a validation flag is not validated
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.New(1)
+	require.Equal(t, &TxStatus{TxID: "tx2", ValidationCode: peer.TxValidationCode_NOT_VALIDATED, BlockNumber: 5}, FindTxStatus(block, "tx2"))
+}
+
+func TestLedgerCommitterNotifiesCommits(t *testing.T) {
+	gb, ledger := createLedger("TestLedgerCommitterNotifiesCommits")
+	ledger.On("CommitLegacy", mock.Anything).Return(nil)
+
+	notifier := NewCommitNotifier()
+	committer := NewLedgerCommitter(ledger).WithCommitNotifier(notifier)
+	statuses, done := notifier.Register("tx1")
+	defer done()
+
+	block := committedBlock(1, nil, "tx1")
+	block.Header.PreviousHash = gb.Header.DataHash
+	err := committer.CommitLegacy(&ledger2.BlockAndPvtData{Block: block}, &ledger2.CommitOptions{})
+	require.NoError(t, err)
+	require.Equal(t, &TxStatus{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, BlockNumber: 1}, <-statuses)
+}
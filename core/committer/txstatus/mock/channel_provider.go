@@ -0,0 +1,121 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/committer/txstatus"
+)
+
+type ChannelProvider struct {
+	ChannelStub        func(string) (txstatus.Ledger, *committer.CommitNotifier, error)
+	channelMutex       sync.RWMutex
+	channelArgsForCall []struct {
+		arg1 string
+	}
+	channelReturns struct {
+		result1 txstatus.Ledger
+		result2 *committer.CommitNotifier
+		result3 error
+	}
+	channelReturnsOnCall map[int]struct {
+		result1 txstatus.Ledger
+		result2 *committer.CommitNotifier
+		result3 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ChannelProvider) Channel(arg1 string) (txstatus.Ledger, *committer.CommitNotifier, error) {
+	fake.channelMutex.Lock()
+	ret, specificReturn := fake.channelReturnsOnCall[len(fake.channelArgsForCall)]
+	fake.channelArgsForCall = append(fake.channelArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("Channel", []interface{}{arg1})
+	fake.channelMutex.Unlock()
+	if fake.ChannelStub != nil {
+		return fake.ChannelStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.channelReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChannelProvider) ChannelCallCount() int {
+	fake.channelMutex.RLock()
+	defer fake.channelMutex.RUnlock()
+	return len(fake.channelArgsForCall)
+}
+
+func (fake *ChannelProvider) ChannelCalls(stub func(string) (txstatus.Ledger, *committer.CommitNotifier, error)) {
+	fake.channelMutex.Lock()
+	defer fake.channelMutex.Unlock()
+	fake.ChannelStub = stub
+}
+
+func (fake *ChannelProvider) ChannelArgsForCall(i int) string {
+	fake.channelMutex.RLock()
+	defer fake.channelMutex.RUnlock()
+	argsForCall := fake.channelArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelProvider) ChannelReturns(result1 txstatus.Ledger, result2 *committer.CommitNotifier, result3 error) {
+	fake.channelMutex.Lock()
+	defer fake.channelMutex.Unlock()
+	fake.ChannelStub = nil
+	fake.channelReturns = struct {
+		result1 txstatus.Ledger
+		result2 *committer.CommitNotifier
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChannelProvider) ChannelReturnsOnCall(i int, result1 txstatus.Ledger, result2 *committer.CommitNotifier, result3 error) {
+	fake.channelMutex.Lock()
+	defer fake.channelMutex.Unlock()
+	fake.ChannelStub = nil
+	if fake.channelReturnsOnCall == nil {
+		fake.channelReturnsOnCall = make(map[int]struct {
+			result1 txstatus.Ledger
+			result2 *committer.CommitNotifier
+			result3 error
+		})
+	}
+	fake.channelReturnsOnCall[i] = struct {
+		result1 txstatus.Ledger
+		result2 *committer.CommitNotifier
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChannelProvider) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.channelMutex.RLock()
+	defer fake.channelMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ChannelProvider) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ txstatus.ChannelProvider = new(ChannelProvider)
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txstatus
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/protoutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var logger = flogging.MustGetLogger("committer.txstatus")
+
+//go:generate counterfeiter -o mock/channel_provider.go -fake-name ChannelProvider . ChannelProvider
+//go:generate counterfeiter -o mock/ledger.go -fake-name Ledger . Ledger
+//go:generate counterfeiter -o mock/acl_provider.go -fake-name ACLProvider . ACLProvider
+
+// Ledger looks up the committed transactions of a channel.
+type Ledger interface {
+	TxIDExists(txID string) (bool, error)
+	GetBlockByTxID(txID string) (*common.Block, error)
+}
+
+// ChannelProvider gives access to the channels joined by the peer.
+type ChannelProvider interface {
+	// Channel returns the ledger and the commit notifier of the channel, or an
+	// error if the peer has not joined it.
+	Channel(channelID string) (Ledger, *committer.CommitNotifier, error)
+}
+
+// ACLProvider checks the access of the clients to the resources of a channel.
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// Service implements the TxStatus gRPC service. The clients wait for their
+// transactions on the commit notifiers of the channels, which are fed by the
+// committers, instead of each following the blocks of the channel.
+type Service struct {
+	ChannelProvider ChannelProvider
+	ACLProvider     ACLProvider
+	// MaxTimeout caps the time a client waits for its transactions, and is the
+	// timeout of the requests which do not set one.
+	MaxTimeout time.Duration
+}
+
+// WaitForCommit sends the status of each of the requested transactions once it
+// is committed, and returns once all of them are committed or the timeout
+// expires. The client must be allowed to receive the filtered blocks of the
+// channel.
+func (s *Service) WaitForCommit(signedRequest *SignedTxStatusRequest, stream TxStatus_WaitForCommitServer) error {
+	request := &TxStatusRequest{}
+	if err := proto.Unmarshal(signedRequest.GetRequest(), request); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to unpack the request: %s", err)
+	}
+	if len(request.TxIds) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one TxID is required")
+	}
+
+	signedData := []*protoutil.SignedData{{
+		Data:      signedRequest.Request,
+		Identity:  request.Identity,
+		Signature: signedRequest.Signature,
+	}}
+	if err := s.ACLProvider.CheckACL(resources.Event_FilteredBlock, request.ChannelId, signedData); err != nil {
+		return status.Errorf(codes.PermissionDenied, "access denied to the transactions of channel %s: %s", request.ChannelId, err)
+	}
+
+	ledger, notifier, err := s.ChannelProvider.Channel(request.ChannelId)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "%s", err)
+	}
+
+	timeout := s.MaxTimeout
+	if request.Timeout != nil {
+		requested, err := ptypes.Duration(request.Timeout)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid timeout: %s", err)
+		}
+		if requested > 0 && requested < timeout {
+			timeout = requested
+		}
+	}
+	ctx, cancel := context.WithTimeout(stream.Context(), timeout)
+	defer cancel()
+
+	// The transactions are registered before they are looked up in the ledger,
+	// so that a transaction committed in between is notified.
+	statuses, done := notifier.Register(request.TxIds...)
+	defer done()
+
+	pending := map[string]struct{}{}
+	for _, txID := range request.TxIds {
+		pending[txID] = struct{}{}
+	}
+	for txID := range pending {
+		txStatus, err := lookUp(ledger, txID)
+		if err != nil {
+			return status.Errorf(codes.Unavailable, "failed to look up transaction %s: %s", txID, err)
+		}
+		if txStatus == nil {
+			continue
+		}
+		if err := send(stream, txStatus); err != nil {
+			return err
+		}
+		delete(pending, txID)
+	}
+
+	for len(pending) > 0 {
+		select {
+		case txStatus := <-statuses:
+			if _, ok := pending[txStatus.TxID]; !ok {
+				continue
+			}
+			if err := send(stream, txStatus); err != nil {
+				return err
+			}
+			delete(pending, txStatus.TxID)
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return status.Errorf(codes.DeadlineExceeded, "timed out waiting for %d transactions to be committed on channel %s", len(pending), request.ChannelId)
+			}
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	return nil
+}
+
+// lookUp returns the status of the transaction if it is already committed.
+func lookUp(ledger Ledger, txID string) (*committer.TxStatus, error) {
+	exists, err := ledger.TxIDExists(txID)
+	if err != nil || !exists {
+		return nil, err
+	}
+	block, err := ledger.GetBlockByTxID(txID)
+	if err != nil {
+		return nil, err
+	}
+	return committer.FindTxStatus(block, txID), nil
+}
+
+func send(stream TxStatus_WaitForCommitServer, txStatus *committer.TxStatus) error {
+	logger.Debugf("Transaction %s was committed in block [%d] with validation code %s", txStatus.TxID, txStatus.BlockNumber, txStatus.ValidationCode)
+	return stream.Send(&TxStatusResponse{
+		TxId:           txStatus.TxID,
+		ValidationCode: txStatus.ValidationCode,
+		BlockNumber:    txStatus.BlockNumber,
+	})
+}
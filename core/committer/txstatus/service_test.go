@@ -0,0 +1,213 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txstatus_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/committer/txstatus"
+	"github.com/hyperledger/fabric/core/committer/txstatus/mock"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate counterfeiter -o mock/wait_for_commit_server.go -fake-name WaitForCommitServer . waitForCommitServer
+
+type waitForCommitServer interface {
+	txstatus.TxStatus_WaitForCommitServer
+}
+
+func committedBlock(number uint64, validationCodes map[string]pb.TxValidationCode, txIDs ...string) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	flags := txflags.New(len(txIDs))
+	for i, txID := range txIDs {
+		env := &common.Envelope{
+			Payload: protoutil.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{ChannelId: "mychannel", TxId: txID}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+		flags.SetFlag(i, validationCodes[txID])
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func signedRequest(channelID string, timeout time.Duration, txIDs ...string) *txstatus.SignedTxStatusRequest {
+	request := &txstatus.TxStatusRequest{
+		ChannelId: channelID,
+		TxIds:     txIDs,
+		Identity:  []byte("client"),
+	}
+	if timeout != 0 {
+		request.Timeout = ptypes.DurationProto(timeout)
+	}
+	return &txstatus.SignedTxStatusRequest{
+		Request:   protoutil.MarshalOrPanic(request),
+		Signature: []byte("signature"),
+	}
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, message string) {
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %s", err)
+	require.Equal(t, code, s.Code(), s.Message())
+	require.Contains(t, s.Message(), message)
+}
+
+type testService struct {
+	service  *txstatus.Service
+	ledger   *mock.Ledger
+	notifier *committer.CommitNotifier
+	acl      *mock.ACLProvider
+	stream   *mock.WaitForCommitServer
+	sent     chan *txstatus.TxStatusResponse
+}
+
+func newTestService() *testService {
+	ts := &testService{
+		ledger:   &mock.Ledger{},
+		notifier: committer.NewCommitNotifier(),
+		acl:      &mock.ACLProvider{},
+		stream:   &mock.WaitForCommitServer{},
+		sent:     make(chan *txstatus.TxStatusResponse, 10),
+	}
+	channels := &mock.ChannelProvider{}
+	channels.ChannelStub = func(channelID string) (txstatus.Ledger, *committer.CommitNotifier, error) {
+		if channelID != "mychannel" {
+			return nil, nil, errors.Errorf("channel %s not found", channelID)
+		}
+		return ts.ledger, ts.notifier, nil
+	}
+	ts.stream.ContextReturns(context.Background())
+	ts.stream.SendStub = func(response *txstatus.TxStatusResponse) error {
+		ts.sent <- response
+		return nil
+	}
+	ts.service = &txstatus.Service{
+		ChannelProvider: channels,
+		ACLProvider:     ts.acl,
+		MaxTimeout:      time.Minute,
+	}
+	return ts
+}
+
+func TestWaitForCommit(t *testing.T) {
+	t.Run("committed before and after the request", func(t *testing.T) {
+		ts := newTestService()
+		ts.ledger.TxIDExistsStub = func(txID string) (bool, error) { return txID == "tx1", nil }
+		ts.ledger.GetBlockByTxIDReturns(committedBlock(4, map[string]pb.TxValidationCode{"tx1": pb.TxValidationCode_MVCC_READ_CONFLICT}, "tx0", "tx1"), nil)
+
+		result := make(chan error, 1)
+		go func() {
+			result <- ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1", "tx2", "tx3"), ts.stream)
+		}()
+
+		require.Equal(t, &txstatus.TxStatusResponse{TxId: "tx1", ValidationCode: pb.TxValidationCode_MVCC_READ_CONFLICT, BlockNumber: 4}, <-ts.sent)
+		require.Eventually(t, func() bool { return ts.ledger.TxIDExistsCallCount() == 3 }, time.Minute, 10*time.Millisecond)
+
+		ts.notifier.Notify(committedBlock(5, nil, "tx1", "tx3"))
+		require.Equal(t, &txstatus.TxStatusResponse{TxId: "tx3", ValidationCode: pb.TxValidationCode_VALID, BlockNumber: 5}, <-ts.sent)
+		ts.notifier.Notify(committedBlock(6, nil, "tx2"))
+		require.Equal(t, &txstatus.TxStatusResponse{TxId: "tx2", ValidationCode: pb.TxValidationCode_VALID, BlockNumber: 6}, <-ts.sent)
+
+		require.NoError(t, <-result)
+		require.Empty(t, ts.sent, "a transaction is sent once")
+
+		resName, channelID, idinfo := ts.acl.CheckACLArgsForCall(0)
+		require.Equal(t, resources.Event_FilteredBlock, resName)
+		require.Equal(t, "mychannel", channelID)
+		require.Equal(t, []*protoutil.SignedData{{Data: signedRequest("mychannel", 0, "tx1", "tx2", "tx3").Request, Identity: []byte("client"), Signature: []byte("signature")}}, idinfo)
+	})
+
+	t.Run("committed without a validation flag", func(t *testing.T) {
+		ts := newTestService()
+		ts.ledger.TxIDExistsReturns(true, nil)
+		block := committedBlock(4, nil, "tx0", "tx1")
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.New(1)
+		ts.ledger.GetBlockByTxIDReturns(block, nil)
+
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1"), ts.stream)
+		require.NoError(t, err)
+		require.Equal(t, &txstatus.TxStatusResponse{TxId: "tx1", ValidationCode: pb.TxValidationCode_NOT_VALIDATED, BlockNumber: 4}, <-ts.sent)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ts := newTestService()
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 10*time.Millisecond, "tx1", "tx2"), ts.stream)
+		requireStatus(t, err, codes.DeadlineExceeded, "timed out waiting for 2 transactions to be committed on channel mychannel")
+	})
+
+	t.Run("the timeout is capped", func(t *testing.T) {
+		ts := newTestService()
+		ts.service.MaxTimeout = 10 * time.Millisecond
+		err := ts.service.WaitForCommit(signedRequest("mychannel", time.Hour, "tx1"), ts.stream)
+		requireStatus(t, err, codes.DeadlineExceeded, "timed out waiting for 1 transactions")
+	})
+
+	t.Run("client goes away", func(t *testing.T) {
+		ts := newTestService()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ts.stream.ContextReturns(ctx)
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1"), ts.stream)
+		requireStatus(t, err, codes.Canceled, "context canceled")
+	})
+
+	t.Run("send fails", func(t *testing.T) {
+		ts := newTestService()
+		ts.ledger.TxIDExistsReturns(true, nil)
+		ts.ledger.GetBlockByTxIDReturns(committedBlock(4, nil, "tx1"), nil)
+		ts.stream.SendReturns(errors.New("stream closed"))
+		ts.stream.SendStub = nil
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1"), ts.stream)
+		require.EqualError(t, err, "stream closed")
+	})
+
+	t.Run("ledger fails", func(t *testing.T) {
+		ts := newTestService()
+		ts.ledger.TxIDExistsReturns(false, errors.New("leveldb: closed"))
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1"), ts.stream)
+		requireStatus(t, err, codes.Unavailable, "failed to look up transaction tx1: leveldb: closed")
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		ts := newTestService()
+		ts.acl.CheckACLReturns(errors.New("signature set did not satisfy policy"))
+		err := ts.service.WaitForCommit(signedRequest("mychannel", 0, "tx1"), ts.stream)
+		requireStatus(t, err, codes.PermissionDenied, "access denied to the transactions of channel mychannel: signature set did not satisfy policy")
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		ts := newTestService()
+		err := ts.service.WaitForCommit(signedRequest("otherchannel", 0, "tx1"), ts.stream)
+		requireStatus(t, err, codes.NotFound, "channel otherchannel not found")
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		ts := newTestService()
+		err := ts.service.WaitForCommit(&txstatus.SignedTxStatusRequest{Request: []byte("garbage")}, ts.stream)
+		requireStatus(t, err, codes.InvalidArgument, "failed to unpack the request")
+
+		err = ts.service.WaitForCommit(signedRequest("mychannel", 0), ts.stream)
+		requireStatus(t, err, codes.InvalidArgument, "at least one TxID is required")
+	})
+}
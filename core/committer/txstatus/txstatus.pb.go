@@ -0,0 +1,344 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: txstatus.proto
+
+package txstatus
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	duration "github.com/golang/protobuf/ptypes/duration"
+	peer "github.com/hyperledger/fabric-protos-go/peer"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SignedTxStatusRequest struct {
+	Request              []byte   `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedTxStatusRequest) Reset()         { *m = SignedTxStatusRequest{} }
+func (m *SignedTxStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SignedTxStatusRequest) ProtoMessage()    {}
+func (*SignedTxStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_11bfc11ae4b36c24, []int{0}
+}
+
+func (m *SignedTxStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedTxStatusRequest.Unmarshal(m, b)
+}
+func (m *SignedTxStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedTxStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *SignedTxStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedTxStatusRequest.Merge(m, src)
+}
+func (m *SignedTxStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_SignedTxStatusRequest.Size(m)
+}
+func (m *SignedTxStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedTxStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedTxStatusRequest proto.InternalMessageInfo
+
+func (m *SignedTxStatusRequest) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedTxStatusRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type TxStatusRequest struct {
+	ChannelId string   `protobuf:"bytes,1,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	TxIds     []string `protobuf:"bytes,2,rep,name=tx_ids,json=txIds,proto3" json:"tx_ids,omitempty"`
+	// The time to wait for the transactions, which is capped by the maximum
+	// timeout of the peer. The maximum timeout is used if it is not set
+	Timeout              *duration.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Identity             []byte             `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *TxStatusRequest) Reset()         { *m = TxStatusRequest{} }
+func (m *TxStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*TxStatusRequest) ProtoMessage()    {}
+func (*TxStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_11bfc11ae4b36c24, []int{1}
+}
+
+func (m *TxStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxStatusRequest.Unmarshal(m, b)
+}
+func (m *TxStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *TxStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxStatusRequest.Merge(m, src)
+}
+func (m *TxStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_TxStatusRequest.Size(m)
+}
+func (m *TxStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxStatusRequest proto.InternalMessageInfo
+
+func (m *TxStatusRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *TxStatusRequest) GetTxIds() []string {
+	if m != nil {
+		return m.TxIds
+	}
+	return nil
+}
+
+func (m *TxStatusRequest) GetTimeout() *duration.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *TxStatusRequest) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+type TxStatusResponse struct {
+	TxId                 string                `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	ValidationCode       peer.TxValidationCode `protobuf:"varint,2,opt,name=validation_code,json=validationCode,proto3,enum=protos.TxValidationCode" json:"validation_code,omitempty"`
+	BlockNumber          uint64                `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *TxStatusResponse) Reset()         { *m = TxStatusResponse{} }
+func (m *TxStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*TxStatusResponse) ProtoMessage()    {}
+func (*TxStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_11bfc11ae4b36c24, []int{2}
+}
+
+func (m *TxStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxStatusResponse.Unmarshal(m, b)
+}
+func (m *TxStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *TxStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxStatusResponse.Merge(m, src)
+}
+func (m *TxStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_TxStatusResponse.Size(m)
+}
+func (m *TxStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxStatusResponse proto.InternalMessageInfo
+
+func (m *TxStatusResponse) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TxStatusResponse) GetValidationCode() peer.TxValidationCode {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return peer.TxValidationCode_VALID
+}
+
+func (m *TxStatusResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SignedTxStatusRequest)(nil), "txstatus.SignedTxStatusRequest")
+	proto.RegisterType((*TxStatusRequest)(nil), "txstatus.TxStatusRequest")
+	proto.RegisterType((*TxStatusResponse)(nil), "txstatus.TxStatusResponse")
+}
+
+func init() { proto.RegisterFile("txstatus.proto", fileDescriptor_11bfc11ae4b36c24) }
+
+var fileDescriptor_11bfc11ae4b36c24 = []byte{
+	// 391 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x6b, 0xdb, 0x40,
+	0x10, 0xc5, 0x51, 0xe2, 0x24, 0xf6, 0x24, 0x75, 0xca, 0x96, 0x14, 0x55, 0xf4, 0x8f, 0xeb, 0x93,
+	0x4f, 0x52, 0x71, 0x28, 0x3d, 0xb7, 0x29, 0x85, 0x5c, 0x52, 0x50, 0x42, 0x0b, 0xb9, 0x98, 0xd5,
+	0xee, 0x44, 0x5e, 0x2a, 0xed, 0xaa, 0xbb, 0xb3, 0x41, 0xf9, 0x10, 0xbd, 0xf7, 0xe3, 0x96, 0xac,
+	0x2c, 0x8b, 0x9a, 0xdc, 0x34, 0x4f, 0x8f, 0x37, 0xef, 0xc7, 0x2c, 0x4c, 0xa9, 0x75, 0xc4, 0xc9,
+	0xbb, 0xb4, 0xb1, 0x86, 0x0c, 0x1b, 0xf7, 0x73, 0xf2, 0xb6, 0x34, 0xa6, 0xac, 0x30, 0x0b, 0x7a,
+	0xe1, 0xef, 0x32, 0xe9, 0x2d, 0x27, 0x65, 0x74, 0xe7, 0x4c, 0x5e, 0x36, 0x88, 0x36, 0x23, 0xcb,
+	0xb5, 0xe3, 0x62, 0xd0, 0xe7, 0xdf, 0xe1, 0xec, 0x5a, 0x95, 0x1a, 0xe5, 0x4d, 0x7b, 0x1d, 0x92,
+	0x72, 0xfc, 0xed, 0xd1, 0x11, 0x8b, 0xe1, 0xc8, 0x76, 0x9f, 0x71, 0x34, 0x8b, 0x16, 0x27, 0x79,
+	0x3f, 0xb2, 0xd7, 0x30, 0x71, 0xaa, 0xd4, 0x9c, 0xbc, 0xc5, 0x78, 0x2f, 0xfc, 0x1b, 0x84, 0xf9,
+	0xdf, 0x08, 0x4e, 0x77, 0xb3, 0xde, 0x00, 0x88, 0x35, 0xd7, 0x1a, 0xab, 0x95, 0x92, 0x21, 0x6e,
+	0x92, 0x4f, 0x36, 0xca, 0xa5, 0x64, 0x67, 0x70, 0x48, 0xed, 0x4a, 0x49, 0x17, 0xef, 0xcd, 0xf6,
+	0x17, 0x93, 0xfc, 0x80, 0xda, 0x4b, 0xe9, 0xd8, 0x39, 0x1c, 0x91, 0xaa, 0xd1, 0x78, 0x8a, 0xf7,
+	0x67, 0xd1, 0xe2, 0x78, 0xf9, 0x2a, 0xed, 0x20, 0xd3, 0x1e, 0x32, 0xfd, 0xba, 0x81, 0xcc, 0x7b,
+	0x27, 0x4b, 0x60, 0xac, 0x24, 0x6a, 0x52, 0xf4, 0x10, 0x8f, 0x42, 0xb7, 0xed, 0x3c, 0xff, 0x13,
+	0xc1, 0xf3, 0xa1, 0x9a, 0x6b, 0x8c, 0x76, 0xc8, 0x5e, 0xc0, 0x01, 0xb5, 0x43, 0xad, 0xd1, 0xe3,
+	0x6e, 0xf6, 0x19, 0x4e, 0xef, 0x79, 0xa5, 0x64, 0x08, 0x5f, 0x09, 0x23, 0x3b, 0xd0, 0xe9, 0x32,
+	0xee, 0x76, 0xbb, 0xf4, 0xa6, 0xfd, 0xb1, 0x35, 0x5c, 0x18, 0x89, 0xf9, 0xf4, 0xfe, 0xbf, 0x99,
+	0xbd, 0x87, 0x93, 0xa2, 0x32, 0xe2, 0xd7, 0x4a, 0xfb, 0xba, 0x40, 0x1b, 0x10, 0x46, 0xf9, 0x71,
+	0xd0, 0xae, 0x82, 0xb4, 0xbc, 0x85, 0x71, 0x5f, 0x87, 0x5d, 0xc1, 0xb3, 0x9f, 0x5c, 0xd1, 0x37,
+	0x63, 0x2f, 0x4c, 0x5d, 0x2b, 0x62, 0xef, 0xd2, 0xed, 0xad, 0x9f, 0x3c, 0x50, 0x92, 0x0c, 0x86,
+	0x5d, 0xa8, 0x0f, 0xd1, 0x97, 0x4f, 0xb7, 0x1f, 0x4b, 0x45, 0x6b, 0x5f, 0xa4, 0xc2, 0xd4, 0xd9,
+	0xfa, 0xa1, 0x41, 0x5b, 0xa1, 0x2c, 0xd1, 0x66, 0x77, 0xbc, 0xb0, 0x4a, 0x64, 0xc2, 0x58, 0xcc,
+	0x44, 0xd8, 0x45, 0x8f, 0x2f, 0x63, 0x93, 0x55, 0x1c, 0x06, 0xc0, 0xf3, 0x7f, 0x03, 0x00, 0x57,
+	0x01, 0xff, 0x95, 0x6b, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// TxStatusClient is the client API for TxStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TxStatusClient interface {
+	// WaitForCommit streams the status of the requested transactions as they are
+	// committed by the peer. The stream ends once all of them are committed, or
+	// with a DeadlineExceeded error when the timeout expires
+	WaitForCommit(ctx context.Context, in *SignedTxStatusRequest, opts ...grpc.CallOption) (TxStatus_WaitForCommitClient, error)
+}
+
+type txStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTxStatusClient(cc grpc.ClientConnInterface) TxStatusClient {
+	return &txStatusClient{cc}
+}
+
+func (c *txStatusClient) WaitForCommit(ctx context.Context, in *SignedTxStatusRequest, opts ...grpc.CallOption) (TxStatus_WaitForCommitClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TxStatus_serviceDesc.Streams[0], "/txstatus.TxStatus/WaitForCommit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &txStatusWaitForCommitClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TxStatus_WaitForCommitClient interface {
+	Recv() (*TxStatusResponse, error)
+	grpc.ClientStream
+}
+
+type txStatusWaitForCommitClient struct {
+	grpc.ClientStream
+}
+
+func (x *txStatusWaitForCommitClient) Recv() (*TxStatusResponse, error) {
+	m := new(TxStatusResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TxStatusServer is the server API for TxStatus service.
+type TxStatusServer interface {
+	// WaitForCommit streams the status of the requested transactions as they are
+	// committed by the peer. The stream ends once all of them are committed, or
+	// with a DeadlineExceeded error when the timeout expires
+	WaitForCommit(*SignedTxStatusRequest, TxStatus_WaitForCommitServer) error
+}
+
+// UnimplementedTxStatusServer can be embedded to have forward compatible implementations.
+type UnimplementedTxStatusServer struct {
+}
+
+func (*UnimplementedTxStatusServer) WaitForCommit(req *SignedTxStatusRequest, srv TxStatus_WaitForCommitServer) error {
+	return status.Errorf(codes.Unimplemented, "method WaitForCommit not implemented")
+}
+
+func RegisterTxStatusServer(s *grpc.Server, srv TxStatusServer) {
+	s.RegisterService(&_TxStatus_serviceDesc, srv)
+}
+
+func _TxStatus_WaitForCommit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SignedTxStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TxStatusServer).WaitForCommit(m, &txStatusWaitForCommitServer{stream})
+}
+
+type TxStatus_WaitForCommitServer interface {
+	Send(*TxStatusResponse) error
+	grpc.ServerStream
+}
+
+type txStatusWaitForCommitServer struct {
+	grpc.ServerStream
+}
+
+func (x *txStatusWaitForCommitServer) Send(m *TxStatusResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TxStatus_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "txstatus.TxStatus",
+	HandlerType: (*TxStatusServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WaitForCommit",
+			Handler:       _TxStatus_WaitForCommit_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "txstatus.proto",
+}
@@ -1546,6 +1546,10 @@ func (m *mockLedger) CancelSnapshotRequest(height uint64) error {
 	return nil
 }
 
+func (m *mockLedger) SnapshotGenerationProgress() *ledger.SnapshotProgress {
+	return nil
+}
+
 // mockQueryExecutor mock of the query executor,
 // needed to simulate inability to access state db, e.g.
 // the case where due to db failure it's not possible to
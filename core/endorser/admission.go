@@ -0,0 +1,209 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/semaphore"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AdmissionLimits are the numbers of proposals of each channel, and of each
+// chaincode of a channel, which are processed concurrently, and which wait for
+// their turn beyond them. A zero concurrency disables the limit.
+type AdmissionLimits struct {
+	ChannelConcurrency   int
+	ChannelQueueDepth    int
+	ChaincodeConcurrency int
+	ChaincodeQueueDepth  int
+}
+
+// BusyError is returned when a proposal is rejected because too many
+// proposals of its channel or chaincode are already waiting.
+type BusyError struct {
+	Resource   string
+	QueueDepth int
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("%s is busy: %d proposals are already waiting, retry later", e.Resource, e.QueueDepth)
+}
+
+// GRPCStatus lets the clients tell the busy errors apart from the failures of
+// the proposals.
+func (e *BusyError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// AdmissionController admits the proposals within the limits of their
+// channel and chaincode, so that a slow chaincode cannot hold all the
+// resources of the endorser.
+type AdmissionController struct {
+	limits  AdmissionLimits
+	metrics *Metrics
+
+	mutex      sync.Mutex
+	channels   map[string]*limiter
+	chaincodes map[string]map[string]*limiter
+}
+
+// limiter runs a number of proposals concurrently, and queues a number of
+// proposals beyond it.
+type limiter struct {
+	resource    string
+	slots       semaphore.Semaphore
+	queueDepth  int
+	queueLength metrics.Gauge
+
+	mutex   sync.Mutex
+	waiting int
+}
+
+// NewAdmissionController creates an AdmissionController enforcing the limits.
+func NewAdmissionController(limits AdmissionLimits, metrics *Metrics) *AdmissionController {
+	return &AdmissionController{
+		limits:     limits,
+		metrics:    metrics,
+		channels:   map[string]*limiter{},
+		chaincodes: map[string]map[string]*limiter{},
+	}
+}
+
+// admit waits until the proposal is admitted by the AdmissionController, if
+// any. The proposals without channel are not limited, and the limiter of a
+// chaincode is only created once its definition is found on the channel, so
+// that the names sent by the clients do not grow the limiters and the label
+// sets of their metrics without bound.
+func (e *Endorser) admit(ctx context.Context, up *UnpackedProposal) (func(), error) {
+	if e.AdmissionController == nil || up.ChannelID() == "" {
+		return func() {}, nil
+	}
+
+	if e.AdmissionController.limitsChaincodes() {
+		qe, err := e.Support.GetTxSimulator(up.ChannelID(), up.TxID())
+		if err != nil {
+			return nil, err
+		}
+		_, err = e.Support.ChaincodeEndorsementInfo(up.ChannelID(), up.ChaincodeName, qe)
+		qe.Done()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "make sure the chaincode %s has been successfully defined on channel %s and try again", up.ChaincodeName, up.ChannelID())
+		}
+	}
+
+	return e.AdmissionController.admit(ctx, up.ChannelID(), up.ChaincodeName)
+}
+
+// limitsChaincodes returns whether the concurrency of each chaincode is
+// limited.
+func (a *AdmissionController) limitsChaincodes() bool {
+	return a.limits.ChaincodeConcurrency > 0
+}
+
+// admit waits until the proposal is admitted by the limits of its chaincode
+// and of its channel, and returns a function to call once it is processed.
+func (a *AdmissionController) admit(ctx context.Context, channelID, chaincodeName string) (func(), error) {
+	if a == nil {
+		return func() {}, nil
+	}
+	chaincode, channel := a.limiters(channelID, chaincodeName)
+
+	// The chaincode limit is applied first, so that the proposals of a busy
+	// chaincode do not wait in the queue of the channel.
+	if err := chaincode.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if err := channel.acquire(ctx); err != nil {
+		chaincode.release()
+		return nil, err
+	}
+	return func() {
+		channel.release()
+		chaincode.release()
+	}, nil
+}
+
+// limiters returns the limiters of the chaincode and of the channel, which are
+// nil when their concurrency is not limited.
+func (a *AdmissionController) limiters(channelID, chaincodeName string) (chaincode, channel *limiter) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	channel, ok := a.channels[channelID]
+	if !ok && a.limits.ChannelConcurrency > 0 {
+		channel = newLimiter(
+			fmt.Sprintf("channel %s", channelID),
+			a.limits.ChannelConcurrency,
+			a.limits.ChannelQueueDepth,
+			a.metrics.ChannelQueueLength.With("channel", channelID),
+		)
+		a.channels[channelID] = channel
+	}
+
+	if a.limits.ChaincodeConcurrency <= 0 {
+		return nil, channel
+	}
+	if a.chaincodes[channelID] == nil {
+		a.chaincodes[channelID] = map[string]*limiter{}
+	}
+	chaincode, ok = a.chaincodes[channelID][chaincodeName]
+	if !ok {
+		chaincode = newLimiter(
+			fmt.Sprintf("chaincode %s on channel %s", chaincodeName, channelID),
+			a.limits.ChaincodeConcurrency,
+			a.limits.ChaincodeQueueDepth,
+			a.metrics.ChaincodeQueueLength.With("channel", channelID, "chaincode", chaincodeName),
+		)
+		a.chaincodes[channelID][chaincodeName] = chaincode
+	}
+
+	return chaincode, channel
+}
+
+func newLimiter(resource string, concurrency, queueDepth int, queueLength metrics.Gauge) *limiter {
+	return &limiter{
+		resource:    resource,
+		slots:       semaphore.New(concurrency),
+		queueDepth:  queueDepth,
+		queueLength: queueLength,
+	}
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	if l == nil || l.slots.TryAcquire() {
+		return nil
+	}
+
+	l.mutex.Lock()
+	if l.waiting >= l.queueDepth {
+		l.mutex.Unlock()
+		return &BusyError{Resource: l.resource, QueueDepth: l.queueDepth}
+	}
+	l.waiting++
+	l.queueLength.Set(float64(l.waiting))
+	l.mutex.Unlock()
+
+	defer func() {
+		l.mutex.Lock()
+		l.waiting--
+		l.queueLength.Set(float64(l.waiting))
+		l.mutex.Unlock()
+	}()
+	return l.slots.Acquire(ctx)
+}
+
+func (l *limiter) release() {
+	if l != nil {
+		l.slots.Release()
+	}
+}
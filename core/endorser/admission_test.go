@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAdmissionController(limits AdmissionLimits) (*AdmissionController, *metricsfakes.Gauge, *metricsfakes.Gauge) {
+	channelQueueLength := &metricsfakes.Gauge{}
+	channelQueueLength.WithReturns(channelQueueLength)
+	chaincodeQueueLength := &metricsfakes.Gauge{}
+	chaincodeQueueLength.WithReturns(chaincodeQueueLength)
+	a := NewAdmissionController(limits, &Metrics{
+		ChannelQueueLength:   channelQueueLength,
+		ChaincodeQueueLength: chaincodeQueueLength,
+	})
+	return a, channelQueueLength, chaincodeQueueLength
+}
+
+func TestAdmissionControllerNil(t *testing.T) {
+	var a *AdmissionController
+	release, err := a.admit(context.Background(), "mychannel", "mycc")
+	require.NoError(t, err)
+	release()
+}
+
+func TestAdmissionControllerChaincodeLimit(t *testing.T) {
+	a, _, queueLength := newTestAdmissionController(AdmissionLimits{ChaincodeConcurrency: 1, ChaincodeQueueDepth: 1})
+
+	release, err := a.admit(context.Background(), "mychannel", "mycc")
+	require.NoError(t, err)
+
+	// Another chaincode, or the same chaincode on another channel, is not limited.
+	releaseOther, err := a.admit(context.Background(), "mychannel", "othercc")
+	require.NoError(t, err)
+	releaseOther()
+	releaseOther, err = a.admit(context.Background(), "otherchannel", "mycc")
+	require.NoError(t, err)
+	releaseOther()
+
+	admitted := make(chan error, 1)
+	go func() {
+		release, err := a.admit(context.Background(), "mychannel", "mycc")
+		if err == nil {
+			release()
+		}
+		admitted <- err
+	}()
+	require.Eventually(t, func() bool { return queueLength.SetCallCount() == 1 }, time.Minute, 10*time.Millisecond)
+	require.Equal(t, float64(1), queueLength.SetArgsForCall(0))
+	require.Equal(t, []string{"channel", "mychannel", "chaincode", "mycc"}, queueLength.WithArgsForCall(0))
+
+	_, err = a.admit(context.Background(), "mychannel", "mycc")
+	require.EqualError(t, err, "chaincode mycc on channel mychannel is busy: 1 proposals are already waiting, retry later")
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.ResourceExhausted, s.Code())
+
+	release()
+	require.NoError(t, <-admitted)
+	require.Equal(t, float64(0), queueLength.SetArgsForCall(1))
+}
+
+func TestAdmissionControllerChannelLimit(t *testing.T) {
+	a, queueLength, _ := newTestAdmissionController(AdmissionLimits{ChannelConcurrency: 1})
+
+	release, err := a.admit(context.Background(), "mychannel", "mycc")
+	require.NoError(t, err)
+
+	_, err = a.admit(context.Background(), "mychannel", "othercc")
+	require.EqualError(t, err, "channel mychannel is busy: 0 proposals are already waiting, retry later")
+	require.Zero(t, queueLength.SetCallCount())
+
+	release()
+	release, err = a.admit(context.Background(), "mychannel", "othercc")
+	require.NoError(t, err)
+	release()
+}
+
+func TestAdmissionControllerReleasesChaincodeWhenChannelIsBusy(t *testing.T) {
+	a, _, _ := newTestAdmissionController(AdmissionLimits{ChannelConcurrency: 1, ChaincodeConcurrency: 1})
+
+	release, err := a.admit(context.Background(), "mychannel", "mycc")
+	require.NoError(t, err)
+	_, err = a.admit(context.Background(), "mychannel", "othercc")
+	require.EqualError(t, err, "channel mychannel is busy: 0 proposals are already waiting, retry later")
+	release()
+
+	release, err = a.admit(context.Background(), "mychannel", "othercc")
+	require.NoError(t, err, "the slot of othercc was released")
+	release()
+}
+
+func TestAdmissionControllerContextDone(t *testing.T) {
+	a, _, queueLength := newTestAdmissionController(AdmissionLimits{ChaincodeConcurrency: 1, ChaincodeQueueDepth: 1})
+
+	release, err := a.admit(context.Background(), "mychannel", "mycc")
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = a.admit(ctx, "mychannel", "mycc")
+	require.Equal(t, context.DeadlineExceeded, err)
+	require.Equal(t, 2, queueLength.SetCallCount())
+	require.Equal(t, float64(0), queueLength.SetArgsForCall(1))
+}
@@ -100,6 +100,12 @@ type Endorser struct {
 	Support                Support
 	PvtRWSetAssembler      PvtRWSetAssembler
 	Metrics                *Metrics
+	// QueryCache, if set, caches the results of the read-only proposals of
+	// the chaincodes it is enabled for.
+	QueryCache *QueryCache
+	// AdmissionController, if set, limits the proposals processed
+	// concurrently for each channel and chaincode.
+	AdmissionController *AdmissionController
 }
 
 // call specified chaincode (system or user)
@@ -337,6 +343,16 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		e.Metrics.ProposalDuration.With(meterLabels...).Observe(time.Since(startTime).Seconds())
 	}()
 
+	release, err := e.admit(ctx, up)
+	if err != nil {
+		if _, ok := err.(*BusyError); ok {
+			e.Metrics.ProposalsRejectedBusy.With("channel", up.ChannelID(), "chaincode", up.ChaincodeName).Add(1)
+		}
+		endorserLogger.Warningf("Proposal for chaincode %s on channel %s was not admitted: %s", up.ChaincodeName, up.ChannelID(), err)
+		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, err
+	}
+	defer release()
+
 	pResp, err := e.ProcessProposalSuccessfullyOrError(up)
 	if err != nil {
 		return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
@@ -364,6 +380,10 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 
 	logger := decorateLogger(endorserLogger, txParams)
 
+	// The query cache is looked up before the simulator is acquired, so that
+	// the result of a simulation which may predate a commit is not cached.
+	query := e.QueryCache.lookUp(up)
+
 	if acquireTxSimulator(up.ChannelHeader.ChannelId, up.ChaincodeName) {
 		txSim, err := e.Support.GetTxSimulator(up.ChannelID(), up.TxID())
 		if err != nil {
@@ -393,10 +413,34 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		return nil, errors.WithMessagef(err, "make sure the chaincode %s has been successfully defined on channel %s and try again", up.ChaincodeName, up.ChannelID())
 	}
 
+	meterLabels := []string{
+		"channel", up.ChannelID(),
+		"chaincode", up.ChaincodeName,
+	}
+
 	// 1 -- simulate
-	res, simulationResult, ccevent, err := e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
-	if err != nil {
-		return nil, errors.WithMessage(err, "error in simulation")
+	var res *pb.Response
+	var simulationResult []byte
+	var ccevent *pb.ChaincodeEvent
+	if cached := query.hit(cdLedger.Version); cached != nil {
+		e.Metrics.QueryCacheHits.With(meterLabels...).Add(1)
+		logger.Debugf("using the cached result of the query")
+		res, simulationResult = cached.response, cached.simulationResult
+	} else {
+		res, simulationResult, ccevent, err = e.SimulateProposal(txParams, up.ChaincodeName, up.Input)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error in simulation")
+		}
+		if query != nil {
+			e.Metrics.QueryCacheMisses.With(meterLabels...).Add(1)
+			if ccevent == nil && res.Status < shim.ERRORTHRESHOLD {
+				e.QueryCache.put(up.ChannelID(), query, &queryResult{
+					chaincodeVersion: cdLedger.Version,
+					response:         res,
+					simulationResult: simulationResult,
+				})
+			}
+		}
 	}
 
 	cceventBytes, err := CreateCCEventBytes(ccevent)
@@ -414,11 +458,6 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 	}
 
 	// if error, capture endorsement failure metric
-	meterLabels := []string{
-		"channel", up.ChannelID(),
-		"chaincode", up.ChaincodeName,
-	}
-
 	switch {
 	case res.Status >= shim.ERROR:
 		return &pb.ProposalResponse{
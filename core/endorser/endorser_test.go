@@ -16,10 +16,12 @@ import (
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	mspproto "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/endorser/fake"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -785,6 +787,201 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when the query cache is enabled for the chaincode", func() {
+		var (
+			fakeQueryCacheHits   *metricsfakes.Counter
+			fakeQueryCacheMisses *metricsfakes.Counter
+			readSet              *kvrwset.KVRWSet
+		)
+
+		BeforeEach(func() {
+			fakeQueryCacheHits = &metricsfakes.Counter{}
+			fakeQueryCacheHits.WithReturns(fakeQueryCacheHits)
+			fakeQueryCacheMisses = &metricsfakes.Counter{}
+			fakeQueryCacheMisses.WithReturns(fakeQueryCacheMisses)
+			e.Metrics.QueryCacheHits = fakeQueryCacheHits
+			e.Metrics.QueryCacheMisses = fakeQueryCacheMisses
+			e.QueryCache = endorser.NewQueryCache([]string{"chaincode-name"}, 10)
+
+			fakeSupport.ExecuteReturns(chaincodeResponse, nil, nil)
+			readSet = &kvrwset.KVRWSet{
+				Reads: []*kvrwset.KVRead{{Key: "key"}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			fakeTxSimulator.GetTxSimulationResultsReturns(
+				&ledger.TxSimulationResults{
+					PubSimulationResults: &rwset.TxReadWriteSet{
+						NsRwset: []*rwset.NsReadWriteSet{{
+							Namespace: "chaincode-name",
+							Rwset:     protoutil.MarshalOrPanic(readSet),
+						}},
+					},
+				},
+				nil,
+			)
+		})
+
+		It("answers the identical queries from the cache", func() {
+			first, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+			Expect(fakeSupport.EndorseWithPluginCallCount()).To(Equal(2))
+			Expect(proto.Equal(first, second)).To(BeTrue())
+
+			Expect(fakeQueryCacheMisses.AddCallCount()).To(Equal(1))
+			Expect(fakeQueryCacheMisses.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id", "chaincode", "chaincode-name"}))
+			Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(1))
+			Expect(fakeQueryCacheHits.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id", "chaincode", "chaincode-name"}))
+		})
+
+		Context("when a block updating the chaincode namespace is committed", func() {
+			It("simulates the query again", func() {
+				_, err := e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = e.QueryCache.HandleStateUpdates(&ledger.StateUpdateTrigger{
+					LedgerID:     "channel-id",
+					StateUpdates: ledger.StateUpdates{"chaincode-name": &ledger.KVStateUpdates{}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				e.QueryCache.StateCommitDone("channel-id")
+
+				_, err = e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeQueryCacheMisses.AddCallCount()).To(Equal(2))
+				Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode definition changes", func() {
+			It("simulates the query again", func() {
+				_, err := e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeSupport.ChaincodeEndorsementInfoReturns(&lifecycle.ChaincodeEndorsementInfo{
+					Version:           "new-chaincode-definition-version",
+					EndorsementPlugin: "plugin-name",
+				}, nil)
+				_, err = e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the simulation writes state", func() {
+			BeforeEach(func() {
+				readSet.Writes = []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.TODO(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeQueryCacheHits.AddCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode sets an event", func() {
+			BeforeEach(func() {
+				fakeSupport.ExecuteReturns(chaincodeResponse, chaincodeEvent, nil)
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.TODO(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode returns an error", func() {
+			BeforeEach(func() {
+				chaincodeResponse.Status = 500
+			})
+
+			It("does not cache the result", func() {
+				for i := 0; i < 2; i++ {
+					_, err := e.ProcessProposal(context.TODO(), signedProposal)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when the chaincode reaches its concurrency limit", func() {
+		var (
+			fakeChaincodeQueueLength  *metricsfakes.Gauge
+			fakeProposalsRejectedBusy *metricsfakes.Counter
+			executing                 chan struct{}
+			done                      chan struct{}
+		)
+
+		BeforeEach(func() {
+			fakeChaincodeQueueLength = &metricsfakes.Gauge{}
+			fakeChaincodeQueueLength.WithReturns(fakeChaincodeQueueLength)
+			fakeProposalsRejectedBusy = &metricsfakes.Counter{}
+			fakeProposalsRejectedBusy.WithReturns(fakeProposalsRejectedBusy)
+			e.Metrics.ChaincodeQueueLength = fakeChaincodeQueueLength
+			e.Metrics.ProposalsRejectedBusy = fakeProposalsRejectedBusy
+			e.AdmissionController = endorser.NewAdmissionController(endorser.AdmissionLimits{ChaincodeConcurrency: 1}, e.Metrics)
+
+			executing = make(chan struct{})
+			done = make(chan struct{})
+			fakeSupport.ExecuteStub = func(*ccprovider.TransactionParams, string, *pb.ChaincodeInput) (*pb.Response, *pb.ChaincodeEvent, error) {
+				close(executing)
+				<-done
+				return chaincodeResponse, chaincodeEvent, nil
+			}
+		})
+
+		It("rejects the proposals of the chaincode as busy", func() {
+			processed := make(chan error, 1)
+			go func() {
+				_, err := e.ProcessProposal(context.TODO(), signedProposal)
+				processed <- err
+			}()
+			Eventually(executing).Should(BeClosed())
+
+			proposalResponse, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).To(MatchError("chaincode chaincode-name on channel channel-id is busy: 0 proposals are already waiting, retry later"))
+			Expect(proposalResponse).To(Equal(&pb.ProposalResponse{
+				Response: &pb.Response{
+					Status:  500,
+					Message: "chaincode chaincode-name on channel channel-id is busy: 0 proposals are already waiting, retry later",
+				},
+			}))
+			Expect(fakeProposalsRejectedBusy.AddCallCount()).To(Equal(1))
+			Expect(fakeProposalsRejectedBusy.WithArgsForCall(0)).To(Equal([]string{"channel", "channel-id", "chaincode", "chaincode-name"}))
+
+			close(done)
+			Eventually(processed).Should(Receive(BeNil()))
+		})
+
+		Context("when the chaincode is not defined", func() {
+			BeforeEach(func() {
+				fakeSupport.ChaincodeEndorsementInfoReturns(nil, errors.New("chaincode not found"))
+			})
+
+			It("rejects the proposal before creating a limiter for the chaincode", func() {
+				_, err := e.ProcessProposal(context.TODO(), signedProposal)
+				Expect(err).To(MatchError("make sure the chaincode chaincode-name has been successfully defined on channel channel-id and try again: chaincode not found"))
+				Expect(fakeChaincodeQueueLength.WithCallCount()).To(Equal(0))
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(0))
+				Expect(fakeTxSimulator.DoneCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	Context("when we're in the degenerate legacy lifecycle case", func() {
 		BeforeEach(func() {
 			chaincodeName = "lscc"
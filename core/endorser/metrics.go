@@ -74,6 +74,46 @@ var (
 		LabelNames:   []string{"channel", "chaincode"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
 	}
+
+	queryCacheHitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_hits",
+		Help:         "The number of proposals answered from the query cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	queryCacheMissesCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_misses",
+		Help:         "The number of cacheable proposals which were simulated.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	chaincodeQueueLengthGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "endorser",
+		Name:         "chaincode_queue_length",
+		Help:         "The number of proposals queued for their chaincode.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	channelQueueLengthGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "endorser",
+		Name:         "channel_queue_length",
+		Help:         "The number of proposals queued for their channel.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	busyRejectionCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "proposals_rejected_busy",
+		Help:         "The number of proposals rejected because a queue was full.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
 )
 
 type Metrics struct {
@@ -86,6 +126,11 @@ type Metrics struct {
 	EndorsementsFailed       metrics.Counter
 	DuplicateTxsFailure      metrics.Counter
 	SimulationFailure        metrics.Counter
+	QueryCacheHits           metrics.Counter
+	QueryCacheMisses         metrics.Counter
+	ChaincodeQueueLength     metrics.Gauge
+	ChannelQueueLength       metrics.Gauge
+	ProposalsRejectedBusy    metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -99,5 +144,10 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		EndorsementsFailed:       p.NewCounter(endorsementFailureCounterOpts),
 		DuplicateTxsFailure:      p.NewCounter(duplicateTxsFailureCounterOpts),
 		SimulationFailure:        p.NewCounter(simulationFailureCounterOpts),
+		QueryCacheHits:           p.NewCounter(queryCacheHitsCounterOpts),
+		QueryCacheMisses:         p.NewCounter(queryCacheMissesCounterOpts),
+		ChaincodeQueueLength:     p.NewGauge(chaincodeQueueLengthGaugeOpts),
+		ChannelQueueLength:       p.NewGauge(channelQueueLengthGaugeOpts),
+		ProposalsRejectedBusy:    p.NewCounter(busyRejectionCounterOpts),
 	}
 }
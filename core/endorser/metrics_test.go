@@ -19,6 +19,7 @@ func TestNewMetrics(t *testing.T) {
 	provider := &metricsfakes.Provider{}
 	provider.NewHistogramReturns(&metricsfakes.Histogram{})
 	provider.NewCounterReturns(&metricsfakes.Counter{})
+	provider.NewGaugeReturns(&metricsfakes.Gauge{})
 
 	endorserMetrics := NewMetrics(provider)
 	gt.Expect(endorserMetrics).To(Equal(&Metrics{
@@ -31,6 +32,11 @@ func TestNewMetrics(t *testing.T) {
 		EndorsementsFailed:       &metricsfakes.Counter{},
 		DuplicateTxsFailure:      &metricsfakes.Counter{},
 		SimulationFailure:        &metricsfakes.Counter{},
+		QueryCacheHits:           &metricsfakes.Counter{},
+		QueryCacheMisses:         &metricsfakes.Counter{},
+		ChaincodeQueueLength:     &metricsfakes.Gauge{},
+		ChannelQueueLength:       &metricsfakes.Gauge{},
+		ProposalsRejectedBusy:    &metricsfakes.Counter{},
 	}))
 
 	gt.Expect(provider.NewHistogramCallCount()).To(Equal(1))
@@ -38,7 +44,7 @@ func TestNewMetrics(t *testing.T) {
 		{proposalDurationHistogramOpts},
 	}))
 
-	gt.Expect(provider.NewCounterCallCount()).To(Equal(8))
+	gt.Expect(provider.NewCounterCallCount()).To(Equal(11))
 	gt.Expect(provider.Invocations()["NewCounter"]).To(ConsistOf([][]interface{}{
 		{receivedProposalsCounterOpts},
 		{successfulProposalsCounterOpts},
@@ -48,5 +54,14 @@ func TestNewMetrics(t *testing.T) {
 		{endorsementFailureCounterOpts},
 		{duplicateTxsFailureCounterOpts},
 		{simulationFailureCounterOpts},
+		{queryCacheHitsCounterOpts},
+		{queryCacheMissesCounterOpts},
+		{busyRejectionCounterOpts},
+	}))
+
+	gt.Expect(provider.NewGaugeCallCount()).To(Equal(2))
+	gt.Expect(provider.Invocations()["NewGauge"]).To(ConsistOf([][]interface{}{
+		{chaincodeQueueLengthGaugeOpts},
+		{channelQueueLengthGaugeOpts},
 	}))
 }
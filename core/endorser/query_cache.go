@@ -0,0 +1,311 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// The chaincode definitions are stored in these namespaces. Any update to them
+// drops all the results cached for the channel.
+var definitionNamespaces = []string{lifecycle.LifecycleNamespace, "lscc"}
+
+// QueryCache caches the results of the read-only proposals of the chaincodes
+// it is enabled for, so that identical queries from the same creator are not
+// simulated again until the state they depend on changes. It is registered
+// with the ledger as a state listener, and drops the results which depend on a
+// namespace once a block updating it is committed. A result depends on the
+// namespaces it read, on the namespace of the chaincode, and on the namespaces
+// of the chaincodes it invoked, since the queries which return nothing, such as
+// a rich query without matches or a history query, leave no read behind. The
+// least recently used results are evicted once the cache is full.
+type QueryCache struct {
+	chaincodes map[string]struct{}
+	size       int
+
+	mutex    sync.Mutex
+	channels map[string]*channelQueryCache
+}
+
+type channelQueryCache struct {
+	// generation is incremented whenever a block updating the cached
+	// namespaces is committed, so that a result simulated before the commit is
+	// not cached after it.
+	generation uint64
+	results    map[string]*list.Element
+	// lru orders the results from the most to the least recently used.
+	lru *list.List
+	// updated lists the namespaces updated by the block being committed.
+	updated map[string]struct{}
+}
+
+// queryResult is the simulation of a read-only proposal.
+type queryResult struct {
+	key              string
+	chaincodeVersion string
+	response         *pb.Response
+	simulationResult []byte
+	namespaces       []string
+}
+
+// queryLookup is the outcome of the lookup of a proposal in the cache.
+type queryLookup struct {
+	key        string
+	chaincode  string
+	generation uint64
+	result     *queryResult
+}
+
+// NewQueryCache creates a QueryCache enabled for the given chaincodes, which
+// holds at most size results for each channel.
+func NewQueryCache(chaincodes []string, size int) *QueryCache {
+	c := &QueryCache{
+		chaincodes: map[string]struct{}{},
+		size:       size,
+		channels:   map[string]*channelQueryCache{},
+	}
+	for _, chaincode := range chaincodes {
+		c.chaincodes[chaincode] = struct{}{}
+	}
+	return c
+}
+
+// lookUp returns the cached result of the proposal, or nil if it cannot be
+// cached. It must be called before the simulator of the proposal is acquired.
+func (c *QueryCache) lookUp(up *UnpackedProposal) *queryLookup {
+	if c == nil || !acquireTxSimulator(up.ChannelID(), up.ChaincodeName) {
+		return nil
+	}
+	if _, ok := c.chaincodes[up.ChaincodeName]; !ok {
+		return nil
+	}
+	cpp, err := protoutil.UnmarshalChaincodeProposalPayload(up.Proposal.Payload)
+	if err != nil || len(cpp.TransientMap) > 0 {
+		// The transient data is not part of the key.
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := c.channel(up.ChannelID())
+	lookup := &queryLookup{
+		key:        queryKey([]byte(up.ChaincodeName), cpp.Input, up.SignatureHeader.Creator),
+		chaincode:  up.ChaincodeName,
+		generation: channel.generation,
+	}
+	if element, ok := channel.results[lookup.key]; ok {
+		channel.lru.MoveToFront(element)
+		lookup.result = element.Value.(*queryResult)
+	}
+	return lookup
+}
+
+// hit returns the cached result if it was simulated with the current version
+// of the chaincode.
+func (l *queryLookup) hit(chaincodeVersion string) *queryResult {
+	if l == nil || l.result == nil || l.result.chaincodeVersion != chaincodeVersion {
+		return nil
+	}
+	return l.result
+}
+
+// put caches the result of the simulation of a proposal, provided that it did
+// not write any state, only depends on the namespaces of the chaincodes the
+// cache is enabled for, and that no block updating them was committed since
+// the lookup.
+func (c *QueryCache) put(channelID string, lookup *queryLookup, result *queryResult) {
+	namespaces, ok := dependencies(lookup.chaincode, result.simulationResult)
+	if !ok {
+		return
+	}
+	for _, ns := range namespaces {
+		if _, ok := c.chaincodes[ns]; !ok && !isDefinitionNamespace(ns) {
+			return
+		}
+	}
+	result.key = lookup.key
+	result.namespaces = namespaces
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := c.channel(channelID)
+	if channel.generation != lookup.generation {
+		return
+	}
+	if element, ok := channel.results[lookup.key]; ok {
+		element.Value = result
+		channel.lru.MoveToFront(element)
+		return
+	}
+	if channel.lru.Len() >= c.size {
+		channel.remove(channel.lru.Back())
+	}
+	channel.results[lookup.key] = channel.lru.PushFront(result)
+}
+
+func (c *QueryCache) channel(channelID string) *channelQueryCache {
+	channel, ok := c.channels[channelID]
+	if !ok {
+		channel = &channelQueryCache{
+			results: map[string]*list.Element{},
+			lru:     list.New(),
+			updated: map[string]struct{}{},
+		}
+		c.channels[channelID] = channel
+	}
+	return channel
+}
+
+func (c *channelQueryCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.results, element.Value.(*queryResult).key)
+}
+
+// Name implements function from interface ledger.StateListener
+func (c *QueryCache) Name() string {
+	return "query cache"
+}
+
+// Initialize implements function from interface ledger.StateListener
+func (c *QueryCache) Initialize(ledgerID string, qe ledger.SimpleQueryExecutor) error {
+	return nil
+}
+
+// InterestedInNamespaces implements function from interface ledger.StateListener
+func (c *QueryCache) InterestedInNamespaces() []string {
+	namespaces := append([]string{}, definitionNamespaces...)
+	for chaincode := range c.chaincodes {
+		namespaces = append(namespaces, chaincode)
+	}
+	return namespaces
+}
+
+// HandleStateUpdates implements function from interface ledger.StateListener.
+// The results are only dropped once the block is committed, since until then
+// they are the ones a simulation would return.
+func (c *QueryCache) HandleStateUpdates(trigger *ledger.StateUpdateTrigger) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := c.channel(trigger.LedgerID)
+	for ns := range trigger.StateUpdates {
+		channel.updated[ns] = struct{}{}
+	}
+	return nil
+}
+
+// StateCommitDone implements function from interface ledger.StateListener
+func (c *QueryCache) StateCommitDone(channelID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	channel := c.channel(channelID)
+	if len(channel.updated) == 0 {
+		return
+	}
+	channel.generation++
+
+	for _, ns := range definitionNamespaces {
+		if _, ok := channel.updated[ns]; ok {
+			channel.results = map[string]*list.Element{}
+			channel.lru.Init()
+			channel.updated = map[string]struct{}{}
+			return
+		}
+	}
+	for _, element := range channel.results {
+		for _, ns := range element.Value.(*queryResult).namespaces {
+			if _, ok := channel.updated[ns]; ok {
+				channel.remove(element)
+				break
+			}
+		}
+	}
+	channel.updated = map[string]struct{}{}
+}
+
+// queryKey hashes the length-prefixed fields identifying a query.
+func queryKey(fields ...[]byte) string {
+	h := sha256.New()
+	for _, field := range fields {
+		length := make([]byte, 8)
+		binary.BigEndian.PutUint64(length, uint64(len(field)))
+		h.Write(length)
+		h.Write(field)
+	}
+	return string(h.Sum(nil))
+}
+
+// dependencies returns the namespaces the result of a simulation of the
+// chaincode depends on, and false if the simulation wrote any state or
+// accessed any private data. Besides the namespaces read, these are the
+// namespace of the chaincode and those of the chaincodes it invoked, whose
+// definitions were read when they were invoked. The results which read
+// private data are not cached, as the private data is also updated by the
+// reconciliation and purged when it expires, which do not notify the state
+// listeners.
+func dependencies(chaincodeName string, simulationResult []byte) ([]string, bool) {
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(simulationResult); err != nil {
+		return nil, false
+	}
+	namespaces := map[string]struct{}{chaincodeName: {}}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if len(nsRWSet.KvRwSet.GetWrites()) > 0 || len(nsRWSet.KvRwSet.GetMetadataWrites()) > 0 {
+			return nil, false
+		}
+		if len(nsRWSet.CollHashedRwSets) > 0 {
+			return nil, false
+		}
+		namespaces[nsRWSet.NameSpace] = struct{}{}
+		for _, read := range nsRWSet.KvRwSet.GetReads() {
+			if invoked, ok := definedChaincode(nsRWSet.NameSpace, read.Key); ok {
+				namespaces[invoked] = struct{}{}
+			}
+		}
+	}
+
+	var result []string
+	for ns := range namespaces {
+		result = append(result, ns)
+	}
+	return result, true
+}
+
+// definedChaincode returns the chaincode whose definition is stored in the key
+// of a definition namespace.
+func definedChaincode(namespace, key string) (string, bool) {
+	switch namespace {
+	case lifecycle.LifecycleNamespace:
+		matches := lifecycle.SequenceMatcher.FindStringSubmatch(key)
+		if len(matches) != 2 {
+			return "", false
+		}
+		return matches[1], true
+	case "lscc":
+		return key, !privdata.IsCollectionConfigKey(key)
+	default:
+		return "", false
+	}
+}
+
+func isDefinitionNamespace(ns string) bool {
+	for _, definitionNS := range definitionNamespaces {
+		if ns == definitionNS {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func queryProposal(chaincodeName string, arg string, transientMap map[string][]byte) *UnpackedProposal {
+	return &UnpackedProposal{
+		ChaincodeName: chaincodeName,
+		ChannelHeader: &cb.ChannelHeader{ChannelId: "mychannel"},
+		Proposal: &pb.Proposal{
+			Payload: protoutil.MarshalOrPanic(&pb.ChaincodeProposalPayload{
+				Input: protoutil.MarshalOrPanic(&pb.ChaincodeInvocationSpec{
+					ChaincodeSpec: &pb.ChaincodeSpec{
+						Input: &pb.ChaincodeInput{Args: [][]byte{[]byte(arg)}},
+					},
+				}),
+				TransientMap: transientMap,
+			}),
+		},
+		SignatureHeader: &cb.SignatureHeader{Creator: []byte("creator")},
+	}
+}
+
+func queryResultOf(writes bool, namespaces ...string) *queryResult {
+	txRWSet := &rwset.TxReadWriteSet{}
+	for _, ns := range namespaces {
+		kvRWSet := &kvrwset.KVRWSet{Reads: []*kvrwset.KVRead{{Key: "key"}}}
+		if writes {
+			kvRWSet.Writes = []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}}
+		}
+		txRWSet.NsRwset = append(txRWSet.NsRwset, &rwset.NsReadWriteSet{
+			Namespace: ns,
+			Rwset:     protoutil.MarshalOrPanic(kvRWSet),
+		})
+	}
+	return &queryResult{
+		chaincodeVersion: "1.0",
+		response:         &pb.Response{Status: 200},
+		simulationResult: protoutil.MarshalOrPanic(txRWSet),
+	}
+}
+
+// privateReadOf returns the result of a simulation which read the private
+// data of a collection of the chaincode.
+func privateReadOf(chaincodeName string) *queryResult {
+	result := queryResultOf(false)
+	result.simulationResult = protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace: chaincodeName,
+			Rwset:     protoutil.MarshalOrPanic(&kvrwset.KVRWSet{}),
+			CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{
+				CollectionName: "collection",
+				HashedRwset: protoutil.MarshalOrPanic(&kvrwset.HashedRWSet{
+					HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("key hash")}},
+				}),
+			}},
+		}},
+	})
+	return result
+}
+
+// invocationOf returns the result of a simulation which invoked the chaincode
+// without reading its state.
+func invocationOf(chaincodeName string) *queryResult {
+	result := queryResultOf(false)
+	result.simulationResult = protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace: "_lifecycle",
+			Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+				Reads: []*kvrwset.KVRead{{Key: "namespaces/fields/" + chaincodeName + "/Sequence"}},
+			}),
+		}},
+	})
+	return result
+}
+
+func commitUpdates(t *testing.T, c *QueryCache, channelID string, namespaces ...string) {
+	updates := ledger.StateUpdates{}
+	for _, ns := range namespaces {
+		updates[ns] = &ledger.KVStateUpdates{}
+	}
+	err := c.HandleStateUpdates(&ledger.StateUpdateTrigger{LedgerID: channelID, StateUpdates: updates})
+	require.NoError(t, err)
+	c.StateCommitDone(channelID)
+}
+
+func TestQueryCacheLookUp(t *testing.T) {
+	var c *QueryCache
+	require.Nil(t, c.lookUp(queryProposal("mycc", "a", nil)), "a nil cache is disabled")
+
+	c = NewQueryCache([]string{"mycc", "qscc"}, 10)
+	require.Nil(t, c.lookUp(queryProposal("othercc", "a", nil)), "the cache is disabled for othercc")
+	require.Nil(t, c.lookUp(queryProposal("qscc", "a", nil)), "qscc is simulated without a simulator")
+	require.Nil(t, c.lookUp(queryProposal("mycc", "a", map[string][]byte{"secret": []byte("s")})), "transient data is not cached")
+
+	lookup := c.lookUp(queryProposal("mycc", "a", nil))
+	require.NotNil(t, lookup)
+	require.Nil(t, lookup.hit("1.0"))
+	c.put("mychannel", lookup, queryResultOf(false, "mycc"))
+
+	require.NotNil(t, c.lookUp(queryProposal("mycc", "a", nil)).hit("1.0"))
+	require.Nil(t, c.lookUp(queryProposal("mycc", "a", nil)).hit("2.0"), "the chaincode was upgraded")
+	require.Nil(t, c.lookUp(queryProposal("mycc", "b", nil)).hit("1.0"), "the input differs")
+
+	other := queryProposal("mycc", "a", nil)
+	other.SignatureHeader.Creator = []byte("other creator")
+	require.Nil(t, c.lookUp(other).hit("1.0"), "the creator differs")
+}
+
+func TestQueryCachePut(t *testing.T) {
+	c := NewQueryCache([]string{"mycc", "yourcc"}, 10)
+
+	tests := []struct {
+		name     string
+		result   *queryResult
+		expected bool
+	}{
+		{name: "read-only", result: queryResultOf(false, "mycc", "yourcc", "_lifecycle"), expected: true},
+		{name: "writes", result: queryResultOf(true, "mycc")},
+		{name: "reads a namespace which is not cached", result: queryResultOf(false, "mycc", "othercc")},
+		{name: "reads nothing", result: queryResultOf(false), expected: true},
+		{name: "invokes a chaincode which is cached", result: invocationOf("yourcc"), expected: true},
+		{name: "invokes a chaincode which is not cached", result: invocationOf("othercc")},
+		{name: "reads private data", result: privateReadOf("mycc")},
+		{name: "invalid simulation result", result: &queryResult{simulationResult: []byte("garbage")}},
+	}
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			up := queryProposal("mycc", string(rune('a'+i)), nil)
+			c.put("mychannel", c.lookUp(up), test.result)
+			require.Equal(t, test.expected, c.lookUp(up).hit("1.0") != nil)
+		})
+	}
+}
+
+func TestQueryCacheStateUpdates(t *testing.T) {
+	c := NewQueryCache([]string{"mycc", "yourcc"}, 10)
+	require.ElementsMatch(t, []string{"_lifecycle", "lscc", "mycc", "yourcc"}, c.InterestedInNamespaces())
+	require.NoError(t, c.Initialize("mychannel", nil))
+
+	cache := func(arg string, namespaces ...string) {
+		up := queryProposal("mycc", arg, nil)
+		c.put("mychannel", c.lookUp(up), queryResultOf(false, namespaces...))
+	}
+	cached := func(arg string) bool {
+		return c.lookUp(queryProposal("mycc", arg, nil)).hit("1.0") != nil
+	}
+
+	cache("a", "mycc")
+	cache("b", "mycc", "yourcc")
+	require.True(t, cached("a"))
+	require.True(t, cached("b"))
+
+	// Until the block is committed, the cached results are still current.
+	err := c.HandleStateUpdates(&ledger.StateUpdateTrigger{
+		LedgerID:     "mychannel",
+		StateUpdates: ledger.StateUpdates{"yourcc": &ledger.KVStateUpdates{}},
+	})
+	require.NoError(t, err)
+	require.True(t, cached("b"))
+	c.StateCommitDone("mychannel")
+	require.True(t, cached("a"))
+	require.False(t, cached("b"))
+
+	// A result simulated before a commit is not cached after it.
+	lookup := c.lookUp(queryProposal("mycc", "c", nil))
+	commitUpdates(t, c, "mychannel", "yourcc")
+	c.put("mychannel", lookup, queryResultOf(false, "mycc"))
+	require.False(t, cached("c"))
+
+	// A commit on another channel does not drop the results.
+	commitUpdates(t, c, "otherchannel", "mycc")
+	require.True(t, cached("a"))
+
+	// A result which read nothing, such as a rich query without matches,
+	// depends on the namespace of the chaincode.
+	cache("e")
+	require.True(t, cached("e"))
+	commitUpdates(t, c, "mychannel", "mycc")
+	require.False(t, cached("e"))
+
+	// A result depends on the namespaces of the chaincodes it invoked.
+	up := queryProposal("mycc", "f", nil)
+	c.put("mychannel", c.lookUp(up), invocationOf("yourcc"))
+	require.True(t, cached("f"))
+	commitUpdates(t, c, "mychannel", "yourcc")
+	require.False(t, cached("f"))
+
+	// Any change of the chaincode definitions drops all the results.
+	cache("d", "yourcc")
+	commitUpdates(t, c, "mychannel", "_lifecycle")
+	require.False(t, cached("a"))
+	require.False(t, cached("d"))
+}
+
+func TestQueryCacheSize(t *testing.T) {
+	c := NewQueryCache([]string{"mycc"}, 2)
+	cache := func(arg string) {
+		up := queryProposal("mycc", arg, nil)
+		c.put("mychannel", c.lookUp(up), queryResultOf(false, "mycc"))
+	}
+	cached := func(arg string) bool {
+		return c.lookUp(queryProposal("mycc", arg, nil)).hit("1.0") != nil
+	}
+
+	cache("a")
+	cache("b")
+	require.True(t, cached("a"))
+	cache("c")
+	require.Len(t, c.channels["mychannel"].results, 2)
+	require.Equal(t, 2, c.channels["mychannel"].lru.Len())
+	require.False(t, cached("b"), "the least recently used result is evicted")
+	require.True(t, cached("a"))
+	require.True(t, cached("c"))
+}
@@ -217,7 +217,8 @@ func (r *Retriever) MostRecentCollectionConfigBelow(blockNum uint64, chaincodeNa
 // records, it would add only 12 MB overhead. Note that the protobuf also adds some
 // extra bytes. Further, the collection config namespace is not expected to have
 // millions of entries.
-func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
+// The number of the exported collection configs and the bytes written are accumulated in the progress, if not nil
+func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHashFunc, progress *snapshot.Progress) (map[string][]byte, error) {
 	nsItr, err := r.dbHandle.getNamespaceIterator(collectionConfigNamespace)
 	if err != nil {
 		return nil, err
@@ -231,7 +232,7 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 			return nil, errors.Wrap(err, "internal leveldb error while iterating for collection config history")
 		}
 		if numCollectionConfigs == 0 { // first iteration, create the data file
-			dataFileWriter, err = snapshot.CreateFile(filepath.Join(dir, snapshotDataFileName), snapshotFileFormat, newHashFunc)
+			dataFileWriter, err = snapshot.CreateFileWithProgress(filepath.Join(dir, snapshotDataFileName), snapshotFileFormat, newHashFunc, progress)
 			if err != nil {
 				return nil, err
 			}
@@ -244,6 +245,7 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 			return nil, err
 		}
 		numCollectionConfigs++
+		progress.AddRecords(1)
 	}
 
 	if dataFileWriter == nil {
@@ -254,7 +256,7 @@ func (r *Retriever) ExportConfigHistory(dir string, newHashFunc snapshot.NewHash
 	if err != nil {
 		return nil, err
 	}
-	metadataFileWriter, err := snapshot.CreateFile(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat, newHashFunc)
+	metadataFileWriter, err := snapshot.CreateFileWithProgress(filepath.Join(dir, snapshotMetadataFileName), snapshotFileFormat, newHashFunc, progress)
 	if err != nil {
 		return nil, err
 	}
@@ -375,7 +375,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		env := newTestEnvForSnapshot(t)
 		defer env.cleanup()
 		retriever := env.mgr.GetRetriever("ledger1")
-		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 		require.Empty(t, fileHashes)
 		files, err := ioutil.ReadDir(env.testSnapshotDir)
@@ -389,9 +389,12 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		defer env.cleanup()
 		storedKVs, _ := setupWithSampleData(env, "ledger1")
 		retriever := env.mgr.GetRetriever("ledger1")
-		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+		progress := &snapshot.Progress{}
+		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, progress)
 		require.NoError(t, err)
 		verifyExportedConfigHistory(t, env.testSnapshotDir, fileHashes, storedKVs)
+		require.Equal(t, uint64(len(storedKVs)), progress.Records())
+		require.NotZero(t, progress.Bytes())
 	})
 
 	t.Run("import confighistory and verify queries", func(t *testing.T) {
@@ -400,7 +403,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		defer env.cleanup()
 		_, ccConfigInfo := setupWithSampleData(env, "ledger1")
 		retriever := env.mgr.GetRetriever("ledger1")
-		_, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+		_, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 
 		importConfigsBatchSize = 100
@@ -416,7 +419,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		defer env.cleanup()
 		storedKVs, _ := setupWithSampleData(env, "ledger1")
 		retriever := env.mgr.GetRetriever("ledger1")
-		_, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+		_, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 
 		importConfigsBatchSize = 100
@@ -425,7 +428,7 @@ func TestExportAndImportConfigHistory(t *testing.T) {
 		require.NoError(t, os.RemoveAll(filepath.Join(env.testSnapshotDir, snapshotMetadataFileName)))
 
 		retriever = env.mgr.GetRetriever("ledger2")
-		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+		fileHashes, err := retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 		require.NoError(t, err)
 		verifyExportedConfigHistory(t, env.testSnapshotDir, fileHashes, storedKVs)
 	})
@@ -563,7 +566,7 @@ func TestExportConfigHistoryErrorCase(t *testing.T) {
 	require.NoError(t, err)
 
 	retriever := env.mgr.GetRetriever("ledger1")
-	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 	require.Contains(t, err.Error(), "error while creating the snapshot file: "+dataFilePath)
 	os.RemoveAll(env.testSnapshotDir)
 
@@ -572,14 +575,14 @@ func TestExportConfigHistoryErrorCase(t *testing.T) {
 	metadataFilePath := filepath.Join(env.testSnapshotDir, snapshotMetadataFileName)
 	_, err = os.Create(metadataFilePath)
 	require.NoError(t, err)
-	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 	require.Contains(t, err.Error(), "error while creating the snapshot file: "+metadataFilePath)
 	os.RemoveAll(env.testSnapshotDir)
 
 	// error while reading from leveldb
 	require.NoError(t, os.MkdirAll(env.testSnapshotDir, 0700))
 	env.mgr.dbProvider.Close()
-	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc)
+	_, err = retriever.ExportConfigHistory(env.testSnapshotDir, testNewHashFunc, nil)
 	require.EqualError(t, err, "internal leveldb error while obtaining db iterator: leveldb: closed")
 	os.RemoveAll(env.testSnapshotDir)
 }
@@ -0,0 +1,34 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// CompactBlockStore rewrites the existing block files of all the channels such that
+// each block is stored with the given compression ("none" or "snappy")
+func CompactBlockStore(rootFSPath, compression string) error {
+	c, err := blkstorage.ParseCompression(compression)
+	if err != nil {
+		return err
+	}
+
+	fileLockPath := fileLockPath(rootFSPath)
+	fileLock := leveldbhelper.NewFileLock(fileLockPath)
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	logger.Infof("Compacting the block store with compression [%s]", c)
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	return blkstorage.CompactBlockFiles(BlockStorePath(rootFSPath), c, indexConfig)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactBlockStore(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.BlockStoreConfig = &lgr.BlockStoreConfig{Compression: "snappy"}
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	block1 := bg.NextBlock([][]byte{[]byte("tx1"), []byte("tx2")})
+	require.NoError(t, l.CommitLegacy(&lgr.BlockAndPvtData{Block: block1}, &lgr.CommitOptions{}))
+
+	// compaction should fail when provider is still open
+	err = CompactBlockStore(conf.RootFSPath, "none")
+	require.EqualError(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying: lock is already acquired on file "+fileLockPath(conf.RootFSPath))
+	provider.Close()
+
+	require.EqualError(t, CompactBlockStore(conf.RootFSPath, "zstd"), "unsupported block compression [zstd], supported values are [none, snappy]")
+	require.NoError(t, CompactBlockStore(conf.RootFSPath, "none"))
+
+	conf.BlockStoreConfig = nil
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	l, err = provider.Open("testLedger")
+	require.NoError(t, err)
+
+	b, err := l.GetBlockByNumber(1)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(block1, b), "proto messages are not equal")
+	txID, err := protoutil.GetOrComputeTxIDFromEnvelope(block1.Data.Data[1])
+	require.NoError(t, err)
+	tx, err := l.GetTransactionByID(txID)
+	require.NoError(t, err)
+	require.Equal(t, block1.Data.Data[1], protoutil.MarshalOrPanic(tx.TransactionEnvelope))
+}
+
+func TestBlockStoreCompressionConfig(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.BlockStoreConfig = &lgr.BlockStoreConfig{Compression: "zstd"}
+	_, err := NewProvider(&lgr.Initializer{Config: conf})
+	require.EqualError(t, err, "unsupported block compression [zstd], supported values are [none, snappy]")
+}
@@ -135,6 +135,15 @@ func (d *DB) Commit(block *common.Block) error {
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(dataKey, emptyValue)
 				}
+
+				// add a history record for each hashed write to a private data collection
+				for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+					hashedNs := hashedDataNs(ns, collHashedRWSet.CollectionName)
+					for _, hashedWrite := range collHashedRWSet.HashedRwSet.HashedWrites {
+						dataKey := constructDataKey(hashedNs, string(hashedWrite.KeyHash), blockNo, tranNo)
+						dbBatch.Put(dataKey, emptyValue)
+					}
+				}
 			}
 
 		} else {
@@ -12,6 +12,7 @@ import (
 	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
@@ -22,7 +23,10 @@ import (
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -234,6 +238,222 @@ func TestHistory(t *testing.T) {
 	})
 }
 
+func TestHistoryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.Open(ledger1id)
+	require.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// blocks 1 to 6, each with a transaction that writes value<blockNum> for key7
+	txTimes := map[string]time.Time{}
+	for i := 1; i <= 6; i++ {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		require.NoError(t, simulator.SetState("ns1", "key7", []byte(fmt.Sprintf("value%d", i))))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block))
+
+		chdr, err := protoutil.ChannelHeader(protoutil.UnmarshalEnvelopeOrPanic(block.Data.Data[0]))
+		require.NoError(t, err)
+		txTimes[fmt.Sprintf("value%d", i)] = time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos))
+	}
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err, "Error upon NewQueryExecutor")
+
+	retrieve := func(t *testing.T, options *ledger.HistoryQueryOptions) ([]string, string) {
+		itr, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key7", options)
+		require.NoError(t, err)
+		retrievedVals := []string{}
+		for {
+			kmod, err := itr.Next()
+			require.NoError(t, err)
+			if kmod == nil {
+				break
+			}
+			retrievedVals = append(retrievedVals, string(kmod.(*queryresult.KeyModification).Value))
+		}
+		return retrievedVals, itr.GetBookmarkAndClose()
+	}
+
+	t.Run("no-options", func(t *testing.T) {
+		vals, bookmark := retrieve(t, &ledger.HistoryQueryOptions{})
+		require.Equal(t, []string{"value6", "value5", "value4", "value3", "value2", "value1"}, vals)
+		require.Empty(t, bookmark)
+	})
+
+	t.Run("block-range", func(t *testing.T) {
+		vals, _ := retrieve(t, &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 4})
+		require.Equal(t, []string{"value4", "value3", "value2"}, vals)
+
+		vals, _ = retrieve(t, &ledger.HistoryQueryOptions{StartBlock: 5})
+		require.Equal(t, []string{"value6", "value5"}, vals)
+
+		vals, _ = retrieve(t, &ledger.HistoryQueryOptions{EndBlock: 1})
+		require.Equal(t, []string{"value1"}, vals)
+	})
+
+	t.Run("time-range", func(t *testing.T) {
+		startTime, endTime := txTimes["value2"], txTimes["value4"]
+		expectedVals := []string{}
+		for i := 6; i >= 1; i-- {
+			val := fmt.Sprintf("value%d", i)
+			if !txTimes[val].Before(startTime) && !txTimes[val].After(endTime) {
+				expectedVals = append(expectedVals, val)
+			}
+		}
+		vals, _ := retrieve(t, &ledger.HistoryQueryOptions{StartTime: startTime, EndTime: endTime})
+		require.Equal(t, expectedVals, vals)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		options := &ledger.HistoryQueryOptions{StartBlock: 2, PageSize: 2}
+		vals, bookmark := retrieve(t, options)
+		require.Equal(t, []string{"value6", "value5"}, vals)
+		require.NotEmpty(t, bookmark)
+
+		options.Bookmark = bookmark
+		vals, bookmark = retrieve(t, options)
+		require.Equal(t, []string{"value4", "value3"}, vals)
+		require.NotEmpty(t, bookmark)
+
+		options.Bookmark = bookmark
+		vals, bookmark = retrieve(t, options)
+		require.Equal(t, []string{"value2"}, vals)
+		require.Empty(t, bookmark)
+	})
+
+	t.Run("pagination-with-time-range", func(t *testing.T) {
+		startTime := txTimes["value5"]
+		expectedVals := []string{}
+		for i := 6; i >= 1; i-- {
+			val := fmt.Sprintf("value%d", i)
+			if !txTimes[val].Before(startTime) {
+				expectedVals = append(expectedVals, val)
+			}
+		}
+		// the older entries are all outside the time range, hence there is no next page
+		vals, bookmark := retrieve(t, &ledger.HistoryQueryOptions{StartTime: startTime, PageSize: int32(len(expectedVals))})
+		require.Equal(t, expectedVals, vals)
+		require.Empty(t, bookmark)
+	})
+
+	t.Run("invalid-options", func(t *testing.T) {
+		_, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{StartBlock: 5, EndBlock: 2})
+		require.EqualError(t, err, "start block [5] is greater than end block [2]")
+
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{PageSize: -1})
+		require.EqualError(t, err, "invalid page size [-1]")
+
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{Bookmark: "not-a-bookmark"})
+		require.EqualError(t, err, "invalid bookmark [not-a-bookmark]")
+
+		_, bookmark := retrieve(t, &ledger.HistoryQueryOptions{PageSize: 5})
+		require.NotEmpty(t, bookmark)
+		_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{StartBlock: 3, Bookmark: bookmark})
+		require.EqualError(t, err, fmt.Sprintf("bookmark [%s] is older than the start block [3]", bookmark))
+	})
+}
+
+func TestPrivateDataHashHistory(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.Open(ledger1id)
+	require.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	require.NoError(t, store1.AddBlock(gb))
+	require.NoError(t, env.testHistoryDB.Commit(gb))
+
+	commitBlock := func(build func(*rwsetutil.RWSetBuilder)) string {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		build(rwsetBuilder)
+		simRes, err := rwsetBuilder.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimResBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		require.NoError(t, store1.AddBlock(block))
+		require.NoError(t, env.testHistoryDB.Commit(block))
+		txid, err := protoutil.GetOrComputeTxIDFromEnvelope(block.Data.Data[0])
+		require.NoError(t, err)
+		return txid
+	}
+
+	txid1 := commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value1"))
+		b.AddToPvtAndHashedWriteSet("ns1", "coll2", "key1", []byte("value-coll2"))
+		b.AddToWriteSet("ns1", "key1", []byte("public-value"))
+	})
+	txid2 := commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte("value2"))
+	})
+	txid3 := commitBlock(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", nil)
+	})
+
+	qhistory, err := env.testHistoryDB.NewQueryExecutor(store1)
+	require.NoError(t, err)
+
+	itr, err := qhistory.GetPrivateDataHashHistory("ns1", "coll1", "key1")
+	require.NoError(t, err)
+	defer itr.Close()
+	expected := []struct {
+		txid      string
+		valueHash []byte
+		isDelete  bool
+	}{
+		{txid: txid3, isDelete: true},
+		{txid: txid2, valueHash: util.ComputeHash([]byte("value2"))},
+		{txid: txid1, valueHash: util.ComputeHash([]byte("value1"))},
+	}
+	for _, e := range expected {
+		res, err := itr.Next()
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		kmod := res.(*queryresult.KeyModification)
+		require.Equal(t, e.txid, kmod.TxId)
+		require.Equal(t, e.valueHash, kmod.Value)
+		require.Equal(t, e.isDelete, kmod.IsDelete)
+		require.NotNil(t, kmod.Timestamp)
+	}
+	res, err := itr.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	// the history of the public key of the same name is not affected by the hashed writes
+	itr2, err := qhistory.GetHistoryForKey("ns1", "key1")
+	require.NoError(t, err)
+	defer itr2.Close()
+	res, err = itr2.Next()
+	require.NoError(t, err)
+	require.Equal(t, txid1, res.(*queryresult.KeyModification).TxId)
+	require.Equal(t, []byte("public-value"), res.(*queryresult.KeyModification).Value)
+	res, err = itr2.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+
+	itr3, err := qhistory.GetPrivateDataHashHistory("ns1", "coll3", "key1")
+	require.NoError(t, err)
+	defer itr3.Close()
+	res, err = itr3.Next()
+	require.NoError(t, err)
+	require.Nil(t, res)
+}
+
 func TestHistoryForInvalidTran(t *testing.T) {
 	env := newTestHistoryEnv(t)
 	defer env.cleanup()
@@ -8,6 +8,7 @@ package history
 
 import (
 	"bytes"
+	"encoding/hex"
 
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/pkg/errors"
@@ -38,6 +39,13 @@ func constructDataKey(ns string, key string, blocknum uint64, trannum uint64) da
 	return dataKey(k)
 }
 
+// hashedDataNs returns the namespace under which the history of the hashed writes to a private data collection
+// is recorded. The format is the same as the one used in the state database for the private data hashes and,
+// as the chaincode names cannot contain '$', does not clash with the namespace of a chaincode
+func hashedDataNs(ns, coll string) string {
+	return ns + "$$h" + coll
+}
+
 // constructRangescanKeys returns start and endKey for performing a range scan
 // that covers all the keys for <ns, key>.
 // startKey = namespace~len(key)~key~
@@ -73,3 +81,21 @@ func (r *rangeScan) decodeBlockNumTranNum(dataKey dataKey) (uint64, uint64, erro
 	}
 	return blockNum, tranNum, nil
 }
+
+// encodeBookmark encodes the blocknum~trannum part of the dataKey as the bookmark for a paginated history query
+func (r *rangeScan) encodeBookmark(dataKey dataKey) string {
+	return hex.EncodeToString(bytes.TrimPrefix(dataKey, r.startKey))
+}
+
+// decodeBookmark returns the dataKey encoded in the bookmark
+func (r *rangeScan) decodeBookmark(bookmark string) (dataKey, error) {
+	blockNumTranNumBytes, err := hex.DecodeString(bookmark)
+	if err != nil {
+		return nil, errors.Errorf("invalid bookmark [%s]", bookmark)
+	}
+	k := append(append([]byte{}, r.startKey...), blockNumTranNumBytes...)
+	if _, _, err := r.decodeBlockNumTranNum(k); err != nil {
+		return nil, errors.Errorf("invalid bookmark [%s]", bookmark)
+	}
+	return k, nil
+}
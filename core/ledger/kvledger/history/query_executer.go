@@ -7,12 +7,18 @@ SPDX-License-Identifier: Apache-2.0
 package history
 
 import (
+	"bytes"
+	"time"
+
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	lutils "github.com/hyperledger/fabric/core/ledger/util"
 	protoutil "github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -26,8 +32,38 @@ type QueryExecutor struct {
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *QueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.GetHistoryForKeyWithOptions(namespace, key, &ledger.HistoryQueryOptions{})
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *QueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string, options *ledger.HistoryQueryOptions) (commonledger.QueryResultsIterator, error) {
+	if err := validateHistoryQueryOptions(options); err != nil {
+		return nil, err
+	}
 	rangeScan := constructRangeScan(namespace, key)
-	dbItr, err := q.levelDB.GetIterator(rangeScan.startKey, rangeScan.endKey)
+	startKey, endKey := rangeScan.startKey, rangeScan.endKey
+	if options.StartBlock > 0 {
+		startKey = append(append([]byte{}, rangeScan.startKey...), util.EncodeOrderPreservingVarUint64(options.StartBlock)...)
+	}
+	if options.EndBlock > 0 {
+		endKey = append(append([]byte{}, rangeScan.startKey...), util.EncodeOrderPreservingVarUint64(options.EndBlock+1)...)
+	}
+	if options.Bookmark != "" {
+		// the bookmark is the last result returned in the previous page and the results are returned
+		// newest first, hence the next page ends just before the bookmark
+		bookmarkKey, err := rangeScan.decodeBookmark(options.Bookmark)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Compare(bookmarkKey, startKey) < 0 {
+			return nil, errors.Errorf("bookmark [%s] is older than the start block [%d]", options.Bookmark, options.StartBlock)
+		}
+		if bytes.Compare(bookmarkKey, endKey) < 0 {
+			endKey = bookmarkKey
+		}
+	}
+
+	dbItr, err := q.levelDB.GetIterator(startKey, endKey)
 	if err != nil {
 		return nil, err
 	}
@@ -38,28 +74,98 @@ func (q *QueryExecutor) GetHistoryForKey(namespace string, key string) (commonle
 	if dbItr.Last() {
 		dbItr.Next()
 	}
-	return &historyScanner{rangeScan, namespace, key, dbItr, q.blockStore}, nil
+	return &historyScanner{
+		rangeScan:  rangeScan,
+		namespace:  namespace,
+		key:        key,
+		dbItr:      dbItr,
+		blockStore: q.blockStore,
+		startTime:  options.StartTime,
+		endTime:    options.EndTime,
+		pageSize:   options.PageSize,
+	}, nil
+}
+
+// GetPrivateDataHashHistory implements method in interface `ledger.HistoryQueryExecutor`
+func (q *QueryExecutor) GetPrivateDataHashHistory(namespace, collection, key string) (commonledger.ResultsIterator, error) {
+	keyHash := lutils.ComputeStringHash(key)
+	rangeScan := constructRangeScan(hashedDataNs(namespace, collection), string(keyHash))
+	dbItr, err := q.levelDB.GetIterator(rangeScan.startKey, rangeScan.endKey)
+	if err != nil {
+		return nil, err
+	}
+	// move the cursor to the end of the entries so that the entries are iterated in the order of newest to oldest
+	if dbItr.Last() {
+		dbItr.Next()
+	}
+	return &historyScanner{
+		rangeScan:  rangeScan,
+		namespace:  namespace,
+		collection: collection,
+		key:        key,
+		keyHash:    keyHash,
+		dbItr:      dbItr,
+		blockStore: q.blockStore,
+	}, nil
+}
+
+func validateHistoryQueryOptions(options *ledger.HistoryQueryOptions) error {
+	if options.EndBlock > 0 && options.StartBlock > options.EndBlock {
+		return errors.Errorf("start block [%d] is greater than end block [%d]", options.StartBlock, options.EndBlock)
+	}
+	if !options.StartTime.IsZero() && !options.EndTime.IsZero() && options.StartTime.After(options.EndTime) {
+		return errors.Errorf("start time [%s] is after end time [%s]", options.StartTime, options.EndTime)
+	}
+	if options.PageSize < 0 {
+		return errors.Errorf("invalid page size [%d]", options.PageSize)
+	}
+	return nil
 }
 
-//historyScanner implements ResultsIterator for iterating through history results
+//historyScanner implements ResultsIterator for iterating through history results.
+// If the collection is set, the scanner iterates through the history of the hashed writes of a private data key
 type historyScanner struct {
 	rangeScan  *rangeScan
 	namespace  string
+	collection string
 	key        string
+	keyHash    []byte
 	dbItr      iterator.Iterator
 	blockStore *blkstorage.BlockStore
+
+	startTime, endTime time.Time
+	pageSize           int32
+	numReturned        int32
+	lastReturnedKey    []byte
 }
 
 // Next iterates to the next key, in the order of newest to oldest, from history scanner.
 // It decodes blockNumTranNumBytes to get blockNum and tranNum,
 // loads the block:tran from block storage, finds the key and returns the result.
+// The modifications made by the transactions whose timestamps are not within the time range are skipped
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
-	// call Prev because history query result is returned from newest to oldest
-	if !scanner.dbItr.Prev() {
+	if scanner.pageSize > 0 && scanner.numReturned >= scanner.pageSize {
 		return nil, nil
 	}
+	for {
+		// call Prev because history query result is returned from newest to oldest
+		if !scanner.dbItr.Prev() {
+			return nil, nil
+		}
+		queryResult, err := scanner.keyModification(scanner.dbItr.Key())
+		if err != nil {
+			return nil, err
+		}
+		if !scanner.isWithinTimeRange(queryResult) {
+			continue
+		}
+		scanner.numReturned++
+		scanner.lastReturnedKey = append(scanner.lastReturnedKey[:0], scanner.dbItr.Key()...)
+		return queryResult, nil
+	}
+}
 
-	historyKey := scanner.dbItr.Key()
+func (scanner *historyScanner) keyModification(historyKey []byte) (*queryresult.KeyModification, error) {
 	blockNum, tranNum, err := scanner.rangeScan.decodeBlockNumTranNum(historyKey)
 	if err != nil {
 		return nil, err
@@ -69,23 +175,73 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 
 	// Get the transaction from block storage that is associated with this history record
 	tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+	if errors.Cause(err) == blkstorage.ErrBlockPruned {
+		return nil, errors.WithMessagef(err,
+			"the history of the key [%s] beyond block [%d] is not available as the block has been pruned, query with a start block greater than [%d]",
+			scanner.key, blockNum, blockNum)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
-	queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+	// Get the txid, key write value (or value hash), timestamp, and delete indicator associated with this transaction
+	var queryResult commonledger.QueryResult
+	if scanner.collection == "" {
+		queryResult, err = getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+	} else {
+		queryResult, err = getKeyHashModificationFromTran(tranEnvelope, scanner.namespace, scanner.collection, scanner.keyHash)
+	}
 	if err != nil {
 		return nil, err
 	}
 	if queryResult == nil {
 		// should not happen, but make sure there is inconsistency between historydb and statedb
-		logger.Errorf("No namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
-		return nil, errors.Errorf("no namespace or key is found for namespace %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.key, blockNum, tranNum)
+		logger.Errorf("No namespace or key is found for namespace %s collection %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.collection, scanner.key, blockNum, tranNum)
+		return nil, errors.Errorf("no namespace or key is found for namespace %s collection %s and key %s with decoded blockNum %d and tranNum %d", scanner.namespace, scanner.collection, scanner.key, blockNum, tranNum)
 	}
+	keyModification := queryResult.(*queryresult.KeyModification)
 	logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s",
-		scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
-	return queryResult, nil
+		scanner.namespace, scanner.key, keyModification.TxId)
+	return keyModification, nil
+}
+
+func (scanner *historyScanner) isWithinTimeRange(keyModification *queryresult.KeyModification) bool {
+	if scanner.startTime.IsZero() && scanner.endTime.IsZero() {
+		return true
+	}
+	txTime := time.Unix(keyModification.Timestamp.GetSeconds(), int64(keyModification.Timestamp.GetNanos()))
+	if !scanner.startTime.IsZero() && txTime.Before(scanner.startTime) {
+		return false
+	}
+	if !scanner.endTime.IsZero() && txTime.After(scanner.endTime) {
+		return false
+	}
+	return true
+}
+
+// GetBookmarkAndClose returns the bookmark for retrieving the next page and closes the scanner.
+// An empty bookmark is returned if there are no more entries within the time range to scan
+func (scanner *historyScanner) GetBookmarkAndClose() string {
+	defer scanner.Close()
+	if scanner.pageSize == 0 || scanner.numReturned < scanner.pageSize || !scanner.hasMore() {
+		return ""
+	}
+	return scanner.rangeScan.encodeBookmark(scanner.lastReturnedKey)
+}
+
+// hasMore scans ahead for an older entry within the time range so that a bookmark never leads to an empty page.
+// If an entry cannot be loaded, it is assumed to be within the range and the error surfaces on the next page
+func (scanner *historyScanner) hasMore() bool {
+	for scanner.dbItr.Prev() {
+		if scanner.startTime.IsZero() && scanner.endTime.IsZero() {
+			return true
+		}
+		queryResult, err := scanner.keyModification(scanner.dbItr.Key())
+		if err != nil || scanner.isWithinTimeRange(queryResult) {
+			return true
+		}
+	}
+	return false
 }
 
 func (scanner *historyScanner) Close() {
@@ -96,52 +252,90 @@ func (scanner *historyScanner) Close() {
 func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran %s:%s", namespace, key)
 
+	chdr, txRWSet, err := unmarshalTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	// look for the namespace and key by looping through the transaction's ReadWriteSets
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == namespace {
+			// got the correct namespace, now find the key write
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if kvWrite.Key == key {
+					return &queryresult.KeyModification{TxId: chdr.TxId, Value: kvWrite.Value,
+						Timestamp: chdr.Timestamp, IsDelete: kvWrite.IsDelete}, nil
+				}
+			} // end keys loop
+			logger.Debugf("key [%s] not found in namespace [%s]'s writeset", key, namespace)
+			return nil, nil
+		} // end if
+	} //end namespaces loop
+	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
+	return nil, nil
+}
+
+// getKeyHashModificationFromTran inspects a transaction for hashed writes to a given key hash of a private data collection.
+// The value hash is returned as the value of the KeyModification
+func getKeyHashModificationFromTran(tranEnvelope *common.Envelope, namespace, collection string, keyHash []byte) (commonledger.QueryResult, error) {
+	logger.Debugf("Entering getKeyHashModificationFromTran %s:%s:%x", namespace, collection, keyHash)
+
+	chdr, txRWSet, err := unmarshalTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName != collection {
+				continue
+			}
+			for _, hashedWrite := range collHashedRWSet.HashedRwSet.HashedWrites {
+				if bytes.Equal(hashedWrite.KeyHash, keyHash) {
+					return &queryresult.KeyModification{TxId: chdr.TxId, Value: hashedWrite.ValueHash,
+						Timestamp: chdr.Timestamp, IsDelete: hashedWrite.IsDelete}, nil
+				}
+			}
+			logger.Debugf("key hash [%x] not found in the hashed writeset of namespace [%s] and collection [%s]", keyHash, namespace, collection)
+			return nil, nil
+		}
+	}
+	logger.Debugf("namespace [%s] and collection [%s] not found in transaction's ReadWriteSets", namespace, collection)
+	return nil, nil
+}
+
+// unmarshalTran returns the channel header and the read-write set of an endorser transaction
+func unmarshalTran(tranEnvelope *common.Envelope) (*common.ChannelHeader, *rwsetutil.TxRwSet, error) {
 	// extract action from the envelope
 	payload, err := protoutil.UnmarshalPayload(tranEnvelope.Payload)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	tx, err := protoutil.UnmarshalTransaction(payload.Data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	_, respPayload, err := protoutil.GetPayloads(tx.Actions[0])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	txID := chdr.TxId
-	timestamp := chdr.Timestamp
-
 	txRWSet := &rwsetutil.TxRwSet{}
 
 	// Get the Result from the Action and then Unmarshal
 	// it into a TxReadWriteSet using custom unmarshalling
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// look for the namespace and key by looping through the transaction's ReadWriteSets
-	for _, nsRWSet := range txRWSet.NsRwSets {
-		if nsRWSet.NameSpace == namespace {
-			// got the correct namespace, now find the key write
-			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
-				if kvWrite.Key == key {
-					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
-						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
-				}
-			} // end keys loop
-			logger.Debugf("key [%s] not found in namespace [%s]'s writeset", key, namespace)
-			return nil, nil
-		} // end if
-	} //end namespaces loop
-	logger.Debugf("namespace [%s] not found in transaction's ReadWriteSets", namespace)
-	return nil, nil
+	return chdr, txRWSet, nil
 }
@@ -139,10 +139,20 @@ func (p *Provider) initLedgerIDInventory() error {
 
 func (p *Provider) initBlockStoreProvider() error {
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	compression, err := blockStoreCompression(p.initializer.Config.BlockStoreConfig)
+	if err != nil {
+		return err
+	}
+	archiveConf, err := blockStoreArchive(p.initializer.Config.BlockStoreConfig)
+	if err != nil {
+		return err
+	}
 	blkStoreProvider, err := blkstorage.NewProvider(
-		blkstorage.NewConf(
+		blkstorage.NewConfWithArchive(
 			BlockStorePath(p.initializer.Config.RootFSPath),
 			maxBlockFileSize,
+			compression,
+			archiveConf,
 		),
 		indexConfig,
 		p.initializer.MetricsProvider,
@@ -154,6 +164,54 @@ func (p *Provider) initBlockStoreProvider() error {
 	return nil
 }
 
+func blockStoreCompression(blockStoreConfig *ledger.BlockStoreConfig) (blkstorage.Compression, error) {
+	if blockStoreConfig == nil {
+		return blkstorage.NoCompression, nil
+	}
+	return blkstorage.ParseCompression(blockStoreConfig.Compression)
+}
+
+func blockStoreArchive(blockStoreConfig *ledger.BlockStoreConfig) (*blkstorage.ArchiveConf, error) {
+	if blockStoreConfig == nil || blockStoreConfig.Archive == nil {
+		return nil, nil
+	}
+	archiveConfig := blockStoreConfig.Archive
+	var store blkstorage.ArchiveStore
+	var err error
+	switch archiveConfig.Type {
+	case "localdir":
+		if archiveConfig.LocalDir == "" {
+			return nil, errors.New("the dir for the block archive of type [localdir] is not specified")
+		}
+		store, err = blkstorage.NewLocalDirArchiveStore(archiveConfig.LocalDir)
+	case "s3":
+		s3Config := archiveConfig.S3
+		if s3Config == nil {
+			s3Config = &ledger.BlockArchiveS3Config{}
+		}
+		store, err = blkstorage.NewS3ArchiveStore(&blkstorage.S3ArchiveConfig{
+			Endpoint:        s3Config.Endpoint,
+			Region:          s3Config.Region,
+			Bucket:          s3Config.Bucket,
+			Prefix:          s3Config.Prefix,
+			AccessKeyID:     s3Config.AccessKeyID,
+			SecretAccessKey: s3Config.SecretAccessKey,
+			Timeout:         s3Config.Timeout,
+		})
+	default:
+		return nil, errors.Errorf("unsupported block archive type [%s], the supported types are [localdir] and [s3]", archiveConfig.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &blkstorage.ArchiveConf{
+		Store:      store,
+		LocalFiles: archiveConfig.LocalFiles,
+		CacheDir:   archiveConfig.CacheDir,
+		CacheSize:  archiveConfig.CacheSize,
+	}, nil
+}
+
 func (p *Provider) initPvtDataStoreProvider() error {
 	privateDataConfig := &pvtdatastorage.PrivateDataConfig{
 		PrivateDataConfig: p.initializer.Config.PrivateDataConfig,
@@ -567,6 +567,58 @@ func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
 
+func TestBlockStoreArchiveConfig(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+
+	testCases := []struct {
+		archiveConfig *lgr.BlockArchiveConfig
+		expectedErr   string
+	}{
+		{
+			archiveConfig: &lgr.BlockArchiveConfig{Type: "tape"},
+			expectedErr:   "unsupported block archive type [tape], the supported types are [localdir] and [s3]",
+		},
+		{
+			archiveConfig: &lgr.BlockArchiveConfig{Type: "localdir"},
+			expectedErr:   "the dir for the block archive of type [localdir] is not specified",
+		},
+		{
+			archiveConfig: &lgr.BlockArchiveConfig{Type: "s3"},
+			expectedErr:   "invalid S3 endpoint [], expected an http or https URL",
+		},
+		{
+			archiveConfig: &lgr.BlockArchiveConfig{Type: "s3", S3: &lgr.BlockArchiveS3Config{Endpoint: "http://minio:9000"}},
+			expectedErr:   "S3 bucket is not specified",
+		},
+	}
+	for _, tc := range testCases {
+		conf.BlockStoreConfig = &lgr.BlockStoreConfig{Archive: tc.archiveConfig}
+		_, err := NewProvider(&lgr.Initializer{Config: conf})
+		require.EqualError(t, err, tc.expectedErr)
+	}
+
+	archiveDir := filepath.Join(conf.RootFSPath, "archive")
+	conf.BlockStoreConfig = &lgr.BlockStoreConfig{
+		Archive: &lgr.BlockArchiveConfig{Type: "localdir", LocalDir: archiveDir, LocalFiles: 2, CacheSize: 3},
+	}
+	archiveConf, err := blockStoreArchive(conf.BlockStoreConfig)
+	require.NoError(t, err)
+	require.IsType(t, &blkstorage.LocalDirArchiveStore{}, archiveConf.Store)
+	require.Equal(t, 2, archiveConf.LocalFiles)
+	require.Equal(t, 3, archiveConf.CacheSize)
+
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	bcInfo, err := l.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), bcInfo.Height)
+	require.DirExists(t, archiveDir)
+}
+
 func testConfig(t *testing.T) (conf *lgr.Config, cleanup func()) {
 	path, err := ioutil.TempDir("", "kvledger")
 	require.NoError(t, err, "Failed to create test ledger directory")
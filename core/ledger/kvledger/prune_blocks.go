@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// PruneBlockStore deletes the block files of a ledger that contain only the blocks below the height
+// of the last snapshot generated for the ledger. The pruned blocks can no longer be retrieved from this peer
+func PruneBlockStore(config *ledger.Config, ledgerID string) error {
+	fileLockPath := fileLockPath(config.RootFSPath)
+	fileLock := leveldbhelper.NewFileLock(fileLockPath)
+	if err := fileLock.Lock(); err != nil {
+		return errors.Wrap(err, "as another peer node command is executing,"+
+			" wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	lastSnapshotBlockNum, found, err := lastSnapshotBlockNum(config.SnapshotsConfig.RootDir, ledgerID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errors.Errorf("cannot prune the blocks of the channel [%s] as no snapshot is found for the channel", ledgerID)
+	}
+
+	logger.Infof("Pruning the blocks of the channel [%s] below the last snapshot height [%d]", ledgerID, lastSnapshotBlockNum+1)
+	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	firstBlockNum, err := blkstorage.PruneBlocks(BlockStorePath(config.RootFSPath), ledgerID, lastSnapshotBlockNum+1, indexConfig)
+	if err != nil {
+		return err
+	}
+	logger.Infof("The blocks of the channel [%s] have been successfully pruned, first available block = [%d]", ledgerID, firstBlockNum)
+	return nil
+}
+
+// lastSnapshotBlockNum returns the highest block number for which a completed snapshot is present for the ledger
+func lastSnapshotBlockNum(snapshotsRootDir, ledgerID string) (uint64, bool, error) {
+	blockNums, err := snapshotBlockNums(snapshotsRootDir, ledgerID)
+	if err != nil || len(blockNums) == 0 {
+		return 0, false, err
+	}
+	return blockNums[len(blockNums)-1], true, nil
+}
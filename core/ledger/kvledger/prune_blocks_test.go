@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneBlockStore(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	l, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	block1 := bg.NextBlock([][]byte{[]byte("tx1"), []byte("tx2")})
+	require.NoError(t, l.CommitLegacy(&lgr.BlockAndPvtData{Block: block1}, &lgr.CommitOptions{}))
+
+	// pruning should fail when provider is still open
+	err = PruneBlockStore(conf, "testLedger")
+	require.EqualError(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying: lock is already acquired on file "+fileLockPath(conf.RootFSPath))
+	provider.Close()
+
+	err = PruneBlockStore(conf, "testLedger")
+	require.EqualError(t, err, "cannot prune the blocks of the channel [testLedger] as no snapshot is found for the channel")
+
+	require.NoError(t, os.MkdirAll(SnapshotDirForLedgerBlockNum(conf.SnapshotsConfig.RootDir, "testLedger", 0), 0755))
+	require.NoError(t, os.MkdirAll(SnapshotDirForLedgerBlockNum(conf.SnapshotsConfig.RootDir, "testLedger", 1), 0755))
+	lastBlockNum, found, err := lastSnapshotBlockNum(conf.SnapshotsConfig.RootDir, "testLedger")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, uint64(1), lastBlockNum)
+
+	// the blocks share the latest block file and hence, no block is pruned
+	require.NoError(t, PruneBlockStore(conf, "testLedger"))
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	l, err = provider.Open("testLedger")
+	require.NoError(t, err)
+	b, err := l.GetBlockByNumber(1)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(block1, b), "proto messages are not equal")
+}
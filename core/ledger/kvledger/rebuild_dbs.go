@@ -35,6 +35,14 @@ func RebuildDBs(config *ledger.Config) error {
 		return errors.Errorf("cannot rebuild databases because the peer contains channel(s) %s that were bootstrapped from snapshot", ledgerIDs)
 	}
 
+	prunedLedgerIDs, err := blkstorage.GetLedgersWithPrunedBlocks(blockstorePath)
+	if err != nil {
+		return errors.WithMessage(err, "error while checking if any ledger has pruned blocks")
+	}
+	if len(prunedLedgerIDs) > 0 {
+		return errors.Errorf("cannot rebuild databases because the peer contains channel(s) %s whose blocks were pruned", prunedLedgerIDs)
+	}
+
 	if config.StateDBConfig.StateDatabase == ledger.CouchDB {
 		if err := statecouchdb.DropApplicationDBs(config.StateDBConfig.CouchDB); err != nil {
 			return err
@@ -31,6 +31,22 @@ func ResetAllKVLedgers(rootFSPath string) error {
 		return errors.Errorf("cannot reset channels because the peer contains channel(s) %s that were bootstrapped from snapshot", ledgerIDs)
 	}
 
+	prunedLedgerIDs, err := blkstorage.GetLedgersWithPrunedBlocks(blockstorePath)
+	if err != nil {
+		return err
+	}
+	if len(prunedLedgerIDs) > 0 {
+		return errors.Errorf("cannot reset channels because the peer contains channel(s) %s whose blocks were pruned", prunedLedgerIDs)
+	}
+
+	archivedLedgerIDs, err := blkstorage.GetLedgersWithArchivedBlocks(blockstorePath)
+	if err != nil {
+		return err
+	}
+	if len(archivedLedgerIDs) > 0 {
+		return errors.Errorf("cannot reset channels because the peer contains channel(s) %s whose blocks were archived", archivedLedgerIDs)
+	}
+
 	logger.Info("Resetting all channel ledgers to genesis block")
 	logger.Infof("Ledger data folder from config = [%s]", rootFSPath)
 	if err := dropDBs(rootFSPath); err != nil {
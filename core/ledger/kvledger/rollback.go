@@ -31,6 +31,22 @@ func RollbackKVLedger(rootFSPath, ledgerID string, blockNum uint64) error {
 		return errors.Errorf("cannot rollback any channel because the peer contains channel(s) %s that were bootstrapped from snapshot", ledgerIDs)
 	}
 
+	prunedLedgerIDs, err := blkstorage.GetLedgersWithPrunedBlocks(blockstorePath)
+	if err != nil {
+		return errors.WithMessage(err, "error while checking if any ledger has pruned blocks")
+	}
+	if len(prunedLedgerIDs) > 0 {
+		return errors.Errorf("cannot rollback any channel because the peer contains channel(s) %s whose blocks were pruned", prunedLedgerIDs)
+	}
+
+	archivedLedgerIDs, err := blkstorage.GetLedgersWithArchivedBlocks(blockstorePath)
+	if err != nil {
+		return errors.WithMessage(err, "error while checking if any ledger has archived blocks")
+	}
+	if len(archivedLedgerIDs) > 0 {
+		return errors.Errorf("cannot rollback any channel because the peer contains channel(s) %s whose blocks were archived", archivedLedgerIDs)
+	}
+
 	if err := blkstorage.ValidateRollbackParams(blockstorePath, ledgerID, blockNum); err != nil {
 		return err
 	}
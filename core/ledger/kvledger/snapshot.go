@@ -17,6 +17,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
@@ -38,10 +39,10 @@ const (
 	simpleKeyValueDB                   = "SimpleKeyValueDB"
 )
 
-// snapshotSignableMetadata is used to build a JSON that represents a unique snapshot and
+// SnapshotSignableMetadata is used to build a JSON that represents a unique snapshot and
 // can be signed by the peer. Hashsum of the resultant JSON is intended to be used as a single
 // hash of the snapshot, if need be.
-type snapshotSignableMetadata struct {
+type SnapshotSignableMetadata struct {
 	ChannelName            string            `json:"channel_name"`
 	LastBlockNumber        uint64            `json:"last_block_number"`
 	LastBlockHashInHex     string            `json:"last_block_hash"`
@@ -50,7 +51,7 @@ type snapshotSignableMetadata struct {
 	StateDBType            string            `json:"state_db_type"`
 }
 
-func (m *snapshotSignableMetadata) toJSON() ([]byte, error) {
+func (m *SnapshotSignableMetadata) toJSON() ([]byte, error) {
 	return json.MarshalIndent(m, "", jsonFileIndent)
 }
 
@@ -64,7 +65,7 @@ func (m *snapshotAdditionalMetadata) toJSON() ([]byte, error) {
 }
 
 type snapshotMetadata struct {
-	*snapshotSignableMetadata
+	*SnapshotSignableMetadata
 	*snapshotAdditionalMetadata
 }
 
@@ -74,7 +75,7 @@ type snapshotMetadataJSONs struct {
 }
 
 func (j *snapshotMetadataJSONs) toMetadata() (*snapshotMetadata, error) {
-	metadata := &snapshotSignableMetadata{}
+	metadata := &SnapshotSignableMetadata{}
 	if err := json.Unmarshal([]byte(j.signableMetadata), metadata); err != nil {
 		return nil, errors.Wrap(err, "error while unmarshaling signable metadata")
 	}
@@ -84,7 +85,7 @@ func (j *snapshotMetadataJSONs) toMetadata() (*snapshotMetadata, error) {
 		return nil, errors.Wrap(err, "error while unmarshaling additional metadata")
 	}
 	return &snapshotMetadata{
-		snapshotSignableMetadata:   metadata,
+		SnapshotSignableMetadata:   metadata,
 		snapshotAdditionalMetadata: additionalMetadata,
 	}, nil
 }
@@ -107,27 +108,49 @@ func (l *kvLedger) generateSnapshot() error {
 	}
 	defer os.RemoveAll(snapshotTempDir)
 
+	estimatedTotalBytes, err := lastSnapshotSize(snapshotsRootDir, l.ledgerID)
+	if err != nil {
+		logger.Warnw("Failed to estimate the size of the snapshot", "channelID", l.ledgerID, "error", err)
+	}
+	progress := newSnapshotProgressTracker(lastBlockNum, time.Now(), estimatedTotalBytes)
+	l.snapshotMgr.setProgressTracker(progress)
+	defer l.snapshotMgr.setProgressTracker(nil)
+
 	newHashFunc := func() (hash.Hash, error) {
 		return l.hashProvider.GetHash(snapshotHashOpts)
 	}
 
-	txIDsExportSummary, err := l.blockStore.ExportTxIds(snapshotTempDir, newHashFunc)
+	txIDsProgress := progress.of(ledger.SnapshotPhaseTxIDs)
+	progress.setStatus(ledger.SnapshotPhaseInProgress, ledger.SnapshotPhaseTxIDs)
+	txIDsExportSummary, err := l.blockStore.ExportTxIds(snapshotTempDir, newHashFunc, txIDsProgress)
 	if err != nil {
 		return err
 	}
-	logger.Debugw("Exported TxIDs from blockstore", "channelID", l.ledgerID)
+	progress.setStatus(ledger.SnapshotPhaseDone, ledger.SnapshotPhaseTxIDs)
+	logger.Infow("Exported TxIDs from blockstore", "channelID", l.ledgerID,
+		"records", txIDsProgress.Records(), "bytes", txIDsProgress.Bytes())
 
-	configsHistoryExportSummary, err := l.configHistoryRetriever.ExportConfigHistory(snapshotTempDir, newHashFunc)
+	configHistoryProgress := progress.of(ledger.SnapshotPhaseConfigHistory)
+	progress.setStatus(ledger.SnapshotPhaseInProgress, ledger.SnapshotPhaseConfigHistory)
+	configsHistoryExportSummary, err := l.configHistoryRetriever.ExportConfigHistory(snapshotTempDir, newHashFunc, configHistoryProgress)
 	if err != nil {
 		return err
 	}
-	logger.Debugw("Exported collection config history", "channelID", l.ledgerID)
+	progress.setStatus(ledger.SnapshotPhaseDone, ledger.SnapshotPhaseConfigHistory)
+	logger.Infow("Exported collection config history", "channelID", l.ledgerID,
+		"records", configHistoryProgress.Records(), "bytes", configHistoryProgress.Bytes())
 
-	stateDBExportSummary, err := l.txmgr.ExportPubStateAndPvtStateHashes(snapshotTempDir, newHashFunc)
+	pubStateProgress := progress.of(ledger.SnapshotPhasePublicState)
+	pvtStateHashesProgress := progress.of(ledger.SnapshotPhasePrivateStateHashes)
+	progress.setStatus(ledger.SnapshotPhaseInProgress, ledger.SnapshotPhasePublicState, ledger.SnapshotPhasePrivateStateHashes)
+	stateDBExportSummary, err := l.txmgr.ExportPubStateAndPvtStateHashes(snapshotTempDir, newHashFunc, pubStateProgress, pvtStateHashesProgress)
 	if err != nil {
 		return err
 	}
-	logger.Debugw("Exported public state and private state hashes", "channelID", l.ledgerID)
+	progress.setStatus(ledger.SnapshotPhaseDone, ledger.SnapshotPhasePublicState, ledger.SnapshotPhasePrivateStateHashes)
+	logger.Infow("Exported public state and private state hashes", "channelID", l.ledgerID,
+		"publicStateRecords", pubStateProgress.Records(), "publicStateBytes", pubStateProgress.Bytes(),
+		"privateStateHashesRecords", pvtStateHashesProgress.Records(), "privateStateHashesBytes", pvtStateHashesProgress.Bytes())
 
 	if err := l.generateSnapshotMetadataFiles(
 		snapshotTempDir, txIDsExportSummary,
@@ -179,7 +202,7 @@ func (l *kvLedger) generateSnapshotMetadataFiles(
 	if stateDBType != ledger.CouchDB {
 		stateDBType = simpleKeyValueDB
 	}
-	signableMetadata := &snapshotSignableMetadata{
+	signableMetadata := &SnapshotSignableMetadata{
 		ChannelName:            l.ledgerID,
 		LastBlockNumber:        bcInfo.Height - 1,
 		LastBlockHashInHex:     hex.EncodeToString(bcInfo.CurrentBlockHash),
@@ -366,6 +389,19 @@ func loadSnapshotMetadataJSONs(snapshotDir string) (*snapshotMetadataJSONs, erro
 	}, nil
 }
 
+// LoadSnapshotSignableMetadata loads the signable metadata of the snapshot present in the snapshotDir
+func LoadSnapshotSignableMetadata(snapshotDir string) (*SnapshotSignableMetadata, error) {
+	signableMetadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotSignableMetadataFileName))
+	if err != nil {
+		return nil, errors.Wrap(err, "error while reading signable metadata")
+	}
+	metadata := &SnapshotSignableMetadata{}
+	if err := json.Unmarshal(signableMetadataBytes, metadata); err != nil {
+		return nil, errors.Wrap(err, "error while unmarshaling signable metadata")
+	}
+	return metadata, nil
+}
+
 func verifySnapshot(snapshotDir string, snapshotMetadata *snapshotMetadata, hashProvider ledger.HashProvider) error {
 	if err := verifyFileHash(
 		snapshotDir,
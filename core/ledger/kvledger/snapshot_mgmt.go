@@ -10,6 +10,7 @@ import (
 	"math"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -42,6 +43,10 @@ type snapshotMgr struct {
 	requestResponses          chan *requestResponse
 	stopped                   bool
 	shutdownLock              sync.Mutex
+
+	// progressTracker tracks the progress of the snapshot being generated, if any
+	progressLock    sync.Mutex
+	progressTracker *snapshotProgressTracker
 }
 
 type event struct {
@@ -87,7 +92,7 @@ func (l *kvLedger) PendingSnapshotRequests() ([]uint64, error) {
 // - requestResponses: a channel returning the response for snapshot request submission/cancellation.
 // The 5 events are:
 // - commitStart: sent before committing a block
-// - commitDone: sent after a block is committed
+// - commitDone: sent after a block is committed, which adds a snapshot request for the block if the snapshot policy calls for it
 // - snapshotDone: sent when a snapshot generation is finished, regardless of success or failure
 // - requestAdd: sent when a snapshot request is submitted
 // - requestCancel: sent when a snapshot request is cancelled
@@ -103,6 +108,17 @@ func (l *kvLedger) processSnapshotMgmtEvents(lastCommittedBlockNumber uint64) {
 	commitProceed := l.snapshotMgr.commitProceed
 	requestResponses := l.snapshotMgr.requestResponses
 
+	// lastSnapshotStartTime is the time at which the generation of the most recent snapshot started, which is used
+	// for the time interval of the snapshot policy. When the ledger has no snapshot, the interval starts now
+	snapshotPolicy := l.config.SnapshotsConfig.Policy
+	lastSnapshotStartTime, err := lastSnapshotTime(l.config.SnapshotsConfig.RootDir, l.ledgerID)
+	if err != nil {
+		logger.Warnw("Failed to get the time of the last snapshot", "channelID", l.ledgerID, "error", err)
+	}
+	if lastSnapshotStartTime.IsZero() {
+		lastSnapshotStartTime = time.Now()
+	}
+
 	for {
 		e := <-events
 		logger.Debugw("Event received", "channelID", l.ledgerID, "type", e.typ, "blockNumber", e.blockNumber, "snapshotInProgress=", snapshotInProgress)
@@ -120,16 +136,23 @@ func (l *kvLedger) processSnapshotMgmtEvents(lastCommittedBlockNumber uint64) {
 		case commitDone:
 			lastCommittedBlockNumber = e.blockNumber
 			committerStatus = idle
+			if snapshotDueAsPerPolicy(snapshotPolicy, lastCommittedBlockNumber, lastSnapshotStartTime, time.Now()) {
+				if err := l.addSnapshotRequestAsPerPolicy(lastCommittedBlockNumber); err != nil {
+					logger.Errorw("Failed to add snapshot request as per the snapshot policy", "channelID", l.ledgerID, "blockNumber", lastCommittedBlockNumber, "error", err)
+				}
+			}
 			if lastCommittedBlockNumber != l.snapshotMgr.snapshotRequestBookkeeper.smallestRequestBlockNum {
 				continue
 			}
 			snapshotInProgress = true
+			lastSnapshotStartTime = time.Now()
 			go func() {
 				logger.Infow("Generating snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber)
 				if err := l.generateSnapshot(); err != nil {
 					logger.Errorw("Failed to generate snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber, "error", err)
 				} else {
 					logger.Infow("Generated snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber)
+					l.pruneSnapshotsAsPerPolicy()
 				}
 				events <- &event{snapshotDone, lastCommittedBlockNumber}
 			}()
@@ -184,12 +207,14 @@ func (l *kvLedger) processSnapshotMgmtEvents(lastCommittedBlockNumber uint64) {
 
 			if committerStatus == idle && requestedBlockNum == lastCommittedBlockNumber {
 				snapshotInProgress = true
+				lastSnapshotStartTime = time.Now()
 				go func() {
 					logger.Infow("Generating snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber)
 					if err := l.generateSnapshot(); err != nil {
 						logger.Errorw("Failed to generate snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber, "error", err)
 					} else {
 						logger.Infow("Generated snapshot", "channelID", l.ledgerID, "lastCommittedBlockNumber", lastCommittedBlockNumber)
+						l.pruneSnapshotsAsPerPolicy()
 					}
 					events <- &event{snapshotDone, requestedBlockNum}
 				}()
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// snapshotDueAsPerPolicy returns true if the policy calls for a snapshot of the given block, given the time at
+// which the generation of the previous snapshot started
+func snapshotDueAsPerPolicy(policy *ledger.SnapshotPolicyConfig, blockNum uint64, lastSnapshotTime, now time.Time) bool {
+	if policy == nil || blockNum == 0 {
+		return false
+	}
+	if policy.BlockInterval > 0 && blockNum%policy.BlockInterval == 0 {
+		return true
+	}
+	return policy.TimeInterval > 0 && now.Sub(lastSnapshotTime) >= policy.TimeInterval
+}
+
+// addSnapshotRequestAsPerPolicy adds a snapshot request for the given block number, unless a request is already present.
+// This is invoked only from the goroutine that processes the snapshot management events
+func (l *kvLedger) addSnapshotRequestAsPerPolicy(blockNum uint64) error {
+	bookkeeper := l.snapshotMgr.snapshotRequestBookkeeper
+	exists, err := bookkeeper.exist(blockNum)
+	if err != nil || exists {
+		return err
+	}
+	logger.Infow("Adding snapshot request as per the snapshot policy", "channelID", l.ledgerID, "blockNumber", blockNum)
+	return bookkeeper.add(blockNum)
+}
+
+// pruneSnapshotsAsPerPolicy deletes the completed snapshots of the ledger beyond the number of snapshots to be retained
+func (l *kvLedger) pruneSnapshotsAsPerPolicy() {
+	policy := l.config.SnapshotsConfig.Policy
+	if policy == nil || policy.Retain <= 0 {
+		return
+	}
+	if err := pruneSnapshots(l.config.SnapshotsConfig.RootDir, l.ledgerID, policy.Retain); err != nil {
+		logger.Errorw("Failed to delete the snapshots beyond the retention", "channelID", l.ledgerID, "retain", policy.Retain, "error", err)
+	}
+}
+
+// pruneSnapshots deletes all but the most recent `retain` completed snapshots of the ledger
+func pruneSnapshots(snapshotsRootDir, ledgerID string, retain int) error {
+	blockNums, err := snapshotBlockNums(snapshotsRootDir, ledgerID)
+	if err != nil {
+		return err
+	}
+	if len(blockNums) <= retain {
+		return nil
+	}
+	for _, blockNum := range blockNums[:len(blockNums)-retain] {
+		snapshotDir := SnapshotDirForLedgerBlockNum(snapshotsRootDir, ledgerID, blockNum)
+		if err := os.RemoveAll(snapshotDir); err != nil {
+			return errors.Wrapf(err, "error while deleting the snapshot dir [%s]", snapshotDir)
+		}
+		logger.Infow("Deleted snapshot as per the snapshot retention policy", "channelID", ledgerID, "blockNumber", blockNum)
+	}
+	return nil
+}
+
+// lastSnapshotTime returns the time at which the most recent completed snapshot of the ledger was generated,
+// or the zero time if no snapshot is present
+func lastSnapshotTime(snapshotsRootDir, ledgerID string) (time.Time, error) {
+	blockNum, found, err := lastSnapshotBlockNum(snapshotsRootDir, ledgerID)
+	if err != nil || !found {
+		return time.Time{}, err
+	}
+	snapshotDir := SnapshotDirForLedgerBlockNum(snapshotsRootDir, ledgerID, blockNum)
+	stat, err := os.Stat(snapshotDir)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "error while reading the snapshot dir [%s]", snapshotDir)
+	}
+	return stat.ModTime(), nil
+}
+
+// snapshotBlockNums returns the block numbers of the completed snapshots of the ledger in increasing order
+func snapshotBlockNums(snapshotsRootDir, ledgerID string) ([]uint64, error) {
+	snapshotsDir := SnapshotsDirForLedger(snapshotsRootDir, ledgerID)
+	entries, err := ioutil.ReadDir(snapshotsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error while reading the snapshots dir [%s]", snapshotsDir)
+	}
+	var blockNums []uint64
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		blockNum, err := strconv.ParseUint(e.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		blockNums = append(blockNums, blockNum)
+	}
+	sort.Slice(blockNums, func(i, j int) bool { return blockNums[i] < blockNums[j] })
+	return blockNums, nil
+}
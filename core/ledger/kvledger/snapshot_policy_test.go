@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotDueAsPerPolicy(t *testing.T) {
+	lastSnapshotTime := time.Unix(1000, 0)
+	tests := []struct {
+		name     string
+		policy   *ledger.SnapshotPolicyConfig
+		blockNum uint64
+		elapsed  time.Duration
+		due      bool
+	}{
+		{name: "no policy", policy: nil, blockNum: 10, elapsed: time.Hour, due: false},
+		{name: "genesis block", policy: &ledger.SnapshotPolicyConfig{BlockInterval: 1, TimeInterval: time.Minute}, blockNum: 0, elapsed: time.Hour, due: false},
+		{name: "block interval reached", policy: &ledger.SnapshotPolicyConfig{BlockInterval: 5}, blockNum: 10, due: true},
+		{name: "block interval not reached", policy: &ledger.SnapshotPolicyConfig{BlockInterval: 5}, blockNum: 11, elapsed: time.Hour, due: false},
+		{name: "time interval reached", policy: &ledger.SnapshotPolicyConfig{TimeInterval: time.Minute}, blockNum: 11, elapsed: time.Minute, due: true},
+		{name: "time interval not reached", policy: &ledger.SnapshotPolicyConfig{TimeInterval: time.Minute}, blockNum: 11, elapsed: time.Second, due: false},
+		{name: "either interval reached", policy: &ledger.SnapshotPolicyConfig{BlockInterval: 5, TimeInterval: time.Minute}, blockNum: 11, elapsed: time.Hour, due: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.due, snapshotDueAsPerPolicy(test.policy, test.blockNum, lastSnapshotTime, lastSnapshotTime.Add(test.elapsed)))
+		})
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	rootDir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+
+	_, err = lastSnapshotTime(rootDir, "ledger1")
+	require.NoError(t, err)
+	require.NoError(t, pruneSnapshots(rootDir, "ledger1", 2))
+
+	for _, blockNum := range []uint64{30, 5, 100, 20} {
+		require.NoError(t, os.MkdirAll(SnapshotDirForLedgerBlockNum(rootDir, "ledger1", blockNum), 0755))
+	}
+	require.NoError(t, os.MkdirAll(SnapshotDirForLedgerBlockNum(rootDir, "ledger2", 1), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(SnapshotsDirForLedger(rootDir, "ledger1"), "1"), []byte{}, 0644))
+
+	blockNums, err := snapshotBlockNums(rootDir, "ledger1")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{5, 20, 30, 100}, blockNums)
+
+	require.NoError(t, pruneSnapshots(rootDir, "ledger1", 2))
+	blockNums, err = snapshotBlockNums(rootDir, "ledger1")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{30, 100}, blockNums)
+	blockNums, err = snapshotBlockNums(rootDir, "ledger2")
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1}, blockNums)
+
+	modTime := time.Unix(1000, 0)
+	require.NoError(t, os.Chtimes(SnapshotDirForLedgerBlockNum(rootDir, "ledger1", 100), modTime, modTime))
+	snapshotTime, err := lastSnapshotTime(rootDir, "ledger1")
+	require.NoError(t, err)
+	require.True(t, modTime.Equal(snapshotTime))
+}
+
+func TestSnapshotPolicy(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	conf.SnapshotsConfig.Policy = &ledger.SnapshotPolicyConfig{
+		BlockInterval: 4,
+		Retain:        2,
+	}
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	ledgerID := "testsnapshotpolicy"
+	bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+	l, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	defer l.Close()
+
+	// a snapshot requested by the admin is subject to the retention as well
+	require.NoError(t, l.SubmitSnapshotRequest(3))
+	testutilCommitBlocks(t, l, bg, 13, protoutil.BlockHeaderHash(gb.Header))
+
+	snapshotsRetained := func() bool {
+		blockNums, err := snapshotBlockNums(conf.SnapshotsConfig.RootDir, ledgerID)
+		require.NoError(t, err)
+		requests, err := l.PendingSnapshotRequests()
+		require.NoError(t, err)
+		return len(requests) == 0 && len(blockNums) == 2 && blockNums[0] == 8 && blockNums[1] == 12
+	}
+	require.Eventually(t, snapshotsRetained, time.Minute, 100*time.Millisecond)
+
+	t.Run("time interval", func(t *testing.T) {
+		conf, cleanup := testConfig(t)
+		defer cleanup()
+		conf.SnapshotsConfig.Policy = &ledger.SnapshotPolicyConfig{
+			TimeInterval: time.Hour,
+		}
+		provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+		defer provider.Close()
+
+		bg, gb := testutil.NewBlockGenerator(t, ledgerID, false)
+		l, err := provider.CreateFromGenesisBlock(gb)
+		require.NoError(t, err)
+		defer l.Close()
+		lastBlock := testutilCommitBlocks(t, l, bg, 2, protoutil.BlockHeaderHash(gb.Header))
+		blockNums, err := snapshotBlockNums(conf.SnapshotsConfig.RootDir, ledgerID)
+		require.NoError(t, err)
+		require.Empty(t, blockNums)
+		l.Close()
+
+		// the time interval starts from the last snapshot, if any, when the ledger is opened
+		require.NoError(t, os.MkdirAll(SnapshotDirForLedgerBlockNum(conf.SnapshotsConfig.RootDir, ledgerID, 1), 0755))
+		twoHoursAgo := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(SnapshotDirForLedgerBlockNum(conf.SnapshotsConfig.RootDir, ledgerID, 1), twoHoursAgo, twoHoursAgo))
+		l, err = provider.Open(ledgerID)
+		require.NoError(t, err)
+		defer l.Close()
+		testutilCommitBlocks(t, l, bg, 4, protoutil.BlockHeaderHash(lastBlock.Header))
+
+		// a snapshot is generated for block 3, and the interval restarts
+		snapshotGenerated := func() bool {
+			blockNums, err := snapshotBlockNums(conf.SnapshotsConfig.RootDir, ledgerID)
+			require.NoError(t, err)
+			return len(blockNums) == 2 && blockNums[1] == 3
+		}
+		require.Eventually(t, snapshotGenerated, time.Minute, 100*time.Millisecond)
+	})
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/snapshot"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// snapshotPhases lists the phases of the snapshot generation in the order of execution
+var snapshotPhases = []ledger.SnapshotPhase{
+	ledger.SnapshotPhaseTxIDs,
+	ledger.SnapshotPhaseConfigHistory,
+	ledger.SnapshotPhasePublicState,
+	ledger.SnapshotPhasePrivateStateHashes,
+}
+
+// snapshotProgressTracker tracks the progress of the generation of a snapshot. The exporters update the
+// progress of the phases concurrently with the progress being reported
+type snapshotProgressTracker struct {
+	blockNum            uint64
+	startTime           time.Time
+	estimatedTotalBytes uint64
+	progress            map[ledger.SnapshotPhase]*snapshot.Progress
+
+	lock   sync.Mutex
+	status map[ledger.SnapshotPhase]ledger.SnapshotPhaseStatus
+}
+
+func newSnapshotProgressTracker(blockNum uint64, startTime time.Time, estimatedTotalBytes uint64) *snapshotProgressTracker {
+	t := &snapshotProgressTracker{
+		blockNum:            blockNum,
+		startTime:           startTime,
+		estimatedTotalBytes: estimatedTotalBytes,
+		progress:            map[ledger.SnapshotPhase]*snapshot.Progress{},
+		status:              map[ledger.SnapshotPhase]ledger.SnapshotPhaseStatus{},
+	}
+	for _, phase := range snapshotPhases {
+		t.progress[phase] = &snapshot.Progress{}
+	}
+	return t
+}
+
+// of returns the progress of the given phase, to be updated by the exporter
+func (t *snapshotProgressTracker) of(phase ledger.SnapshotPhase) *snapshot.Progress {
+	return t.progress[phase]
+}
+
+func (t *snapshotProgressTracker) setStatus(status ledger.SnapshotPhaseStatus, phases ...ledger.SnapshotPhase) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, phase := range phases {
+		t.status[phase] = status
+	}
+}
+
+func (t *snapshotProgressTracker) report(now time.Time) *ledger.SnapshotProgress {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	p := &ledger.SnapshotProgress{
+		BlockNumber:         t.blockNum,
+		StartTime:           t.startTime,
+		EstimatedTotalBytes: t.estimatedTotalBytes,
+	}
+	for _, phase := range snapshotPhases {
+		phaseProgress := &ledger.SnapshotPhaseProgress{
+			Phase:          phase,
+			Status:         t.status[phase],
+			RecordsWritten: t.progress[phase].Records(),
+			BytesWritten:   t.progress[phase].Bytes(),
+		}
+		p.Phases = append(p.Phases, phaseProgress)
+		p.RecordsWritten += phaseProgress.RecordsWritten
+		p.BytesWritten += phaseProgress.BytesWritten
+	}
+	if p.BytesWritten > 0 && p.BytesWritten < p.EstimatedTotalBytes && now.After(t.startTime) {
+		elapsed := now.Sub(t.startTime)
+		estimatedDuration := time.Duration(float64(elapsed) * float64(p.EstimatedTotalBytes) / float64(p.BytesWritten))
+		p.EstimatedCompletionTime = t.startTime.Add(estimatedDuration)
+	}
+	return p
+}
+
+// SnapshotGenerationProgress returns the progress of the snapshot being generated, or nil if no snapshot is being generated
+func (l *kvLedger) SnapshotGenerationProgress() *ledger.SnapshotProgress {
+	tracker := l.snapshotMgr.getProgressTracker()
+	if tracker == nil {
+		return nil
+	}
+	return tracker.report(time.Now())
+}
+
+func (m *snapshotMgr) getProgressTracker() *snapshotProgressTracker {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+	return m.progressTracker
+}
+
+func (m *snapshotMgr) setProgressTracker(t *snapshotProgressTracker) {
+	m.progressLock.Lock()
+	defer m.progressLock.Unlock()
+	m.progressTracker = t
+}
+
+// lastSnapshotSize returns the total size of the files of the most recent snapshot of the ledger,
+// or zero if no snapshot is present
+func lastSnapshotSize(snapshotsRootDir, ledgerID string) (uint64, error) {
+	blockNum, found, err := lastSnapshotBlockNum(snapshotsRootDir, ledgerID)
+	if err != nil || !found {
+		return 0, err
+	}
+	snapshotDir := SnapshotDirForLedgerBlockNum(snapshotsRootDir, ledgerID, blockNum)
+	files, err := ioutil.ReadDir(snapshotDir)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error while reading the snapshot dir [%s]", snapshotDir)
+	}
+	var size uint64
+	for _, f := range files {
+		if f.Mode().IsRegular() {
+			size += uint64(f.Size())
+		}
+	}
+	return size, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"hash"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotProgressTracker(t *testing.T) {
+	startTime := time.Unix(1000, 0)
+	tracker := newSnapshotProgressTracker(10, startTime, 1000)
+	p := tracker.report(startTime.Add(time.Minute))
+	require.Equal(t, &ledger.SnapshotProgress{
+		BlockNumber: 10,
+		StartTime:   startTime,
+		Phases: []*ledger.SnapshotPhaseProgress{
+			{Phase: ledger.SnapshotPhaseTxIDs, Status: ledger.SnapshotPhasePending},
+			{Phase: ledger.SnapshotPhaseConfigHistory, Status: ledger.SnapshotPhasePending},
+			{Phase: ledger.SnapshotPhasePublicState, Status: ledger.SnapshotPhasePending},
+			{Phase: ledger.SnapshotPhasePrivateStateHashes, Status: ledger.SnapshotPhasePending},
+		},
+		EstimatedTotalBytes: 1000,
+	}, p)
+
+	tracker.setStatus(ledger.SnapshotPhaseDone, ledger.SnapshotPhaseTxIDs)
+	tracker.of(ledger.SnapshotPhaseTxIDs).AddRecords(20)
+	tracker.of(ledger.SnapshotPhaseTxIDs).Write(make([]byte, 150))
+	tracker.setStatus(ledger.SnapshotPhaseInProgress, ledger.SnapshotPhasePublicState, ledger.SnapshotPhasePrivateStateHashes)
+	tracker.of(ledger.SnapshotPhasePublicState).AddRecords(5)
+	tracker.of(ledger.SnapshotPhasePublicState).Write(make([]byte, 100))
+
+	// 250 of the estimated 1000 bytes written in a minute
+	p = tracker.report(startTime.Add(time.Minute))
+	require.Equal(t, uint64(25), p.RecordsWritten)
+	require.Equal(t, uint64(250), p.BytesWritten)
+	require.Equal(t, startTime.Add(4*time.Minute), p.EstimatedCompletionTime)
+	require.Equal(t, &ledger.SnapshotPhaseProgress{
+		Phase:          ledger.SnapshotPhaseTxIDs,
+		Status:         ledger.SnapshotPhaseDone,
+		RecordsWritten: 20,
+		BytesWritten:   150,
+	}, p.Phases[0])
+	require.Equal(t, ledger.SnapshotPhasePending, p.Phases[1].Status)
+	require.Equal(t, ledger.SnapshotPhaseInProgress, p.Phases[2].Status)
+	require.Equal(t, ledger.SnapshotPhaseInProgress, p.Phases[3].Status)
+
+	// the snapshot has outgrown the estimate
+	tracker.of(ledger.SnapshotPhasePublicState).Write(make([]byte, 1000))
+	p = tracker.report(startTime.Add(2 * time.Minute))
+	require.True(t, p.EstimatedCompletionTime.IsZero())
+
+	// no estimate without a previous snapshot
+	tracker = newSnapshotProgressTracker(10, startTime, 0)
+	tracker.of(ledger.SnapshotPhaseTxIDs).Write(make([]byte, 150))
+	p = tracker.report(startTime.Add(time.Minute))
+	require.True(t, p.EstimatedCompletionTime.IsZero())
+}
+
+func TestSnapshotGenerationProgress(t *testing.T) {
+	conf, cleanup := testConfig(t)
+	defer cleanup()
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	blkGenerator, genesisBlk := testutil.NewBlockGenerator(t, "testLedgerid", false)
+	lgr, err := provider.CreateFromGenesisBlock(genesisBlk)
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr := lgr.(*kvLedger)
+	blockAndPvtdata1 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1", "key2": "value2.1"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata1, &ledger.CommitOptions{}))
+	require.Nil(t, kvlgr.SnapshotGenerationProgress())
+
+	// capture the progress whenever a snapshot file is created
+	hashProvider := &progressCapturingHashProvider{HashProvider: kvlgr.hashProvider, lgr: kvlgr}
+	kvlgr.hashProvider = hashProvider
+	require.NoError(t, kvlgr.generateSnapshot())
+	require.Nil(t, kvlgr.SnapshotGenerationProgress())
+
+	require.NotEmpty(t, hashProvider.captured)
+	first := hashProvider.captured[0]
+	require.Equal(t, uint64(1), first.BlockNumber)
+	require.Equal(t, ledger.SnapshotPhaseInProgress, first.Phases[0].Status)
+	require.Zero(t, first.EstimatedTotalBytes)
+
+	last := hashProvider.captured[len(hashProvider.captured)-1]
+	for _, phaseProgress := range last.Phases {
+		require.Equal(t, ledger.SnapshotPhaseDone, phaseProgress.Status)
+	}
+	require.Equal(t, uint64(2), last.Phases[0].RecordsWritten) // txids of the genesis block and block-1
+	require.Equal(t, uint64(2), last.Phases[2].RecordsWritten) // public state keys
+	require.Zero(t, last.Phases[3].RecordsWritten)
+	require.Equal(t, uint64(2+2), last.RecordsWritten)
+
+	snapshotSize, err := lastSnapshotSize(conf.SnapshotsConfig.RootDir, kvlgr.ledgerID)
+	require.NoError(t, err)
+	require.True(t, snapshotSize > last.BytesWritten) // the metadata files are not tracked
+
+	// the previous snapshot serves as the estimate for the next one
+	hashProvider.captured = nil
+	blockAndPvtdata2 := prepareNextBlockForTest(t, kvlgr, blkGenerator, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2"},
+		nil,
+	)
+	require.NoError(t, kvlgr.CommitLegacy(blockAndPvtdata2, &ledger.CommitOptions{}))
+	require.NoError(t, kvlgr.generateSnapshot())
+	require.Equal(t, snapshotSize, hashProvider.captured[0].EstimatedTotalBytes)
+}
+
+type progressCapturingHashProvider struct {
+	ledger.HashProvider
+	lgr      *kvLedger
+	captured []*ledger.SnapshotProgress
+}
+
+func (p *progressCapturingHashProvider) GetHash(opts bccsp.HashOpts) (hash.Hash, error) {
+	if progress := p.lgr.SnapshotGenerationProgress(); progress != nil {
+		p.captured = append(p.captured, progress)
+	}
+	return p.HashProvider.GetHash(opts)
+}
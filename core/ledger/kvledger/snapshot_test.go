@@ -657,7 +657,7 @@ func testCreateLedgerFromSnapshotErrorPaths(t *testing.T, originalSnapshotDir st
 	}
 
 	overwriteModifiedSignableMetadata := func() {
-		signaleMetadataJSON, err := metadata.snapshotSignableMetadata.toJSON()
+		signaleMetadataJSON, err := metadata.SnapshotSignableMetadata.toJSON()
 		require.NoError(t, err)
 		require.NoError(t, ioutil.WriteFile(signableMetadataFile, signaleMetadataJSON, 0600))
 
@@ -670,7 +670,7 @@ func testCreateLedgerFromSnapshotErrorPaths(t *testing.T, originalSnapshotDir st
 	overwriteDataFile := func(fileName string, content []byte) {
 		filePath := filepath.Join(snapshotDirForTest, fileName)
 		require.NoError(t, ioutil.WriteFile(filePath, content, 0600))
-		metadata.snapshotSignableMetadata.FilesAndHashes[fileName] = computeHashForTest(t, provider, content)
+		metadata.SnapshotSignableMetadata.FilesAndHashes[fileName] = computeHashForTest(t, provider, content)
 		overwriteModifiedSignableMetadata()
 	}
 
@@ -775,7 +775,7 @@ func testCreateLedgerFromSnapshotErrorPaths(t *testing.T, originalSnapshotDir st
 		init(t)
 		defer cleanup()
 
-		metadata.snapshotSignableMetadata.LastBlockHashInHex = "invalid-hex"
+		metadata.SnapshotSignableMetadata.LastBlockHashInHex = "invalid-hex"
 		overwriteModifiedSignableMetadata()
 
 		_, _, err := provider.CreateFromSnapshot(snapshotDirForTest)
@@ -787,7 +787,7 @@ func testCreateLedgerFromSnapshotErrorPaths(t *testing.T, originalSnapshotDir st
 		init(t)
 		defer cleanup()
 
-		metadata.snapshotSignableMetadata.PreviousBlockHashInHex = "invalid-hex"
+		metadata.SnapshotSignableMetadata.PreviousBlockHashInHex = "invalid-hex"
 		overwriteModifiedSignableMetadata()
 
 		_, _, err := provider.CreateFromSnapshot(snapshotDirForTest)
@@ -888,7 +888,7 @@ func verifySnapshotOutput(
 	}
 
 	// verify the contents of the file snapshot_metadata.json
-	m := &snapshotSignableMetadata{}
+	m := &SnapshotSignableMetadata{}
 	mJSON, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotSignableMetadataFileName))
 	require.NoError(t, err)
 	require.NoError(t, json.Unmarshal(mJSON, m))
@@ -898,7 +898,7 @@ func verifySnapshotOutput(
 		previousBlockHashHex = hex.EncodeToString(o.previousBlockHash)
 	}
 	require.Equal(t,
-		&snapshotSignableMetadata{
+		&SnapshotSignableMetadata{
 			ChannelName:            o.ledgerID,
 			LastBlockNumber:        o.lastBlockNumber,
 			LastBlockHashInHex:     hex.EncodeToString(o.lastBlockHash),
@@ -19,8 +19,10 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
-	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
+	// the in-tree state databases register themselves with the statedb registry
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statejsondb"
+	_ "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/pkg/errors"
 )
@@ -37,7 +39,8 @@ const (
 type StateDBConfig struct {
 	// ledger.StateDBConfig is used to configure the stateDB for the ledger.
 	*ledger.StateDBConfig
-	// LevelDBPath is the filesystem path when statedb type is "goleveldb".
+	// LevelDBPath is the filesystem path when statedb type is "goleveldb", or any other
+	// embedded database (see statedb.ProviderConfig.DBPath).
 	// It is internally computed by the ledger component,
 	// so it is not in ledger.StateDBConfig and not exposed to other components.
 	LevelDBPath string
@@ -49,6 +52,7 @@ type DBProvider struct {
 	VersionedDBProvider statedb.VersionedDBProvider
 	HealthCheckRegistry ledger.HealthCheckRegistry
 	bookkeepingProvider *bookkeeping.Provider
+	stateDatabase       string
 }
 
 // NewDBProvider constructs an instance of DBProvider
@@ -60,23 +64,34 @@ func NewDBProvider(
 	sysNamespaces []string,
 ) (*DBProvider, error) {
 
-	var vdbProvider statedb.VersionedDBProvider
-	var err error
-
-	if stateDBConf != nil && stateDBConf.StateDatabase == ledger.CouchDB {
-		if vdbProvider, err = statecouchdb.NewVersionedDBProvider(stateDBConf.CouchDB, metricsProvider, sysNamespaces); err != nil {
-			return nil, err
-		}
-	} else {
-		if vdbProvider, err = stateleveldb.NewVersionedDBProvider(stateDBConf.LevelDBPath); err != nil {
-			return nil, err
-		}
+	// as before the state databases were registered, any unknown state database falls back to goleveldb
+	stateDatabase := stateDBConf.StateDatabase
+	switch {
+	case stateDatabase == "":
+		stateDatabase = ledger.GoLevelDB
+	case !statedb.IsRegistered(stateDatabase):
+		logger.Warningf("State database [%s] is not supported, using [%s] instead; supported state databases are %s",
+			stateDatabase, ledger.GoLevelDB, statedb.RegisteredProviders())
+		stateDatabase = ledger.GoLevelDB
+	}
+	vdbProvider, err := statedb.NewProvider(
+		stateDatabase,
+		&statedb.ProviderConfig{
+			StateDBConfig:   stateDBConf.StateDBConfig,
+			DBPath:          stateDBConf.LevelDBPath,
+			MetricsProvider: metricsProvider,
+			SysNamespaces:   sysNamespaces,
+		},
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	dbProvider := &DBProvider{
 		VersionedDBProvider: vdbProvider,
 		HealthCheckRegistry: healthCheckRegistry,
 		bookkeepingProvider: bookkeeperProvider,
+		stateDatabase:       stateDatabase,
 	}
 
 	err = dbProvider.RegisterHealthChecker()
@@ -87,12 +102,13 @@ func NewDBProvider(
 	return dbProvider, nil
 }
 
-// RegisterHealthChecker registers the underlying stateDB with the healthChecker.
-// For now, we register only the CouchDB as it runs as a separate process but not
-// for the GoLevelDB as it is an embedded database.
+// RegisterHealthChecker registers the underlying stateDB with the healthChecker, if the stateDB
+// implements healthz.HealthChecker. This is the case for the CouchDB as it runs as a separate process
+// but not for the embedded databases. The checker is registered under the lowercased name of the
+// stateDB, e.g., "couchdb".
 func (p *DBProvider) RegisterHealthChecker() error {
 	if healthChecker, ok := p.VersionedDBProvider.(healthz.HealthChecker); ok {
-		return p.HealthCheckRegistry.RegisterChecker("couchdb", healthChecker)
+		return p.HealthCheckRegistry.RegisterChecker(strings.ToLower(p.stateDatabase), healthChecker)
 	}
 	return nil
 }
@@ -11,13 +11,18 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	testmock "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate/mock"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
@@ -39,6 +44,7 @@ func TestHealthCheckRegister(t *testing.T) {
 	require.Equal(t, 0, fakeHealthCheckRegistry.RegisterCheckerCallCount())
 
 	dbProvider.VersionedDBProvider = &statecouchdb.VersionedDBProvider{}
+	dbProvider.stateDatabase = ledger.CouchDB
 	err = dbProvider.RegisterHealthChecker()
 	require.NoError(t, err)
 	require.Equal(t, 1, fakeHealthCheckRegistry.RegisterCheckerCallCount())
@@ -48,6 +54,29 @@ func TestHealthCheckRegister(t *testing.T) {
 	require.NotNil(t, arg2)
 }
 
+func TestNewDBProviderUnknownStateDatabase(t *testing.T) {
+	bookkeeperTestEnv := bookkeeping.NewTestEnv(t)
+	defer bookkeeperTestEnv.Cleanup()
+	dbPath, err := ioutil.TempDir("", "privacyenabledstate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dbPath)
+
+	dbProvider, err := NewDBProvider(
+		bookkeeperTestEnv.TestProvider,
+		&disabled.Provider{},
+		&mock.HealthCheckRegistry{},
+		&StateDBConfig{
+			&ledger.StateDBConfig{StateDatabase: "unknowndb"},
+			dbPath,
+		},
+		[]string{"lscc", "_lifecycle"},
+	)
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	require.IsType(t, &stateleveldb.VersionedDBProvider{}, dbProvider.VersionedDBProvider)
+	require.Equal(t, ledger.GoLevelDB, dbProvider.stateDatabase)
+}
+
 func TestGetIndexInfo(t *testing.T) {
 	chaincodeIndexPath := "META-INF/statedb/couchdb/indexes/indexColorSortName.json"
 	actualIndexInfo := getIndexInfo(chaincodeIndexPath)
@@ -31,7 +31,13 @@ const (
 // contains the exported public state and the files private_state_hashes.data and private_state_hashes.metadata contain the exported private state hashes.
 // The file format for public state and the private state hashes are the same. The data files contains a series serialized proto message SnapshotRecord
 // and the metadata files contains a series of tuple <namespace, num entries for the namespace in the data file>.
-func (s *DB) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
+// The number of the exported records and the bytes written for the public state and for the private state hashes are
+// accumulated in pubStateProgress and pvtStateHashesProgress respectively, if not nil.
+func (s *DB) ExportPubStateAndPvtStateHashes(
+	dir string,
+	newHashFunc snapshot.NewHashFunc,
+	pubStateProgress, pvtStateHashesProgress *snapshot.Progress,
+) (map[string][]byte, error) {
 	itr, err := s.GetFullScanIterator(isPvtdataNs)
 	if err != nil {
 		return nil, err
@@ -73,6 +79,7 @@ func (s *DB) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.Ne
 					pvtStateHashesFileName,
 					pvtStateHashesMetadataFileName,
 					newHashFunc,
+					pvtStateHashesProgress,
 				)
 				if err != nil {
 					return nil, err
@@ -82,6 +89,7 @@ func (s *DB) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.Ne
 			if err := pvtStateHashesWriter.addData(namespace, snapshotRecord); err != nil {
 				return nil, err
 			}
+			pvtStateHashesProgress.AddRecords(1)
 		default:
 			if pubStateWriter == nil { // encountered first time the pub state element
 				pubStateWriter, err = newSnapshotWriter(
@@ -89,6 +97,7 @@ func (s *DB) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.Ne
 					pubStateDataFileName,
 					pubStateMetadataFileName,
 					newHashFunc,
+					pubStateProgress,
 				)
 				if err != nil {
 					return nil, err
@@ -98,6 +107,7 @@ func (s *DB) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.Ne
 			if err := pubStateWriter.addData(namespace, snapshotRecord); err != nil {
 				return nil, err
 			}
+			pubStateProgress.AddRecords(1)
 		}
 	}
 
@@ -135,6 +145,7 @@ type snapshotWriter struct {
 func newSnapshotWriter(
 	dir, dataFileName, metadataFileName string,
 	newHash func() (hash.Hash, error),
+	progress *snapshot.Progress,
 ) (*snapshotWriter, error) {
 
 	dataFilePath := filepath.Join(dir, dataFileName)
@@ -149,12 +160,12 @@ func newSnapshotWriter(
 		}
 	}()
 
-	dataFile, err = snapshot.CreateFile(dataFilePath, snapshotFileFormat, newHash)
+	dataFile, err = snapshot.CreateFileWithProgress(dataFilePath, snapshotFileFormat, newHash, progress)
 	if err != nil {
 		return nil, err
 	}
 
-	metadataFile, err = snapshot.CreateFile(metadataFilePath, snapshotFileFormat, newHash)
+	metadataFile, err = snapshot.CreateFileWithProgress(metadataFilePath, snapshotFileFormat, newHash, progress)
 	if err != nil {
 		return nil, err
 	}
@@ -432,6 +443,81 @@ func (r *worldStateSnapshotReader) Close() {
 	r.pvtStateHashes.Close()
 }
 
+// SnapshotKV represents a record exported in a snapshot. For the public state, Key and Value contain the key and the value
+// and Collection is empty. For the private state hashes, Key and Value contain the key hash and the value hash
+type SnapshotKV struct {
+	Namespace  string
+	Collection string
+	Key        []byte
+	Value      []byte
+	Metadata   []byte
+	BlockNum   uint64
+	TxNum      uint64
+}
+
+// SnapshotKVReader reads, in the order of export, either the public state or the private state hashes
+// exported by the function `ExportPubStateAndPvtStateHashes`
+type SnapshotKVReader struct {
+	reader         *snapshotReader
+	pvtStateHashes bool
+}
+
+// NewPubStateSnapshotKVReader returns a SnapshotKVReader for the public state exported in the snapshot present in dir
+func NewPubStateSnapshotKVReader(dir string) (*SnapshotKVReader, error) {
+	reader, err := newSnapshotReader(dir, pubStateDataFileName, pubStateMetadataFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotKVReader{reader: reader}, nil
+}
+
+// NewPvtStateHashesSnapshotKVReader returns a SnapshotKVReader for the private state hashes exported in the snapshot present in dir
+func NewPvtStateHashesSnapshotKVReader(dir string) (*SnapshotKVReader, error) {
+	reader, err := newSnapshotReader(dir, pvtStateHashesFileName, pvtStateHashesMetadataFileName)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotKVReader{reader: reader, pvtStateHashes: true}, nil
+}
+
+// Next returns the next record. A nil record is returned when the records are exhausted or when
+// the snapshot does not contain the corresponding files
+func (r *SnapshotKVReader) Next() (*SnapshotKV, error) {
+	if r.reader == nil || !r.reader.hasMore() {
+		return nil, nil
+	}
+	namespace, snapshotRecord, err := r.reader.Next()
+	if err != nil {
+		return nil, err
+	}
+	version, _, err := version.NewHeightFromBytes(snapshotRecord.Version)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while decoding version")
+	}
+	kv := &SnapshotKV{
+		Namespace: namespace,
+		Key:       snapshotRecord.Key,
+		Value:     snapshotRecord.Value,
+		Metadata:  snapshotRecord.Metadata,
+		BlockNum:  version.BlockNum,
+		TxNum:     version.TxNum,
+	}
+	if r.pvtStateHashes {
+		if kv.Namespace, kv.Collection, err = decodeHashedDataNsColl(namespace); err != nil {
+			return nil, err
+		}
+	}
+	return kv, nil
+}
+
+// Close closes the underlying snapshot files
+func (r *SnapshotKVReader) Close() {
+	if r == nil {
+		return
+	}
+	r.reader.Close()
+}
+
 // snapshotReader reads data from a pair of files (a data file and the corresponding metadata file)
 type snapshotReader struct {
 	dataFile *snapshot.FileReader
@@ -171,7 +171,8 @@ func testSnapshotWithSampleData(t *testing.T, env TestEnv,
 	}()
 
 	// verify exported snapshot files
-	filesAndHashesSrcDB, err := sourceDB.ExportPubStateAndPvtStateHashes(snapshotDirSrcDB, testNewHashFunc)
+	pubStateProgress, pvtStateHashesProgress := &snapshot.Progress{}, &snapshot.Progress{}
+	filesAndHashesSrcDB, err := sourceDB.ExportPubStateAndPvtStateHashes(snapshotDirSrcDB, testNewHashFunc, pubStateProgress, pvtStateHashesProgress)
 	require.NoError(t, err)
 	verifyExportedSnapshot(t,
 		snapshotDirSrcDB,
@@ -179,6 +180,10 @@ func testSnapshotWithSampleData(t *testing.T, env TestEnv,
 		publicState != nil,
 		pvtStateHashes != nil,
 	)
+	require.Equal(t, uint64(len(publicState)), pubStateProgress.Records())
+	require.Equal(t, uint64(len(pvtStateHashes)), pvtStateHashesProgress.Records())
+	require.Equal(t, exportedFilesSize(t, snapshotDirSrcDB, pubStateDataFileName, pubStateMetadataFileName), pubStateProgress.Bytes())
+	require.Equal(t, exportedFilesSize(t, snapshotDirSrcDB, pvtStateHashesFileName, pvtStateHashesMetadataFileName), pvtStateHashesProgress.Bytes())
 
 	// import snapshot in a fresh db and verify the imported state
 	destinationDBName := generateLedgerID(t)
@@ -196,11 +201,24 @@ func testSnapshotWithSampleData(t *testing.T, env TestEnv,
 	defer func() {
 		os.RemoveAll(snapshotDirDestDB)
 	}()
-	filesAndHashesDestDB, err := destinationDB.ExportPubStateAndPvtStateHashes(snapshotDirDestDB, testNewHashFunc)
+	filesAndHashesDestDB, err := destinationDB.ExportPubStateAndPvtStateHashes(snapshotDirDestDB, testNewHashFunc, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, filesAndHashesSrcDB, filesAndHashesDestDB)
 }
 
+func exportedFilesSize(t *testing.T, dir string, fileNames ...string) uint64 {
+	var size int64
+	for _, fileName := range fileNames {
+		fileInfo, err := os.Stat(filepath.Join(dir, fileName))
+		if os.IsNotExist(err) {
+			continue
+		}
+		require.NoError(t, err)
+		size += fileInfo.Size()
+	}
+	return uint64(size)
+}
+
 func verifyExportedSnapshot(
 	t *testing.T,
 	snapshotDir string,
@@ -312,7 +330,7 @@ func TestSnapshotImportMetadtaHintImport(t *testing.T) {
 	defer func() {
 		os.RemoveAll(snapshotDir)
 	}()
-	_, err = sourceDB.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+	_, err = sourceDB.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 	require.NoError(t, err)
 
 	// import snapshot in a fresh db
@@ -340,7 +358,7 @@ func TestSnapshotReaderNextFunction(t *testing.T) {
 	require.NoError(t, err)
 	defer os.RemoveAll(testdir)
 
-	w, err := newSnapshotWriter(testdir, "datafile", "metadatafile", testNewHashFunc)
+	w, err := newSnapshotWriter(testdir, "datafile", "metadatafile", testNewHashFunc, nil)
 	require.NoError(t, err)
 
 	snapshotRecord := &SnapshotRecord{
@@ -368,6 +386,94 @@ func TestSnapshotReaderNextFunction(t *testing.T) {
 	require.Nil(t, retrievedSr)
 }
 
+func TestSnapshotKVReader(t *testing.T) {
+	testdir, err := ioutil.TempDir("", "testsnapshot-")
+	require.NoError(t, err)
+	defer os.RemoveAll(testdir)
+
+	pubStateWriter, err := newSnapshotWriter(testdir, pubStateDataFileName, pubStateMetadataFileName, testNewHashFunc, nil)
+	require.NoError(t, err)
+	defer pubStateWriter.close()
+	require.NoError(t, pubStateWriter.addData("ns1", &SnapshotRecord{
+		Key:      []byte("key1"),
+		Value:    []byte("value1"),
+		Metadata: []byte("metadata1"),
+		Version:  version.NewHeight(5, 2).ToBytes(),
+	}))
+	_, _, err = pubStateWriter.done()
+	require.NoError(t, err)
+
+	pvtStateHashesWriter, err := newSnapshotWriter(testdir, pvtStateHashesFileName, pvtStateHashesMetadataFileName, testNewHashFunc, nil)
+	require.NoError(t, err)
+	defer pvtStateHashesWriter.close()
+	require.NoError(t, pvtStateHashesWriter.addData(deriveHashedDataNs("ns1", "coll1"), &SnapshotRecord{
+		Key:     []byte("key-hash"),
+		Value:   []byte("value-hash"),
+		Version: version.NewHeight(7, 3).ToBytes(),
+	}))
+	_, _, err = pvtStateHashesWriter.done()
+	require.NoError(t, err)
+
+	t.Run("pub-state", func(t *testing.T) {
+		r, err := NewPubStateSnapshotKVReader(testdir)
+		require.NoError(t, err)
+		defer r.Close()
+
+		kv, err := r.Next()
+		require.NoError(t, err)
+		require.Equal(t,
+			&SnapshotKV{
+				Namespace: "ns1",
+				Key:       []byte("key1"),
+				Value:     []byte("value1"),
+				Metadata:  []byte("metadata1"),
+				BlockNum:  5,
+				TxNum:     2,
+			},
+			kv,
+		)
+		kv, err = r.Next()
+		require.NoError(t, err)
+		require.Nil(t, kv)
+	})
+
+	t.Run("pvt-state-hashes", func(t *testing.T) {
+		r, err := NewPvtStateHashesSnapshotKVReader(testdir)
+		require.NoError(t, err)
+		defer r.Close()
+
+		kv, err := r.Next()
+		require.NoError(t, err)
+		require.Equal(t,
+			&SnapshotKV{
+				Namespace:  "ns1",
+				Collection: "coll1",
+				Key:        []byte("key-hash"),
+				Value:      []byte("value-hash"),
+				BlockNum:   7,
+				TxNum:      3,
+			},
+			kv,
+		)
+		kv, err = r.Next()
+		require.NoError(t, err)
+		require.Nil(t, kv)
+	})
+
+	t.Run("missing-files", func(t *testing.T) {
+		emptydir, err := ioutil.TempDir("", "testsnapshot-")
+		require.NoError(t, err)
+		defer os.RemoveAll(emptydir)
+
+		r, err := NewPubStateSnapshotKVReader(emptydir)
+		require.NoError(t, err)
+		defer r.Close()
+		kv, err := r.Next()
+		require.NoError(t, err)
+		require.Nil(t, kv)
+	})
+}
+
 func TestMetadataCursor(t *testing.T) {
 	metadata := []*metadataRow{}
 	for i := 1; i <= 100; i++ {
@@ -451,7 +557,7 @@ func TestSnapshotExportErrorPropagation(t *testing.T) {
 		pubStateDataFilePath := filepath.Join(snapshotDir, pubStateDataFileName)
 		_, err = os.Create(pubStateDataFilePath)
 		require.NoError(t, err)
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.Contains(t, err.Error(), "error while creating the snapshot file: "+pubStateDataFilePath)
 	})
 
@@ -462,7 +568,7 @@ func TestSnapshotExportErrorPropagation(t *testing.T) {
 		pubStateMetadataFilePath := filepath.Join(snapshotDir, pubStateMetadataFileName)
 		_, err = os.Create(pubStateMetadataFilePath)
 		require.NoError(t, err)
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.Contains(t, err.Error(), "error while creating the snapshot file: "+pubStateMetadataFilePath)
 	})
 
@@ -473,7 +579,7 @@ func TestSnapshotExportErrorPropagation(t *testing.T) {
 		pvtStateHashesDataFilePath := filepath.Join(snapshotDir, pvtStateHashesFileName)
 		_, err = os.Create(pvtStateHashesDataFilePath)
 		require.NoError(t, err)
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.Contains(t, err.Error(), "error while creating the snapshot file: "+pvtStateHashesDataFilePath)
 	})
 
@@ -484,7 +590,7 @@ func TestSnapshotExportErrorPropagation(t *testing.T) {
 		pvtStateHashesMetadataFilePath := filepath.Join(snapshotDir, pvtStateHashesMetadataFileName)
 		_, err = os.Create(pvtStateHashesMetadataFilePath)
 		require.NoError(t, err)
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.Contains(t, err.Error(), "error while creating the snapshot file: "+pvtStateHashesMetadataFilePath)
 	})
 
@@ -493,7 +599,7 @@ func TestSnapshotExportErrorPropagation(t *testing.T) {
 		defer cleanup()
 
 		dbEnv.provider.Close()
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.Contains(t, err.Error(), "internal leveldb error while obtaining db iterator:")
 	})
 }
@@ -514,7 +620,7 @@ func TestSnapshotImportErrorPropagation(t *testing.T) {
 		require.NoError(t, db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 1)))
 		snapshotDir, err = ioutil.TempDir("", "testsnapshot")
 		require.NoError(t, err)
-		_, err := db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err := db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.NoError(t, err)
 		cleanup = func() {
 			dbEnv.Cleanup()
@@ -722,7 +828,7 @@ func testSnapshotImportPvtdataHashesConsumer(t *testing.T, dbEnv TestEnv) {
 		require.NoError(t, db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 1)))
 		snapshotDir, err = ioutil.TempDir("", "testsnapshot")
 		require.NoError(t, err)
-		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc)
+		_, err = db.ExportPubStateAndPvtStateHashes(snapshotDir, testNewHashFunc, nil, nil)
 		require.NoError(t, err)
 	}
 
@@ -34,7 +34,7 @@ type TestEnv interface {
 
 // Tests will be run against each environment in this array
 // For example, to skip CouchDB tests, remove &CouchDBLockBasedEnv{}
-var testEnvs = []TestEnv{&LevelDBTestEnv{}, &CouchDBTestEnv{}}
+var testEnvs = []TestEnv{&LevelDBTestEnv{}, &JSONDBTestEnv{}, &CouchDBTestEnv{}}
 
 ///////////// LevelDB Environment //////////////
 
@@ -103,6 +103,41 @@ func (env *LevelDBTestEnv) Cleanup() {
 	os.RemoveAll(env.dbPath)
 }
 
+///////////// JSONDB Environment //////////////
+
+// JSONDBTestEnv implements TestEnv interface for the embedded JSON state database
+type JSONDBTestEnv struct {
+	LevelDBTestEnv
+}
+
+// Init implements corresponding function from interface TestEnv
+func (env *JSONDBTestEnv) Init(t testing.TB) {
+	dbPath, err := ioutil.TempDir("", "cstestenv")
+	if err != nil {
+		t.Fatalf("Failed to create jsondb storage directory: %s", err)
+	}
+	env.bookkeeperTestEnv = bookkeeping.NewTestEnv(t)
+	dbProvider, err := NewDBProvider(
+		env.bookkeeperTestEnv.TestProvider,
+		&disabled.Provider{},
+		&mock.HealthCheckRegistry{},
+		&StateDBConfig{
+			&ledger.StateDBConfig{StateDatabase: ledger.JSONDB},
+			dbPath,
+		},
+		[]string{"lscc", "_lifecycle"},
+	)
+	require.NoError(t, err)
+	env.t = t
+	env.provider = dbProvider
+	env.dbPath = dbPath
+}
+
+// GetName implements corresponding function from interface TestEnv
+func (env *JSONDBTestEnv) GetName() string {
+	return "jsonDBTestEnv"
+}
+
 ///////////// CouchDB Environment //////////////
 
 // CouchDBTestEnv implements TestEnv interface for couchdb based storage
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
+
+// ProviderFactory constructs the VersionedDBProvider of a state database implementation
+type ProviderFactory func(conf *ProviderConfig) (VersionedDBProvider, error)
+
+// ProviderConfig encapsulates the parameters that are passed to a ProviderFactory
+type ProviderConfig struct {
+	// StateDBConfig is the state database configuration of the ledger, as specified in core.yaml
+	StateDBConfig *ledger.StateDBConfig
+	// DBPath is the directory, within the ledger's file system, that is reserved for the state database.
+	// An embedded state database is expected to store its data in this directory so that the data is
+	// removed when the peer drops the ledger databases (e.g., during a rebuild, reset, or rollback)
+	DBPath string
+	// MetricsProvider is the provider of the metrics that a state database implementation may record
+	MetricsProvider metrics.Provider
+	// SysNamespaces are the namespaces of the system chaincodes
+	SysNamespaces []string
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = map[string]ProviderFactory{}
+)
+
+// RegisterProvider makes a state database implementation available under the given name, which can then be
+// selected via the property `ledger.state.stateDatabase` in core.yaml. This function is expected to be invoked
+// from an init function of the package that implements the state database and it panics if the name is empty
+// or already registered
+func RegisterProvider(name string, factory ProviderFactory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if name == "" || factory == nil {
+		panic("a state database must be registered with a name and a non-nil factory")
+	}
+	if _, ok := registry[name]; ok {
+		panic("state database [" + name + "] is already registered")
+	}
+	registry[name] = factory
+}
+
+// NewProvider constructs the VersionedDBProvider of the state database registered with the given name
+func NewProvider(name string, conf *ProviderConfig) (VersionedDBProvider, error) {
+	registryLock.RLock()
+	factory, ok := registry[name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("state database [%s] is not supported, supported state databases are %s", name, RegisteredProviders())
+	}
+	return factory(conf)
+}
+
+// IsRegistered returns true if a state database is registered with the given name
+func IsRegistered(name string) bool {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
+
+// RegisteredProviders returns the sorted names of the registered state databases
+func RegisteredProviders() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	var receivedConf *ProviderConfig
+	RegisterProvider("testdb", func(conf *ProviderConfig) (VersionedDBProvider, error) {
+		receivedConf = conf
+		return nil, errors.New("testdb-error")
+	})
+	defer func() {
+		registryLock.Lock()
+		delete(registry, "testdb")
+		registryLock.Unlock()
+	}()
+
+	require.Contains(t, RegisteredProviders(), "testdb")
+	require.True(t, IsRegistered("testdb"))
+	require.False(t, IsRegistered("unknowndb"))
+
+	conf := &ProviderConfig{DBPath: "test-path"}
+	_, err := NewProvider("testdb", conf)
+	require.EqualError(t, err, "testdb-error")
+	require.Equal(t, conf, receivedConf)
+
+	_, err = NewProvider("unknowndb", conf)
+	require.EqualError(t, err, "state database [unknowndb] is not supported, supported state databases are [testdb]")
+
+	require.PanicsWithValue(t, "state database [testdb] is already registered", func() {
+		RegisterProvider("testdb", func(conf *ProviderConfig) (VersionedDBProvider, error) { return nil, nil })
+	})
+	require.PanicsWithValue(t, "a state database must be registered with a name and a non-nil factory", func() {
+		RegisterProvider("", func(conf *ProviderConfig) (VersionedDBProvider, error) { return nil, nil })
+	})
+}
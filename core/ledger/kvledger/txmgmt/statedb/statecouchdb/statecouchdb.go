@@ -42,6 +42,12 @@ var (
 	maxDataImportBatchMemorySize = 2 * 1024 * 1024
 )
 
+func init() {
+	statedb.RegisterProvider(ledger.CouchDB, func(conf *statedb.ProviderConfig) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(conf.StateDBConfig.CouchDB, conf.MetricsProvider, conf.SysNamespaces)
+	})
+}
+
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	couchInstance      *couchInstance
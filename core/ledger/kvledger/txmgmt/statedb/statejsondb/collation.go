@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// The JSON values are encoded such that the byte order of the encoded values follows the collation order
+// used by CouchDB for the types of the JSON values, i.e., null < false < true < numbers < strings < arrays < objects.
+// Unlike CouchDB, that uses the Unicode Collation Algorithm, the strings are ordered by their code points,
+// and the members of an object are ordered by their names, as the original order of the members is not preserved.
+// The encoding is used both for the entries of the indexes and for evaluating the comparison operators of a query.
+const (
+	nullTag   = byte(0x01)
+	falseTag  = byte(0x02)
+	trueTag   = byte(0x03)
+	numberTag = byte(0x04)
+	stringTag = byte(0x05)
+	arrayTag  = byte(0x06)
+	objectTag = byte(0x07)
+
+	// terminator marks the end of a string, an array, or an object. It sorts before any tag
+	// so that a value sorts before any other value of the same type that it is a prefix of
+	terminator = byte(0x00)
+	// escapedZero replaces a zero byte that appears within a string
+	escapedZero = byte(0xff)
+	// memberTag precedes each member of an object
+	memberTag = byte(0x01)
+)
+
+// encodeJSONValue appends the collation preserving encoding of a JSON value, as decoded by a json.Decoder
+// with UseNumber, to b
+func encodeJSONValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, nullTag)
+	case bool:
+		if v {
+			return append(b, trueTag)
+		}
+		return append(b, falseTag)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			// the number is out of the range of float64 and is ordered as an infinity of the same sign
+			f = math.Inf(1)
+			if len(v) > 0 && v[0] == '-' {
+				f = math.Inf(-1)
+			}
+		}
+		return encodeNumber(b, f)
+	case float64:
+		return encodeNumber(b, v)
+	case string:
+		return encodeString(append(b, stringTag), v)
+	case []interface{}:
+		b = append(b, arrayTag)
+		for _, e := range v {
+			b = encodeJSONValue(b, e)
+		}
+		return append(b, terminator)
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b = append(b, objectTag)
+		for _, name := range names {
+			b = append(b, memberTag)
+			b = encodeString(b, name)
+			b = encodeJSONValue(b, v[name])
+		}
+		return append(b, terminator)
+	default:
+		// not a JSON value, ordered after all the JSON values
+		return append(b, objectTag+1)
+	}
+}
+
+func encodeNumber(b []byte, f float64) []byte {
+	if f == 0 {
+		// -0 and +0 are encoded alike
+		f = 0
+	}
+	bits := math.Float64bits(f)
+	if f < 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	b = append(b, numberTag)
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], bits)
+	return append(b, buf[:]...)
+}
+
+func encodeString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 {
+			b = append(b, 0, escapedZero)
+			continue
+		}
+		b = append(b, s[i])
+	}
+	return append(b, terminator, terminator)
+}
+
+// compareJSONValues compares two JSON values as per the collation order
+func compareJSONValues(a, b interface{}) int {
+	return bytes.Compare(encodeJSONValue(nil, a), encodeJSONValue(nil, b))
+}
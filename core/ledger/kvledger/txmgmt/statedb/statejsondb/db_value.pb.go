@@ -0,0 +1,97 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: db_value.proto
+
+package statejsondb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type DBValue struct {
+	Version              []byte   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DBValue) Reset()         { *m = DBValue{} }
+func (m *DBValue) String() string { return proto.CompactTextString(m) }
+func (*DBValue) ProtoMessage()    {}
+func (*DBValue) Descriptor() ([]byte, []int) {
+	return fileDescriptor_1f72618b1cd7c254, []int{0}
+}
+
+func (m *DBValue) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DBValue.Unmarshal(m, b)
+}
+func (m *DBValue) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DBValue.Marshal(b, m, deterministic)
+}
+func (m *DBValue) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DBValue.Merge(m, src)
+}
+func (m *DBValue) XXX_Size() int {
+	return xxx_messageInfo_DBValue.Size(m)
+}
+func (m *DBValue) XXX_DiscardUnknown() {
+	xxx_messageInfo_DBValue.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DBValue proto.InternalMessageInfo
+
+func (m *DBValue) GetVersion() []byte {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
+func (m *DBValue) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *DBValue) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*DBValue)(nil), "statejsondb.DBValue")
+}
+
+func init() { proto.RegisterFile("db_value.proto", fileDescriptor_1f72618b1cd7c254) }
+
+var fileDescriptor_1f72618b1cd7c254 = []byte{
+	// 172 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4b, 0x49, 0x8a, 0x2f,
+	0x4b, 0xcc, 0x29, 0x4d, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x2e, 0x2e, 0x49, 0x2c,
+	0x49, 0xcd, 0x2a, 0xce, 0xcf, 0x4b, 0x49, 0x52, 0x0a, 0xe5, 0x62, 0x77, 0x71, 0x0a, 0x03, 0xc9,
+	0x0a, 0x49, 0x70, 0xb1, 0x97, 0xa5, 0x16, 0x15, 0x67, 0xe6, 0xe7, 0x49, 0x30, 0x2a, 0x30, 0x6a,
+	0xf0, 0x04, 0xc1, 0xb8, 0x42, 0x22, 0x5c, 0xac, 0x60, 0x03, 0x24, 0x98, 0xc0, 0xe2, 0x10, 0x8e,
+	0x90, 0x14, 0x17, 0x47, 0x6e, 0x6a, 0x49, 0x62, 0x4a, 0x62, 0x49, 0xa2, 0x04, 0x33, 0x58, 0x02,
+	0xce, 0x77, 0xf2, 0x8f, 0xf2, 0x4d, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5,
+	0xcf, 0xa8, 0x2c, 0x48, 0x2d, 0xca, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2, 0x4f, 0x4b, 0x4c, 0x2a,
+	0xca, 0x4c, 0xd6, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x87, 0x0a, 0x65, 0x97, 0x41, 0x19, 0x25, 0x15,
+	0xb9, 0xe9, 0xb9, 0x25, 0xfa, 0x60, 0xf7, 0xa5, 0x24, 0xe9, 0x23, 0xb9, 0x33, 0x89, 0x0d, 0xec,
+	0x76, 0x63, 0xc0, 0x00, 0xce, 0xdc, 0xa6, 0x18, 0xcd, 0x00, 0x00, 0x00,
+}
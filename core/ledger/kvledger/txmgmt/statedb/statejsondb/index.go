@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const designDocPrefix = "_design/"
+
+// indexDefinition is a secondary index over one or more fields of the JSON values in a namespace.
+// The definitions are accepted in the format of the CouchDB index definitions, for instance,
+// {"index":{"fields":["docType","owner"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}
+// so that the indexes packaged with a chaincode for CouchDB are applicable to this state database as well
+type indexDefinition struct {
+	DDoc   string   `json:"ddoc"`
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// couchDBIndexDefinition is the format of a CouchDB index definition
+type couchDBIndexDefinition struct {
+	Index *struct {
+		Fields                []interface{}   `json:"fields"`
+		PartialFilterSelector json.RawMessage `json:"partial_filter_selector"`
+	} `json:"index"`
+	DDoc string `json:"ddoc"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// parseIndexDefinition parses an index definition in the format of CouchDB. A field may either be specified
+// by its name or as an object {"<name>":"asc|desc"}. The direction is ignored as an index can be scanned
+// in either direction. The design document defaults to the name of the index, if not specified
+func parseIndexDefinition(definition []byte) (*indexDefinition, error) {
+	couchDef := &couchDBIndexDefinition{}
+	if err := json.Unmarshal(definition, couchDef); err != nil {
+		return nil, errors.Wrap(err, "invalid index definition")
+	}
+	if couchDef.Index == nil || len(couchDef.Index.Fields) == 0 {
+		return nil, errors.New("invalid index definition, the fields of the index are not specified")
+	}
+	if couchDef.Type != "" && couchDef.Type != "json" {
+		return nil, errors.Errorf("invalid index definition, unsupported index type [%s]", couchDef.Type)
+	}
+	if len(couchDef.Index.PartialFilterSelector) > 0 {
+		return nil, errors.New("invalid index definition, partial_filter_selector is not supported")
+	}
+	if couchDef.Name == "" {
+		return nil, errors.New("invalid index definition, the name of the index is not specified")
+	}
+
+	def := &indexDefinition{
+		DDoc: strings.TrimPrefix(couchDef.DDoc, designDocPrefix),
+		Name: couchDef.Name,
+	}
+	if def.DDoc == "" {
+		def.DDoc = def.Name
+	}
+	if strings.IndexByte(def.DDoc, 0) >= 0 || strings.IndexByte(def.Name, 0) >= 0 {
+		return nil, errors.New("invalid index definition, the name and the design document of the index must not contain a zero byte")
+	}
+	for _, f := range couchDef.Index.Fields {
+		switch f := f.(type) {
+		case string:
+			def.Fields = append(def.Fields, f)
+		case map[string]interface{}:
+			if len(f) != 1 {
+				return nil, errors.Errorf("invalid index definition, invalid field %v", f)
+			}
+			for name, direction := range f {
+				if direction != "asc" && direction != "desc" {
+					return nil, errors.Errorf("invalid index definition, invalid sort direction [%v] for field [%s]", direction, name)
+				}
+				def.Fields = append(def.Fields, name)
+			}
+		default:
+			return nil, errors.Errorf("invalid index definition, invalid field %v", f)
+		}
+	}
+	for _, field := range def.Fields {
+		if field == "" {
+			return nil, errors.New("invalid index definition, a field name must not be empty")
+		}
+	}
+	return def, nil
+}
+
+func (def *indexDefinition) sameAs(other *indexDefinition) bool {
+	if def.DDoc != other.DDoc || def.Name != other.Name || len(def.Fields) != len(other.Fields) {
+		return false
+	}
+	for i, f := range def.Fields {
+		if other.Fields[i] != f {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeIndexDefKey returns the key under which an index definition is stored
+func encodeIndexDefKey(ns, ddoc, name string) []byte {
+	k := append([]byte{}, indexDefKeyPrefix...)
+	k = append(k, ns...)
+	k = append(k, nsKeySep...)
+	k = append(k, ddoc...)
+	k = append(k, nsKeySep...)
+	return append(k, name...)
+}
+
+func decodeIndexDefKey(k []byte) (ns string) {
+	return string(bytes.SplitN(k[len(indexDefKeyPrefix):], nsKeySep, 2)[0])
+}
+
+// indexEntriesPrefix returns the common prefix of the entries of an index. An entry is of the
+// format <prefix><encoded value of field1>...<encoded value of fieldN><0x00><key> and the value of
+// an entry is the key of the indexed JSON value
+func indexEntriesPrefix(ns string, def *indexDefinition) []byte {
+	k := append([]byte{}, indexKeyPrefix...)
+	k = append(k, ns...)
+	k = append(k, nsKeySep...)
+	k = append(k, def.DDoc...)
+	k = append(k, nsKeySep...)
+	k = append(k, def.Name...)
+	return append(k, nsKeySep...)
+}
+
+// indexEntryKey returns the key of the entry of an index for a JSON value, or nil if the value
+// is not indexed as it is not a JSON object or misses any of the fields of the index
+func indexEntryKey(ns string, def *indexDefinition, key string, doc map[string]interface{}) []byte {
+	if doc == nil {
+		return nil
+	}
+	k := indexEntriesPrefix(ns, def)
+	for _, field := range def.Fields {
+		v, ok := lookupField(doc, splitFieldPath(field))
+		if !ok {
+			return nil
+		}
+		k = encodeJSONValue(k, v)
+	}
+	k = append(k, terminator)
+	return append(k, key...)
+}
+
+// endOfRange returns the smallest key that is greater than all the keys that begin with the given prefix,
+// given that the prefix ends with a zero byte
+func endOfRange(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	end[len(end)-1] = lastKeyIndicator
+	return end
+}
@@ -0,0 +1,433 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const idField = "_id"
+
+// queryDefinition is a parsed query in the format of the CouchDB (Mango) queries. The parameters
+// "limit" and "bookmark" are ignored, as in the case of the CouchDB state database, as the pagination
+// is controlled by the parameters passed to the ExecuteQueryWithPagination function
+type queryDefinition struct {
+	rawSelector map[string]interface{}
+	selector    *selector
+	fields      [][]string
+	sortFields  []string
+	descending  bool
+	useIndex    []string
+	skip        int64
+}
+
+func parseQuery(query string) (*queryDefinition, error) {
+	queryMap := map[string]interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(query))
+	decoder.UseNumber()
+	if err := decoder.Decode(&queryMap); err != nil {
+		return nil, errors.Wrapf(err, "invalid query [%s]", query)
+	}
+
+	q := &queryDefinition{}
+	for name, value := range queryMap {
+		switch name {
+		case "selector":
+			rawSelector, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("invalid query [%s], the selector must be a JSON object", query)
+			}
+			sel, err := parseSelector(rawSelector)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "invalid query [%s]", query)
+			}
+			q.rawSelector, q.selector = rawSelector, sel
+		case "fields":
+			fields, ok := value.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("invalid query [%s], fields definition must be an array", query)
+			}
+			for _, f := range fields {
+				field, ok := f.(string)
+				if !ok {
+					return nil, errors.Errorf("invalid query [%s], a field must be a string", query)
+				}
+				q.fields = append(q.fields, splitFieldPath(field))
+			}
+		case "sort":
+			if err := q.parseSort(value); err != nil {
+				return nil, errors.WithMessagef(err, "invalid query [%s]", query)
+			}
+		case "use_index":
+			if err := q.parseUseIndex(value); err != nil {
+				return nil, errors.WithMessagef(err, "invalid query [%s]", query)
+			}
+		case "skip":
+			skip, ok := toInteger(value)
+			if !ok || skip < 0 {
+				return nil, errors.Errorf("invalid query [%s], skip must be a non-negative integer", query)
+			}
+			q.skip = skip
+		case "limit", "bookmark", "execution_stats", "r", "conflicts", "update", "stable", "stale":
+			// these parameters either do not affect the results or are controlled by the ledger
+		default:
+			return nil, errors.Errorf("invalid query [%s], unsupported parameter [%s]", query, name)
+		}
+	}
+	if q.selector == nil {
+		return nil, errors.Errorf("invalid query [%s], the selector is not specified", query)
+	}
+	return q, nil
+}
+
+func (q *queryDefinition) parseSort(value interface{}) error {
+	sortArray, ok := value.([]interface{})
+	if !ok {
+		return errors.New("sort definition must be an array")
+	}
+	for i, s := range sortArray {
+		var field, direction string
+		switch s := s.(type) {
+		case string:
+			field, direction = s, "asc"
+		case map[string]interface{}:
+			if len(s) != 1 {
+				return errors.Errorf("invalid sort field %v", s)
+			}
+			for f, d := range s {
+				field = f
+				if direction, ok = d.(string); !ok || (direction != "asc" && direction != "desc") {
+					return errors.Errorf("invalid sort direction %v for field [%s]", d, f)
+				}
+			}
+		default:
+			return errors.Errorf("invalid sort field %v", s)
+		}
+		if i > 0 && (direction == "desc") != q.descending {
+			return errors.New("sort fields must all be in the same direction")
+		}
+		q.descending = direction == "desc"
+		q.sortFields = append(q.sortFields, field)
+	}
+	return nil
+}
+
+func (q *queryDefinition) parseUseIndex(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		q.useIndex = []string{strings.TrimPrefix(v, designDocPrefix)}
+	case []interface{}:
+		if len(v) == 0 || len(v) > 2 {
+			return errors.New("use_index must be a design document name or an array of a design document name and an index name")
+		}
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return errors.New("use_index must be a design document name or an array of a design document name and an index name")
+			}
+			q.useIndex = append(q.useIndex, s)
+		}
+		q.useIndex[0] = strings.TrimPrefix(q.useIndex[0], designDocPrefix)
+	default:
+		return errors.New("use_index must be a design document name or an array of a design document name and an index name")
+	}
+	return nil
+}
+
+// scanPlan describes the range of the keys that is scanned for evaluating a query. The range is either
+// a range of the keys of the namespace or a range of the entries of an index. The selector is evaluated
+// against each of the scanned values and hence the range is only required to include all the matching values
+type scanPlan struct {
+	index      *indexDefinition
+	startKey   []byte
+	endKey     []byte
+	descending bool
+}
+
+// planQuery selects the index, if any, for evaluating a query. The index requested in use_index is used,
+// if it exists. Otherwise, if the query specifies a sort other than by _id, an index whose leading fields
+// are the sort fields is required, as in the case of CouchDB. Otherwise, an index whose fields are all
+// constrained by the selector and whose leading field is constrained by a comparison operator is used,
+// preferring the index with more fields. In the absence of an applicable index, all the keys of the
+// namespace are scanned, restricted to the range of the _id field implied by the selector, if any.
+// Note that, as in the case of CouchDB, a value that is missing any of the fields of the selected index
+// is not included in the results
+func planQuery(ns string, q *queryDefinition, indexes []*indexDefinition) (*scanPlan, error) {
+	var index *indexDefinition
+	if len(q.useIndex) > 0 {
+		for _, def := range indexes {
+			if def.DDoc == q.useIndex[0] && (len(q.useIndex) == 1 || def.Name == q.useIndex[1]) {
+				index = def
+				break
+			}
+		}
+		if index == nil {
+			logger.Warningf("The index %v specified in use_index does not exist for namespace [%s], the index is selected automatically", q.useIndex, ns)
+		}
+	}
+
+	sortByID := len(q.sortFields) == 1 && q.sortFields[0] == idField
+	if index != nil && len(q.sortFields) > 0 && !sortByID && !hasLeadingFields(index, q.sortFields) {
+		return nil, errors.Errorf("the index %v specified in use_index cannot be used for the sort %v", q.useIndex, q.sortFields)
+	}
+	if index != nil && sortByID {
+		index = nil
+	}
+
+	ranges := extractFieldRanges(q.rawSelector)
+	if index == nil && len(q.sortFields) > 0 && !sortByID {
+		for _, def := range indexes {
+			if hasLeadingFields(def, q.sortFields) && (index == nil || len(def.Fields) < len(index.Fields)) {
+				index = def
+			}
+		}
+		if index == nil {
+			return nil, errors.Errorf("no index exists for the sort %v, try indexing by the sort fields", q.sortFields)
+		}
+	}
+	if index == nil && len(q.sortFields) == 0 {
+		for _, def := range indexes {
+			if !isConstrainedBy(def, ranges) {
+				continue
+			}
+			if index == nil || len(def.Fields) > len(index.Fields) {
+				index = def
+			}
+		}
+	}
+
+	plan := &scanPlan{index: index, descending: q.descending}
+	if index == nil {
+		prefix := encodeDataKeyPrefix(ns)
+		plan.startKey, plan.endKey = prefix, endOfRange(prefix)
+		if r, ok := ranges[idField]; ok && r.stringBounds() {
+			// a key is followed by the keys that it is a prefix of and hence the range
+			// excludes only the key itself when the bound on the key is exclusive
+			plan.narrow(
+				prefix, r,
+				func(b []byte, v interface{}) []byte { return append(b, v.(string)...) },
+				func(b []byte) []byte { return append(b, 0x00) },
+			)
+		}
+		return plan, nil
+	}
+	prefix := indexEntriesPrefix(ns, index)
+	plan.startKey, plan.endKey = prefix, endOfRange(prefix)
+	if r, ok := ranges[index.Fields[0]]; ok {
+		// the encoding of a value is followed either by the encoding of the next field, which begins with a
+		// type tag, or by the terminator, and hence is not a prefix of a key with a different value
+		plan.narrow(
+			prefix, r,
+			encodeJSONValue,
+			func(b []byte) []byte { return append(b, 0xff) },
+		)
+	}
+	return plan, nil
+}
+
+// narrow restricts the range of the scan as per the bounds on the leading component of the keys. The function
+// `successor` returns the smallest key that is greater than all the keys that begin with the given key
+func (p *scanPlan) narrow(prefix []byte, r *fieldRange, encode func([]byte, interface{}) []byte, successor func([]byte) []byte) {
+	if r.hasLower {
+		start := encode(append([]byte{}, prefix...), r.lower)
+		if !r.lowerInclusive {
+			start = successor(start)
+		}
+		p.startKey = start
+	}
+	if r.hasUpper {
+		end := encode(append([]byte{}, prefix...), r.upper)
+		if r.upperInclusive {
+			end = successor(end)
+		}
+		p.endKey = end
+	}
+}
+
+// fieldRange captures the bounds on the value of a field that are implied by the comparison
+// operators in the top-level conjunction of a selector
+type fieldRange struct {
+	lower, upper                   interface{}
+	hasLower, hasUpper             bool
+	lowerInclusive, upperInclusive bool
+	constrained                    bool
+}
+
+func (r *fieldRange) stringBounds() bool {
+	_, lowerIsString := r.lower.(string)
+	_, upperIsString := r.upper.(string)
+	return (!r.hasLower || lowerIsString) && (!r.hasUpper || upperIsString)
+}
+
+func (r *fieldRange) addLower(v interface{}, inclusive bool) {
+	if !r.hasLower || compareJSONValues(v, r.lower) > 0 || (compareJSONValues(v, r.lower) == 0 && !inclusive) {
+		r.lower, r.hasLower, r.lowerInclusive = v, true, inclusive
+	}
+}
+
+func (r *fieldRange) addUpper(v interface{}, inclusive bool) {
+	if !r.hasUpper || compareJSONValues(v, r.upper) < 0 || (compareJSONValues(v, r.upper) == 0 && !inclusive) {
+		r.upper, r.hasUpper, r.upperInclusive = v, true, inclusive
+	}
+}
+
+// extractFieldRanges returns the ranges of the fields constrained by the top-level conjunction of a selector,
+// including the conjunctions nested under $and. A field is considered constrained if the selector cannot
+// match a value that is missing the field
+func extractFieldRanges(rawSelector map[string]interface{}) map[string]*fieldRange {
+	ranges := map[string]*fieldRange{}
+	var visit func(sel map[string]interface{}, pathPrefix string)
+	visit = func(sel map[string]interface{}, pathPrefix string) {
+		for name, arg := range sel {
+			if name == "$and" {
+				args, _ := arg.([]interface{})
+				for _, a := range args {
+					if m, ok := a.(map[string]interface{}); ok {
+						visit(m, pathPrefix)
+					}
+				}
+				continue
+			}
+			if strings.HasPrefix(name, "$") {
+				continue
+			}
+			field := pathPrefix + name
+			r := ranges[field]
+			if r == nil {
+				r = &fieldRange{}
+			}
+			m, isMap := arg.(map[string]interface{})
+			if !isMap {
+				r.addLower(arg, true)
+				r.addUpper(arg, true)
+				r.constrained = true
+				ranges[field] = r
+				continue
+			}
+			hasOperator := false
+			for op, opArg := range m {
+				if !strings.HasPrefix(op, "$") {
+					continue
+				}
+				hasOperator = true
+				switch op {
+				case "$eq":
+					r.addLower(opArg, true)
+					r.addUpper(opArg, true)
+				case "$gt":
+					r.addLower(opArg, false)
+				case "$gte":
+					r.addLower(opArg, true)
+				case "$lt":
+					r.addUpper(opArg, false)
+				case "$lte":
+					r.addUpper(opArg, true)
+				case "$exists":
+					if b, ok := opArg.(bool); !ok || !b {
+						continue
+					}
+				case "$ne", "$type", "$in", "$nin", "$size", "$mod", "$regex", "$all", "$elemMatch", "$allMatch":
+				default:
+					continue
+				}
+				r.constrained = true
+			}
+			if hasOperator {
+				if r.constrained {
+					ranges[field] = r
+				}
+				continue
+			}
+			// a nested selector
+			visit(m, field+".")
+		}
+	}
+	visit(rawSelector, "")
+	return ranges
+}
+
+func hasLeadingFields(def *indexDefinition, fields []string) bool {
+	if len(fields) > len(def.Fields) {
+		return false
+	}
+	for i, f := range fields {
+		if def.Fields[i] != f {
+			return false
+		}
+	}
+	return true
+}
+
+func isConstrainedBy(def *indexDefinition, ranges map[string]*fieldRange) bool {
+	for _, f := range def.Fields {
+		if r, ok := ranges[f]; !ok || !r.constrained {
+			return false
+		}
+	}
+	r := ranges[def.Fields[0]]
+	return r.hasLower || r.hasUpper
+}
+
+// project returns a JSON object that contains only the given fields of a JSON value
+func project(doc map[string]interface{}, fields [][]string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, path := range fields {
+		v, ok := lookupField(doc, path)
+		if !ok {
+			continue
+		}
+		target := result
+		for _, name := range path[:len(path)-1] {
+			next, ok := target[name].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[name] = next
+			}
+			target = next
+		}
+		target[path[len(path)-1]] = v
+	}
+	return result
+}
+
+// decodeJSONObject decodes a value as a JSON object. It returns nil if the value is not a JSON object
+func decodeJSONObject(value []byte) map[string]interface{} {
+	if len(value) == 0 || !bytes.HasPrefix(bytes.TrimLeft(value, " \t\r\n"), []byte("{")) {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+	doc := map[string]interface{}{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil
+	}
+	// the value is not a JSON object if there is any trailing content
+	if _, err := decoder.Token(); err == nil {
+		return nil
+	}
+	return doc
+}
+
+// sortedIndexes returns the index definitions in a deterministic order
+func sortedIndexes(indexes map[string]*indexDefinition) []*indexDefinition {
+	sorted := make([]*indexDefinition, 0, len(indexes))
+	for _, def := range indexes {
+		sorted = append(sorted, def)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].DDoc != sorted[j].DDoc {
+			return sorted[i].DDoc < sorted[j].DDoc
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
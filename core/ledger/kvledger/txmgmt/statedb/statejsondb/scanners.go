@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+)
+
+type kvScanner struct {
+	namespace            string
+	dbItr                iterator.Iterator
+	requestedLimit       int32
+	totalRecordsReturned int32
+}
+
+func (scanner *kvScanner) Next() (*statedb.VersionedKV, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
+	if !scanner.dbItr.Next() {
+		return nil, nil
+	}
+
+	dbKey := scanner.dbItr.Key()
+	dbVal := scanner.dbItr.Value()
+	dbValCopy := make([]byte, len(dbVal))
+	copy(dbValCopy, dbVal)
+	_, key := decodeDataKey(dbKey)
+	vv, err := decodeValue(dbValCopy)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner.totalRecordsReturned++
+	return &statedb.VersionedKV{
+		CompositeKey: &statedb.CompositeKey{
+			Namespace: scanner.namespace,
+			Key:       key,
+		},
+		VersionedValue: vv,
+	}, nil
+}
+
+func (scanner *kvScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	retval := ""
+	if scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		_, key := decodeDataKey(dbKey)
+		retval = key
+	}
+	scanner.Close()
+	return retval
+}
+
+// queryScanner scans the range of the keys selected for a query and returns the values that match the selector
+type queryScanner struct {
+	vdb                  *versionedDB
+	namespace            string
+	query                *queryDefinition
+	plan                 *scanPlan
+	dbItr                iterator.Iterator
+	started              bool
+	toSkip               int64
+	requestedLimit       int32
+	totalRecordsReturned int32
+	bookmark             string
+	lastDBKey            []byte
+}
+
+func (scanner *queryScanner) advance() bool {
+	if !scanner.plan.descending {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+func (scanner *queryScanner) Next() (*statedb.VersionedKV, error) {
+	for {
+		if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+			return nil, nil
+		}
+		if !scanner.advance() {
+			if err := scanner.dbItr.Error(); err != nil {
+				return nil, errors.Wrap(err, "internal leveldb error while retrieving data from db iterator")
+			}
+			return nil, nil
+		}
+		dbKey := append([]byte{}, scanner.dbItr.Key()...)
+
+		var key string
+		var vv *statedb.VersionedValue
+		var err error
+		if scanner.plan.index != nil {
+			key = string(scanner.dbItr.Value())
+			if vv, err = scanner.vdb.GetState(scanner.namespace, key); err != nil {
+				return nil, err
+			}
+			if vv == nil {
+				// the index entry of a key deleted after this scan began
+				continue
+			}
+		} else {
+			_, key = decodeDataKey(dbKey)
+			if vv, err = decodeValue(append([]byte{}, scanner.dbItr.Value()...)); err != nil {
+				return nil, err
+			}
+		}
+
+		doc := decodeJSONObject(vv.Value)
+		if doc == nil {
+			doc = map[string]interface{}{}
+		}
+		doc[idField] = key
+		if !scanner.query.selector.matches(doc) {
+			continue
+		}
+		if scanner.toSkip > 0 {
+			scanner.toSkip--
+			continue
+		}
+
+		value := vv.Value
+		if len(scanner.query.fields) > 0 {
+			if value, err = json.Marshal(project(doc, scanner.query.fields)); err != nil {
+				return nil, errors.Wrap(err, "failed to marshal the projected fields")
+			}
+		}
+		scanner.totalRecordsReturned++
+		scanner.lastDBKey = dbKey
+		return &statedb.VersionedKV{
+			CompositeKey: &statedb.CompositeKey{
+				Namespace: scanner.namespace,
+				Key:       key,
+			},
+			VersionedValue: &statedb.VersionedValue{
+				Value:    value,
+				Metadata: vv.Metadata,
+				Version:  vv.Version,
+			},
+		}, nil
+	}
+}
+
+func (scanner *queryScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose returns the bookmark that identifies the last returned result. If no
+// result was returned, the bookmark that was passed for the query is returned
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	retval := scanner.bookmark
+	if scanner.lastDBKey != nil {
+		retval = hex.EncodeToString(scanner.lastDBKey)
+	}
+	scanner.Close()
+	return retval
+}
+
+type fullDBScanner struct {
+	db     *leveldbhelper.DBHandle
+	dbItr  iterator.Iterator
+	toSkip func(namespace string) bool
+}
+
+func newFullDBScanner(db *leveldbhelper.DBHandle, skipNamespace func(namespace string) bool) (*fullDBScanner, error) {
+	dbItr, err := db.GetIterator(dataKeyPrefix, dataKeyStopper)
+	if err != nil {
+		return nil, err
+	}
+	return &fullDBScanner{
+			db:     db,
+			dbItr:  dbItr,
+			toSkip: skipNamespace,
+		},
+		nil
+}
+
+// Next returns the key-values in the lexical order of <Namespace, key>
+func (s *fullDBScanner) Next() (*statedb.VersionedKV, error) {
+	for s.dbItr.Next() {
+		ns, key := decodeDataKey(s.dbItr.Key())
+		compositeKey := &statedb.CompositeKey{
+			Namespace: ns,
+			Key:       key,
+		}
+
+		versionedVal, err := decodeValue(s.dbItr.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case !s.toSkip(ns):
+			return &statedb.VersionedKV{
+				CompositeKey:   compositeKey,
+				VersionedValue: versionedVal,
+			}, nil
+		default:
+			s.dbItr.Seek(dataKeyStarterForNextNamespace(ns))
+			s.dbItr.Prev()
+		}
+	}
+	return nil, errors.Wrap(s.dbItr.Error(), "internal leveldb error while retrieving data from db iterator")
+}
+
+func (s *fullDBScanner) Close() {
+	if s == nil {
+		return
+	}
+	s.dbItr.Release()
+}
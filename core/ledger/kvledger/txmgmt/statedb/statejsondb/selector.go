@@ -0,0 +1,430 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// missing represents the value of a field that is not present in a JSON value
+type missingValue struct{}
+
+var missing = missingValue{}
+
+// selector evaluates a CouchDB (Mango) selector against JSON values. The supported operators are
+// the combination operators $and, $or, $nor, $not, and the condition operators $lt, $lte, $eq,
+// $ne, $gte, $gt, $exists, $type, $in, $nin, $size, $mod, $regex, $all, $elemMatch, and $allMatch.
+// As in CouchDB, the condition operators other than $elemMatch, $allMatch, $all, $in, $nin, and $size
+// do not look into the elements of an array and the comparison operators compare values of
+// different types as per the collation order of the types
+type selector struct {
+	conditions []condition
+}
+
+// condition is a single element of a selector
+type condition interface {
+	matches(v interface{}) bool
+}
+
+func parseSelector(s interface{}) (*selector, error) {
+	m, ok := s.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("invalid selector %v, a selector must be a JSON object", s)
+	}
+	sel := &selector{}
+	for name, arg := range m {
+		var c condition
+		var err error
+		switch {
+		case strings.HasPrefix(name, "$"):
+			c, err = parseOperator(name, arg)
+		default:
+			c, err = parseFieldCondition(name, arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sel.conditions = append(sel.conditions, c)
+	}
+	return sel, nil
+}
+
+// parseFieldCondition parses the condition on a field. The argument is either an object that consists of
+// operators, an object that is a selector for the nested fields, or a value that the field must be equal to
+func parseFieldCondition(field string, arg interface{}) (condition, error) {
+	path := splitFieldPath(field)
+	if m, ok := arg.(map[string]interface{}); ok {
+		sel, err := parseSelector(m)
+		if err != nil {
+			return nil, err
+		}
+		return &fieldCondition{path: path, selector: sel}, nil
+	}
+	return &fieldCondition{path: path, selector: &selector{conditions: []condition{&compare{op: "$eq", arg: arg}}}}, nil
+}
+
+func parseOperator(op string, arg interface{}) (condition, error) {
+	switch op {
+	case "$and", "$or", "$nor":
+		args, ok := arg.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid argument for operator %s, the argument must be an array of selectors", op)
+		}
+		c := &combination{op: op}
+		for _, a := range args {
+			sel, err := parseSelector(a)
+			if err != nil {
+				return nil, err
+			}
+			c.selectors = append(c.selectors, sel)
+		}
+		return c, nil
+	case "$not":
+		sel, err := parseSelector(arg)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid argument for operator $not")
+		}
+		return &not{selector: sel}, nil
+	case "$lt", "$lte", "$eq", "$ne", "$gte", "$gt":
+		return &compare{op: op, arg: arg}, nil
+	case "$exists":
+		b, ok := arg.(bool)
+		if !ok {
+			return nil, errors.New("invalid argument for operator $exists, the argument must be a boolean")
+		}
+		return &exists{exists: b}, nil
+	case "$type":
+		t, ok := arg.(string)
+		if !ok {
+			return nil, errors.New("invalid argument for operator $type, the argument must be a string")
+		}
+		switch t {
+		case "null", "boolean", "number", "string", "array", "object":
+		default:
+			return nil, errors.Errorf("invalid argument for operator $type, unknown type [%s]", t)
+		}
+		return &typeIs{typ: t}, nil
+	case "$in", "$nin", "$all":
+		args, ok := arg.([]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid argument for operator %s, the argument must be an array", op)
+		}
+		return &inList{op: op, args: args}, nil
+	case "$size":
+		n, ok := toInteger(arg)
+		if !ok {
+			return nil, errors.New("invalid argument for operator $size, the argument must be an integer")
+		}
+		return &size{size: n}, nil
+	case "$mod":
+		args, ok := arg.([]interface{})
+		if !ok || len(args) != 2 {
+			return nil, errors.New("invalid argument for operator $mod, the argument must be an array of [divisor, remainder]")
+		}
+		divisor, ok1 := toInteger(args[0])
+		remainder, ok2 := toInteger(args[1])
+		if !ok1 || !ok2 || divisor == 0 {
+			return nil, errors.New("invalid argument for operator $mod, the divisor and the remainder must be integers and the divisor must not be zero")
+		}
+		return &mod{divisor: divisor, remainder: remainder}, nil
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return nil, errors.New("invalid argument for operator $regex, the argument must be a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid argument for operator $regex")
+		}
+		return &regex{re: re}, nil
+	case "$elemMatch", "$allMatch":
+		sel, err := parseSelector(arg)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid argument for operator %s", op)
+		}
+		return &elemMatch{all: op == "$allMatch", selector: sel}, nil
+	default:
+		return nil, errors.Errorf("unsupported operator %s", op)
+	}
+}
+
+func (s *selector) matches(v interface{}) bool {
+	for _, c := range s.conditions {
+		if !c.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+type fieldCondition struct {
+	path     []string
+	selector *selector
+}
+
+func (c *fieldCondition) matches(v interface{}) bool {
+	fieldValue, ok := lookupField(v, c.path)
+	if !ok {
+		fieldValue = missing
+	}
+	return c.selector.matches(fieldValue)
+}
+
+type combination struct {
+	op        string
+	selectors []*selector
+}
+
+func (c *combination) matches(v interface{}) bool {
+	switch c.op {
+	case "$and":
+		for _, s := range c.selectors {
+			if !s.matches(v) {
+				return false
+			}
+		}
+		return true
+	case "$or":
+		for _, s := range c.selectors {
+			if s.matches(v) {
+				return true
+			}
+		}
+		return false
+	default: // $nor
+		for _, s := range c.selectors {
+			if s.matches(v) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+type not struct {
+	selector *selector
+}
+
+func (c *not) matches(v interface{}) bool {
+	return !c.selector.matches(v)
+}
+
+type compare struct {
+	op  string
+	arg interface{}
+}
+
+func (c *compare) matches(v interface{}) bool {
+	if v == missing {
+		return false
+	}
+	cmp := compareJSONValues(v, c.arg)
+	switch c.op {
+	case "$lt":
+		return cmp < 0
+	case "$lte":
+		return cmp <= 0
+	case "$eq":
+		return cmp == 0
+	case "$ne":
+		return cmp != 0
+	case "$gte":
+		return cmp >= 0
+	default: // $gt
+		return cmp > 0
+	}
+}
+
+type exists struct {
+	exists bool
+}
+
+func (c *exists) matches(v interface{}) bool {
+	return (v != missing) == c.exists
+}
+
+type typeIs struct {
+	typ string
+}
+
+func (c *typeIs) matches(v interface{}) bool {
+	switch v.(type) {
+	case nil:
+		return c.typ == "null"
+	case bool:
+		return c.typ == "boolean"
+	case json.Number, float64:
+		return c.typ == "number"
+	case string:
+		return c.typ == "string"
+	case []interface{}:
+		return c.typ == "array"
+	case map[string]interface{}:
+		return c.typ == "object"
+	default:
+		return false
+	}
+}
+
+type inList struct {
+	op   string
+	args []interface{}
+}
+
+func (c *inList) matches(v interface{}) bool {
+	if v == missing {
+		return false
+	}
+	contains := func(list []interface{}, e interface{}) bool {
+		for _, l := range list {
+			if compareJSONValues(l, e) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	switch c.op {
+	case "$all":
+		elements, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, a := range c.args {
+			if !contains(elements, a) {
+				return false
+			}
+		}
+		return true
+	default:
+		found := false
+		if elements, ok := v.([]interface{}); ok {
+			for _, e := range elements {
+				if contains(c.args, e) {
+					found = true
+					break
+				}
+			}
+		} else {
+			found = contains(c.args, v)
+		}
+		if c.op == "$in" {
+			return found
+		}
+		return !found
+	}
+}
+
+type size struct {
+	size int64
+}
+
+func (c *size) matches(v interface{}) bool {
+	elements, ok := v.([]interface{})
+	return ok && int64(len(elements)) == c.size
+}
+
+type mod struct {
+	divisor   int64
+	remainder int64
+}
+
+func (c *mod) matches(v interface{}) bool {
+	n, ok := toInteger(v)
+	return ok && n%c.divisor == c.remainder
+}
+
+type regex struct {
+	re *regexp.Regexp
+}
+
+func (c *regex) matches(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && c.re.MatchString(s)
+}
+
+type elemMatch struct {
+	all      bool
+	selector *selector
+}
+
+func (c *elemMatch) matches(v interface{}) bool {
+	elements, ok := v.([]interface{})
+	if !ok || len(elements) == 0 {
+		return false
+	}
+	for _, e := range elements {
+		m := c.selector.matches(e)
+		if m && !c.all {
+			return true
+		}
+		if !m && c.all {
+			return false
+		}
+	}
+	return c.all
+}
+
+// splitFieldPath splits a field name into the names of the nested fields. The names are separated by
+// a period and a period that is part of a name is escaped by a backslash
+func splitFieldPath(field string) []string {
+	var path []string
+	var current strings.Builder
+	for i := 0; i < len(field); i++ {
+		switch {
+		case field[i] == '\\' && i+1 < len(field) && field[i+1] == '.':
+			current.WriteByte('.')
+			i++
+		case field[i] == '.':
+			path = append(path, current.String())
+			current.Reset()
+		default:
+			current.WriteByte(field[i])
+		}
+	}
+	return append(path, current.String())
+}
+
+// lookupField returns the value of a nested field of a JSON value
+func lookupField(v interface{}, path []string) (interface{}, bool) {
+	for _, name := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[name]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+func toInteger(v interface{}) (int64, bool) {
+	var f float64
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, true
+		}
+		var err error
+		if f, err = v.Float64(); err != nil {
+			return 0, false
+		}
+	case float64:
+		f = v
+	default:
+		return 0, false
+	}
+	if f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
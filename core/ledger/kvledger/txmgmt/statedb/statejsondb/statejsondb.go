@@ -0,0 +1,569 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("statejsondb")
+
+var (
+	dataKeyPrefix          = []byte{'d'}
+	dataKeyStopper         = []byte{'e'}
+	indexKeyPrefix         = []byte{'i'}
+	indexDefKeyPrefix      = []byte{'x'}
+	indexDefKeyStopper     = []byte{'y'}
+	nsKeySep               = []byte{0x00}
+	lastKeyIndicator       = byte(0x01)
+	savePointKey           = []byte{'s'}
+	maxDataImportBatchSize = 4 * 1024 * 1024
+)
+
+// dataFormat distinguishes the data of this state database from the data of the stateleveldb, as both
+// the databases are stored in the same directory, so that switching the type of the state database of
+// an existing peer is detected, instead of misinterpreting the data
+const dataFormat = dataformat.CurrentFormat + "-jsondb"
+
+func init() {
+	statedb.RegisterProvider(ledger.JSONDB, func(conf *statedb.ProviderConfig) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(conf.DBPath)
+	})
+}
+
+// VersionedDBProvider implements interface VersionedDBProvider for an embedded state database
+// that stores the state in a goleveldb and supports rich queries on JSON values, without requiring
+// a CouchDB instance. The queries and the index definitions are expected in the format of CouchDB
+type VersionedDBProvider struct {
+	dbProvider *leveldbhelper.Provider
+	mux        sync.Mutex
+	databases  map[string]*versionedDB
+}
+
+// NewVersionedDBProvider instantiates VersionedDBProvider
+func NewVersionedDBProvider(dbPath string) (*VersionedDBProvider, error) {
+	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
+	dbProvider, err := leveldbhelper.NewProvider(
+		&leveldbhelper.Conf{
+			DBPath:         dbPath,
+			ExpectedFormat: dataFormat,
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &VersionedDBProvider{
+		dbProvider: dbProvider,
+		databases:  map[string]*versionedDB{},
+	}, nil
+}
+
+// GetDBHandle gets the handle to a named database
+func (provider *VersionedDBProvider) GetDBHandle(dbName string, namespaceProvider statedb.NamespaceProvider) (statedb.VersionedDB, error) {
+	return provider.getDB(dbName)
+}
+
+func (provider *VersionedDBProvider) getDB(dbName string) (*versionedDB, error) {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	vdb, ok := provider.databases[dbName]
+	if ok {
+		return vdb, nil
+	}
+	vdb, err := newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName)
+	if err != nil {
+		return nil, err
+	}
+	provider.databases[dbName] = vdb
+	return vdb, nil
+}
+
+// ImportFromSnapshot loads the public state and pvtdata hashes from the snapshot files previously generated
+func (provider *VersionedDBProvider) ImportFromSnapshot(
+	dbName string,
+	savepoint *version.Height,
+	itr statedb.FullScanIterator,
+) error {
+	vdb, err := provider.getDB(dbName)
+	if err != nil {
+		return err
+	}
+	return vdb.importState(itr, savepoint)
+}
+
+// BytesKeySupported returns true if a db created supports bytes as a key
+func (provider *VersionedDBProvider) BytesKeySupported() bool {
+	return true
+}
+
+// Close closes the underlying db
+func (provider *VersionedDBProvider) Close() {
+	provider.dbProvider.Close()
+}
+
+// Drop drops channel-specific data from the state database.
+// It is not an error if a database does not exist.
+func (provider *VersionedDBProvider) Drop(dbName string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	delete(provider.databases, dbName)
+	return provider.dbProvider.Drop(dbName)
+}
+
+// versionedDB implements VersionedDB interface. In addition to the data, it maintains the entries of
+// the indexes defined for the namespaces. The indexes are updated atomically with the data
+type versionedDB struct {
+	db     *leveldbhelper.DBHandle
+	dbName string
+
+	// mux serializes the updates to the data with the creation of the indexes
+	mux sync.RWMutex
+	// indexes maintains the definitions of the indexes, by namespace and by <ddoc>/<name>
+	indexes map[string]map[string]*indexDefinition
+}
+
+// newVersionedDB constructs an instance of VersionedDB and loads the definitions of the indexes
+func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) (*versionedDB, error) {
+	vdb := &versionedDB{
+		db:      db,
+		dbName:  dbName,
+		indexes: map[string]map[string]*indexDefinition{},
+	}
+	itr, err := db.GetIterator(indexDefKeyPrefix, indexDefKeyStopper)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	for itr.Next() {
+		def := &indexDefinition{}
+		if err := json.Unmarshal(itr.Value(), def); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal the definition of the index stored under the key [%x]", itr.Key())
+		}
+		vdb.addIndex(decodeIndexDefKey(itr.Key()), def)
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "internal leveldb error while loading the index definitions")
+	}
+	return vdb, nil
+}
+
+// Open implements method in VersionedDB interface
+func (vdb *versionedDB) Open() error {
+	// do nothing because shared db is used
+	return nil
+}
+
+// Close implements method in VersionedDB interface
+func (vdb *versionedDB) Close() {
+	// do nothing because shared db is used
+}
+
+// ValidateKeyValue implements method in VersionedDB interface
+func (vdb *versionedDB) ValidateKeyValue(key string, value []byte) error {
+	return nil
+}
+
+// BytesKeySupported implements method in VersionedDB interface
+func (vdb *versionedDB) BytesKeySupported() bool {
+	return true
+}
+
+// GetState implements method in VersionedDB interface
+func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
+	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
+	dbVal, err := vdb.db.Get(encodeDataKey(namespace, key))
+	if err != nil {
+		return nil, err
+	}
+	if dbVal == nil {
+		return nil, nil
+	}
+	return decodeValue(dbVal)
+}
+
+// GetVersion implements method in VersionedDB interface
+func (vdb *versionedDB) GetVersion(namespace string, key string) (*version.Height, error) {
+	versionedValue, err := vdb.GetState(namespace, key)
+	if err != nil {
+		return nil, err
+	}
+	if versionedValue == nil {
+		return nil, nil
+	}
+	return versionedValue.Version, nil
+}
+
+// GetStateMultipleKeys implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
+	vals := make([]*statedb.VersionedValue, len(keys))
+	for i, key := range keys {
+		val, err := vdb.GetState(namespace, key)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// GetStateRangeScanIterator implements method in VersionedDB interface
+// startKey is inclusive
+// endKey is exclusive
+func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	// pageSize = 0 denotes unlimited page size
+	return vdb.GetStateRangeScanIteratorWithPagination(namespace, startKey, endKey, 0)
+}
+
+// GetStateRangeScanIteratorWithPagination implements method in VersionedDB interface
+func (vdb *versionedDB) GetStateRangeScanIteratorWithPagination(namespace string, startKey string, endKey string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	dataStartKey := encodeDataKey(namespace, startKey)
+	dataEndKey := encodeDataKey(namespace, endKey)
+	if endKey == "" {
+		dataEndKey[len(dataEndKey)-1] = lastKeyIndicator
+	}
+	dbItr, err := vdb.db.GetIterator(dataStartKey, dataEndKey)
+	if err != nil {
+		return nil, err
+	}
+	return &kvScanner{namespace: namespace, dbItr: dbItr, requestedLimit: pageSize}, nil
+}
+
+// ExecuteQuery implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return vdb.ExecuteQueryWithPagination(namespace, query, "", 0)
+}
+
+// ExecuteQueryWithPagination implements method in VersionedDB interface. The bookmark is an opaque
+// string that identifies the position of the last returned result in the scanned range of the keys
+func (vdb *versionedDB) ExecuteQueryWithPagination(namespace, query, bookmark string, pageSize int32) (statedb.QueryResultsIterator, error) {
+	logger.Debugf("Entering ExecuteQueryWithPagination namespace: %s,  query: %s,  bookmark: %s, pageSize: %d", namespace, query, bookmark, pageSize)
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	vdb.mux.RLock()
+	indexes := sortedIndexes(vdb.indexes[namespace])
+	vdb.mux.RUnlock()
+
+	plan, err := planQuery(namespace, q, indexes)
+	if err != nil {
+		return nil, err
+	}
+	skip := q.skip
+	if bookmark != "" {
+		bookmarkKey, err := hex.DecodeString(bookmark)
+		if err != nil {
+			return nil, errors.Errorf("invalid bookmark [%s]", bookmark)
+		}
+		// resume after the last returned result, the skip applies only to the first page
+		switch {
+		case plan.descending && bytes.Compare(bookmarkKey, plan.endKey) < 0:
+			plan.endKey = bookmarkKey
+		case !plan.descending && bytes.Compare(bookmarkKey, plan.startKey) >= 0:
+			plan.startKey = append(bookmarkKey, 0x00)
+		}
+		skip = 0
+	}
+	logger.Debugf("Executing query [%s] on namespace [%s] using the index %+v", query, namespace, plan.index)
+
+	dbItr, err := vdb.db.GetIterator(plan.startKey, plan.endKey)
+	if err != nil {
+		return nil, err
+	}
+	return &queryScanner{
+		vdb:            vdb,
+		namespace:      namespace,
+		query:          q,
+		plan:           plan,
+		dbItr:          dbItr,
+		toSkip:         skip,
+		requestedLimit: pageSize,
+		bookmark:       bookmark,
+	}, nil
+}
+
+// ApplyUpdates implements method in VersionedDB interface
+func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	vdb.mux.Lock()
+	defer vdb.mux.Unlock()
+
+	dbBatch := vdb.db.NewUpdateBatch()
+	namespaces := batch.GetUpdatedNamespaces()
+	for _, ns := range namespaces {
+		updates := batch.GetUpdates(ns)
+		indexes := sortedIndexes(vdb.indexes[ns])
+		for k, vv := range updates {
+			dataKey := encodeDataKey(ns, k)
+			logger.Debugf("Channel [%s]: Applying key(string)=[%s] key(bytes)=[%#v]", vdb.dbName, string(dataKey), dataKey)
+
+			if len(indexes) > 0 {
+				if err := vdb.updateIndexEntries(dbBatch, ns, k, vv, indexes); err != nil {
+					return err
+				}
+			}
+			if vv.Value == nil {
+				dbBatch.Delete(dataKey)
+			} else {
+				encodedVal, err := encodeValue(vv)
+				if err != nil {
+					return err
+				}
+				dbBatch.Put(dataKey, encodedVal)
+			}
+		}
+	}
+	// Record a savepoint at a given height
+	// If a given height is nil, it denotes that we are committing pvt data of old blocks.
+	// In this case, we should not store a savepoint for recovery. The lastUpdatedOldBlockList
+	// in the pvtstore acts as a savepoint for pvt data.
+	if height != nil {
+		dbBatch.Put(savePointKey, height.ToBytes())
+	}
+	// Setting snyc to true as a precaution, false may be an ok optimization after further testing.
+	return vdb.db.WriteBatch(dbBatch, true)
+}
+
+// updateIndexEntries replaces the index entries for the committed value of a key with the
+// entries for the new value
+func (vdb *versionedDB) updateIndexEntries(
+	dbBatch *leveldbhelper.UpdateBatch,
+	ns, key string,
+	vv *statedb.VersionedValue,
+	indexes []*indexDefinition,
+) error {
+	committedVal, err := vdb.GetState(ns, key)
+	if err != nil {
+		return err
+	}
+	if committedVal != nil {
+		committedDoc := decodeJSONObject(committedVal.Value)
+		for _, def := range indexes {
+			if entryKey := indexEntryKey(ns, def, key, committedDoc); entryKey != nil {
+				dbBatch.Delete(entryKey)
+			}
+		}
+	}
+	if vv.Value == nil {
+		return nil
+	}
+	doc := decodeJSONObject(vv.Value)
+	for _, def := range indexes {
+		if entryKey := indexEntryKey(ns, def, key, doc); entryKey != nil {
+			dbBatch.Put(entryKey, []byte(key))
+		}
+	}
+	return nil
+}
+
+// GetLatestSavePoint implements method in VersionedDB interface
+func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
+	versionBytes, err := vdb.db.Get(savePointKey)
+	if err != nil {
+		return nil, err
+	}
+	if versionBytes == nil {
+		return nil, nil
+	}
+	version, _, err := version.NewHeightFromBytes(versionBytes)
+	if err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// GetFullScanIterator implements method in VersionedDB interface. This function returns a
+// FullScanIterator that can be used to iterate over entire data in the statedb for a channel.
+// `skipNamespace` parameter can be used to control if the consumer wants the FullScanIterator
+// to skip one or more namespaces from the returned results. The index entries are not included
+// in the results, as the indexes are rebuilt from the chaincode definitions
+func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(string) bool) (statedb.FullScanIterator, error) {
+	return newFullDBScanner(vdb.db, skipNamespace)
+}
+
+// GetDBType implements method in IndexCapable interface. The type "couchdb" is returned so
+// that the CouchDB indexes packaged with a chaincode are applied to this state database
+func (vdb *versionedDB) GetDBType() string {
+	return "couchdb"
+}
+
+// ProcessIndexesForChaincodeDeploy implements method in IndexCapable interface. As in the case
+// of CouchDB, the index files are processed in the order of their names, so that the resulting
+// indexes are the same in all the peers, and an invalid index file is logged and skipped
+func (vdb *versionedDB) ProcessIndexesForChaincodeDeploy(namespace string, indexFilesData map[string][]byte) error {
+	var indexFilesName []string
+	for fileName := range indexFilesData {
+		indexFilesName = append(indexFilesName, fileName)
+	}
+	sort.Strings(indexFilesName)
+	for _, fileName := range indexFilesName {
+		def, err := parseIndexDefinition(indexFilesData[fileName])
+		if err == nil {
+			err = vdb.createIndex(namespace, def)
+		}
+		switch {
+		case err != nil:
+			logger.Errorf("error creating index from file [%s] for chaincode [%s] on channel [%s]: %+v",
+				fileName, namespace, vdb.dbName, err)
+		default:
+			logger.Infof("successfully created index present in the file [%s] for chaincode [%s] on channel [%s]",
+				fileName, namespace, vdb.dbName)
+		}
+	}
+	return nil
+}
+
+// createIndex builds the entries of an index for the existing data of a namespace and stores the definition
+// of the index. The definition is stored after the entries so that an index is never used before all of its
+// entries are built. An existing index with the same design document and name is replaced
+func (vdb *versionedDB) createIndex(ns string, def *indexDefinition) error {
+	vdb.mux.Lock()
+	defer vdb.mux.Unlock()
+
+	if existing, ok := vdb.indexes[ns][def.DDoc+"/"+def.Name]; ok && existing.sameAs(def) {
+		return nil
+	}
+
+	// remove the entries of the existing index, if any, and any leftovers of a previously interrupted creation
+	prefix := indexEntriesPrefix(ns, def)
+	if err := vdb.deleteRange(prefix, endOfRange(prefix)); err != nil {
+		return err
+	}
+
+	dataPrefix := encodeDataKeyPrefix(ns)
+	dbItr, err := vdb.db.GetIterator(dataPrefix, endOfRange(dataPrefix))
+	if err != nil {
+		return err
+	}
+	defer dbItr.Release()
+	dbBatch := vdb.db.NewUpdateBatch()
+	batchSize := 0
+	for dbItr.Next() {
+		_, key := decodeDataKey(dbItr.Key())
+		vv, err := decodeValue(dbItr.Value())
+		if err != nil {
+			return err
+		}
+		entryKey := indexEntryKey(ns, def, key, decodeJSONObject(vv.Value))
+		if entryKey == nil {
+			continue
+		}
+		dbBatch.Put(entryKey, []byte(key))
+		batchSize += len(entryKey) + len(key)
+		if batchSize >= maxDataImportBatchSize {
+			if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+				return err
+			}
+			batchSize = 0
+			dbBatch.Reset()
+		}
+	}
+	if err := dbItr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while building the index")
+	}
+
+	defBytes, err := json.Marshal(def)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the index definition")
+	}
+	dbBatch.Put(encodeIndexDefKey(ns, def.DDoc, def.Name), defBytes)
+	if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	vdb.addIndex(ns, def)
+	return nil
+}
+
+func (vdb *versionedDB) addIndex(ns string, def *indexDefinition) {
+	if vdb.indexes[ns] == nil {
+		vdb.indexes[ns] = map[string]*indexDefinition{}
+	}
+	vdb.indexes[ns][def.DDoc+"/"+def.Name] = def
+}
+
+func (vdb *versionedDB) deleteRange(startKey, endKey []byte) error {
+	dbItr, err := vdb.db.GetIterator(startKey, endKey)
+	if err != nil {
+		return err
+	}
+	defer dbItr.Release()
+	dbBatch := vdb.db.NewUpdateBatch()
+	for dbItr.Next() {
+		dbBatch.Delete(append([]byte{}, dbItr.Key()...))
+	}
+	if err := dbItr.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while deleting the index entries")
+	}
+	return vdb.db.WriteBatch(dbBatch, true)
+}
+
+// importState implements method in VersionedDB interface. The function is expected to be used
+// for importing the state from a previously snapshotted state. The parameter itr provides access to
+// the snapshotted state.
+func (vdb *versionedDB) importState(itr statedb.FullScanIterator, savepoint *version.Height) error {
+	if itr == nil {
+		return vdb.db.Put(savePointKey, savepoint.ToBytes(), true)
+	}
+	dbBatch := vdb.db.NewUpdateBatch()
+	batchSize := 0
+	for {
+		versionedKV, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if versionedKV == nil {
+			break
+		}
+		dbKey := encodeDataKey(versionedKV.Namespace, versionedKV.Key)
+		dbValue, err := encodeValue(versionedKV.VersionedValue)
+		if err != nil {
+			return err
+		}
+		batchSize += len(dbKey) + len(dbValue)
+		dbBatch.Put(dbKey, dbValue)
+		if batchSize >= maxDataImportBatchSize {
+			if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+				return err
+			}
+			batchSize = 0
+			dbBatch.Reset()
+		}
+	}
+	dbBatch.Put(savePointKey, savepoint.ToBytes())
+	return vdb.db.WriteBatch(dbBatch, true)
+}
+
+func encodeDataKeyPrefix(ns string) []byte {
+	k := append([]byte{}, dataKeyPrefix...)
+	k = append(k, []byte(ns)...)
+	return append(k, nsKeySep...)
+}
+
+func encodeDataKey(ns, key string) []byte {
+	return append(encodeDataKeyPrefix(ns), []byte(key)...)
+}
+
+func decodeDataKey(encodedDataKey []byte) (string, string) {
+	split := bytes.SplitN(encodedDataKey, nsKeySep, 2)
+	return string(split[0][1:]), string(split[1])
+}
+
+func dataKeyStarterForNextNamespace(ns string) []byte {
+	k := append([]byte{}, dataKeyPrefix...)
+	k = append(k, []byte(ns)...)
+	return append(k, lastKeyIndicator)
+}
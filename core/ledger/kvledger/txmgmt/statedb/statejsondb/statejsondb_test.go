@@ -0,0 +1,324 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBasicRW(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestBasicRW(t, env.DBProvider)
+}
+
+func TestMultiDBBasicRW(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestMultiDBBasicRW(t, env.DBProvider)
+}
+
+func TestDeletes(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDeletes(t, env.DBProvider)
+}
+
+func TestIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestIterator(t, env.DBProvider)
+}
+
+func TestQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestQuery(t, env.DBProvider)
+}
+
+func TestGetStateMultipleKeys(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestGetStateMultipleKeys(t, env.DBProvider)
+}
+
+func TestGetVersion(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestGetVersion(t, env.DBProvider)
+}
+
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
+func TestRangeQuerySpecialCharacters(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestRangeQuerySpecialCharacters(t, env.DBProvider)
+}
+
+func TestApplyUpdatesWithNilHeight(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestApplyUpdatesWithNilHeight(t, env.DBProvider)
+}
+
+func TestDataExportImport(t *testing.T) {
+	// smaller batch size for testing to cover the boundary case of writing the final batch
+	maxDataImportBatchSize = 10
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestDataExportImport(
+		t,
+		env.DBProvider,
+	)
+}
+
+func TestDrop(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+
+	checkDBsAfterDropFunc := func(channelName string) {
+		empty, err := env.DBProvider.dbProvider.GetDBHandle(channelName).IsEmpty()
+		require.NoError(t, err)
+		require.True(t, empty)
+	}
+
+	commontests.TestDrop(t, env.DBProvider, checkDBsAfterDropFunc)
+}
+
+func TestRegistry(t *testing.T) {
+	dbPath, err := ioutil.TempDir("", "statejsondb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dbPath)
+
+	require.Contains(t, statedb.RegisteredProviders(), ledger.JSONDB)
+	provider, err := statedb.NewProvider(ledger.JSONDB, &statedb.ProviderConfig{DBPath: dbPath})
+	require.NoError(t, err)
+	defer provider.Close()
+	require.IsType(t, &VersionedDBProvider{}, provider)
+}
+
+func TestDataFormatMismatch(t *testing.T) {
+	dbPath, err := ioutil.TempDir("", "statejsondb")
+	require.NoError(t, err)
+	defer os.RemoveAll(dbPath)
+	p, err := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath, ExpectedFormat: "2.0"})
+	require.NoError(t, err)
+	require.NoError(t, p.GetDBHandle("ch1").Put([]byte("key"), []byte("value"), true))
+	p.Close()
+
+	_, err = NewVersionedDBProvider(dbPath)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unexpected format")
+}
+
+func TestIndexes(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testindexes", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 6; i++ {
+		owner := []string{"tom", "jerry"}[i%2]
+		batch.Put("ns1", fmt.Sprintf("key%d", i),
+			[]byte(fmt.Sprintf(`{"docType":"marble","owner":"%s","size":%d}`, owner, i*10)), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns1", "nonjson", []byte("not a json value"), version.NewHeight(1, 7))
+	batch.Put("ns1", "nosize", []byte(`{"docType":"marble","owner":"tom"}`), version.NewHeight(1, 8))
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 8)))
+
+	indexCapable, ok := db.(statedb.IndexCapable)
+	require.True(t, ok)
+	require.Equal(t, "couchdb", indexCapable.GetDBType())
+
+	// a sort requires an index
+	_, err = db.ExecuteQuery("ns1", `{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`)
+	require.EqualError(t, err, "no index exists for the sort [size], try indexing by the sort fields")
+
+	require.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"indexSize.json":    []byte(`{"index":{"fields":[{"size":"desc"}]},"ddoc":"indexSizeDoc","name":"indexSize","type":"json"}`),
+		"indexOwner.json":   []byte(`{"index":{"fields":["owner","size"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`),
+		"invalidIndex.json": []byte(`{"index":{"fields":["owner"]},"type":"text"}`),
+	}))
+
+	t.Run("sort using an index built for the existing data", func(t *testing.T) {
+		itr, err := db.ExecuteQuery("ns1", `{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key6", "key5", "key4", "key3", "key2", "key1"}, keys(t, itr))
+	})
+
+	t.Run("index maintained on updates", func(t *testing.T) {
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns1", "key1", []byte(`{"docType":"marble","owner":"jerry","size":100}`), version.NewHeight(2, 1))
+		batch.Delete("ns1", "key6", version.NewHeight(2, 2))
+		batch.Put("ns1", "key7", []byte(`{"docType":"marble","owner":"tom","size":35}`), version.NewHeight(2, 3))
+		require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 3)))
+
+		itr, err := db.ExecuteQuery("ns1", `{"selector":{"size":{"$gt":20}},"sort":[{"size":"asc"}]}`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key3", "key7", "key4", "key5", "key1"}, keys(t, itr))
+
+		itr, err = db.ExecuteQuery("ns1", `{"selector":{"owner":"tom","size":{"$lte":40}}}`)
+		require.NoError(t, err)
+		require.Equal(t, []string{"key2", "key7", "key4"}, keys(t, itr))
+	})
+
+	t.Run("index survives reopening", func(t *testing.T) {
+		env.DBProvider.Close()
+		provider, err := NewVersionedDBProvider(env.dbPath)
+		require.NoError(t, err)
+		env.DBProvider = provider
+		db, err := provider.GetDBHandle("testindexes", nil)
+		require.NoError(t, err)
+
+		itr, err := db.ExecuteQuery("ns1", `{"selector":{"owner":"jerry"},"sort":["owner","size"],"fields":["size"]}`)
+		require.NoError(t, err)
+		var values []string
+		for {
+			kv, err := itr.Next()
+			require.NoError(t, err)
+			if kv == nil {
+				break
+			}
+			values = append(values, string(kv.Value))
+		}
+		require.Equal(t, []string{`{"size":30}`, `{"size":50}`, `{"size":100}`}, values)
+	})
+}
+
+func TestQueryPagination(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testpagination", nil)
+	require.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 9; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf(`{"color":"blue","size":%d}`, i)), version.NewHeight(1, uint64(i)))
+	}
+	require.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 9)))
+	require.NoError(t, db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1", map[string][]byte{
+		"indexSize.json": []byte(`{"index":{"fields":["size"]},"name":"indexSize"}`),
+	}))
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected [][]string
+	}{
+		{
+			name:     "data scan",
+			query:    `{"selector":{"color":"blue","_id":{"$gt":"key1"}}}`,
+			expected: [][]string{{"key2", "key3", "key4"}, {"key5", "key6", "key7"}, {"key8", "key9"}, nil},
+		},
+		{
+			name:     "descending index scan with skip",
+			query:    `{"selector":{"size":{"$lt":9}},"sort":[{"size":"desc"}],"skip":2}`,
+			expected: [][]string{{"key6", "key5", "key4"}, {"key3", "key2", "key1"}, nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bookmark := ""
+			for _, expected := range tc.expected {
+				itr, err := db.ExecuteQueryWithPagination("ns1", tc.query, bookmark, 3)
+				require.NoError(t, err)
+				var results []string
+				for {
+					kv, err := itr.Next()
+					require.NoError(t, err)
+					if kv == nil {
+						break
+					}
+					results = append(results, kv.Key)
+				}
+				require.Equal(t, expected, results)
+				bookmark = itr.GetBookmarkAndClose()
+			}
+		})
+	}
+
+	_, err = db.ExecuteQueryWithPagination("ns1", `{"selector":{"color":"blue"}}`, "not-a-bookmark", 3)
+	require.EqualError(t, err, "invalid bookmark [not-a-bookmark]")
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := map[string]string{
+		`{"fields":["owner"]}`:                                                  "the selector is not specified",
+		`{"selector":{"owner":{"$near":"tom"}}}`:                                "unsupported operator $near",
+		`{"selector":{"owner":"tom"},"limit2":10}`:                              "unsupported parameter [limit2]",
+		`{"selector":{"owner":"tom"},"sort":[{"owner":"asc"},{"size":"desc"}]}`: "sort fields must all be in the same direction",
+	}
+	for query, expectedErr := range testCases {
+		_, err := parseQuery(query)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), expectedErr)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	doc := decodeJSONObject([]byte(`{"owner":"tom","size":10,"tags":["a","b"],"address":{"city":"x.y"},"a.b":1}`))
+	testCases := map[string]bool{
+		`{"owner":"tom"}`:                                         true,
+		`{"owner":{"$ne":"tom"}}`:                                 false,
+		`{"size":{"$gte":10,"$lt":11}}`:                           true,
+		`{"size":{"$gt":"10"}}`:                                   false,
+		`{"owner":{"$gt":10}}`:                                    true,
+		`{"address.city":"x.y"}`:                                  true,
+		`{"address":{"city":{"$regex":"^x"}}}`:                    true,
+		`{"a\\.b":1}`:                                             true,
+		`{"tags":{"$all":["b","a"]}}`:                             true,
+		`{"tags":{"$elemMatch":{"$eq":"c"}}}`:                     false,
+		`{"tags":{"$size":2}}`:                                    true,
+		`{"size":{"$in":[5,10]}}`:                                 true,
+		`{"size":{"$nin":[5,10]}}`:                                false,
+		`{"size":{"$mod":[3,1]}}`:                                 true,
+		`{"color":{"$exists":false}}`:                             true,
+		`{"$nor":[{"owner":"jerry"},{"size":11}]}`:                true,
+		`{"$or":[{"owner":"jerry"},{"size":{"$type":"string"}}]}`: false,
+	}
+	for sel, expected := range testCases {
+		q, err := parseQuery(`{"selector":` + sel + `}`)
+		require.NoError(t, err, sel)
+		require.Equal(t, expected, q.selector.matches(doc), sel)
+	}
+}
+
+func keys(t *testing.T, itr statedb.ResultsIterator) []string {
+	defer itr.Close()
+	var keys []string
+	for {
+		kv, err := itr.Next()
+		require.NoError(t, err)
+		if kv == nil {
+			return keys
+		}
+		keys = append(keys, kv.Key)
+	}
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statejsondb
+
+import (
+	proto "github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+)
+
+// encodeValue encodes the value, version, and metadata
+func encodeValue(v *statedb.VersionedValue) ([]byte, error) {
+	return proto.Marshal(
+		&DBValue{
+			Version:  v.Version.ToBytes(),
+			Value:    v.Value,
+			Metadata: v.Metadata,
+		},
+	)
+}
+
+// decodeValue decodes the statedb value bytes
+func decodeValue(encodedValue []byte) (*statedb.VersionedValue, error) {
+	dbValue := &DBValue{}
+	err := proto.Unmarshal(encodedValue, dbValue)
+	if err != nil {
+		return nil, err
+	}
+	ver, _, err := version.NewHeightFromBytes(dbValue.Version)
+	if err != nil {
+		return nil, err
+	}
+	val := dbValue.Value
+	metadata := dbValue.Metadata
+	// protobuf always makes an empty byte array as nil
+	if val == nil {
+		val = []byte{}
+	}
+	return &statedb.VersionedValue{Version: ver, Value: val, Metadata: metadata}, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/dataformat"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/pkg/errors"
@@ -29,6 +30,12 @@ var (
 	maxDataImportBatchSize = 4 * 1024 * 1024
 )
 
+func init() {
+	statedb.RegisterProvider(ledger.GoLevelDB, func(conf *statedb.ProviderConfig) (statedb.VersionedDBProvider, error) {
+		return NewVersionedDBProvider(conf.DBPath)
+	})
+}
+
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
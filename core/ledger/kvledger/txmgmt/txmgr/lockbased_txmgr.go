@@ -618,9 +618,13 @@ func (txmgr *LockBasedTxMgr) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvt
 
 // ExportPubStateAndPvtStateHashes simply delegates the call to the statedb for exporting the data for a snapshot.
 // It is assumed that the consumer would invoke this function when the commits are paused
-func (txmgr *LockBasedTxMgr) ExportPubStateAndPvtStateHashes(dir string, newHashFunc snapshot.NewHashFunc) (map[string][]byte, error) {
+func (txmgr *LockBasedTxMgr) ExportPubStateAndPvtStateHashes(
+	dir string,
+	newHashFunc snapshot.NewHashFunc,
+	pubStateProgress, pvtStateHashesProgress *snapshot.Progress,
+) (map[string][]byte, error) {
 	// no need to acuqire any lock in this function, as the commits would be paused
-	return txmgr.db.ExportPubStateAndPvtStateHashes(dir, newHashFunc)
+	return txmgr.db.ExportPubStateAndPvtStateHashes(dir, newHashFunc, pubStateProgress, pvtStateHashesProgress)
 }
 
 func extractStateUpdates(batch *privacyenabledstate.UpdateBatch, namespaces []string) ledger.StateUpdates {
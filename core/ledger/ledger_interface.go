@@ -25,6 +25,7 @@ import (
 const (
 	GoLevelDB = "goleveldb"
 	CouchDB   = "CouchDB"
+	JSONDB    = "JSONDB"
 )
 
 // Initializer encapsulates dependencies for PeerLedgerProvider
@@ -52,12 +53,53 @@ type Config struct {
 	HistoryDBConfig *HistoryDBConfig
 	// SnapshotsConfig holds the configuration parameters for the snapshots.
 	SnapshotsConfig *SnapshotsConfig
+	// BlockStoreConfig holds the configuration parameters for the block store.
+	BlockStoreConfig *BlockStoreConfig
+}
+
+// BlockStoreConfig is a structure used to configure the block store.
+type BlockStoreConfig struct {
+	// Compression is the compression applied to the blocks added to the block store. The supported values
+	// are "none" and "snappy". The blocks that are already stored are not affected by a change of this value.
+	Compression string
+	// Archive, if not nil, enables moving the completed block files to an archive store.
+	Archive *BlockArchiveConfig
+}
+
+// BlockArchiveConfig is a structure used to configure the archiving of the completed block files.
+type BlockArchiveConfig struct {
+	// Type is the type of the archive store. The supported values are "localdir" and "s3".
+	Type string
+	// LocalFiles is the number of the most recent completed block files that are retained on the local disk,
+	// in addition to the block file being appended.
+	LocalFiles int
+	// CacheDir is the directory in which the archived block files are cached when these are read.
+	// It defaults to a directory under the block store.
+	CacheDir string
+	// CacheSize is the maximum number of the archived block files cached per ledger.
+	CacheSize int
+	// LocalDir is the directory in which a "localdir" archive store keeps the archived block files.
+	LocalDir string
+	// S3 holds the configuration parameters for an "s3" archive store.
+	S3 *BlockArchiveS3Config
+}
+
+// BlockArchiveS3Config is a structure used to configure an archive store that implements the Amazon S3 API.
+type BlockArchiveS3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	Timeout         time.Duration
 }
 
 // StateDBConfig is a structure used to configure the state parameters for the ledger.
 type StateDBConfig struct {
 	// StateDatabase is the database to use for storing last known state.  The
-	// two supported options are "goleveldb" and "CouchDB" (captured in the constants GoLevelDB and CouchDB respectively).
+	// in-tree options are "goleveldb", "CouchDB", and "JSONDB" (captured in the constants GoLevelDB, CouchDB,
+	// and JSONDB respectively). Additional state databases may be registered with the statedb registry.
 	StateDatabase string
 	// CouchDB is the configuration for CouchDB.  It is used when StateDatabase
 	// is set to "CouchDB".
@@ -132,6 +174,24 @@ type HistoryDBConfig struct {
 type SnapshotsConfig struct {
 	// RootDir is the top-level directory for the snapshots.
 	RootDir string
+	// Policy, if not nil, configures the automatic generation of the snapshots and the retention of the
+	// completed snapshots, for each channel.
+	Policy *SnapshotPolicyConfig
+}
+
+// SnapshotPolicyConfig is a structure used to configure the automatic generation of the snapshots.
+// A snapshot is generated when either of the intervals is reached, in addition to the snapshots
+// requested via SubmitSnapshotRequest.
+type SnapshotPolicyConfig struct {
+	// BlockInterval, if non-zero, causes a snapshot to be generated for each block number that is a
+	// multiple of BlockInterval, so that the peers of a channel generate the snapshots at the same heights.
+	BlockInterval uint64
+	// TimeInterval, if non-zero, causes a snapshot to be generated at the first block committed after
+	// TimeInterval has elapsed since the generation of the previous snapshot started.
+	TimeInterval time.Duration
+	// Retain, if non-zero, is the number of the most recent completed snapshots retained for a channel.
+	// The older snapshots, including the ones generated on request, are deleted after a snapshot is generated.
+	Retain int
 }
 
 // PeerLedgerProvider provides handle to ledger instances
@@ -218,6 +278,58 @@ type PeerLedger interface {
 	CancelSnapshotRequest(height uint64) error
 	// PendingSnapshotRequests returns a list of heights for the pending (or under processing) snapshot requests.
 	PendingSnapshotRequests() ([]uint64, error)
+	// SnapshotGenerationProgress returns the progress of the snapshot being generated, or nil if no snapshot
+	// is being generated.
+	SnapshotGenerationProgress() *SnapshotProgress
+}
+
+// SnapshotPhase is a phase of the snapshot generation
+type SnapshotPhase string
+
+const (
+	// SnapshotPhaseTxIDs exports the IDs of the transactions from the block store
+	SnapshotPhaseTxIDs SnapshotPhase = "txids"
+	// SnapshotPhaseConfigHistory exports the history of the collection configurations
+	SnapshotPhaseConfigHistory SnapshotPhase = "config_history"
+	// SnapshotPhasePublicState exports the public state. This phase runs along with the phase
+	// SnapshotPhasePrivateStateHashes, in a single pass over the state database
+	SnapshotPhasePublicState SnapshotPhase = "public_state"
+	// SnapshotPhasePrivateStateHashes exports the hashes of the private state
+	SnapshotPhasePrivateStateHashes SnapshotPhase = "private_state_hashes"
+)
+
+// SnapshotPhaseStatus is the status of a phase of the snapshot generation
+type SnapshotPhaseStatus int
+
+const (
+	SnapshotPhasePending SnapshotPhaseStatus = iota
+	SnapshotPhaseInProgress
+	SnapshotPhaseDone
+)
+
+// SnapshotPhaseProgress reports the progress of a phase of the snapshot generation
+type SnapshotPhaseProgress struct {
+	Phase          SnapshotPhase
+	Status         SnapshotPhaseStatus
+	RecordsWritten uint64
+	BytesWritten   uint64
+}
+
+// SnapshotProgress reports the progress of the snapshot generation
+type SnapshotProgress struct {
+	// BlockNumber is the block number for which the snapshot is being generated
+	BlockNumber uint64
+	StartTime   time.Time
+	// Phases contains the progress of each phase, in the order of execution
+	Phases         []*SnapshotPhaseProgress
+	RecordsWritten uint64
+	BytesWritten   uint64
+	// EstimatedTotalBytes is the size of the most recent snapshot of the ledger, if any, which is taken
+	// as the estimate of the size of the snapshot being generated. It is zero if there is no previous snapshot.
+	EstimatedTotalBytes uint64
+	// EstimatedCompletionTime extrapolates the rate at which the bytes have been written so far to
+	// EstimatedTotalBytes. It is the zero time if the completion time cannot be estimated.
+	EstimatedCompletionTime time.Time
 }
 
 // SimpleQueryExecutor encapsulates basic functions
@@ -294,6 +406,36 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, newest first, restricted by the
+	// supplied options. The returned QueryResultsIterator contains results of type *KeyModification which is
+	// defined in fabric-protos/ledger/queryresult. If a page size is specified in the options, the bookmark
+	// returned by the iterator can be used in the options of the subsequent call for retrieving the next page
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (commonledger.QueryResultsIterator, error)
+	// GetPrivateDataHashHistory retrieves the history of the hashed writes to a private data key, newest first.
+	// The returned ResultsIterator contains results of type *KeyModification which is defined in fabric-protos/ledger/queryresult,
+	// with the Value carrying the hash of the value written. Note that only the hashed writes committed after the
+	// upgrade to a peer version that indexes the private data hashes are present, unless the databases are rebuilt
+	// with `peer node rebuild-dbs`
+	GetPrivateDataHashHistory(namespace, collection, key string) (commonledger.ResultsIterator, error)
+}
+
+// HistoryQueryOptions restricts the results of a history query
+type HistoryQueryOptions struct {
+	// StartBlock and EndBlock restrict the results to the modifications committed in the blocks within
+	// [StartBlock, EndBlock]. An EndBlock of zero means no upper bound
+	StartBlock uint64
+	EndBlock   uint64
+	// StartTime and EndTime restrict the results to the modifications made by the transactions whose
+	// timestamps are within [StartTime, EndTime]. A zero value means no bound. As the timestamps are
+	// recorded in the transactions, the transactions in the block range are retrieved for evaluating
+	// the time range and hence a block range should also be specified for large histories
+	StartTime time.Time
+	EndTime   time.Time
+	// PageSize limits the number of results returned, zero means no limit. Bookmark is the bookmark
+	// returned by the query for the previous page and an empty bookmark is returned when there are no more
+	// results. A bookmark older than the StartBlock is rejected
+	PageSize int32
+	Bookmark string
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
@@ -107,6 +108,82 @@ func (s *SnapshotService) QueryPendings(ctx context.Context, signedRequest *pb.S
 	return &pb.QueryPendingSnapshotsResponse{BlockNumbers: result}, nil
 }
 
+// QueryStatus returns the pending snapshot requests along with the progress of the snapshot being generated, if any.
+func (s *SnapshotService) QueryStatus(ctx context.Context, signedQuery *SignedSnapshotQuery) (*QuerySnapshotStatusResponse, error) {
+	signedRequest := &pb.SignedSnapshotRequest{Request: signedQuery.Request, Signature: signedQuery.Signature}
+	query := &pb.SnapshotQuery{}
+	if err := proto.Unmarshal(signedRequest.Request, query); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal snapshot request")
+	}
+
+	if err := s.checkACL(resources.Snapshot_status, query.SignatureHeader, signedRequest); err != nil {
+		return nil, err
+	}
+
+	lgr, err := s.getLedger(query.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+
+	// the progress is read before the pending requests so that a request that completes
+	// in between is not reported as pending without the progress
+	progress := lgr.SnapshotGenerationProgress()
+	blockNumbers, err := lgr.PendingSnapshotRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &QuerySnapshotStatusResponse{}
+	for _, blockNumber := range blockNumbers {
+		requestStatus := &SnapshotRequestStatus{BlockNumber: blockNumber}
+		if progress != nil && progress.BlockNumber == blockNumber {
+			if requestStatus.Progress, err = toSnapshotProgressProto(progress); err != nil {
+				return nil, err
+			}
+		}
+		response.Requests = append(response.Requests, requestStatus)
+	}
+	return response, nil
+}
+
+func toSnapshotProgressProto(progress *ledger.SnapshotProgress) (*SnapshotProgress, error) {
+	startTime, err := ptypes.TimestampProto(progress.StartTime)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert the start time of the snapshot generation")
+	}
+	p := &SnapshotProgress{
+		StartTime:           startTime,
+		RecordsWritten:      progress.RecordsWritten,
+		BytesWritten:        progress.BytesWritten,
+		EstimatedTotalBytes: progress.EstimatedTotalBytes,
+	}
+	if !progress.EstimatedCompletionTime.IsZero() {
+		if p.EstimatedCompletionTime, err = ptypes.TimestampProto(progress.EstimatedCompletionTime); err != nil {
+			return nil, errors.Wrap(err, "failed to convert the estimated completion time of the snapshot generation")
+		}
+	}
+	for _, phaseProgress := range progress.Phases {
+		p.Phases = append(p.Phases, &SnapshotPhaseProgress{
+			Phase:          string(phaseProgress.Phase),
+			Status:         toPhaseStatusProto(phaseProgress.Status),
+			RecordsWritten: phaseProgress.RecordsWritten,
+			BytesWritten:   phaseProgress.BytesWritten,
+		})
+	}
+	return p, nil
+}
+
+func toPhaseStatusProto(status ledger.SnapshotPhaseStatus) SnapshotPhaseProgress_Status {
+	switch status {
+	case ledger.SnapshotPhaseInProgress:
+		return SnapshotPhaseProgress_IN_PROGRESS
+	case ledger.SnapshotPhaseDone:
+		return SnapshotPhaseProgress_DONE
+	default:
+		return SnapshotPhaseProgress_PENDING
+	}
+}
+
 func (s *SnapshotService) checkACL(resName string, signatureHdr *cb.SignatureHeader, signedRequest *pb.SignedSnapshotRequest) error {
 	if signatureHdr == nil {
 		return errors.New("missing signature header")
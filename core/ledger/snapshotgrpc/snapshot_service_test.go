@@ -11,13 +11,19 @@ import (
 	"fmt"
 	"io/ioutil"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
 	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc/mock"
+	peermock "github.com/hyperledger/fabric/core/peer/mock"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
 )
@@ -152,6 +158,83 @@ func TestSnapshot(t *testing.T) {
 	)
 }
 
+func TestQueryStatus(t *testing.T) {
+	startTime := time.Unix(1000, 0)
+	fakeLedger := &peermock.PeerLedger{}
+	fakeLedger.PendingSnapshotRequestsReturns([]uint64{50, 200}, nil)
+	fakeLedgerGetter := &mock.LedgerGetter{}
+	fakeLedgerGetter.GetLedgerReturns(fakeLedger)
+	fakeACLProvider := &mock.ACLProvider{}
+	snapshotSvc := &SnapshotService{LedgerGetter: fakeLedgerGetter, ACLProvider: fakeACLProvider}
+	signedQuery := createSignedStatusQuery("testsnapshot")
+
+	resp, err := snapshotSvc.QueryStatus(context.Background(), signedQuery)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&QuerySnapshotStatusResponse{
+		Requests: []*SnapshotRequestStatus{{BlockNumber: 50}, {BlockNumber: 200}},
+	}, resp))
+	resName, _ := fakeACLProvider.CheckACLNoChannelArgsForCall(0)
+	require.Equal(t, resources.Snapshot_status, resName)
+
+	fakeLedger.SnapshotGenerationProgressReturns(&ledger.SnapshotProgress{
+		BlockNumber: 50,
+		StartTime:   startTime,
+		Phases: []*ledger.SnapshotPhaseProgress{
+			{Phase: ledger.SnapshotPhaseTxIDs, Status: ledger.SnapshotPhaseDone, RecordsWritten: 10, BytesWritten: 100},
+			{Phase: ledger.SnapshotPhasePublicState, Status: ledger.SnapshotPhaseInProgress, RecordsWritten: 5, BytesWritten: 50},
+			{Phase: ledger.SnapshotPhasePrivateStateHashes, Status: ledger.SnapshotPhasePending},
+		},
+		RecordsWritten:          15,
+		BytesWritten:            150,
+		EstimatedTotalBytes:     300,
+		EstimatedCompletionTime: startTime.Add(time.Minute),
+	})
+	resp, err = snapshotSvc.QueryStatus(context.Background(), signedQuery)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(&QuerySnapshotStatusResponse{
+		Requests: []*SnapshotRequestStatus{
+			{
+				BlockNumber: 50,
+				Progress: &SnapshotProgress{
+					StartTime: &timestamp.Timestamp{Seconds: 1000},
+					Phases: []*SnapshotPhaseProgress{
+						{Phase: "txids", Status: SnapshotPhaseProgress_DONE, RecordsWritten: 10, BytesWritten: 100},
+						{Phase: "public_state", Status: SnapshotPhaseProgress_IN_PROGRESS, RecordsWritten: 5, BytesWritten: 50},
+						{Phase: "private_state_hashes", Status: SnapshotPhaseProgress_PENDING},
+					},
+					RecordsWritten:          15,
+					BytesWritten:            150,
+					EstimatedTotalBytes:     300,
+					EstimatedCompletionTime: &timestamp.Timestamp{Seconds: 1060},
+				},
+			},
+			{BlockNumber: 200},
+		},
+	}, resp), "unexpected response %s", resp)
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		_, err := snapshotSvc.QueryStatus(context.Background(), &SignedSnapshotQuery{Request: []byte("dummy")})
+		require.EqualError(t, err, "failed to unmarshal snapshot request: proto: can't skip unknown wire type 4")
+	})
+
+	t.Run("missing channel ID", func(t *testing.T) {
+		_, err := snapshotSvc.QueryStatus(context.Background(), createSignedStatusQuery(""))
+		require.EqualError(t, err, "missing channel ID")
+	})
+
+	t.Run("ledger error", func(t *testing.T) {
+		fakeLedger.PendingSnapshotRequestsReturns(nil, fmt.Errorf("fake-ledger-error"))
+		_, err := snapshotSvc.QueryStatus(context.Background(), signedQuery)
+		require.EqualError(t, err, "fake-ledger-error")
+	})
+
+	t.Run("check acl error", func(t *testing.T) {
+		fakeACLProvider.CheckACLNoChannelReturns(fmt.Errorf("fake-check-acl-error"))
+		_, err := snapshotSvc.QueryStatus(context.Background(), signedQuery)
+		require.EqualError(t, err, "fake-check-acl-error")
+	})
+}
+
 func createSignedRequest(channelID string, blockNumber uint64) *pb.SignedSnapshotRequest {
 	sigHeader := &common.SignatureHeader{
 		Creator: []byte("creator"),
@@ -182,3 +265,11 @@ func createSignedQuery(channelID string) *pb.SignedSnapshotRequest {
 		Signature: []byte("dummy-signatures"),
 	}
 }
+
+func createSignedStatusQuery(channelID string) *SignedSnapshotQuery {
+	signedQuery := createSignedQuery(channelID)
+	return &SignedSnapshotQuery{
+		Request:   signedQuery.Request,
+		Signature: signedQuery.Signature,
+	}
+}
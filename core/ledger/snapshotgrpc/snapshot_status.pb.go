@@ -0,0 +1,464 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: snapshot_status.proto
+
+package snapshotgrpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SnapshotPhaseProgress_Status int32
+
+const (
+	SnapshotPhaseProgress_PENDING     SnapshotPhaseProgress_Status = 0
+	SnapshotPhaseProgress_IN_PROGRESS SnapshotPhaseProgress_Status = 1
+	SnapshotPhaseProgress_DONE        SnapshotPhaseProgress_Status = 2
+)
+
+var SnapshotPhaseProgress_Status_name = map[int32]string{
+	0: "PENDING",
+	1: "IN_PROGRESS",
+	2: "DONE",
+}
+
+var SnapshotPhaseProgress_Status_value = map[string]int32{
+	"PENDING":     0,
+	"IN_PROGRESS": 1,
+	"DONE":        2,
+}
+
+func (x SnapshotPhaseProgress_Status) String() string {
+	return proto.EnumName(SnapshotPhaseProgress_Status_name, int32(x))
+}
+
+func (SnapshotPhaseProgress_Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{4, 0}
+}
+
+// SignedSnapshotQuery is wire compatible with the message protos.SignedSnapshotRequest,
+// where the request is a serialized protos.SnapshotQuery
+type SignedSnapshotQuery struct {
+	Request              []byte   `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedSnapshotQuery) Reset()         { *m = SignedSnapshotQuery{} }
+func (m *SignedSnapshotQuery) String() string { return proto.CompactTextString(m) }
+func (*SignedSnapshotQuery) ProtoMessage()    {}
+func (*SignedSnapshotQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{0}
+}
+
+func (m *SignedSnapshotQuery) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedSnapshotQuery.Unmarshal(m, b)
+}
+func (m *SignedSnapshotQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedSnapshotQuery.Marshal(b, m, deterministic)
+}
+func (m *SignedSnapshotQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedSnapshotQuery.Merge(m, src)
+}
+func (m *SignedSnapshotQuery) XXX_Size() int {
+	return xxx_messageInfo_SignedSnapshotQuery.Size(m)
+}
+func (m *SignedSnapshotQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedSnapshotQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedSnapshotQuery proto.InternalMessageInfo
+
+func (m *SignedSnapshotQuery) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedSnapshotQuery) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type QuerySnapshotStatusResponse struct {
+	Requests             []*SnapshotRequestStatus `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *QuerySnapshotStatusResponse) Reset()         { *m = QuerySnapshotStatusResponse{} }
+func (m *QuerySnapshotStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*QuerySnapshotStatusResponse) ProtoMessage()    {}
+func (*QuerySnapshotStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{1}
+}
+
+func (m *QuerySnapshotStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuerySnapshotStatusResponse.Unmarshal(m, b)
+}
+func (m *QuerySnapshotStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuerySnapshotStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *QuerySnapshotStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuerySnapshotStatusResponse.Merge(m, src)
+}
+func (m *QuerySnapshotStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_QuerySnapshotStatusResponse.Size(m)
+}
+func (m *QuerySnapshotStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuerySnapshotStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuerySnapshotStatusResponse proto.InternalMessageInfo
+
+func (m *QuerySnapshotStatusResponse) GetRequests() []*SnapshotRequestStatus {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+type SnapshotRequestStatus struct {
+	BlockNumber          uint64            `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Progress             *SnapshotProgress `protobuf:"bytes,2,opt,name=progress,proto3" json:"progress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SnapshotRequestStatus) Reset()         { *m = SnapshotRequestStatus{} }
+func (m *SnapshotRequestStatus) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequestStatus) ProtoMessage()    {}
+func (*SnapshotRequestStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{2}
+}
+
+func (m *SnapshotRequestStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequestStatus.Unmarshal(m, b)
+}
+func (m *SnapshotRequestStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequestStatus.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequestStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequestStatus.Merge(m, src)
+}
+func (m *SnapshotRequestStatus) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequestStatus.Size(m)
+}
+func (m *SnapshotRequestStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequestStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequestStatus proto.InternalMessageInfo
+
+func (m *SnapshotRequestStatus) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *SnapshotRequestStatus) GetProgress() *SnapshotProgress {
+	if m != nil {
+		return m.Progress
+	}
+	return nil
+}
+
+type SnapshotProgress struct {
+	StartTime               *timestamp.Timestamp     `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	Phases                  []*SnapshotPhaseProgress `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
+	RecordsWritten          uint64                   `protobuf:"varint,3,opt,name=records_written,json=recordsWritten,proto3" json:"records_written,omitempty"`
+	BytesWritten            uint64                   `protobuf:"varint,4,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	EstimatedTotalBytes     uint64                   `protobuf:"varint,5,opt,name=estimated_total_bytes,json=estimatedTotalBytes,proto3" json:"estimated_total_bytes,omitempty"`
+	EstimatedCompletionTime *timestamp.Timestamp     `protobuf:"bytes,6,opt,name=estimated_completion_time,json=estimatedCompletionTime,proto3" json:"estimated_completion_time,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{}                 `json:"-"`
+	XXX_unrecognized        []byte                   `json:"-"`
+	XXX_sizecache           int32                    `json:"-"`
+}
+
+func (m *SnapshotProgress) Reset()         { *m = SnapshotProgress{} }
+func (m *SnapshotProgress) String() string { return proto.CompactTextString(m) }
+func (*SnapshotProgress) ProtoMessage()    {}
+func (*SnapshotProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{3}
+}
+
+func (m *SnapshotProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotProgress.Unmarshal(m, b)
+}
+func (m *SnapshotProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotProgress.Marshal(b, m, deterministic)
+}
+func (m *SnapshotProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotProgress.Merge(m, src)
+}
+func (m *SnapshotProgress) XXX_Size() int {
+	return xxx_messageInfo_SnapshotProgress.Size(m)
+}
+func (m *SnapshotProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotProgress proto.InternalMessageInfo
+
+func (m *SnapshotProgress) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *SnapshotProgress) GetPhases() []*SnapshotPhaseProgress {
+	if m != nil {
+		return m.Phases
+	}
+	return nil
+}
+
+func (m *SnapshotProgress) GetRecordsWritten() uint64 {
+	if m != nil {
+		return m.RecordsWritten
+	}
+	return 0
+}
+
+func (m *SnapshotProgress) GetBytesWritten() uint64 {
+	if m != nil {
+		return m.BytesWritten
+	}
+	return 0
+}
+
+func (m *SnapshotProgress) GetEstimatedTotalBytes() uint64 {
+	if m != nil {
+		return m.EstimatedTotalBytes
+	}
+	return 0
+}
+
+func (m *SnapshotProgress) GetEstimatedCompletionTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EstimatedCompletionTime
+	}
+	return nil
+}
+
+type SnapshotPhaseProgress struct {
+	Phase                string                       `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	Status               SnapshotPhaseProgress_Status `protobuf:"varint,2,opt,name=status,proto3,enum=snapshotgrpc.SnapshotPhaseProgress_Status" json:"status,omitempty"`
+	RecordsWritten       uint64                       `protobuf:"varint,3,opt,name=records_written,json=recordsWritten,proto3" json:"records_written,omitempty"`
+	BytesWritten         uint64                       `protobuf:"varint,4,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *SnapshotPhaseProgress) Reset()         { *m = SnapshotPhaseProgress{} }
+func (m *SnapshotPhaseProgress) String() string { return proto.CompactTextString(m) }
+func (*SnapshotPhaseProgress) ProtoMessage()    {}
+func (*SnapshotPhaseProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f86f96326635d8a7, []int{4}
+}
+
+func (m *SnapshotPhaseProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotPhaseProgress.Unmarshal(m, b)
+}
+func (m *SnapshotPhaseProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotPhaseProgress.Marshal(b, m, deterministic)
+}
+func (m *SnapshotPhaseProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotPhaseProgress.Merge(m, src)
+}
+func (m *SnapshotPhaseProgress) XXX_Size() int {
+	return xxx_messageInfo_SnapshotPhaseProgress.Size(m)
+}
+func (m *SnapshotPhaseProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotPhaseProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotPhaseProgress proto.InternalMessageInfo
+
+func (m *SnapshotPhaseProgress) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *SnapshotPhaseProgress) GetStatus() SnapshotPhaseProgress_Status {
+	if m != nil {
+		return m.Status
+	}
+	return SnapshotPhaseProgress_PENDING
+}
+
+func (m *SnapshotPhaseProgress) GetRecordsWritten() uint64 {
+	if m != nil {
+		return m.RecordsWritten
+	}
+	return 0
+}
+
+func (m *SnapshotPhaseProgress) GetBytesWritten() uint64 {
+	if m != nil {
+		return m.BytesWritten
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("snapshotgrpc.SnapshotPhaseProgress_Status", SnapshotPhaseProgress_Status_name, SnapshotPhaseProgress_Status_value)
+	proto.RegisterType((*SignedSnapshotQuery)(nil), "snapshotgrpc.SignedSnapshotQuery")
+	proto.RegisterType((*QuerySnapshotStatusResponse)(nil), "snapshotgrpc.QuerySnapshotStatusResponse")
+	proto.RegisterType((*SnapshotRequestStatus)(nil), "snapshotgrpc.SnapshotRequestStatus")
+	proto.RegisterType((*SnapshotProgress)(nil), "snapshotgrpc.SnapshotProgress")
+	proto.RegisterType((*SnapshotPhaseProgress)(nil), "snapshotgrpc.SnapshotPhaseProgress")
+}
+
+func init() { proto.RegisterFile("snapshot_status.proto", fileDescriptor_f86f96326635d8a7) }
+
+var fileDescriptor_f86f96326635d8a7 = []byte{
+	// 532 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x26, 0x69, 0x9a, 0x26, 0xe3, 0x90, 0x46, 0x5b, 0x22, 0x4c, 0x40, 0x50, 0xdc, 0x03, 0x85,
+	0x83, 0x8d, 0x8c, 0x84, 0xf8, 0x39, 0x20, 0x85, 0x46, 0x55, 0x0f, 0xa4, 0xc1, 0xa9, 0x40, 0x02,
+	0x09, 0xcb, 0x76, 0xa6, 0x8e, 0x85, 0xed, 0x35, 0xbb, 0x6b, 0x50, 0xde, 0x97, 0x17, 0xe0, 0x0d,
+	0x90, 0xd7, 0x6b, 0xa7, 0x2d, 0x51, 0xe1, 0xc0, 0x71, 0xbf, 0x9f, 0xf1, 0xcc, 0xe7, 0x19, 0x18,
+	0xf2, 0xd4, 0xcb, 0xf8, 0x92, 0x0a, 0x97, 0x0b, 0x4f, 0xe4, 0xdc, 0xcc, 0x18, 0x15, 0x94, 0xf4,
+	0x2a, 0x38, 0x64, 0x59, 0x30, 0x7a, 0x10, 0x52, 0x1a, 0xc6, 0x68, 0x49, 0xce, 0xcf, 0xcf, 0x2d,
+	0x11, 0x25, 0xc8, 0x85, 0x97, 0x64, 0xa5, 0xdc, 0x78, 0x07, 0x7b, 0xf3, 0x28, 0x4c, 0x71, 0x31,
+	0x57, 0xb6, 0xf7, 0x39, 0xb2, 0x15, 0xd1, 0x61, 0x87, 0xe1, 0xb7, 0x1c, 0xb9, 0xd0, 0x1b, 0xfb,
+	0x8d, 0xc3, 0x9e, 0x53, 0x3d, 0xc9, 0x3d, 0xe8, 0xf2, 0x28, 0x4c, 0x3d, 0x91, 0x33, 0xd4, 0x9b,
+	0x92, 0x5b, 0x03, 0xc6, 0x17, 0xb8, 0x2b, 0x0b, 0x54, 0xd5, 0xe6, 0xb2, 0x35, 0x07, 0x79, 0x46,
+	0x53, 0x8e, 0xe4, 0x0d, 0x74, 0x54, 0x1d, 0xae, 0x37, 0xf6, 0xb7, 0x0e, 0x35, 0xfb, 0xc0, 0xbc,
+	0xd8, 0xaf, 0x59, 0xf9, 0x9c, 0x52, 0xa5, 0xec, 0xb5, 0xc9, 0xf8, 0x0e, 0xc3, 0x8d, 0x12, 0xf2,
+	0x10, 0x7a, 0x7e, 0x4c, 0x83, 0xaf, 0x6e, 0x9a, 0x27, 0x3e, 0x32, 0xd9, 0x75, 0xcb, 0xd1, 0x24,
+	0x36, 0x95, 0x10, 0x79, 0x05, 0x9d, 0x8c, 0xd1, 0x90, 0x21, 0xe7, 0xb2, 0x71, 0xcd, 0xbe, 0xbf,
+	0xf9, 0xe3, 0x33, 0xa5, 0x72, 0x6a, 0xbd, 0xf1, 0xb3, 0x09, 0x83, 0xab, 0x34, 0x79, 0x09, 0xc0,
+	0x85, 0xc7, 0x84, 0x5b, 0x84, 0x2a, 0xbf, 0xa8, 0xd9, 0x23, 0xb3, 0x4c, 0xdc, 0xac, 0x12, 0x37,
+	0xcf, 0xaa, 0xc4, 0x9d, 0xae, 0x54, 0x17, 0x6f, 0xf2, 0x1a, 0xda, 0xd9, 0xd2, 0xe3, 0x58, 0x74,
+	0x72, 0x4d, 0x0c, 0xb3, 0x42, 0x53, 0xb7, 0xa3, 0x2c, 0xe4, 0x11, 0xec, 0x32, 0x0c, 0x28, 0x5b,
+	0x70, 0xf7, 0x07, 0x8b, 0x84, 0xc0, 0x54, 0xdf, 0x92, 0xe3, 0xf6, 0x15, 0xfc, 0xb1, 0x44, 0xc9,
+	0x01, 0xdc, 0xf4, 0x57, 0x02, 0xd7, 0xb2, 0x96, 0x94, 0xf5, 0x24, 0x58, 0x89, 0x6c, 0x18, 0x22,
+	0x17, 0x51, 0xe2, 0x09, 0x5c, 0xb8, 0x82, 0x0a, 0x2f, 0x76, 0x25, 0xaf, 0x6f, 0x4b, 0xf1, 0x5e,
+	0x4d, 0x9e, 0x15, 0xdc, 0xb8, 0xa0, 0xc8, 0x07, 0xb8, 0xb3, 0xf6, 0x04, 0x34, 0xc9, 0x62, 0x14,
+	0x11, 0x4d, 0xcb, 0x20, 0xda, 0x7f, 0x0d, 0xe2, 0x76, 0x6d, 0x7e, 0x5b, 0x7b, 0x0b, 0xd6, 0xf8,
+	0xd5, 0x80, 0xe1, 0xc6, 0xd9, 0xc9, 0x2d, 0xd8, 0x96, 0xd3, 0xcb, 0x98, 0xbb, 0x4e, 0xf9, 0x20,
+	0x63, 0x68, 0x97, 0xcb, 0x2f, 0x7f, 0x68, 0xdf, 0x7e, 0xf2, 0x0f, 0x31, 0x9a, 0x6a, 0xa9, 0x94,
+	0xf3, 0xff, 0xa6, 0x69, 0x3c, 0x85, 0xb6, 0xda, 0x48, 0x0d, 0x76, 0x66, 0x93, 0xe9, 0xd1, 0xc9,
+	0xf4, 0x78, 0x70, 0x83, 0xec, 0x82, 0x76, 0x32, 0x75, 0x67, 0xce, 0xe9, 0xb1, 0x33, 0x99, 0xcf,
+	0x07, 0x0d, 0xd2, 0x81, 0xd6, 0xd1, 0xe9, 0x74, 0x32, 0x68, 0xda, 0x09, 0xf4, 0x2f, 0x5f, 0x0b,
+	0xf9, 0x0c, 0x5a, 0x79, 0x44, 0x6a, 0xb5, 0xaf, 0x0c, 0xf5, 0xe7, 0xb9, 0x8e, 0x1e, 0x5f, 0x96,
+	0x5c, 0x73, 0x82, 0xe3, 0x17, 0x9f, 0x9e, 0x87, 0x91, 0x58, 0xe6, 0xbe, 0x19, 0xd0, 0xc4, 0x5a,
+	0xae, 0x32, 0x64, 0x31, 0x2e, 0x42, 0x64, 0xd6, 0xb9, 0xe7, 0xb3, 0x28, 0xb0, 0x02, 0xca, 0xd0,
+	0x52, 0xd0, 0xc5, 0xaa, 0x7e, 0x5b, 0xfe, 0xc9, 0x67, 0xbf, 0x07, 0x00, 0xf0, 0x55, 0x07, 0xa8,
+	0x79, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// SnapshotStatusClient is the client API for SnapshotStatus service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SnapshotStatusClient interface {
+	// QueryStatus returns the pending snapshot requests, along with the progress of the
+	// snapshot being generated, if any
+	QueryStatus(ctx context.Context, in *SignedSnapshotQuery, opts ...grpc.CallOption) (*QuerySnapshotStatusResponse, error)
+}
+
+type snapshotStatusClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSnapshotStatusClient(cc grpc.ClientConnInterface) SnapshotStatusClient {
+	return &snapshotStatusClient{cc}
+}
+
+func (c *snapshotStatusClient) QueryStatus(ctx context.Context, in *SignedSnapshotQuery, opts ...grpc.CallOption) (*QuerySnapshotStatusResponse, error) {
+	out := new(QuerySnapshotStatusResponse)
+	err := c.cc.Invoke(ctx, "/snapshotgrpc.SnapshotStatus/QueryStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SnapshotStatusServer is the server API for SnapshotStatus service.
+type SnapshotStatusServer interface {
+	// QueryStatus returns the pending snapshot requests, along with the progress of the
+	// snapshot being generated, if any
+	QueryStatus(context.Context, *SignedSnapshotQuery) (*QuerySnapshotStatusResponse, error)
+}
+
+// UnimplementedSnapshotStatusServer can be embedded to have forward compatible implementations.
+type UnimplementedSnapshotStatusServer struct {
+}
+
+func (*UnimplementedSnapshotStatusServer) QueryStatus(ctx context.Context, req *SignedSnapshotQuery) (*QuerySnapshotStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryStatus not implemented")
+}
+
+func RegisterSnapshotStatusServer(s *grpc.Server, srv SnapshotStatusServer) {
+	s.RegisterService(&_SnapshotStatus_serviceDesc, srv)
+}
+
+func _SnapshotStatus_QueryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedSnapshotQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnapshotStatusServer).QueryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snapshotgrpc.SnapshotStatus/QueryStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnapshotStatusServer).QueryStatus(ctx, req.(*SignedSnapshotQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _SnapshotStatus_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "snapshotgrpc.SnapshotStatus",
+	HandlerType: (*SnapshotStatusServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "QueryStatus",
+			Handler:    _SnapshotStatus_QueryStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "snapshot_status.proto",
+}
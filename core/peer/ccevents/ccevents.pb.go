@@ -0,0 +1,481 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ccevents.proto
+
+package ccevents
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	peer "github.com/hyperledger/fabric-protos-go/peer"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ChaincodeEventsRequest struct {
+	// A signed envelope of type DELIVER_SEEK_INFO whose payload is an orderer.SeekInfo
+	SeekInfo *common.Envelope `protobuf:"bytes,1,opt,name=seek_info,json=seekInfo,proto3" json:"seek_info,omitempty"`
+	// The name of the chaincode whose events are delivered
+	ChaincodeId string `protobuf:"bytes,2,opt,name=chaincode_id,json=chaincodeId,proto3" json:"chaincode_id,omitempty"`
+	// If set, only the events whose name matches this regular expression are delivered
+	EventNamePattern string `protobuf:"bytes,3,opt,name=event_name_pattern,json=eventNamePattern,proto3" json:"event_name_pattern,omitempty"`
+	// If set, the events up to and including this checkpoint are not delivered, so that
+	// a client seeking from the block of its last checkpoint resumes exactly after it
+	Checkpoint           *Checkpoint `protobuf:"bytes,4,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ChaincodeEventsRequest) Reset()         { *m = ChaincodeEventsRequest{} }
+func (m *ChaincodeEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEventsRequest) ProtoMessage()    {}
+func (*ChaincodeEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_117efb46f13d4aad, []int{0}
+}
+
+func (m *ChaincodeEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEventsRequest.Unmarshal(m, b)
+}
+func (m *ChaincodeEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEventsRequest.Merge(m, src)
+}
+func (m *ChaincodeEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEventsRequest.Size(m)
+}
+func (m *ChaincodeEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEventsRequest proto.InternalMessageInfo
+
+func (m *ChaincodeEventsRequest) GetSeekInfo() *common.Envelope {
+	if m != nil {
+		return m.SeekInfo
+	}
+	return nil
+}
+
+func (m *ChaincodeEventsRequest) GetChaincodeId() string {
+	if m != nil {
+		return m.ChaincodeId
+	}
+	return ""
+}
+
+func (m *ChaincodeEventsRequest) GetEventNamePattern() string {
+	if m != nil {
+		return m.EventNamePattern
+	}
+	return ""
+}
+
+func (m *ChaincodeEventsRequest) GetCheckpoint() *Checkpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
+// Checkpoint is the position of a transaction in the ledger of the channel
+type Checkpoint struct {
+	BlockNumber          uint64   `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	TransactionIndex     uint64   `protobuf:"varint,2,opt,name=transaction_index,json=transactionIndex,proto3" json:"transaction_index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Checkpoint) Reset()         { *m = Checkpoint{} }
+func (m *Checkpoint) String() string { return proto.CompactTextString(m) }
+func (*Checkpoint) ProtoMessage()    {}
+func (*Checkpoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_117efb46f13d4aad, []int{1}
+}
+
+func (m *Checkpoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Checkpoint.Unmarshal(m, b)
+}
+func (m *Checkpoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Checkpoint.Marshal(b, m, deterministic)
+}
+func (m *Checkpoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Checkpoint.Merge(m, src)
+}
+func (m *Checkpoint) XXX_Size() int {
+	return xxx_messageInfo_Checkpoint.Size(m)
+}
+func (m *Checkpoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_Checkpoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Checkpoint proto.InternalMessageInfo
+
+func (m *Checkpoint) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *Checkpoint) GetTransactionIndex() uint64 {
+	if m != nil {
+		return m.TransactionIndex
+	}
+	return 0
+}
+
+type ChaincodeEventsResponse struct {
+	// Types that are valid to be assigned to Type:
+	//	*ChaincodeEventsResponse_Status
+	//	*ChaincodeEventsResponse_Events
+	Type                 isChaincodeEventsResponse_Type `protobuf_oneof:"type"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *ChaincodeEventsResponse) Reset()         { *m = ChaincodeEventsResponse{} }
+func (m *ChaincodeEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeEventsResponse) ProtoMessage()    {}
+func (*ChaincodeEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_117efb46f13d4aad, []int{2}
+}
+
+func (m *ChaincodeEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeEventsResponse.Unmarshal(m, b)
+}
+func (m *ChaincodeEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *ChaincodeEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeEventsResponse.Merge(m, src)
+}
+func (m *ChaincodeEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeEventsResponse.Size(m)
+}
+func (m *ChaincodeEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeEventsResponse proto.InternalMessageInfo
+
+type isChaincodeEventsResponse_Type interface {
+	isChaincodeEventsResponse_Type()
+}
+
+type ChaincodeEventsResponse_Status struct {
+	Status common.Status `protobuf:"varint,1,opt,name=status,proto3,enum=common.Status,oneof"`
+}
+
+type ChaincodeEventsResponse_Events struct {
+	Events *BlockChaincodeEvents `protobuf:"bytes,2,opt,name=events,proto3,oneof"`
+}
+
+func (*ChaincodeEventsResponse_Status) isChaincodeEventsResponse_Type() {}
+
+func (*ChaincodeEventsResponse_Events) isChaincodeEventsResponse_Type() {}
+
+func (m *ChaincodeEventsResponse) GetType() isChaincodeEventsResponse_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *ChaincodeEventsResponse) GetStatus() common.Status {
+	if x, ok := m.GetType().(*ChaincodeEventsResponse_Status); ok {
+		return x.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *ChaincodeEventsResponse) GetEvents() *BlockChaincodeEvents {
+	if x, ok := m.GetType().(*ChaincodeEventsResponse_Events); ok {
+		return x.Events
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ChaincodeEventsResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ChaincodeEventsResponse_Status)(nil),
+		(*ChaincodeEventsResponse_Events)(nil),
+	}
+}
+
+// BlockChaincodeEvents lists the events of the chaincode in a block. Blocks without
+// any event are not delivered.
+type BlockChaincodeEvents struct {
+	BlockNumber          uint64                       `protobuf:"varint,1,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Events               []*TransactionChaincodeEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *BlockChaincodeEvents) Reset()         { *m = BlockChaincodeEvents{} }
+func (m *BlockChaincodeEvents) String() string { return proto.CompactTextString(m) }
+func (*BlockChaincodeEvents) ProtoMessage()    {}
+func (*BlockChaincodeEvents) Descriptor() ([]byte, []int) {
+	return fileDescriptor_117efb46f13d4aad, []int{3}
+}
+
+func (m *BlockChaincodeEvents) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockChaincodeEvents.Unmarshal(m, b)
+}
+func (m *BlockChaincodeEvents) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockChaincodeEvents.Marshal(b, m, deterministic)
+}
+func (m *BlockChaincodeEvents) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockChaincodeEvents.Merge(m, src)
+}
+func (m *BlockChaincodeEvents) XXX_Size() int {
+	return xxx_messageInfo_BlockChaincodeEvents.Size(m)
+}
+func (m *BlockChaincodeEvents) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockChaincodeEvents.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockChaincodeEvents proto.InternalMessageInfo
+
+func (m *BlockChaincodeEvents) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *BlockChaincodeEvents) GetEvents() []*TransactionChaincodeEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type TransactionChaincodeEvent struct {
+	// The checkpoint of the event, to send back in order to resume after it
+	Checkpoint           *Checkpoint          `protobuf:"bytes,1,opt,name=checkpoint,proto3" json:"checkpoint,omitempty"`
+	Event                *peer.ChaincodeEvent `protobuf:"bytes,2,opt,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *TransactionChaincodeEvent) Reset()         { *m = TransactionChaincodeEvent{} }
+func (m *TransactionChaincodeEvent) String() string { return proto.CompactTextString(m) }
+func (*TransactionChaincodeEvent) ProtoMessage()    {}
+func (*TransactionChaincodeEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_117efb46f13d4aad, []int{4}
+}
+
+func (m *TransactionChaincodeEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionChaincodeEvent.Unmarshal(m, b)
+}
+func (m *TransactionChaincodeEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionChaincodeEvent.Marshal(b, m, deterministic)
+}
+func (m *TransactionChaincodeEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionChaincodeEvent.Merge(m, src)
+}
+func (m *TransactionChaincodeEvent) XXX_Size() int {
+	return xxx_messageInfo_TransactionChaincodeEvent.Size(m)
+}
+func (m *TransactionChaincodeEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionChaincodeEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionChaincodeEvent proto.InternalMessageInfo
+
+func (m *TransactionChaincodeEvent) GetCheckpoint() *Checkpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
+func (m *TransactionChaincodeEvent) GetEvent() *peer.ChaincodeEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ChaincodeEventsRequest)(nil), "ccevents.ChaincodeEventsRequest")
+	proto.RegisterType((*Checkpoint)(nil), "ccevents.Checkpoint")
+	proto.RegisterType((*ChaincodeEventsResponse)(nil), "ccevents.ChaincodeEventsResponse")
+	proto.RegisterType((*BlockChaincodeEvents)(nil), "ccevents.BlockChaincodeEvents")
+	proto.RegisterType((*TransactionChaincodeEvent)(nil), "ccevents.TransactionChaincodeEvent")
+}
+
+func init() { proto.RegisterFile("ccevents.proto", fileDescriptor_117efb46f13d4aad) }
+
+var fileDescriptor_117efb46f13d4aad = []byte{
+	// 452 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x41, 0x6f, 0xd3, 0x30,
+	0x18, 0x25, 0xac, 0x54, 0xdb, 0x17, 0x54, 0x8a, 0x99, 0x4a, 0xe9, 0x01, 0x75, 0xe1, 0x12, 0x89,
+	0xd1, 0x4c, 0x81, 0x03, 0x12, 0xb7, 0x8e, 0x49, 0xf4, 0x32, 0xa1, 0xc0, 0x09, 0x21, 0x45, 0x8e,
+	0xf3, 0x75, 0xb5, 0xda, 0xd8, 0xc6, 0x76, 0x2b, 0x76, 0xe2, 0xc4, 0xdf, 0xe3, 0x37, 0xa1, 0xd8,
+	0x6d, 0xd3, 0x95, 0x31, 0xed, 0x14, 0xe5, 0xbd, 0x97, 0xf7, 0xbe, 0xe7, 0xcf, 0x81, 0x0e, 0x63,
+	0xb8, 0x42, 0x61, 0xcd, 0x48, 0x69, 0x69, 0x25, 0x39, 0xdc, 0xbc, 0x0f, 0x9e, 0x31, 0x59, 0x55,
+	0x52, 0x24, 0xfe, 0xe1, 0xe9, 0xc1, 0x40, 0x21, 0xea, 0x84, 0xcd, 0x28, 0x17, 0x4c, 0x96, 0x98,
+	0x3b, 0xad, 0xe7, 0xa2, 0x3f, 0x01, 0xf4, 0xce, 0x37, 0xcc, 0x85, 0x33, 0xc9, 0xf0, 0xc7, 0x12,
+	0x8d, 0x25, 0x6f, 0xe0, 0xc8, 0x20, 0xce, 0x73, 0x2e, 0xa6, 0xb2, 0x1f, 0x0c, 0x83, 0x38, 0x4c,
+	0xbb, 0xa3, 0xb5, 0xf1, 0x85, 0x58, 0xe1, 0x42, 0x2a, 0xcc, 0x0e, 0x6b, 0xc9, 0x44, 0x4c, 0x25,
+	0x39, 0x81, 0xc7, 0x4d, 0x04, 0x2f, 0xfb, 0x0f, 0x87, 0x41, 0x7c, 0x94, 0x85, 0x5b, 0x6c, 0x52,
+	0x92, 0x53, 0x20, 0x2e, 0x3b, 0x17, 0xb4, 0xc2, 0x5c, 0x51, 0x6b, 0x51, 0x8b, 0xfe, 0x81, 0x13,
+	0x76, 0x1d, 0x73, 0x49, 0x2b, 0xfc, 0xec, 0x71, 0xf2, 0x0e, 0x80, 0xcd, 0x90, 0xcd, 0x95, 0xe4,
+	0xc2, 0xf6, 0x5b, 0x6e, 0x80, 0xe3, 0xd1, 0xb6, 0xfa, 0xf9, 0x96, 0xcb, 0x76, 0x74, 0xd1, 0x77,
+	0x80, 0x86, 0xa9, 0x87, 0x2a, 0x16, 0x92, 0xcd, 0x73, 0xb1, 0xac, 0x0a, 0xd4, 0xae, 0x46, 0x2b,
+	0x0b, 0x1d, 0x76, 0xe9, 0x20, 0xf2, 0x1a, 0x9e, 0x5a, 0x4d, 0x85, 0xa1, 0xcc, 0x72, 0x29, 0x72,
+	0x2e, 0x4a, 0xfc, 0xe9, 0x86, 0x6f, 0x65, 0xdd, 0x1d, 0x62, 0x52, 0xe3, 0xd1, 0xef, 0x00, 0x9e,
+	0xff, 0x73, 0x5c, 0x46, 0x49, 0x61, 0x90, 0xc4, 0xd0, 0x36, 0x96, 0xda, 0xa5, 0x71, 0x29, 0x9d,
+	0xb4, 0xb3, 0x39, 0xac, 0x2f, 0x0e, 0xfd, 0xf4, 0x20, 0x5b, 0xf3, 0xe4, 0x3d, 0xb4, 0x7d, 0x09,
+	0x97, 0x13, 0xa6, 0x2f, 0x9b, 0x56, 0xe3, 0x7a, 0xb2, 0xbd, 0x84, 0xfa, 0x4b, 0x4f, 0x8f, 0xdb,
+	0xd0, 0xb2, 0xd7, 0x0a, 0xa3, 0x15, 0x1c, 0xdf, 0xa6, 0xbc, 0x4f, 0xdf, 0x0f, 0x3b, 0xe1, 0x07,
+	0x71, 0x98, 0xbe, 0x6a, 0xc2, 0xbf, 0x36, 0x75, 0x6f, 0x1a, 0x6f, 0xf2, 0xa3, 0x5f, 0xf0, 0xe2,
+	0xbf, 0xa2, 0xbd, 0x85, 0x05, 0xf7, 0x5b, 0x18, 0x39, 0x85, 0x47, 0x4e, 0xb0, 0x3e, 0x8b, 0x9e,
+	0xbf, 0x98, 0x66, 0x74, 0xd3, 0x3c, 0xf3, 0xa2, 0xd4, 0xc2, 0x93, 0xfd, 0xce, 0x14, 0x7a, 0x1f,
+	0x71, 0xc1, 0x57, 0xa8, 0xf7, 0x99, 0xe1, 0x6e, 0xf8, 0x6d, 0x77, 0x7c, 0x70, 0x72, 0x87, 0xc2,
+	0xaf, 0x35, 0x0e, 0xce, 0x82, 0x71, 0xfa, 0xed, 0xec, 0x8a, 0xdb, 0xd9, 0xb2, 0xa8, 0x57, 0x9a,
+	0xcc, 0xae, 0x15, 0xea, 0x05, 0x96, 0x57, 0xa8, 0x93, 0x29, 0x2d, 0x34, 0x67, 0x09, 0x93, 0x1a,
+	0x13, 0xff, 0x9b, 0xad, 0xfd, 0x8a, 0xb6, 0xeb, 0xf1, 0xf6, 0xef, 0x00, 0x2e, 0xb1, 0x37, 0x9e,
+	0xad, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ChaincodeEventsClient is the client API for ChaincodeEvents service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChaincodeEventsClient interface {
+	// DeliverChaincodeEvents first requires a ChaincodeEventsRequest, whose seek info envelope
+	// is the one of the Deliver service, and then replies with the chaincode events of the
+	// requested blocks, followed by a status once the blocks are delivered
+	DeliverChaincodeEvents(ctx context.Context, opts ...grpc.CallOption) (ChaincodeEvents_DeliverChaincodeEventsClient, error)
+}
+
+type chaincodeEventsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChaincodeEventsClient(cc grpc.ClientConnInterface) ChaincodeEventsClient {
+	return &chaincodeEventsClient{cc}
+}
+
+func (c *chaincodeEventsClient) DeliverChaincodeEvents(ctx context.Context, opts ...grpc.CallOption) (ChaincodeEvents_DeliverChaincodeEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ChaincodeEvents_serviceDesc.Streams[0], "/ccevents.ChaincodeEvents/DeliverChaincodeEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaincodeEventsDeliverChaincodeEventsClient{stream}
+	return x, nil
+}
+
+type ChaincodeEvents_DeliverChaincodeEventsClient interface {
+	Send(*ChaincodeEventsRequest) error
+	Recv() (*ChaincodeEventsResponse, error)
+	grpc.ClientStream
+}
+
+type chaincodeEventsDeliverChaincodeEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaincodeEventsDeliverChaincodeEventsClient) Send(m *ChaincodeEventsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chaincodeEventsDeliverChaincodeEventsClient) Recv() (*ChaincodeEventsResponse, error) {
+	m := new(ChaincodeEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChaincodeEventsServer is the server API for ChaincodeEvents service.
+type ChaincodeEventsServer interface {
+	// DeliverChaincodeEvents first requires a ChaincodeEventsRequest, whose seek info envelope
+	// is the one of the Deliver service, and then replies with the chaincode events of the
+	// requested blocks, followed by a status once the blocks are delivered
+	DeliverChaincodeEvents(ChaincodeEvents_DeliverChaincodeEventsServer) error
+}
+
+// UnimplementedChaincodeEventsServer can be embedded to have forward compatible implementations.
+type UnimplementedChaincodeEventsServer struct {
+}
+
+func (*UnimplementedChaincodeEventsServer) DeliverChaincodeEvents(srv ChaincodeEvents_DeliverChaincodeEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method DeliverChaincodeEvents not implemented")
+}
+
+func RegisterChaincodeEventsServer(s *grpc.Server, srv ChaincodeEventsServer) {
+	s.RegisterService(&_ChaincodeEvents_serviceDesc, srv)
+}
+
+func _ChaincodeEvents_DeliverChaincodeEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChaincodeEventsServer).DeliverChaincodeEvents(&chaincodeEventsDeliverChaincodeEventsServer{stream})
+}
+
+type ChaincodeEvents_DeliverChaincodeEventsServer interface {
+	Send(*ChaincodeEventsResponse) error
+	Recv() (*ChaincodeEventsRequest, error)
+	grpc.ServerStream
+}
+
+type chaincodeEventsDeliverChaincodeEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaincodeEventsDeliverChaincodeEventsServer) Send(m *ChaincodeEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chaincodeEventsDeliverChaincodeEventsServer) Recv() (*ChaincodeEventsRequest, error) {
+	m := new(ChaincodeEventsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _ChaincodeEvents_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "ccevents.ChaincodeEvents",
+	HandlerType: (*ChaincodeEventsServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "DeliverChaincodeEvents",
+			Handler:       _ChaincodeEvents_DeliverChaincodeEvents_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "ccevents.proto",
+}
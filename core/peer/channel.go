@@ -15,8 +15,10 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/transientstore"
+	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
 	"github.com/hyperledger/fabric/msp"
 )
 
@@ -24,6 +26,8 @@ import (
 type Channel struct {
 	ledger         ledger.PeerLedger
 	store          *transientstore.Store
+	ordererSource  *orderers.ConnectionSource
+	commitNotifier *committer.CommitNotifier
 	cryptoProvider bccsp.BCCSP
 
 	// applyLock is used to serialize calls to Apply and bundle update processing.
@@ -136,6 +140,18 @@ func (c *Channel) Store() *transientstore.Store {
 	return c.store
 }
 
+// OrdererSource returns the source of the orderer endpoints of this channel,
+// which is kept up to date with the channel configuration.
+func (c *Channel) OrdererSource() *orderers.ConnectionSource {
+	return c.ordererSource
+}
+
+// CommitNotifier returns the notifier of the transactions committed on this
+// channel.
+func (c *Channel) CommitNotifier() *committer.CommitNotifier {
+	return c.commitNotifier
+}
+
 // Reader returns a blockledger.Reader backed by the ledger associated with
 // this channel.
 func (c *Channel) Reader() blockledger.Reader {
@@ -110,6 +110,32 @@ type Config struct {
 	// after overpopulation purge.
 	DiscoveryAuthCachePurgeRetentionRatio float64
 
+	// ----- Gateway -----
+
+	// The gateway service lets the clients endorse and submit their transactions,
+	// and wait for them to be committed, through a single connection to the peer.
+
+	// GatewayEnabled is used to enable the gateway service.
+	GatewayEnabled bool
+	// GatewayEndorsementTimeout is the time allowed to collect the endorsements
+	// of a proposal.
+	GatewayEndorsementTimeout time.Duration
+
+	// ----- TxStatus -----
+
+	// TxStatusMaxTimeout is the longest time a client of the transaction status
+	// service waits for its transactions to be committed.
+	TxStatusMaxTimeout time.Duration
+
+	// ----- Query Cache -----
+
+	// QueryCacheChaincodes lists the chaincodes whose read-only queries are
+	// cached by the endorser.
+	QueryCacheChaincodes []string
+	// QueryCacheSize is the largest number of query results cached for each
+	// channel.
+	QueryCacheSize int
+
 	// ----- Limits -----
 	// Limits is used to configure some internal resource limits.
 	// TODO: create separate sub-struct for Limits config.
@@ -123,6 +149,20 @@ type Config struct {
 	// registered to deliver service for blocks and transaction events.
 	LimitsConcurrencyDeliverService int
 
+	// LimitsEndorserChannelConcurrency sets the limit of the proposals of each
+	// channel which the endorser processes concurrently.
+	LimitsEndorserChannelConcurrency int
+	// LimitsEndorserChannelQueueDepth sets the limit of the proposals of each
+	// channel which wait for the concurrency limit of the channel.
+	LimitsEndorserChannelQueueDepth int
+	// LimitsEndorserChaincodeConcurrency sets the limit of the proposals of each
+	// chaincode of a channel which the endorser processes concurrently.
+	LimitsEndorserChaincodeConcurrency int
+	// LimitsEndorserChaincodeQueueDepth sets the limit of the proposals of each
+	// chaincode of a channel which wait for the concurrency limit of the
+	// chaincode.
+	LimitsEndorserChaincodeQueueDepth int
+
 	// ----- TLS -----
 	// Require server-side TLS.
 	// TODO: create separate sub-struct for PeerTLS config.
@@ -242,6 +282,10 @@ func (c *Config) load() error {
 	c.NetworkID = viper.GetString("peer.networkId")
 	c.LimitsConcurrencyEndorserService = viper.GetInt("peer.limits.concurrency.endorserService")
 	c.LimitsConcurrencyDeliverService = viper.GetInt("peer.limits.concurrency.deliverService")
+	c.LimitsEndorserChannelConcurrency = viper.GetInt("peer.limits.endorser.channel.concurrency")
+	c.LimitsEndorserChannelQueueDepth = viper.GetInt("peer.limits.endorser.channel.queueDepth")
+	c.LimitsEndorserChaincodeConcurrency = viper.GetInt("peer.limits.endorser.chaincode.concurrency")
+	c.LimitsEndorserChaincodeQueueDepth = viper.GetInt("peer.limits.endorser.chaincode.queueDepth")
 	c.DiscoveryEnabled = viper.GetBool("peer.discovery.enabled")
 	c.ProfileEnabled = viper.GetBool("peer.profile.enabled")
 	c.ProfileListenAddress = viper.GetString("peer.profile.listenAddress")
@@ -249,6 +293,20 @@ func (c *Config) load() error {
 	c.DiscoveryAuthCacheEnabled = viper.GetBool("peer.discovery.authCacheEnabled")
 	c.DiscoveryAuthCacheMaxSize = viper.GetInt("peer.discovery.authCacheMaxSize")
 	c.DiscoveryAuthCachePurgeRetentionRatio = viper.GetFloat64("peer.discovery.authCachePurgeRetentionRatio")
+	c.GatewayEnabled = viper.GetBool("peer.gateway.enabled")
+	c.GatewayEndorsementTimeout = viper.GetDuration("peer.gateway.endorsementTimeout")
+	if c.GatewayEndorsementTimeout <= 0 {
+		c.GatewayEndorsementTimeout = 30 * time.Second
+	}
+	c.TxStatusMaxTimeout = viper.GetDuration("peer.txStatus.maxTimeout")
+	if c.TxStatusMaxTimeout <= 0 {
+		c.TxStatusMaxTimeout = time.Minute
+	}
+	c.QueryCacheChaincodes = viper.GetStringSlice("peer.queryCache.chaincodes")
+	c.QueryCacheSize = viper.GetInt("peer.queryCache.size")
+	if c.QueryCacheSize <= 0 {
+		c.QueryCacheSize = 10000
+	}
 	c.ChaincodeListenAddress = viper.GetString("peer.chaincodeListenAddress")
 	c.ChaincodeAddress = viper.GetString("peer.chaincodeAddress")
 
@@ -227,6 +227,10 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.networkId", "testNetwork")
 	viper.Set("peer.limits.concurrency.endorserService", 2500)
 	viper.Set("peer.limits.concurrency.deliverService", 2500)
+	viper.Set("peer.limits.endorser.channel.concurrency", 500)
+	viper.Set("peer.limits.endorser.channel.queueDepth", 1000)
+	viper.Set("peer.limits.endorser.chaincode.concurrency", 100)
+	viper.Set("peer.limits.endorser.chaincode.queueDepth", 200)
 	viper.Set("peer.discovery.enabled", true)
 	viper.Set("peer.profile.enabled", false)
 	viper.Set("peer.profile.listenAddress", "peer.authentication.timewindow")
@@ -234,6 +238,11 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.discovery.authCacheEnabled", true)
 	viper.Set("peer.discovery.authCacheMaxSize", 1000)
 	viper.Set("peer.discovery.authCachePurgeRetentionRatio", 0.75)
+	viper.Set("peer.gateway.enabled", true)
+	viper.Set("peer.gateway.endorsementTimeout", "10s")
+	viper.Set("peer.txStatus.maxTimeout", "5m")
+	viper.Set("peer.queryCache.chaincodes", []string{"mycc", "yourcc"})
+	viper.Set("peer.queryCache.size", 500)
 	viper.Set("peer.chaincodeListenAddress", "0.0.0.0:7052")
 	viper.Set("peer.chaincodeAddress", "0.0.0.0:7052")
 	viper.Set("peer.validatorPoolSize", 1)
@@ -284,6 +293,10 @@ func TestGlobalConfig(t *testing.T) {
 		NetworkID:                             "testNetwork",
 		LimitsConcurrencyEndorserService:      2500,
 		LimitsConcurrencyDeliverService:       2500,
+		LimitsEndorserChannelConcurrency:      500,
+		LimitsEndorserChannelQueueDepth:       1000,
+		LimitsEndorserChaincodeConcurrency:    100,
+		LimitsEndorserChaincodeQueueDepth:     200,
 		DiscoveryEnabled:                      true,
 		ProfileEnabled:                        false,
 		ProfileListenAddress:                  "peer.authentication.timewindow",
@@ -291,6 +304,11 @@ func TestGlobalConfig(t *testing.T) {
 		DiscoveryAuthCacheEnabled:             true,
 		DiscoveryAuthCacheMaxSize:             1000,
 		DiscoveryAuthCachePurgeRetentionRatio: 0.75,
+		GatewayEnabled:                        true,
+		GatewayEndorsementTimeout:             10 * time.Second,
+		TxStatusMaxTimeout:                    5 * time.Minute,
+		QueryCacheChaincodes:                  []string{"mycc", "yourcc"},
+		QueryCacheSize:                        500,
 		ChaincodeListenAddress:                "0.0.0.0:7052",
 		ChaincodeAddress:                      "0.0.0.0:7052",
 		ValidatorPoolSize:                     1,
@@ -349,6 +367,9 @@ func TestGlobalConfigDefault(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		GatewayEndorsementTimeout:     30 * time.Second,
+		TxStatusMaxTimeout:            time.Minute,
+		QueryCacheSize:                10000,
 	}
 
 	require.Equal(t, expectedConfig, coreConfig)
@@ -384,6 +405,9 @@ func TestPropagateEnvironment(t *testing.T) {
 		ValidatorPoolSize:             runtime.NumCPU(),
 		VMNetworkMode:                 "host",
 		DeliverClientKeepaliveOptions: comm.DefaultKeepaliveOptions,
+		GatewayEndorsementTimeout:     30 * time.Second,
+		TxStatusMaxTimeout:            time.Minute,
+		QueryCacheSize:                10000,
 		ExternalBuilders: []ExternalBuilder{
 			{
 				Name:                 "testName",
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package peer
 
 import (
+	"regexp"
 	"runtime/debug"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -17,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/peer/ccevents"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
@@ -130,6 +132,86 @@ func (fbrs *filteredBlockResponseSender) DataType() string {
 	return "filtered_block"
 }
 
+// chaincodeEventsServer receives the chaincode events requests and sends the
+// events of the requested chaincode. The deliver handler receives the next
+// request only once the blocks of the current one are sent, so the filter is
+// never used concurrently.
+type chaincodeEventsServer struct {
+	ccevents.ChaincodeEvents_DeliverChaincodeEventsServer
+	filter *chaincodeEventsFilter
+}
+
+// chaincodeEventsFilter selects the chaincode events sent to a client.
+type chaincodeEventsFilter struct {
+	chaincodeID string
+	eventName   *regexp.Regexp
+	checkpoint  *ccevents.Checkpoint
+}
+
+// Recv receives the next request, and returns its seek info envelope to the
+// deliver handler.
+func (ces *chaincodeEventsServer) Recv() (*common.Envelope, error) {
+	request, err := ces.ChaincodeEvents_DeliverChaincodeEventsServer.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if request.ChaincodeId == "" {
+		return nil, errors.New("chaincode ID is required")
+	}
+	filter := &chaincodeEventsFilter{
+		chaincodeID: request.ChaincodeId,
+		checkpoint:  request.Checkpoint,
+	}
+	if request.EventNamePattern != "" {
+		filter.eventName, err = regexp.Compile(request.EventNamePattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid event name pattern %q", request.EventNamePattern)
+		}
+	}
+	ces.filter = filter
+	return request.SeekInfo, nil
+}
+
+// SendStatusResponse generates status reply proto message
+func (ces *chaincodeEventsServer) SendStatusResponse(status common.Status) error {
+	response := &ccevents.ChaincodeEventsResponse{
+		Type: &ccevents.ChaincodeEventsResponse_Status{Status: status},
+	}
+	return ces.Send(response)
+}
+
+// SendBlockResponse generates chaincode events response with the events of the
+// block which pass the filter, and sends nothing if there are none
+func (ces *chaincodeEventsServer) SendBlockResponse(
+	block *common.Block,
+	channelID string,
+	chain deliver.Chain,
+	signedData *protoutil.SignedData,
+) error {
+	b := blockEvent(*block)
+	events, err := b.toChaincodeEvents(ces.filter)
+	if err != nil {
+		logger.Warningf("Failed to extract chaincode events due to: %s", err)
+		return ces.SendStatusResponse(common.Status_BAD_REQUEST)
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	response := &ccevents.ChaincodeEventsResponse{
+		Type: &ccevents.ChaincodeEventsResponse_Events{
+			Events: &ccevents.BlockChaincodeEvents{
+				BlockNumber: block.Header.Number,
+				Events:      events,
+			},
+		},
+	}
+	return ces.Send(response)
+}
+
+func (ces *chaincodeEventsServer) DataType() string {
+	return "chaincode_events"
+}
+
 // blockResponseSender structure used to send block responses
 type blockAndPrivateDataResponseSender struct {
 	peer.Deliver_DeliverWithPrivateDataServer
@@ -290,6 +372,23 @@ func (s *DeliverServer) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPriva
 	return err
 }
 
+// DeliverChaincodeEvents sends a stream of the events of a chaincode to a
+// client after commitment
+func (s *DeliverServer) DeliverChaincodeEvents(srv ccevents.ChaincodeEvents_DeliverChaincodeEventsServer) error {
+	logger.Debugf("Starting new DeliverChaincodeEvents handler")
+	defer dumpStacktraceOnPanic()
+	ccEventsServer := &chaincodeEventsServer{
+		ChaincodeEvents_DeliverChaincodeEventsServer: srv,
+	}
+	// getting policy checker based on resources.Event_ChaincodeEvents resource name
+	deliverServer := &deliver.Server{
+		Receiver:       ccEventsServer,
+		PolicyChecker:  s.PolicyCheckerProvider(resources.Event_ChaincodeEvents),
+		ResponseSender: ccEventsServer,
+	}
+	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
+}
+
 func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	filteredBlock := &peer.FilteredBlock{
 		Number: block.Header.Number,
@@ -353,6 +452,75 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 	return filteredBlock, nil
 }
 
+// toChaincodeEvents returns the chaincode events of the valid transactions of
+// the block which pass the filter and are after its checkpoint.
+func (block *blockEvent) toChaincodeEvents(filter *chaincodeEventsFilter) ([]*ccevents.TransactionChaincodeEvent, error) {
+	blockNum := block.Header.Number
+	if filter.checkpoint != nil && blockNum < filter.checkpoint.BlockNumber {
+		return nil, nil
+	}
+
+	txsFltr := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	var events []*ccevents.TransactionChaincodeEvent
+	for txIndex, ebytes := range block.Data.Data {
+		if filter.checkpoint != nil && blockNum == filter.checkpoint.BlockNumber && uint64(txIndex) <= filter.checkpoint.TransactionIndex {
+			continue
+		}
+		if txIndex >= len(txsFltr) || !txsFltr.IsValid(txIndex) {
+			continue
+		}
+
+		env, err := protoutil.GetEnvelopeFromBlock(ebytes)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting tx from block")
+		}
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not extract payload from envelope")
+		}
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.GetHeader().GetChannelHeader())
+		if err != nil {
+			return nil, err
+		}
+		if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+			continue
+		}
+		tx, err := protoutil.UnmarshalTransaction(payload.Data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error unmarshal transaction payload for block event")
+		}
+
+		for _, action := range tx.Actions {
+			chaincodeActionPayload, err := protoutil.UnmarshalChaincodeActionPayload(action.Payload)
+			if err != nil {
+				return nil, errors.WithMessage(err, "error unmarshal transaction action payload for block event")
+			}
+			if chaincodeActionPayload.Action == nil {
+				continue
+			}
+			ccEvent, err := chaincodeEvent(chaincodeActionPayload.Action)
+			if err != nil {
+				return nil, err
+			}
+			if ccEvent.GetChaincodeId() != filter.chaincodeID {
+				continue
+			}
+			if filter.eventName != nil && !filter.eventName.MatchString(ccEvent.EventName) {
+				continue
+			}
+			events = append(events, &ccevents.TransactionChaincodeEvent{
+				Checkpoint: &ccevents.Checkpoint{
+					BlockNumber:      blockNum,
+					TransactionIndex: uint64(txIndex),
+				},
+				Event: ccEvent,
+			})
+		}
+	}
+
+	return events, nil
+}
+
 func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_TransactionActions, error) {
 	transactionActions := &peer.FilteredTransactionActions{}
 	for _, action := range ta {
@@ -365,19 +533,9 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 			logger.Debugf("chaincode action, the payload action is nil, skipping")
 			continue
 		}
-		propRespPayload, err := protoutil.UnmarshalProposalResponsePayload(chaincodeActionPayload.Action.ProposalResponsePayload)
-		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal proposal response payload for block event")
-		}
-
-		caPayload, err := protoutil.UnmarshalChaincodeAction(propRespPayload.Extension)
-		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal chaincode action for block event")
-		}
-
-		ccEvent, err := protoutil.UnmarshalChaincodeEvents(caPayload.Events)
+		ccEvent, err := chaincodeEvent(chaincodeActionPayload.Action)
 		if err != nil {
-			return nil, errors.WithMessage(err, "error unmarshal chaincode event for block event")
+			return nil, err
 		}
 
 		if ccEvent.GetChaincodeId() != "" {
@@ -396,6 +554,25 @@ func (ta transactionActions) toFilteredActions() (*peer.FilteredTransaction_Tran
 	}, nil
 }
 
+// chaincodeEvent extracts the chaincode event of an endorsed action.
+func chaincodeEvent(action *peer.ChaincodeEndorsedAction) (*peer.ChaincodeEvent, error) {
+	propRespPayload, err := protoutil.UnmarshalProposalResponsePayload(action.ProposalResponsePayload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error unmarshal proposal response payload for block event")
+	}
+
+	caPayload, err := protoutil.UnmarshalChaincodeAction(propRespPayload.Extension)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error unmarshal chaincode action for block event")
+	}
+
+	ccEvent, err := protoutil.UnmarshalChaincodeEvents(caPayload.Events)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error unmarshal chaincode event for block event")
+	}
+	return ccEvent, nil
+}
+
 func dumpStacktraceOnPanic() {
 	func() {
 		if r := recover(); r != nil {
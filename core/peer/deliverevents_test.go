@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
@@ -26,10 +27,12 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/peer/ccevents"
 	fake "github.com/hyperledger/fabric/core/peer/mock"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	peer2 "google.golang.org/grpc/peer"
 )
@@ -579,6 +582,220 @@ func TestEventsServer_DeliverWithPrivateData(t *testing.T) {
 	}
 }
 
+// mockChaincodeEventsServer receives the given requests, and records the
+// responses sent to the client
+type mockChaincodeEventsServer struct {
+	grpc.ServerStream
+	requests  []*ccevents.ChaincodeEventsRequest
+	responses []*ccevents.ChaincodeEventsResponse
+}
+
+func (m *mockChaincodeEventsServer) Context() context.Context {
+	return peer2.NewContext(context.TODO(), &peer2.Peer{})
+}
+
+func (m *mockChaincodeEventsServer) Recv() (*ccevents.ChaincodeEventsRequest, error) {
+	if len(m.requests) == 0 {
+		return nil, io.EOF
+	}
+	request := m.requests[0]
+	m.requests = m.requests[1:]
+	return request, nil
+}
+
+func (m *mockChaincodeEventsServer) Send(response *ccevents.ChaincodeEventsResponse) error {
+	m.responses = append(m.responses, response)
+	return nil
+}
+
+func TestEventsServer_DeliverChaincodeEvents(t *testing.T) {
+	config := testConfig{
+		channelID:     "testChannelID",
+		eventName:     "testEvent",
+		chaincodeName: "mycc",
+		txID:          "testID",
+		Assertions:    require.New(t),
+	}
+	seekInfo := &common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{
+					ChannelId: "testChannelID",
+					Timestamp: util.CreateUtcTimestamp(),
+				}),
+				SignatureHeader: protoutil.MarshalOrPanic(&common.SignatureHeader{}),
+			},
+			Data: protoutil.MarshalOrPanic(&orderer.SeekInfo{
+				Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 0}}},
+				Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+				Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+			}),
+		}),
+	}
+	successStatus := &ccevents.ChaincodeEventsResponse{
+		Type: &ccevents.ChaincodeEventsResponse_Status{Status: common.Status_SUCCESS},
+	}
+	eventsOfBlock0 := &ccevents.ChaincodeEventsResponse{
+		Type: &ccevents.ChaincodeEventsResponse_Events{
+			Events: &ccevents.BlockChaincodeEvents{
+				BlockNumber: 0,
+				Events: []*ccevents.TransactionChaincodeEvent{{
+					Checkpoint: &ccevents.Checkpoint{BlockNumber: 0, TransactionIndex: 0},
+					Event:      &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "testEvent", TxId: "testID"},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		request           *ccevents.ChaincodeEventsRequest
+		expectedResponses []*ccevents.ChaincodeEventsResponse
+		expectedErr       string
+	}{
+		{
+			name:              "all events of the chaincode",
+			request:           &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo, ChaincodeId: "mycc"},
+			expectedResponses: []*ccevents.ChaincodeEventsResponse{eventsOfBlock0, successStatus},
+		},
+		{
+			name:              "matching event name",
+			request:           &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo, ChaincodeId: "mycc", EventNamePattern: "^test"},
+			expectedResponses: []*ccevents.ChaincodeEventsResponse{eventsOfBlock0, successStatus},
+		},
+		{
+			name:              "other event name",
+			request:           &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo, ChaincodeId: "mycc", EventNamePattern: "^other"},
+			expectedResponses: []*ccevents.ChaincodeEventsResponse{successStatus},
+		},
+		{
+			name:              "other chaincode",
+			request:           &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo, ChaincodeId: "othercc"},
+			expectedResponses: []*ccevents.ChaincodeEventsResponse{successStatus},
+		},
+		{
+			name: "resume after the checkpoint",
+			request: &ccevents.ChaincodeEventsRequest{
+				SeekInfo:    seekInfo,
+				ChaincodeId: "mycc",
+				Checkpoint:  &ccevents.Checkpoint{BlockNumber: 0, TransactionIndex: 0},
+			},
+			expectedResponses: []*ccevents.ChaincodeEventsResponse{successStatus},
+		},
+		{
+			name:        "missing chaincode ID",
+			request:     &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo},
+			expectedErr: "chaincode ID is required",
+		},
+		{
+			name:        "invalid event name pattern",
+			request:     &ccevents.ChaincodeEventsRequest{SeekInfo: seekInfo, ChaincodeId: "mycc", EventNamePattern: "("},
+			expectedErr: "invalid event name pattern \"(\": error parsing regexp: missing closing ): `(`",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chaincodeActionPayload, err := createChaincodeAction(config.chaincodeName, config.eventName, config.txID)
+			require.NoError(t, err)
+			chainManager := createDefaultSupportMamangerMock(config, chaincodeActionPayload, nil)
+
+			var checkedResources []string
+			server := &DeliverServer{
+				DeliverHandler: deliver.NewHandler(chainManager, time.Second, false, deliver.NewMetrics(&disabled.Provider{}), false),
+				PolicyCheckerProvider: func(resourceName string) deliver.PolicyCheckerFunc {
+					checkedResources = append(checkedResources, resourceName)
+					return func(_ *common.Envelope, _ string) error {
+						return nil
+					}
+				},
+			}
+
+			stream := &mockChaincodeEventsServer{requests: []*ccevents.ChaincodeEventsRequest{test.request}}
+			err = server.DeliverChaincodeEvents(stream)
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{"event/ChaincodeEvents"}, checkedResources)
+			require.Len(t, stream.responses, len(test.expectedResponses))
+			for i, expected := range test.expectedResponses {
+				require.True(t, proto.Equal(expected, stream.responses[i]), "response %d: %v", i, stream.responses[i])
+			}
+		})
+	}
+}
+
+func TestBlockEventToChaincodeEvents(t *testing.T) {
+	var envelopes []*common.Envelope
+	for i, event := range []struct{ chaincodeName, eventName string }{
+		{"mycc", "created"},
+		{"othercc", "created"},
+		{"mycc", "deleted"},
+		{"mycc", "created"},
+		{"mycc", "updated"},
+	} {
+		chaincodeActionPayload, err := createChaincodeAction(event.chaincodeName, event.eventName, fmt.Sprintf("tx%d", i))
+		require.NoError(t, err)
+		payload, err := createEndorsement("testChannelID", fmt.Sprintf("tx%d", i), chaincodeActionPayload)
+		require.NoError(t, err)
+		envelopes = append(envelopes, &common.Envelope{Payload: protoutil.MarshalOrPanic(payload)})
+	}
+	block, err := createTestBlock(envelopes)
+	require.NoError(t, err)
+	block.Header.Number = 5
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER][3] = byte(peer.TxValidationCode_MVCC_READ_CONFLICT)
+
+	checkpointsOf := func(events []*ccevents.TransactionChaincodeEvent) []uint64 {
+		var txIndexes []uint64
+		for _, e := range events {
+			require.Equal(t, uint64(5), e.Checkpoint.BlockNumber)
+			require.Equal(t, fmt.Sprintf("tx%d", e.Checkpoint.TransactionIndex), e.Event.TxId)
+			txIndexes = append(txIndexes, e.Checkpoint.TransactionIndex)
+		}
+		return txIndexes
+	}
+
+	tests := []struct {
+		name              string
+		filter            *chaincodeEventsFilter
+		expectedTxIndexes []uint64
+	}{
+		{
+			name:              "events of the valid transactions",
+			filter:            &chaincodeEventsFilter{chaincodeID: "mycc"},
+			expectedTxIndexes: []uint64{0, 2, 4},
+		},
+		{
+			name:              "matching event name",
+			filter:            &chaincodeEventsFilter{chaincodeID: "mycc", eventName: regexp.MustCompile("^(created|updated)$")},
+			expectedTxIndexes: []uint64{0, 4},
+		},
+		{
+			name:              "after a checkpoint in the block",
+			filter:            &chaincodeEventsFilter{chaincodeID: "mycc", checkpoint: &ccevents.Checkpoint{BlockNumber: 5, TransactionIndex: 2}},
+			expectedTxIndexes: []uint64{4},
+		},
+		{
+			name:              "after a checkpoint in a previous block",
+			filter:            &chaincodeEventsFilter{chaincodeID: "mycc", checkpoint: &ccevents.Checkpoint{BlockNumber: 4, TransactionIndex: 7}},
+			expectedTxIndexes: []uint64{0, 2, 4},
+		},
+		{
+			name:   "before a checkpoint in a next block",
+			filter: &chaincodeEventsFilter{chaincodeID: "mycc", checkpoint: &ccevents.Checkpoint{BlockNumber: 6, TransactionIndex: 0}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := blockEvent(*block)
+			events, err := b.toChaincodeEvents(test.filter)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTxIndexes, checkpointsOf(events))
+		})
+	}
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload, pvtData []*ledger.TxPvtData) *mockChainManager {
 	chainManager := &mockChainManager{}
 	iter := &mockIterator{}
@@ -337,6 +337,8 @@ func (p *Peer) createChannel(
 	channel := &Channel{
 		ledger:         l,
 		resources:      bundle,
+		ordererSource:  ordererSource,
+		commitNotifier: committer.NewCommitNotifier(),
 		cryptoProvider: p.CryptoProvider,
 	}
 
@@ -349,7 +351,7 @@ func (p *Peer) createChannel(
 		channel.bundleUpdate,
 	)
 
-	committer := committer.NewLedgerCommitter(l)
+	committer := committer.NewLedgerCommitter(l).WithCommitNotifier(channel.commitNotifier)
 	validator := &txvalidator.ValidationRouter{
 		CapabilityProvider: channel,
 		V14Validator: validatorv14.NewTxValidator(
@@ -146,6 +146,7 @@ type OrdererStatsd struct {
 }
 
 type ChannelParticipation struct {
-	Enabled            bool   `yaml:"Enabled"`
-	MaxRequestBodySize string `yaml:"MaxRequestBodySize,omitempty"`
+	Enabled                bool   `yaml:"Enabled"`
+	MaxRequestBodySize     string `yaml:"MaxRequestBodySize,omitempty"`
+	MaxBulkRequestBodySize string `yaml:"MaxBulkRequestBodySize,omitempty"`
 }
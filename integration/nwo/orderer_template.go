@@ -130,4 +130,5 @@ Admin:
 ChannelParticipation:
   Enabled: {{ .Consensus.ChannelParticipationEnabled }}
   MaxRequestBodySize: 1 MB
+  MaxBulkRequestBodySize: 10 MB
 `
@@ -0,0 +1,299 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/pkg/errors"
+)
+
+// ComparisonReport is the JSON representation of the outcome of comparing two snapshots of a channel
+type ComparisonReport struct {
+	ChannelID      string               `json:"channel_id"`
+	LastBlockNum   uint64               `json:"last_block_num"`
+	Snapshot1      string               `json:"snapshot1"`
+	Snapshot2      string               `json:"snapshot2"`
+	NumDifferences int                  `json:"num_differences"`
+	Namespaces     []*NamespaceDiffInfo `json:"namespaces"`
+	Differences    []*KeyDiff           `json:"differences"`
+}
+
+// NamespaceDiffInfo captures the number of differing keys in a namespace or in a collection of a namespace
+type NamespaceDiffInfo struct {
+	Namespace      string `json:"namespace"`
+	Collection     string `json:"collection,omitempty"`
+	NumDifferences int    `json:"num_differences"`
+}
+
+// KeyDiff is the JSON representation of a key that differs between the two snapshots. For a private data
+// collection, the key hash is reported instead of the key. The state of the key in a snapshot is null if
+// the key is not present in that snapshot
+type KeyDiff struct {
+	Namespace  string    `json:"namespace"`
+	Collection string    `json:"collection,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	KeyHash    string    `json:"key_hash,omitempty"`
+	Snapshot1  *KeyState `json:"snapshot1"`
+	Snapshot2  *KeyState `json:"snapshot2"`
+}
+
+// KeyState is the JSON representation of the state of a key in a snapshot. BlockNum and TxNum identify the
+// transaction that last wrote the key. The value is reported as a string if it is valid UTF-8 and hex encoded otherwise
+type KeyState struct {
+	Value     string `json:"value,omitempty"`
+	ValueHex  string `json:"value_hex,omitempty"`
+	ValueHash string `json:"value_hash,omitempty"`
+	Metadata  string `json:"metadata,omitempty"`
+	BlockNum  uint64 `json:"block_num"`
+	TxNum     uint64 `json:"tx_num"`
+}
+
+// Compare compares the public state and the private state hashes exported in two snapshots of a channel and writes
+// the keys that differ to w. The two snapshots are expected to be of the same channel and at the same block height.
+// The records in a snapshot are exported in the order of namespaces and keys and hence the comparison is performed
+// by merging the two sorted streams, without holding either of the snapshots in memory.
+// The returned value indicates whether the two snapshots are identical
+func Compare(snapshotDir1, snapshotDir2 string, w io.Writer) (bool, error) {
+	metadata1, err := kvledger.LoadSnapshotSignableMetadata(snapshotDir1)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to load the metadata of the snapshot [%s]", snapshotDir1)
+	}
+	metadata2, err := kvledger.LoadSnapshotSignableMetadata(snapshotDir2)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to load the metadata of the snapshot [%s]", snapshotDir2)
+	}
+	if metadata1.ChannelName != metadata2.ChannelName {
+		return false, errors.Errorf(
+			"the snapshots belong to different channels, snapshot [%s] is of channel [%s] and snapshot [%s] is of channel [%s]",
+			snapshotDir1, metadata1.ChannelName, snapshotDir2, metadata2.ChannelName,
+		)
+	}
+	if metadata1.LastBlockNumber != metadata2.LastBlockNumber {
+		return false, errors.Errorf(
+			"the snapshots are at different heights, snapshot [%s] is at block [%d] and snapshot [%s] is at block [%d]",
+			snapshotDir1, metadata1.LastBlockNumber, snapshotDir2, metadata2.LastBlockNumber,
+		)
+	}
+
+	c := &comparator{
+		report: &ComparisonReport{
+			ChannelID:    metadata1.ChannelName,
+			LastBlockNum: metadata1.LastBlockNumber,
+			Snapshot1:    snapshotDir1,
+			Snapshot2:    snapshotDir2,
+			Namespaces:   []*NamespaceDiffInfo{},
+			Differences:  []*KeyDiff{},
+		},
+	}
+
+	// identical hashes of all the snapshot files imply identical snapshots
+	if !reflect.DeepEqual(metadata1.FilesAndHashes, metadata2.FilesAndHashes) {
+		if err := c.compare(
+			snapshotDir1, snapshotDir2,
+			privacyenabledstate.NewPubStateSnapshotKVReader,
+		); err != nil {
+			return false, errors.WithMessage(err, "failed to compare the public state")
+		}
+		if err := c.compare(
+			snapshotDir1, snapshotDir2,
+			privacyenabledstate.NewPvtStateHashesSnapshotKVReader,
+		); err != nil {
+			return false, errors.WithMessage(err, "failed to compare the private state hashes")
+		}
+	}
+
+	if err := writeJSON(w, c.report); err != nil {
+		return false, err
+	}
+	return c.report.NumDifferences == 0, nil
+}
+
+type comparator struct {
+	report *ComparisonReport
+}
+
+func (c *comparator) compare(
+	snapshotDir1, snapshotDir2 string,
+	newReader func(dir string) (*privacyenabledstate.SnapshotKVReader, error),
+) error {
+	r1, err := newOrderedReader(snapshotDir1, newReader)
+	if err != nil {
+		return err
+	}
+	defer r1.close()
+	r2, err := newOrderedReader(snapshotDir2, newReader)
+	if err != nil {
+		return err
+	}
+	defer r2.close()
+
+	kv1, err := r1.next()
+	if err != nil {
+		return err
+	}
+	kv2, err := r2.next()
+	if err != nil {
+		return err
+	}
+	for kv1 != nil || kv2 != nil {
+		switch cmp := comparePositions(kv1, kv2); {
+		case cmp < 0:
+			c.addDiff(kv1, nil)
+			if kv1, err = r1.next(); err != nil {
+				return err
+			}
+		case cmp > 0:
+			c.addDiff(nil, kv2)
+			if kv2, err = r2.next(); err != nil {
+				return err
+			}
+		default:
+			if !bytes.Equal(kv1.Value, kv2.Value) ||
+				!bytes.Equal(kv1.Metadata, kv2.Metadata) ||
+				kv1.BlockNum != kv2.BlockNum ||
+				kv1.TxNum != kv2.TxNum {
+				c.addDiff(kv1, kv2)
+			}
+			if kv1, err = r1.next(); err != nil {
+				return err
+			}
+			if kv2, err = r2.next(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *comparator) addDiff(kv1, kv2 *privacyenabledstate.SnapshotKV) {
+	kv := kv1
+	if kv == nil {
+		kv = kv2
+	}
+	diff := &KeyDiff{
+		Namespace:  kv.Namespace,
+		Collection: kv.Collection,
+		Snapshot1:  keyState(kv1),
+		Snapshot2:  keyState(kv2),
+	}
+	if kv.Collection == "" {
+		diff.Key = string(kv.Key)
+	} else {
+		diff.KeyHash = hex.EncodeToString(kv.Key)
+	}
+	c.report.Differences = append(c.report.Differences, diff)
+	c.report.NumDifferences++
+
+	// the differences are added in the order of namespaces and collections and hence a new
+	// summary entry is needed only when the namespace or the collection changes
+	numNamespaces := len(c.report.Namespaces)
+	if numNamespaces == 0 ||
+		c.report.Namespaces[numNamespaces-1].Namespace != kv.Namespace ||
+		c.report.Namespaces[numNamespaces-1].Collection != kv.Collection {
+		c.report.Namespaces = append(c.report.Namespaces, &NamespaceDiffInfo{
+			Namespace:  kv.Namespace,
+			Collection: kv.Collection,
+		})
+	}
+	c.report.Namespaces[len(c.report.Namespaces)-1].NumDifferences++
+}
+
+func keyState(kv *privacyenabledstate.SnapshotKV) *KeyState {
+	if kv == nil {
+		return nil
+	}
+	s := &KeyState{
+		BlockNum: kv.BlockNum,
+		TxNum:    kv.TxNum,
+	}
+	switch {
+	case kv.Collection != "":
+		s.ValueHash = hex.EncodeToString(kv.Value)
+	case utf8.Valid(kv.Value):
+		s.Value = string(kv.Value)
+	default:
+		s.ValueHex = hex.EncodeToString(kv.Value)
+	}
+	if len(kv.Metadata) > 0 {
+		s.Metadata = hex.EncodeToString(kv.Metadata)
+	}
+	return s
+}
+
+// comparePositions compares the positions of two records in the sorted stream of snapshot records.
+// A nil record is positioned after all the records
+func comparePositions(kv1, kv2 *privacyenabledstate.SnapshotKV) int {
+	switch {
+	case kv1 == nil && kv2 == nil:
+		return 0
+	case kv1 == nil:
+		return 1
+	case kv2 == nil:
+		return -1
+	}
+	if c := strings.Compare(kv1.Namespace, kv2.Namespace); c != 0 {
+		return c
+	}
+	if c := strings.Compare(kv1.Collection, kv2.Collection); c != 0 {
+		return c
+	}
+	return bytes.Compare(kv1.Key, kv2.Key)
+}
+
+// orderedReader wraps a SnapshotKVReader and returns an error if the records are not in the order
+// expected by the comparison. This may be the case, for instance, for a snapshot generated from a
+// CouchDB state database, if the keys contain characters for which the collation order of CouchDB
+// differs from the byte order
+type orderedReader struct {
+	snapshotDir string
+	reader      *privacyenabledstate.SnapshotKVReader
+	previous    *privacyenabledstate.SnapshotKV
+}
+
+func newOrderedReader(
+	snapshotDir string,
+	newReader func(dir string) (*privacyenabledstate.SnapshotKVReader, error),
+) (*orderedReader, error) {
+	reader, err := newReader(snapshotDir)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to open the snapshot [%s]", snapshotDir)
+	}
+	return &orderedReader{
+		snapshotDir: snapshotDir,
+		reader:      reader,
+	}, nil
+}
+
+func (r *orderedReader) next() (*privacyenabledstate.SnapshotKV, error) {
+	kv, err := r.reader.Next()
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to read the snapshot [%s]", r.snapshotDir)
+	}
+	if kv == nil {
+		return nil, nil
+	}
+	if r.previous != nil && comparePositions(r.previous, kv) >= 0 {
+		return nil, errors.Errorf(
+			"the records in the snapshot [%s] are not in the expected order, key [%x] in namespace [%s] and collection [%s] follows key [%x] in namespace [%s] and collection [%s]",
+			r.snapshotDir, kv.Key, kv.Namespace, kv.Collection, r.previous.Key, r.previous.Namespace, r.previous.Collection,
+		)
+	}
+	r.previous = kv
+	return kv, nil
+}
+
+func (r *orderedReader) close() {
+	r.reader.Close()
+}
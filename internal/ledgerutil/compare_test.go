@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/snapshot"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/stretchr/testify/require"
+)
+
+type testKV struct {
+	namespace string
+	key       string
+	value     string
+	blockNum  uint64
+	txNum     uint64
+}
+
+type testSnapshot struct {
+	channelID      string
+	lastBlockNum   uint64
+	pubState       []*testKV
+	pvtStateHashes []*testKV
+}
+
+// createSnapshot writes the state data files and the signable metadata file in the format
+// generated by the peer. The namespaces of the private state hashes are expected to be in
+// the form <namespace>$$h<collection>, as stored in the state database
+func createSnapshot(t *testing.T, s *testSnapshot) (string, func()) {
+	dir, err := ioutil.TempDir("", "ledgerutil-snapshot")
+	require.NoError(t, err)
+
+	filesAndHashes := map[string]string{}
+	writeStateFiles := func(dataFileName, metadataFileName string, kvs []*testKV) {
+		if len(kvs) == 0 {
+			return
+		}
+		newHashFunc := func() (hash.Hash, error) { return sha256.New(), nil }
+		dataFile, err := snapshot.CreateFile(filepath.Join(dir, dataFileName), 1, newHashFunc)
+		require.NoError(t, err)
+		metadataFile, err := snapshot.CreateFile(filepath.Join(dir, metadataFileName), 1, newHashFunc)
+		require.NoError(t, err)
+
+		namespaces := []string{}
+		counts := map[string]uint64{}
+		for _, kv := range kvs {
+			if counts[kv.namespace] == 0 {
+				namespaces = append(namespaces, kv.namespace)
+			}
+			counts[kv.namespace]++
+			require.NoError(t, dataFile.EncodeProtoMessage(&privacyenabledstate.SnapshotRecord{
+				Key:   []byte(kv.key),
+				Value: []byte(kv.value),
+				Version: append(
+					util.EncodeOrderPreservingVarUint64(kv.blockNum),
+					util.EncodeOrderPreservingVarUint64(kv.txNum)...,
+				),
+			}))
+		}
+		dataHash, err := dataFile.Done()
+		require.NoError(t, err)
+
+		require.NoError(t, metadataFile.EncodeUVarint(uint64(len(namespaces))))
+		for _, ns := range namespaces {
+			require.NoError(t, metadataFile.EncodeString(ns))
+			require.NoError(t, metadataFile.EncodeUVarint(counts[ns]))
+		}
+		metadataHash, err := metadataFile.Done()
+		require.NoError(t, err)
+
+		filesAndHashes[dataFileName] = hex.EncodeToString(dataHash)
+		filesAndHashes[metadataFileName] = hex.EncodeToString(metadataHash)
+	}
+	writeStateFiles("public_state.data", "public_state.metadata", s.pubState)
+	writeStateFiles("private_state_hashes.data", "private_state_hashes.metadata", s.pvtStateHashes)
+
+	metadataJSON, err := json.Marshal(&kvledger.SnapshotSignableMetadata{
+		ChannelName:     s.channelID,
+		LastBlockNumber: s.lastBlockNum,
+		FilesAndHashes:  filesAndHashes,
+		StateDBType:     "SimpleKeyValueDB",
+	})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "_snapshot_signable_metadata.json"), metadataJSON, 0644))
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestCompare(t *testing.T) {
+	baseSnapshot := &testSnapshot{
+		channelID:    "testchannel",
+		lastBlockNum: 10,
+		pubState: []*testKV{
+			{namespace: "ns1", key: "key1", value: "value1", blockNum: 2, txNum: 0},
+			{namespace: "ns1", key: "key2", value: "value2", blockNum: 3, txNum: 1},
+			{namespace: "ns2", key: "key1", value: "value1", blockNum: 4, txNum: 0},
+		},
+		pvtStateHashes: []*testKV{
+			{namespace: "ns1$$hcoll1", key: "key-hash1", value: "value-hash1", blockNum: 5, txNum: 0},
+		},
+	}
+	snapshotDir1, cleanup := createSnapshot(t, baseSnapshot)
+	defer cleanup()
+
+	t.Run("identical-snapshots", func(t *testing.T) {
+		snapshotDir2, cleanup := createSnapshot(t, baseSnapshot)
+		defer cleanup()
+
+		buf := &bytes.Buffer{}
+		identical, err := Compare(snapshotDir1, snapshotDir2, buf)
+		require.NoError(t, err)
+		require.True(t, identical)
+		report := &ComparisonReport{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), report))
+		require.Equal(t, "testchannel", report.ChannelID)
+		require.Equal(t, uint64(10), report.LastBlockNum)
+		require.Equal(t, 0, report.NumDifferences)
+		require.Empty(t, report.Differences)
+	})
+
+	t.Run("diverged-snapshots", func(t *testing.T) {
+		snapshotDir2, cleanup := createSnapshot(t, &testSnapshot{
+			channelID:    "testchannel",
+			lastBlockNum: 10,
+			pubState: []*testKV{
+				{namespace: "ns1", key: "key1", value: "value1", blockNum: 2, txNum: 0},
+				{namespace: "ns1", key: "key2", value: "value2-diverged", blockNum: 7, txNum: 2},
+				{namespace: "ns1", key: "key3", value: "value3", blockNum: 8, txNum: 0},
+				{namespace: "ns2", key: "key1", value: "value1", blockNum: 4, txNum: 0},
+			},
+			pvtStateHashes: []*testKV{
+				{namespace: "ns1$$hcoll1", key: "key-hash1", value: "value-hash1-diverged", blockNum: 5, txNum: 0},
+			},
+		})
+		defer cleanup()
+
+		buf := &bytes.Buffer{}
+		identical, err := Compare(snapshotDir1, snapshotDir2, buf)
+		require.NoError(t, err)
+		require.False(t, identical)
+		report := &ComparisonReport{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), report))
+		require.Equal(t, 3, report.NumDifferences)
+		require.Equal(t,
+			[]*NamespaceDiffInfo{
+				{Namespace: "ns1", NumDifferences: 2},
+				{Namespace: "ns1", Collection: "coll1", NumDifferences: 1},
+			},
+			report.Namespaces,
+		)
+		require.Equal(t,
+			[]*KeyDiff{
+				{
+					Namespace: "ns1",
+					Key:       "key2",
+					Snapshot1: &KeyState{Value: "value2", BlockNum: 3, TxNum: 1},
+					Snapshot2: &KeyState{Value: "value2-diverged", BlockNum: 7, TxNum: 2},
+				},
+				{
+					Namespace: "ns1",
+					Key:       "key3",
+					Snapshot2: &KeyState{Value: "value3", BlockNum: 8, TxNum: 0},
+				},
+				{
+					Namespace:  "ns1",
+					Collection: "coll1",
+					KeyHash:    hex.EncodeToString([]byte("key-hash1")),
+					Snapshot1:  &KeyState{ValueHash: hex.EncodeToString([]byte("value-hash1")), BlockNum: 5},
+					Snapshot2:  &KeyState{ValueHash: hex.EncodeToString([]byte("value-hash1-diverged")), BlockNum: 5},
+				},
+			},
+			report.Differences,
+		)
+	})
+
+	t.Run("different-channels", func(t *testing.T) {
+		snapshotDir2, cleanup := createSnapshot(t, &testSnapshot{channelID: "anotherchannel", lastBlockNum: 10})
+		defer cleanup()
+
+		_, err := Compare(snapshotDir1, snapshotDir2, &bytes.Buffer{})
+		require.EqualError(t, err,
+			"the snapshots belong to different channels, snapshot ["+snapshotDir1+"] is of channel [testchannel] and snapshot ["+snapshotDir2+"] is of channel [anotherchannel]",
+		)
+	})
+
+	t.Run("different-heights", func(t *testing.T) {
+		snapshotDir2, cleanup := createSnapshot(t, &testSnapshot{channelID: "testchannel", lastBlockNum: 11})
+		defer cleanup()
+
+		_, err := Compare(snapshotDir1, snapshotDir2, &bytes.Buffer{})
+		require.EqualError(t, err,
+			"the snapshots are at different heights, snapshot ["+snapshotDir1+"] is at block [10] and snapshot ["+snapshotDir2+"] is at block [11]",
+		)
+	})
+
+	t.Run("unordered-records", func(t *testing.T) {
+		snapshotDir2, cleanup := createSnapshot(t, &testSnapshot{
+			channelID:    "testchannel",
+			lastBlockNum: 10,
+			pubState: []*testKV{
+				{namespace: "ns1", key: "key2", value: "value2", blockNum: 3, txNum: 1},
+				{namespace: "ns1", key: "key1", value: "value1", blockNum: 2, txNum: 0},
+			},
+		})
+		defer cleanup()
+
+		_, err := Compare(snapshotDir1, snapshotDir2, &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the records in the snapshot ["+snapshotDir2+"] are not in the expected order")
+	})
+
+	t.Run("missing-metadata", func(t *testing.T) {
+		_, err := Compare(snapshotDir1, "non-existing-dir", &bytes.Buffer{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load the metadata of the snapshot [non-existing-dir]")
+	})
+}
@@ -0,0 +1,343 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// LedgerInfo summarizes the block files and the block index of a ledger
+type LedgerInfo struct {
+	ChannelID         string             `json:"channel_id"`
+	FirstBlockNum     uint64             `json:"first_block_num"`
+	LastBlockNum      *uint64            `json:"last_block_num"`
+	LatestFileNum     int                `json:"latest_file_num"`
+	BootstrapSnapshot *BootstrapSnapshot `json:"bootstrap_snapshot,omitempty"`
+	Pruning           *Pruning           `json:"pruning,omitempty"`
+	Index             *IndexInfo         `json:"index"`
+}
+
+// BootstrapSnapshot captures the details of the snapshot from which a ledger was bootstrapped
+type BootstrapSnapshot struct {
+	LastBlockNum      uint64 `json:"last_block_num"`
+	LastBlockHash     string `json:"last_block_hash"`
+	PreviousBlockHash string `json:"previous_block_hash"`
+}
+
+// Pruning captures the details of the blocks pruned from the block files
+type Pruning struct {
+	FirstBlockNum uint64 `json:"first_block_num"`
+	FirstFileNum  uint64 `json:"first_file_num"`
+}
+
+// IndexInfo summarizes the block index of a ledger
+type IndexInfo struct {
+	Format           string   `json:"format"`
+	IndexedAttrs     []string `json:"indexed_attributes"`
+	LastBlockIndexed *uint64  `json:"last_block_indexed"`
+}
+
+// BlockSummary is the JSON representation of a block. The fully decoded block is included
+// only when requested
+type BlockSummary struct {
+	Number       uint64          `json:"number"`
+	Hash         string          `json:"hash"`
+	PreviousHash string          `json:"previous_hash"`
+	DataHash     string          `json:"data_hash"`
+	Location     *Location       `json:"location,omitempty"`
+	Transactions []*TxSummary    `json:"transactions"`
+	Block        json.RawMessage `json:"block,omitempty"`
+}
+
+// TxSummary is the JSON representation of a transaction present in a block
+type TxSummary struct {
+	Index          int    `json:"index"`
+	TxID           string `json:"tx_id"`
+	Type           string `json:"type"`
+	ValidationCode string `json:"validation_code,omitempty"`
+}
+
+// TxLookupResult is the JSON representation of the entries found in the txID index for a txID
+type TxLookupResult struct {
+	TxID    string        `json:"tx_id"`
+	Entries []*TxLocation `json:"entries"`
+}
+
+// TxLocation is the JSON representation of a txID index entry. The decoded envelope is included
+// only when requested
+type TxLocation struct {
+	BlockNum       uint64          `json:"block_num"`
+	TxNum          uint64          `json:"tx_num"`
+	ValidationCode string          `json:"validation_code"`
+	Pruned         bool            `json:"pruned,omitempty"`
+	BlockLocation  *Location       `json:"block_location,omitempty"`
+	TxLocation     *Location       `json:"tx_location,omitempty"`
+	Envelope       json.RawMessage `json:"envelope,omitempty"`
+}
+
+// Location is the JSON representation of a placement in the block files
+type Location struct {
+	FileNum int   `json:"file_num"`
+	Offset  int64 `json:"offset"`
+	Length  int   `json:"length,omitempty"`
+	// OffsetInBlock and LengthInBlock locate a transaction within the decompressed bytes of a compressed block
+	OffsetInBlock int64 `json:"offset_in_block,omitempty"`
+	LengthInBlock int   `json:"length_in_block,omitempty"`
+}
+
+// Info writes the summary of the block files and the block index of the ledger
+func Info(inspector *blkstorage.Inspector, channelID string, w io.Writer) error {
+	latestFileNum, lastBlockNum, noBlockFiles, err := inspector.BlockfilesInfo()
+	if err != nil {
+		return err
+	}
+	indexInfo, err := inspector.IndexInfo()
+	if err != nil {
+		return err
+	}
+
+	info := &LedgerInfo{
+		ChannelID:     channelID,
+		FirstBlockNum: inspector.FirstBlockNum(),
+		LatestFileNum: latestFileNum,
+		Index: &IndexInfo{
+			Format:       indexInfo.Format,
+			IndexedAttrs: []string{},
+		},
+	}
+	if !noBlockFiles {
+		info.LastBlockNum = &lastBlockNum
+	}
+	if bsi := inspector.BootstrappingSnapshotInfo(); bsi != nil {
+		info.BootstrapSnapshot = &BootstrapSnapshot{
+			LastBlockNum:      bsi.LastBlockNum,
+			LastBlockHash:     hex.EncodeToString(bsi.LastBlockHash),
+			PreviousBlockHash: hex.EncodeToString(bsi.PreviousBlockHash),
+		}
+	}
+	if pruningInfo := inspector.PruningInfo(); pruningInfo != nil {
+		info.Pruning = &Pruning{
+			FirstBlockNum: pruningInfo.FirstBlockNum,
+			FirstFileNum:  pruningInfo.FirstFileNum,
+		}
+	}
+	for _, a := range indexInfo.IndexedAttrs {
+		info.Index.IndexedAttrs = append(info.Index.IndexedAttrs, string(a))
+	}
+	if indexInfo.LastBlockIndexedFound {
+		info.Index.LastBlockIndexed = &indexInfo.LastBlockIndexed
+	}
+	return writeJSON(w, info)
+}
+
+// DumpBlocks writes, as a JSON array, the blocks in the range [startNum, endNum] read sequentially
+// from the block files
+func DumpBlocks(inspector *blkstorage.Inspector, startNum, endNum uint64, full bool, w io.Writer) error {
+	if startNum > endNum {
+		return errors.Errorf("start block [%d] is greater than end block [%d]", startNum, endNum)
+	}
+	itr, err := inspector.RetrieveBlocks(startNum)
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+	first := true
+	for {
+		block, loc, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if block == nil || block.Header.Number > endNum {
+			break
+		}
+		summary, err := summarizeBlock(block, loc, full)
+		if err != nil {
+			return err
+		}
+		b, err := json.MarshalIndent(summary, "\t", "\t")
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal block [%d]", block.Header.Number)
+		}
+		sep := ",\n\t"
+		if first {
+			sep, first = "\n\t", false
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	if !first {
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+	}
+	_, err = io.WriteString(w, "]\n")
+	return err
+}
+
+// LookupBlockByNumber writes the block with the given number, located via the block index
+func LookupBlockByNumber(inspector *blkstorage.Inspector, blockNum uint64, full bool, w io.Writer) error {
+	loc, err := inspector.BlockLocationByNumber(blockNum)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to locate block [%d] in the block index", blockNum)
+	}
+	return writeBlockAt(inspector, loc, full, w)
+}
+
+// LookupBlockByHash writes the block with the given header hash, located via the block index
+func LookupBlockByHash(inspector *blkstorage.Inspector, blockHash []byte, full bool, w io.Writer) error {
+	loc, err := inspector.BlockLocationByHash(blockHash)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to locate block with hash [%x] in the block index", blockHash)
+	}
+	return writeBlockAt(inspector, loc, full, w)
+}
+
+// LookupTx writes all the entries present in the txID index for the given txID
+func LookupTx(inspector *blkstorage.Inspector, txID string, full bool, w io.Writer) error {
+	entries, err := inspector.TxIndexEntries(txID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to retrieve txID [%s] from the block index", txID)
+	}
+	if len(entries) == 0 {
+		return errors.Errorf("txID [%s] not found in the block index", txID)
+	}
+
+	result := &TxLookupResult{TxID: txID}
+	for _, e := range entries {
+		l := &TxLocation{
+			BlockNum:       e.BlockNum,
+			TxNum:          e.TxNum,
+			ValidationCode: validationCodeString(e.ValidationCode),
+			Pruned:         e.Pruned,
+		}
+		if e.BlockLocation != nil {
+			l.BlockLocation = &Location{FileNum: e.BlockLocation.FileNum, Offset: e.BlockLocation.Offset}
+		}
+		if e.TxLocation != nil {
+			l.TxLocation = &Location{FileNum: e.TxLocation.FileNum, Offset: e.TxLocation.Offset, Length: e.TxLocation.Length}
+			if e.TxLocation.InBlock != nil {
+				l.TxLocation.OffsetInBlock, l.TxLocation.LengthInBlock = e.TxLocation.InBlock.Offset, e.TxLocation.InBlock.Length
+			}
+			if full {
+				env, err := inspector.RetrieveTxAt(e.TxLocation)
+				if err != nil {
+					return err
+				}
+				if l.Envelope, err = deepMarshalJSON(env); err != nil {
+					return errors.WithMessagef(err, "malformed transaction contents for txID [%s]", txID)
+				}
+			}
+		}
+		result.Entries = append(result.Entries, l)
+	}
+	return writeJSON(w, result)
+}
+
+func writeBlockAt(inspector *blkstorage.Inspector, loc *blkstorage.BlockLocation, full bool, w io.Writer) error {
+	block, err := inspector.RetrieveBlockAt(loc)
+	if err != nil {
+		return err
+	}
+	summary, err := summarizeBlock(block, loc, full)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, summary)
+}
+
+func summarizeBlock(block *common.Block, loc *blkstorage.BlockLocation, full bool) (*BlockSummary, error) {
+	summary := &BlockSummary{
+		Number:       block.Header.Number,
+		Hash:         hex.EncodeToString(protoutil.BlockHeaderHash(block.Header)),
+		PreviousHash: hex.EncodeToString(block.Header.PreviousHash),
+		DataHash:     hex.EncodeToString(block.Header.DataHash),
+		Transactions: []*TxSummary{},
+	}
+	if loc != nil {
+		summary.Location = &Location{FileNum: loc.FileNum, Offset: loc.Offset}
+	}
+
+	var txsFilter txflags.ValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	for i, envBytes := range block.Data.Data {
+		tx := &TxSummary{Index: i}
+		if chdr, err := channelHeader(envBytes); err == nil {
+			tx.TxID = chdr.TxId
+			tx.Type = common.HeaderType(chdr.Type).String()
+		} else {
+			tx.Type = "UNKNOWN"
+		}
+		if i < len(txsFilter) {
+			tx.ValidationCode = validationCodeString(txsFilter.Flag(i))
+		}
+		summary.Transactions = append(summary.Transactions, tx)
+	}
+
+	if full {
+		var err error
+		if summary.Block, err = deepMarshalJSON(block); err != nil {
+			return nil, errors.WithMessagef(err, "malformed block contents for block [%d]", block.Header.Number)
+		}
+	}
+	return summary, nil
+}
+
+func channelHeader(envBytes []byte) (*common.ChannelHeader, error) {
+	env, err := protoutil.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	return protoutil.ChannelHeader(env)
+}
+
+func validationCodeString(c peer.TxValidationCode) string {
+	if c < 0 {
+		return "UNKNOWN"
+	}
+	return c.String()
+}
+
+func deepMarshalJSON(msg proto.Message) (json.RawMessage, error) {
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, msg); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal output")
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+var peerIndexConfig = &blkstorage.IndexConfig{
+	AttrsToIndex: []blkstorage.IndexableAttr{
+		blkstorage.IndexableAttrBlockHash,
+		blkstorage.IndexableAttrBlockNum,
+		blkstorage.IndexableAttrTxID,
+		blkstorage.IndexableAttrBlockNumTranNum,
+	},
+}
+
+func createBlockStore(t *testing.T, blocks []*common.Block) (string, func()) {
+	blockStoreDir, err := ioutil.TempDir("", "ledgerutil")
+	require.NoError(t, err)
+	provider, err := blkstorage.NewProvider(blkstorage.NewConf(blockStoreDir, 0), peerIndexConfig, &disabled.Provider{})
+	require.NoError(t, err)
+	store, err := provider.Open("testchannel")
+	require.NoError(t, err)
+	for _, b := range blocks {
+		require.NoError(t, store.AddBlock(b))
+	}
+	store.Shutdown()
+	provider.Close()
+	return blockStoreDir, func() { os.RemoveAll(blockStoreDir) }
+}
+
+// constructTestBlocks returns a genesis block followed by blocks with transactions
+// that carry empty read-write sets, so that the blocks can be fully decoded
+func constructTestBlocks(t *testing.T, numBlocks int) []*common.Block {
+	rwsetBytes, err := proto.Marshal(&rwset.TxReadWriteSet{DataModel: rwset.TxReadWriteSet_KV})
+	require.NoError(t, err)
+	bg, gb := testutil.NewBlockGenerator(t, "testchannel", false)
+	blocks := []*common.Block{gb}
+	for i := 1; i < numBlocks; i++ {
+		block := bg.NextBlock([][]byte{rwsetBytes, rwsetBytes, rwsetBytes})
+		block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txflags.NewWithValues(3, peer.TxValidationCode_VALID)
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func openInspector(t *testing.T, blockStoreDir string) *blkstorage.Inspector {
+	inspector, err := blkstorage.OpenInspector(blockStoreDir, "testchannel")
+	require.NoError(t, err)
+	return inspector
+}
+
+func TestInfo(t *testing.T) {
+	blocks := constructTestBlocks(t, 5)
+	blockStoreDir, cleanup := createBlockStore(t, blocks)
+	defer cleanup()
+	inspector := openInspector(t, blockStoreDir)
+	defer inspector.Close()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Info(inspector, "testchannel", buf))
+
+	info := &LedgerInfo{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), info))
+	lastBlockNum := uint64(4)
+	require.Equal(t,
+		&LedgerInfo{
+			ChannelID:     "testchannel",
+			FirstBlockNum: 0,
+			LastBlockNum:  &lastBlockNum,
+			LatestFileNum: 0,
+			Index: &IndexInfo{
+				Format:           "2.0",
+				IndexedAttrs:     []string{"BlockHash", "BlockNum", "TxID", "BlockNumTranNum"},
+				LastBlockIndexed: &lastBlockNum,
+			},
+		},
+		info,
+	)
+}
+
+func TestDumpBlocks(t *testing.T) {
+	blocks := constructTestBlocks(t, 10)
+	blockStoreDir, cleanup := createBlockStore(t, blocks)
+	defer cleanup()
+	inspector := openInspector(t, blockStoreDir)
+	defer inspector.Close()
+
+	t.Run("summary", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, DumpBlocks(inspector, 3, 6, false, buf))
+
+		summaries := []*BlockSummary{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summaries))
+		require.Len(t, summaries, 4)
+		for i, s := range summaries {
+			block := blocks[i+3]
+			require.Equal(t, block.Header.Number, s.Number)
+			require.Equal(t, hex.EncodeToString(protoutil.BlockHeaderHash(block.Header)), s.Hash)
+			require.Equal(t, hex.EncodeToString(block.Header.PreviousHash), s.PreviousHash)
+			require.Len(t, s.Transactions, len(block.Data.Data))
+			require.Equal(t, "ENDORSER_TRANSACTION", s.Transactions[0].Type)
+			require.Equal(t, "VALID", s.Transactions[0].ValidationCode)
+			require.NotNil(t, s.Location)
+			require.Nil(t, s.Block)
+		}
+	})
+
+	t.Run("full", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, DumpBlocks(inspector, 0, 1, true, buf))
+
+		summaries := []*BlockSummary{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &summaries))
+		require.Len(t, summaries, 2)
+		require.Equal(t, "CONFIG", summaries[0].Transactions[0].Type)
+		require.Contains(t, string(summaries[0].Block), `"channel_group"`)
+		require.Contains(t, string(summaries[1].Block), `"data_hash"`)
+	})
+
+	t.Run("empty-range", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, DumpBlocks(inspector, 15, 20, false, buf))
+		require.Equal(t, "[]\n", buf.String())
+	})
+
+	t.Run("invalid-range", func(t *testing.T) {
+		err := DumpBlocks(inspector, 6, 3, false, &bytes.Buffer{})
+		require.EqualError(t, err, "start block [6] is greater than end block [3]")
+	})
+}
+
+func TestLookupBlock(t *testing.T) {
+	blocks := constructTestBlocks(t, 5)
+	blockStoreDir, cleanup := createBlockStore(t, blocks)
+	defer cleanup()
+	inspector := openInspector(t, blockStoreDir)
+	defer inspector.Close()
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, LookupBlockByNumber(inspector, 2, false, buf))
+	byNumber := &BlockSummary{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), byNumber))
+	require.Equal(t, uint64(2), byNumber.Number)
+
+	buf.Reset()
+	require.NoError(t, LookupBlockByHash(inspector, protoutil.BlockHeaderHash(blocks[2].Header), false, buf))
+	byHash := &BlockSummary{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), byHash))
+	require.Equal(t, byNumber, byHash)
+
+	err := LookupBlockByNumber(inspector, 10, false, buf)
+	require.EqualError(t, err, "failed to locate block [10] in the block index: Entry not found in index")
+}
+
+func TestLookupTx(t *testing.T) {
+	blocks := constructTestBlocks(t, 5)
+	blockStoreDir, cleanup := createBlockStore(t, blocks)
+	defer cleanup()
+	inspector := openInspector(t, blockStoreDir)
+	defer inspector.Close()
+
+	txID, err := protoutil.GetOrComputeTxIDFromEnvelope(blocks[3].Data.Data[1])
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, LookupTx(inspector, txID, true, buf))
+	result := &TxLookupResult{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), result))
+	require.Equal(t, txID, result.TxID)
+	require.Len(t, result.Entries, 1)
+	require.Equal(t, uint64(3), result.Entries[0].BlockNum)
+	require.Equal(t, uint64(1), result.Entries[0].TxNum)
+	require.Equal(t, "VALID", result.Entries[0].ValidationCode)
+	require.NotNil(t, result.Entries[0].Envelope)
+
+	err = LookupTx(inspector, "non-existing-txid", false, buf)
+	require.EqualError(t, err, "txID [non-existing-txid] not found in the block index")
+}
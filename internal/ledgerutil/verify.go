@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// VerificationReport is the JSON representation of the outcome of verifying a ledger
+type VerificationReport struct {
+	ChannelID        string   `json:"channel_id"`
+	FirstBlockNum    uint64   `json:"first_block_num"`
+	LastBlockNum     *uint64  `json:"last_block_num"`
+	BlocksVerified   uint64   `json:"blocks_verified"`
+	LastBlockIndexed *uint64  `json:"last_block_indexed"`
+	HashChainIssues  []*Issue `json:"hash_chain_issues"`
+	IndexIssues      []*Issue `json:"index_issues"`
+}
+
+// Issue describes an inconsistency found for a block
+type Issue struct {
+	BlockNum uint64 `json:"block_num"`
+	TxNum    *int   `json:"tx_num,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// Verify reads the blocks sequentially from the block files and verifies that the block numbers are contiguous,
+// that the data hash in each block header matches the block data, and that the previous hash in each block header
+// matches the hash of the preceding block header (or the last block hash recorded in the bootstrapping snapshot).
+// In addition, for each block that is covered by the block index, Verify checks that the index entries for the block
+// and its transactions point to the location where the block is actually found in the block files.
+// The report is written to w and the returned value indicates whether any issue was found
+func Verify(inspector *blkstorage.Inspector, channelID string, w io.Writer) (bool, error) {
+	indexInfo, err := inspector.IndexInfo()
+	if err != nil {
+		return false, err
+	}
+	report := &VerificationReport{
+		ChannelID:       channelID,
+		FirstBlockNum:   inspector.FirstBlockNum(),
+		HashChainIssues: []*Issue{},
+		IndexIssues:     []*Issue{},
+	}
+	if indexInfo.LastBlockIndexedFound {
+		report.LastBlockIndexed = &indexInfo.LastBlockIndexed
+	}
+
+	itr, err := inspector.RetrieveBlocks(inspector.FirstBlockNum())
+	if err != nil {
+		return false, err
+	}
+	defer itr.Close()
+
+	expectedBlockNum := inspector.FirstBlockNum()
+	var previousHash []byte
+	// the hash of the block preceding the first available block is unknown if the blocks are pruned
+	if bsi := inspector.BootstrappingSnapshotInfo(); bsi != nil && expectedBlockNum == bsi.LastBlockNum+1 {
+		previousHash = bsi.LastBlockHash
+	}
+
+	for {
+		block, loc, err := itr.Next()
+		if err != nil {
+			return false, err
+		}
+		if block == nil {
+			break
+		}
+		blockNum := block.Header.Number
+		if blockNum != expectedBlockNum {
+			report.HashChainIssues = append(report.HashChainIssues, &Issue{
+				BlockNum: blockNum,
+				Reason:   fmt.Sprintf("unexpected block number, expected [%d]", expectedBlockNum),
+			})
+		}
+		if dataHash := protoutil.BlockDataHash(block.Data); !bytes.Equal(dataHash, block.Header.DataHash) {
+			report.HashChainIssues = append(report.HashChainIssues, &Issue{
+				BlockNum: blockNum,
+				Reason: fmt.Sprintf(
+					"data hash mismatch, data hash in header = [%x], computed data hash = [%x]",
+					block.Header.DataHash, dataHash,
+				),
+			})
+		}
+		if previousHash != nil && !bytes.Equal(previousHash, block.Header.PreviousHash) {
+			report.HashChainIssues = append(report.HashChainIssues, &Issue{
+				BlockNum: blockNum,
+				Reason: fmt.Sprintf(
+					"previous hash mismatch, previous hash in header = [%x], hash of the previous block header = [%x]",
+					block.Header.PreviousHash, previousHash,
+				),
+			})
+		}
+		blockHash := protoutil.BlockHeaderHash(block.Header)
+
+		if indexInfo.LastBlockIndexedFound && blockNum <= indexInfo.LastBlockIndexed {
+			report.IndexIssues = append(report.IndexIssues, verifyIndexEntries(inspector, block, blockHash, loc)...)
+		}
+
+		lastBlockNum := blockNum
+		report.LastBlockNum = &lastBlockNum
+		report.BlocksVerified++
+		previousHash = blockHash
+		expectedBlockNum = blockNum + 1
+	}
+
+	if err := writeJSON(w, report); err != nil {
+		return false, err
+	}
+	return len(report.HashChainIssues) == 0 && len(report.IndexIssues) == 0, nil
+}
+
+func verifyIndexEntries(inspector *blkstorage.Inspector, block *common.Block, blockHash []byte, loc *blkstorage.BlockLocation) []*Issue {
+	var issues []*Issue
+	blockNum := block.Header.Number
+	addIssue := func(txNum *int, format string, args ...interface{}) {
+		issues = append(issues, &Issue{BlockNum: blockNum, TxNum: txNum, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if inspector.IsAttributeIndexed(blkstorage.IndexableAttrBlockNum) {
+		indexedLoc, err := inspector.BlockLocationByNumber(blockNum)
+		switch {
+		case err != nil:
+			addIssue(nil, "block number index lookup failed: %s", err)
+		case *indexedLoc != *loc:
+			addIssue(nil, "block number index points to %s, block found at %s", locString(indexedLoc), locString(loc))
+		}
+	}
+
+	if inspector.IsAttributeIndexed(blkstorage.IndexableAttrBlockHash) {
+		indexedLoc, err := inspector.BlockLocationByHash(blockHash)
+		switch {
+		case err != nil:
+			addIssue(nil, "block hash index lookup failed: %s", err)
+		case *indexedLoc != *loc:
+			addIssue(nil, "block hash index points to %s, block found at %s", locString(indexedLoc), locString(loc))
+		}
+	}
+
+	if !inspector.IsAttributeIndexed(blkstorage.IndexableAttrTxID) {
+		return issues
+	}
+	txsFilter := txflags.ValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, envBytes := range block.Data.Data {
+		txNum := i
+		chdr, err := channelHeader(envBytes)
+		if err != nil {
+			addIssue(&txNum, "failed to extract the channel header: %s", err)
+			continue
+		}
+		if chdr.TxId == "" {
+			continue
+		}
+		entries, err := inspector.TxIndexEntries(chdr.TxId)
+		if err != nil {
+			addIssue(&txNum, "txID index lookup for txID [%s] failed: %s", chdr.TxId, err)
+			continue
+		}
+		var entry *blkstorage.TxIndexEntry
+		for _, e := range entries {
+			if e.BlockNum == blockNum && e.TxNum == uint64(i) {
+				entry = e
+				break
+			}
+		}
+		switch {
+		case entry == nil:
+			addIssue(&txNum, "txID [%s] is missing in the txID index", chdr.TxId)
+		case entry.BlockLocation == nil || *entry.BlockLocation != *loc:
+			addIssue(&txNum, "txID index for txID [%s] points to block at %s, block found at %s",
+				chdr.TxId, locString(entry.BlockLocation), locString(loc))
+		case i < len(txsFilter) && entry.ValidationCode != txsFilter.Flag(i):
+			addIssue(&txNum, "txID index for txID [%s] records validation code [%s], block records [%s]",
+				chdr.TxId, entry.ValidationCode, txsFilter.Flag(i))
+		}
+	}
+	return issues
+}
+
+func locString(loc *blkstorage.BlockLocation) string {
+	if loc == nil {
+		return "[none]"
+	}
+	return fmt.Sprintf("[fileNum=%d, offset=%d]", loc.FileNum, loc.Offset)
+}
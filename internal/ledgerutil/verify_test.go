@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledgerutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	t.Run("consistent-ledger", func(t *testing.T) {
+		blocks := constructTestBlocks(t, 10)
+		blockStoreDir, cleanup := createBlockStore(t, blocks)
+		defer cleanup()
+		inspector := openInspector(t, blockStoreDir)
+		defer inspector.Close()
+
+		buf := &bytes.Buffer{}
+		clean, err := Verify(inspector, "testchannel", buf)
+		require.NoError(t, err)
+		require.True(t, clean)
+
+		report := &VerificationReport{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), report))
+		require.Equal(t, uint64(10), report.BlocksVerified)
+		require.Equal(t, uint64(9), *report.LastBlockNum)
+		require.Equal(t, uint64(9), *report.LastBlockIndexed)
+		require.Empty(t, report.HashChainIssues)
+		require.Empty(t, report.IndexIssues)
+	})
+
+	t.Run("data-hash-mismatch", func(t *testing.T) {
+		blocks := constructTestBlocks(t, 10)
+		blocks[4].Data.Data = blocks[4].Data.Data[1:]
+		blockStoreDir, cleanup := createBlockStore(t, blocks)
+		defer cleanup()
+		inspector := openInspector(t, blockStoreDir)
+		defer inspector.Close()
+
+		buf := &bytes.Buffer{}
+		clean, err := Verify(inspector, "testchannel", buf)
+		require.NoError(t, err)
+		require.False(t, clean)
+
+		report := &VerificationReport{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), report))
+		require.Equal(t, uint64(10), report.BlocksVerified)
+		require.Len(t, report.HashChainIssues, 1)
+		require.Equal(t, uint64(4), report.HashChainIssues[0].BlockNum)
+		require.Contains(t, report.HashChainIssues[0].Reason, "data hash mismatch")
+		require.Empty(t, report.IndexIssues)
+	})
+}
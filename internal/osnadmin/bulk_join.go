@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+)
+
+// Joins an OSN to several new or existing channels at once.
+func BulkJoin(osnURL string, blocksBytes [][]byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/bulk/channels", osnURL)
+	req, err := createBulkJoinRequest(url, blocksBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpDo(req, caCertPool, tlsClientCert)
+}
+
+func createBulkJoinRequest(url string, blocksBytes [][]byte) (*http.Request, error) {
+	joinBody := new(bytes.Buffer)
+	writer := multipart.NewWriter(joinBody)
+	for i, blockBytes := range blocksBytes {
+		part, err := writer.CreateFormFile("config-block", fmt.Sprintf("config-%d.block", i))
+		if err != nil {
+			return nil, err
+		}
+		part.Write(blockBytes)
+	}
+	err := writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, joinBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/internal/osnadmin"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// NewOrderer returns an Orderer that connects to the ordering service endpoint of the OSN at the given address,
+// and signs the Deliver requests with the given signer.
+func NewOrderer(address string, clientConfig comm.ClientConfig, signer identity.SignerSerializer) (Orderer, error) {
+	client, err := comm.NewGRPCClient(clientConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create gRPC client")
+	}
+	var tlsCertHash []byte
+	if cert := client.Certificate(); len(cert.Certificate) > 0 {
+		tlsCertHash = util.ComputeSHA256(cert.Certificate[0])
+	}
+	return &grpcOrderer{
+		address:     address,
+		client:      client,
+		signer:      signer,
+		tlsCertHash: tlsCertHash,
+	}, nil
+}
+
+type grpcOrderer struct {
+	address     string
+	client      *comm.GRPCClient
+	signer      identity.SignerSerializer
+	tlsCertHash []byte
+}
+
+func (o *grpcOrderer) ConfigBlock(channelID string) (*cb.Block, error) {
+	conn, err := o.client.NewConnection(o.address)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to connect to %s", o.address)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	deliver, err := ab.NewAtomicBroadcastClient(conn).Deliver(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Deliver stream")
+	}
+
+	block, err := o.pull(deliver, channelID, &ab.SeekPosition{Type: &ab.SeekPosition_Newest{Newest: &ab.SeekNewest{}}})
+	if err != nil {
+		return nil, err
+	}
+	lastConfig, err := protoutil.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get the last config index from block [%d]", block.Header.Number)
+	}
+	if lastConfig == block.Header.Number {
+		return block, nil
+	}
+	return o.pull(deliver, channelID, &ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: lastConfig}}})
+}
+
+func (o *grpcOrderer) pull(deliver ab.AtomicBroadcast_DeliverClient, channelID string, position *ab.SeekPosition) (*cb.Block, error) {
+	seekInfo := &ab.SeekInfo{
+		Start:    position,
+		Stop:     position,
+		Behavior: ab.SeekInfo_FAIL_IF_NOT_READY,
+	}
+	env, err := protoutil.CreateSignedEnvelopeWithTLSBinding(cb.HeaderType_DELIVER_SEEK_INFO, channelID, o.signer, seekInfo, 0, 0, o.tlsCertHash)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create seek request")
+	}
+	if err := deliver.Send(env); err != nil {
+		return nil, errors.Wrap(err, "failed to send seek request")
+	}
+
+	var block *cb.Block
+	for {
+		resp, err := deliver.Recv()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to receive block")
+		}
+		switch t := resp.Type.(type) {
+		case *ab.DeliverResponse_Block:
+			block = t.Block
+		case *ab.DeliverResponse_Status:
+			if t.Status != cb.Status_SUCCESS || block == nil || block.Header == nil {
+				return nil, errors.Errorf("failed to pull block, got status: %s", t.Status)
+			}
+			return block, nil
+		}
+	}
+}
+
+func (o *grpcOrderer) Broadcast(env *cb.Envelope) error {
+	conn, err := o.client.NewConnection(o.address)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to connect to %s", o.address)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	broadcast, err := ab.NewAtomicBroadcastClient(conn).Broadcast(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create Broadcast stream")
+	}
+	if err := broadcast.Send(env); err != nil {
+		return errors.Wrap(err, "failed to send envelope")
+	}
+	resp, err := broadcast.Recv()
+	if err != nil {
+		return errors.Wrap(err, "failed to receive response")
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		return errors.Errorf("got unexpected status: %v -- %s", resp.Status, resp.Info)
+	}
+	return nil
+}
+
+// NewAdmin returns an Admin that calls the channel participation API of the OSN at the given URL.
+func NewAdmin(osnURL string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) Admin {
+	return &restAdmin{
+		osnURL:        osnURL,
+		caCertPool:    caCertPool,
+		tlsClientCert: tlsClientCert,
+	}
+}
+
+type restAdmin struct {
+	osnURL        string
+	caCertPool    *x509.CertPool
+	tlsClientCert tls.Certificate
+}
+
+func (a *restAdmin) ChannelList() (types.ChannelList, error) {
+	var list types.ChannelList
+	resp, err := osnadmin.ListAllChannels(a.osnURL, a.caCertPool, a.tlsClientCert)
+	if err != nil {
+		return list, err
+	}
+	return list, decodeResponse(resp, &list)
+}
+
+func (a *restAdmin) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	var info types.ChannelInfo
+	resp, err := osnadmin.ListSingleChannel(a.osnURL, channelID, a.caCertPool, a.tlsClientCert)
+	if err != nil {
+		return info, err
+	}
+	return info, decodeResponse(resp, &info)
+}
+
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errResp := &types.ErrorResponse{}
+		json.NewDecoder(resp.Body).Decode(errResp)
+		return errors.Errorf("channel participation API returned status %d: %s", resp.StatusCode, errResp.Error)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errors.Wrap(err, "failed to decode channel participation API response")
+	}
+	return nil
+}
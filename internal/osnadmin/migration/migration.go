@@ -0,0 +1,418 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/internal/configtxlator/update"
+	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// Stage is the stage of the migration from kafka to etcdraft a channel is at, as derived from its latest config.
+type Stage string
+
+const (
+	// The channel is ordered by kafka.
+	StageKafka Stage = "kafka"
+	// The channel is ordered by kafka, in maintenance mode.
+	StageMaintenance Stage = "maintenance"
+	// The consensus type of the channel was switched to etcdraft, in maintenance mode.
+	// etcdraft takes over once the OSNs are restarted.
+	StageSwitched Stage = "switched"
+	// The channel is ordered by etcdraft.
+	StageCompleted Stage = "completed"
+)
+
+var stageOrder = map[Stage]int{
+	StageKafka:       0,
+	StageMaintenance: 1,
+	StageSwitched:    2,
+	StageCompleted:   3,
+}
+
+//go:generate counterfeiter -o mocks/orderer.go -fake-name Orderer . Orderer
+
+// Orderer is the ordering service endpoint of the OSN, through which the config of the channels
+// is pulled and the config updates are submitted.
+type Orderer interface {
+	// ConfigBlock returns the latest config block of the channel.
+	ConfigBlock(channelID string) (*cb.Block, error)
+	// Broadcast submits the envelope for ordering.
+	Broadcast(env *cb.Envelope) error
+}
+
+//go:generate counterfeiter -o mocks/admin.go -fake-name Admin . Admin
+
+// Admin is the channel participation API of the OSN.
+type Admin interface {
+	// ChannelList returns the channels the OSN is a member of.
+	ChannelList() (types.ChannelList, error)
+	// ChannelInfo returns the details of a channel.
+	ChannelInfo(channelID string) (types.ChannelInfo, error)
+}
+
+// ChannelStatus carries the migration stage of a channel.
+type ChannelStatus struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The consensus type and state of the latest config of the channel.
+	ConsensusType  string `json:"consensusType"`
+	ConsensusState string `json:"consensusState"`
+	// The migration stage of the channel.
+	Stage Stage `json:"stage"`
+	// The etcdraft leader of the channel as seen by the OSN, 0 if there is none
+	// or if the OSN did not restart as an etcdraft consenter of the channel yet.
+	Leader uint64 `json:"leader,omitempty"`
+}
+
+// Report carries the migration stage of all the channels of the OSN, and the next step of the migration.
+type Report struct {
+	// The channels of the OSN, the system channel first.
+	Channels []ChannelStatus `json:"channels"`
+	// The channels the config of which was updated.
+	Updated []string `json:"updated,omitempty"`
+	// The next step the administrator has to take.
+	NextStep string `json:"nextStep"`
+}
+
+// Migrator migrates all the channels of an OSN from kafka to etcdraft, following the consensus-type
+// migration process through maintenance mode. Every Run advances all the channels to the next stage,
+// as long as it does not require the administrator to act on the OSNs first, i.e. to take a backup after
+// entering maintenance mode, or to restart the OSNs after switching to etcdraft.
+//
+// The stage of each channel is derived from its latest config, hence a migration that failed midway is
+// resumed by running it again. The config updates are signed by a single orderer organization admin,
+// which must satisfy the mod policy of the ConsensusType value on its own.
+type Migrator struct {
+	Orderer Orderer
+	Admin   Admin
+	Signer  identity.SignerSerializer
+	// The etcdraft metadata all the channels are switched to, only required for switching to etcdraft.
+	RaftMetadata *etcdraft.ConfigMetadata
+	// How long to wait for a config update to be committed, and how often to pull the config meanwhile.
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+type channel struct {
+	ChannelStatus
+	config        *cb.Config
+	consensusType *ab.ConsensusType
+}
+
+// Status returns the migration stage of all the channels, without updating them.
+func (m *Migrator) Status() (*Report, error) {
+	channels, err := m.channels()
+	if err != nil {
+		return nil, err
+	}
+	return report(channels, nil), nil
+}
+
+// Run advances all the channels to the next stage of the migration, and returns the stages they are at.
+func (m *Migrator) Run() (*Report, error) {
+	channels, err := m.channels()
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []string
+	var failed error
+	switch lowest, highest := stageRange(channels); lowest.Stage {
+	case StageKafka:
+		if stageOrder[highest.Stage] > stageOrder[StageMaintenance] {
+			return nil, inconsistentError(lowest, highest)
+		}
+		updated, failed = m.advance(channels, StageKafka, StageMaintenance, func(ct *ab.ConsensusType) {
+			ct.State = ab.ConsensusType_STATE_MAINTENANCE
+		})
+	case StageMaintenance:
+		if highest.Stage == StageCompleted {
+			return nil, inconsistentError(lowest, highest)
+		}
+		if m.RaftMetadata == nil {
+			return nil, errors.New("etcdraft metadata is required to switch the channels to etcdraft")
+		}
+		metadata, err := proto.Marshal(m.RaftMetadata)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal etcdraft metadata")
+		}
+		updated, failed = m.advance(channels, StageMaintenance, StageSwitched, func(ct *ab.ConsensusType) {
+			ct.Type = "etcdraft"
+			ct.Metadata = metadata
+		})
+	case StageSwitched:
+		var notReady []string
+		for _, ch := range channels {
+			if ch.Stage == StageSwitched && ch.Leader == 0 {
+				notReady = append(notReady, ch.Name)
+			}
+		}
+		if len(notReady) > 0 {
+			return nil, errors.Errorf("no etcdraft leader was elected on channels %v, the OSNs must be restarted before exiting maintenance mode", notReady)
+		}
+		updated, failed = m.advance(channels, StageSwitched, StageCompleted, func(ct *ab.ConsensusType) {
+			ct.State = ab.ConsensusType_STATE_NORMAL
+		})
+	}
+	if failed != nil {
+		return nil, errors.WithMessagef(failed, "migration stopped after updating channels %v, run it again to resume", updated)
+	}
+
+	channels, err = m.channels()
+	if err != nil {
+		return nil, err
+	}
+	return report(channels, updated), nil
+}
+
+// advance updates the consensus type of the channels at the given stage, one at a time, and waits
+// for each update to be committed.
+func (m *Migrator) advance(channels []channel, from, to Stage, change func(*ab.ConsensusType)) ([]string, error) {
+	var updated []string
+	for _, ch := range channels {
+		if ch.Stage != from {
+			continue
+		}
+		consensusType := proto.Clone(ch.consensusType).(*ab.ConsensusType)
+		change(consensusType)
+		if err := m.update(ch, consensusType); err != nil {
+			return updated, err
+		}
+		if err := m.waitForStage(ch.Name, to); err != nil {
+			return updated, err
+		}
+		updated = append(updated, ch.Name)
+	}
+	return updated, nil
+}
+
+func (m *Migrator) update(ch channel, consensusType *ab.ConsensusType) error {
+	updatedConfig := proto.Clone(ch.config).(*cb.Config)
+	ordererGroup := updatedConfig.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	ordererGroup.Values[channelconfig.ConsensusTypeKey].Value = protoutil.MarshalOrPanic(consensusType)
+
+	configUpdate, err := update.Compute(ch.config, updatedConfig)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to compute the config update of channel %s", ch.Name)
+	}
+	configUpdate.ChannelId = ch.Name
+
+	env, err := signConfigUpdate(ch.Name, configUpdate, m.Signer)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to sign the config update of channel %s", ch.Name)
+	}
+	if err := m.Orderer.Broadcast(env); err != nil {
+		return errors.WithMessagef(err, "failed to submit the config update of channel %s", ch.Name)
+	}
+	return nil
+}
+
+func (m *Migrator) waitForStage(channelID string, stage Stage) error {
+	deadline := time.Now().Add(m.Timeout)
+	for {
+		ch, err := m.channel(channelID)
+		if err == nil && ch.Stage == stage {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("timed out waiting for the config update of channel %s to be committed", channelID)
+		}
+		time.Sleep(m.PollInterval)
+	}
+}
+
+// channels returns the channels of the OSN, the system channel first.
+func (m *Migrator) channels() ([]channel, error) {
+	list, err := m.Admin.ChannelList()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list the channels")
+	}
+	var names []string
+	if list.SystemChannel != nil {
+		names = append(names, list.SystemChannel.Name)
+	}
+	for _, c := range list.Channels {
+		names = append(names, c.Name)
+	}
+	if len(names) == 0 {
+		return nil, errors.New("the OSN is not a member of any channel")
+	}
+
+	var channels []channel
+	for _, name := range names {
+		ch, err := m.channel(name)
+		if err != nil {
+			return nil, err
+		}
+		if ch.Stage == StageSwitched || ch.Stage == StageCompleted {
+			info, err := m.Admin.ChannelInfo(name)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to get the details of channel %s", name)
+			}
+			if info.Cluster != nil {
+				ch.Leader = info.Cluster.Leader
+			}
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
+
+func (m *Migrator) channel(channelID string) (channel, error) {
+	block, err := m.Orderer.ConfigBlock(channelID)
+	if err != nil {
+		return channel{}, errors.WithMessagef(err, "failed to pull the config block of channel %s", channelID)
+	}
+	config, err := configFromBlock(block)
+	if err != nil {
+		return channel{}, errors.WithMessagef(err, "invalid config block of channel %s", channelID)
+	}
+	consensusType, err := consensusTypeFromConfig(config)
+	if err != nil {
+		return channel{}, errors.WithMessagef(err, "invalid config of channel %s", channelID)
+	}
+	stage, err := stageOf(consensusType)
+	if err != nil {
+		return channel{}, errors.WithMessagef(err, "channel %s", channelID)
+	}
+	return channel{
+		ChannelStatus: ChannelStatus{
+			Name:           channelID,
+			ConsensusType:  consensusType.Type,
+			ConsensusState: consensusType.State.String(),
+			Stage:          stage,
+		},
+		config:        config,
+		consensusType: consensusType,
+	}, nil
+}
+
+func stageOf(consensusType *ab.ConsensusType) (Stage, error) {
+	maintenance := consensusType.State == ab.ConsensusType_STATE_MAINTENANCE
+	switch {
+	case consensusType.Type == "kafka" && !maintenance:
+		return StageKafka, nil
+	case consensusType.Type == "kafka" && maintenance:
+		return StageMaintenance, nil
+	case consensusType.Type == "etcdraft" && maintenance:
+		return StageSwitched, nil
+	case consensusType.Type == "etcdraft" && !maintenance:
+		return StageCompleted, nil
+	default:
+		return "", errors.Errorf("consensus type %s cannot be migrated to etcdraft", consensusType.Type)
+	}
+}
+
+func stageRange(channels []channel) (lowest, highest channel) {
+	lowest, highest = channels[0], channels[0]
+	for _, ch := range channels[1:] {
+		if stageOrder[ch.Stage] < stageOrder[lowest.Stage] {
+			lowest = ch
+		}
+		if stageOrder[ch.Stage] > stageOrder[highest.Stage] {
+			highest = ch
+		}
+	}
+	return lowest, highest
+}
+
+func inconsistentError(lowest, highest channel) error {
+	return errors.Errorf("channel %s is at stage %s while channel %s is at stage %s, all the channels must be migrated together",
+		lowest.Name, lowest.Stage, highest.Name, highest.Stage)
+}
+
+func report(channels []channel, updated []string) *Report {
+	r := &Report{Updated: updated}
+	for _, ch := range channels {
+		r.Channels = append(r.Channels, ch.ChannelStatus)
+	}
+
+	lowest, _ := stageRange(channels)
+	switch lowest.Stage {
+	case StageKafka:
+		r.NextStep = "run the migration to put the channels in maintenance mode"
+	case StageMaintenance:
+		r.NextStep = "stop the OSNs and then the Kafka cluster, back them up, restart them, and run the migration to switch the channels to etcdraft"
+	case StageSwitched:
+		r.NextStep = "stop the OSNs and the Kafka cluster, restart the OSNs only, and once a leader is elected on every channel run the migration to exit maintenance mode"
+		ready := true
+		for _, ch := range channels {
+			if ch.Stage == StageSwitched && ch.Leader == 0 {
+				ready = false
+			}
+		}
+		if ready {
+			r.NextStep = "run the migration to exit maintenance mode"
+		}
+	case StageCompleted:
+		r.NextStep = "none, the migration is complete"
+	}
+	return r
+}
+
+func configFromBlock(block *cb.Block) (*cb.Config, error) {
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	configEnv := &cb.ConfigEnvelope{}
+	if _, err := protoutil.UnmarshalEnvelopeOfType(env, cb.HeaderType_CONFIG, configEnv); err != nil {
+		return nil, err
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return nil, errors.New("config is empty")
+	}
+	return configEnv.Config, nil
+}
+
+func consensusTypeFromConfig(config *cb.Config) (*ab.ConsensusType, error) {
+	ordererGroup, ok := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, errors.New("config has no orderer group")
+	}
+	value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return nil, errors.New("config has no consensus type")
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	return consensusType, nil
+}
+
+func signConfigUpdate(channelID string, configUpdate *cb.ConfigUpdate, signer identity.SignerSerializer) (*cb.Envelope, error) {
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: protoutil.MarshalOrPanic(configUpdate),
+	}
+
+	sigHeader, err := protoutil.NewSignatureHeader(signer)
+	if err != nil {
+		return nil, err
+	}
+	configSig := &cb.ConfigSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(sigHeader),
+	}
+	configSig.Signature, err = signer.Sign(util.ConcatenateBytes(configSig.SignatureHeader, configUpdateEnv.ConfigUpdate))
+	if err != nil {
+		return nil, err
+	}
+	configUpdateEnv.Signatures = append(configUpdateEnv.Signatures, configSig)
+
+	return protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, signer, configUpdateEnv, 0, 0)
+}
@@ -0,0 +1,334 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/internal/osnadmin/migration"
+	"github.com/hyperledger/fabric/internal/osnadmin/migration/mocks"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+type signer struct{}
+
+func (s *signer) Sign(message []byte) ([]byte, error) { return []byte("signature"), nil }
+
+func (s *signer) Serialize() ([]byte, error) { return []byte("creator"), nil }
+
+// ordererNetwork commits the config updates it receives, unless it is told to reject or ignore them.
+type ordererNetwork struct {
+	mutex          sync.Mutex
+	consensusTypes map[string]*ab.ConsensusType
+	reject         map[string]bool
+	ignore         bool
+}
+
+func newOrdererNetwork(consensusTypes map[string]*ab.ConsensusType) *ordererNetwork {
+	return &ordererNetwork{consensusTypes: consensusTypes, reject: map[string]bool{}}
+}
+
+func (n *ordererNetwork) configBlock(channelID string) (*cb.Block, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	consensusType, ok := n.consensusTypes[channelID]
+	if !ok {
+		return nil, errors.New("channel does not exist")
+	}
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				channelconfig.OrdererGroupKey: {
+					Values: map[string]*cb.ConfigValue{
+						channelconfig.ConsensusTypeKey: {
+							Value:     protoutil.MarshalOrPanic(consensusType),
+							ModPolicy: channelconfig.AdminsPolicyKey,
+						},
+					},
+					ModPolicy: channelconfig.AdminsPolicyKey,
+				},
+			},
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		},
+	}
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_CONFIG), ChannelId: channelID}),
+			},
+			Data: protoutil.MarshalOrPanic(&cb.ConfigEnvelope{Config: config}),
+		}),
+	}
+	block := protoutil.NewBlock(0, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(env)}
+	return block, nil
+}
+
+func (n *ordererNetwork) broadcast(env *cb.Envelope) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return err
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return err
+	}
+	if n.reject[channelHeader.ChannelId] {
+		return errors.New("BAD_REQUEST")
+	}
+	if n.ignore {
+		return nil
+	}
+
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configUpdateEnv); err != nil {
+		return err
+	}
+	if len(configUpdateEnv.Signatures) != 1 {
+		return errors.New("config update is not signed")
+	}
+	configUpdate := &cb.ConfigUpdate{}
+	if err := proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate); err != nil {
+		return err
+	}
+	if configUpdate.ChannelId != channelHeader.ChannelId {
+		return errors.New("mismatched channel ID")
+	}
+	value := configUpdate.WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey]
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return err
+	}
+	n.consensusTypes[channelHeader.ChannelId] = consensusType
+	return nil
+}
+
+func kafka(state ab.ConsensusType_State) *ab.ConsensusType {
+	return &ab.ConsensusType{Type: "kafka", State: state}
+}
+
+func raft(state ab.ConsensusType_State) *ab.ConsensusType {
+	return &ab.ConsensusType{Type: "etcdraft", State: state, Metadata: []byte("metadata")}
+}
+
+func setup(consensusTypes map[string]*ab.ConsensusType) (*migration.Migrator, *ordererNetwork, *mocks.Orderer, *mocks.Admin) {
+	network := newOrdererNetwork(consensusTypes)
+	orderer := &mocks.Orderer{}
+	orderer.ConfigBlockStub = network.configBlock
+	orderer.BroadcastStub = network.broadcast
+
+	admin := &mocks.Admin{}
+	list := types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: "system-channel"}}
+	for name := range consensusTypes {
+		if name != "system-channel" {
+			list.Channels = append(list.Channels, types.ChannelInfoShort{Name: name})
+		}
+	}
+	admin.ChannelListReturns(list, nil)
+
+	m := &migration.Migrator{
+		Orderer:      orderer,
+		Admin:        admin,
+		Signer:       &signer{},
+		Timeout:      time.Second,
+		PollInterval: time.Millisecond,
+	}
+	return m, network, orderer, admin
+}
+
+func stages(r *migration.Report) map[string]migration.Stage {
+	stages := map[string]migration.Stage{}
+	for _, ch := range r.Channels {
+		stages[ch.Name] = ch.Stage
+	}
+	return stages
+}
+
+func TestMigratorStatus(t *testing.T) {
+	m, _, orderer, admin := setup(map[string]*ab.ConsensusType{
+		"system-channel": raft(ab.ConsensusType_STATE_MAINTENANCE),
+		"mychannel":      kafka(ab.ConsensusType_STATE_MAINTENANCE),
+	})
+	admin.ChannelInfoReturns(types.ChannelInfo{Cluster: &types.ClusterInfo{Leader: 2}}, nil)
+
+	report, err := m.Status()
+	require.NoError(t, err)
+	require.Equal(t, []migration.ChannelStatus{
+		{
+			Name:           "system-channel",
+			ConsensusType:  "etcdraft",
+			ConsensusState: "STATE_MAINTENANCE",
+			Stage:          migration.StageSwitched,
+			Leader:         2,
+		},
+		{
+			Name:           "mychannel",
+			ConsensusType:  "kafka",
+			ConsensusState: "STATE_MAINTENANCE",
+			Stage:          migration.StageMaintenance,
+		},
+	}, report.Channels)
+	require.Empty(t, report.Updated)
+	require.Contains(t, report.NextStep, "back them up")
+	require.Equal(t, 0, orderer.BroadcastCallCount())
+	require.Equal(t, 1, admin.ChannelInfoCallCount())
+	require.Equal(t, "system-channel", admin.ChannelInfoArgsForCall(0))
+}
+
+func TestMigratorRun(t *testing.T) {
+	m, network, orderer, admin := setup(map[string]*ab.ConsensusType{
+		"system-channel": kafka(ab.ConsensusType_STATE_NORMAL),
+		"mychannel":      kafka(ab.ConsensusType_STATE_NORMAL),
+	})
+
+	// enter maintenance mode
+	report, err := m.Run()
+	require.NoError(t, err)
+	require.Equal(t, []string{"system-channel", "mychannel"}, report.Updated)
+	require.Equal(t, map[string]migration.Stage{
+		"system-channel": migration.StageMaintenance,
+		"mychannel":      migration.StageMaintenance,
+	}, stages(report))
+	require.Contains(t, report.NextStep, "back them up")
+	require.True(t, proto.Equal(kafka(ab.ConsensusType_STATE_MAINTENANCE), network.consensusTypes["mychannel"]))
+
+	// switch to etcdraft
+	_, err = m.Run()
+	require.EqualError(t, err, "etcdraft metadata is required to switch the channels to etcdraft")
+
+	m.RaftMetadata = &etcdraft.ConfigMetadata{Consenters: []*etcdraft.Consenter{{Host: "orderer1", Port: 7050}}}
+	report, err = m.Run()
+	require.NoError(t, err)
+	require.Equal(t, []string{"system-channel", "mychannel"}, report.Updated)
+	require.Equal(t, map[string]migration.Stage{
+		"system-channel": migration.StageSwitched,
+		"mychannel":      migration.StageSwitched,
+	}, stages(report))
+	require.Contains(t, report.NextStep, "restart the OSNs only")
+	require.True(t, proto.Equal(&ab.ConsensusType{
+		Type:     "etcdraft",
+		State:    ab.ConsensusType_STATE_MAINTENANCE,
+		Metadata: protoutil.MarshalOrPanic(m.RaftMetadata),
+	}, network.consensusTypes["mychannel"]))
+
+	// exit maintenance mode once a leader is elected on every channel
+	broadcasts := orderer.BroadcastCallCount()
+	admin.ChannelInfoReturns(types.ChannelInfo{}, nil)
+	_, err = m.Run()
+	require.EqualError(t, err, "no etcdraft leader was elected on channels [system-channel mychannel], the OSNs must be restarted before exiting maintenance mode")
+	require.Equal(t, broadcasts, orderer.BroadcastCallCount())
+
+	admin.ChannelInfoReturns(types.ChannelInfo{Cluster: &types.ClusterInfo{Leader: 1}}, nil)
+	report, err = m.Run()
+	require.NoError(t, err)
+	require.Equal(t, []string{"system-channel", "mychannel"}, report.Updated)
+	require.Equal(t, map[string]migration.Stage{
+		"system-channel": migration.StageCompleted,
+		"mychannel":      migration.StageCompleted,
+	}, stages(report))
+	require.Equal(t, "none, the migration is complete", report.NextStep)
+
+	// nothing left to do
+	broadcasts = orderer.BroadcastCallCount()
+	report, err = m.Run()
+	require.NoError(t, err)
+	require.Empty(t, report.Updated)
+	require.Equal(t, broadcasts, orderer.BroadcastCallCount())
+}
+
+func TestMigratorRunResume(t *testing.T) {
+	m, network, _, _ := setup(map[string]*ab.ConsensusType{
+		"system-channel": kafka(ab.ConsensusType_STATE_NORMAL),
+		"mychannel":      kafka(ab.ConsensusType_STATE_NORMAL),
+	})
+
+	network.reject["mychannel"] = true
+	_, err := m.Run()
+	require.EqualError(t, err, "migration stopped after updating channels [system-channel], run it again to resume: failed to submit the config update of channel mychannel: BAD_REQUEST")
+
+	report, err := m.Status()
+	require.NoError(t, err)
+	require.Equal(t, map[string]migration.Stage{
+		"system-channel": migration.StageMaintenance,
+		"mychannel":      migration.StageKafka,
+	}, stages(report))
+
+	network.reject["mychannel"] = false
+	report, err = m.Run()
+	require.NoError(t, err)
+	require.Equal(t, []string{"mychannel"}, report.Updated)
+	require.Equal(t, map[string]migration.Stage{
+		"system-channel": migration.StageMaintenance,
+		"mychannel":      migration.StageMaintenance,
+	}, stages(report))
+}
+
+func TestMigratorRunErrors(t *testing.T) {
+	t.Run("update is not committed", func(t *testing.T) {
+		m, network, _, _ := setup(map[string]*ab.ConsensusType{
+			"system-channel": kafka(ab.ConsensusType_STATE_NORMAL),
+		})
+		m.Timeout = 10 * time.Millisecond
+		network.ignore = true
+		_, err := m.Run()
+		require.EqualError(t, err, "migration stopped after updating channels [], run it again to resume: timed out waiting for the config update of channel system-channel to be committed")
+	})
+
+	t.Run("channels are at inconsistent stages", func(t *testing.T) {
+		m, _, orderer, _ := setup(map[string]*ab.ConsensusType{
+			"system-channel": raft(ab.ConsensusType_STATE_MAINTENANCE),
+			"mychannel":      kafka(ab.ConsensusType_STATE_NORMAL),
+		})
+		_, err := m.Run()
+		require.EqualError(t, err, "channel mychannel is at stage kafka while channel system-channel is at stage switched, all the channels must be migrated together")
+		require.Equal(t, 0, orderer.BroadcastCallCount())
+	})
+
+	t.Run("consensus type cannot be migrated", func(t *testing.T) {
+		m, _, _, _ := setup(map[string]*ab.ConsensusType{
+			"system-channel": {Type: "solo"},
+		})
+		_, err := m.Run()
+		require.EqualError(t, err, "channel system-channel: consensus type solo cannot be migrated to etcdraft")
+	})
+
+	t.Run("channels cannot be listed", func(t *testing.T) {
+		m, _, _, admin := setup(nil)
+		admin.ChannelListReturns(types.ChannelList{}, errors.New("connection refused"))
+		_, err := m.Run()
+		require.EqualError(t, err, "failed to list the channels: connection refused")
+	})
+
+	t.Run("no channels", func(t *testing.T) {
+		m, _, _, admin := setup(nil)
+		admin.ChannelListReturns(types.ChannelList{}, nil)
+		_, err := m.Status()
+		require.EqualError(t, err, "the OSN is not a member of any channel")
+	})
+
+	t.Run("config block cannot be pulled", func(t *testing.T) {
+		m, _, orderer, _ := setup(map[string]*ab.ConsensusType{
+			"system-channel": kafka(ab.ConsensusType_STATE_NORMAL),
+		})
+		orderer.ConfigBlockStub = nil
+		orderer.ConfigBlockReturns(nil, errors.New("SERVICE_UNAVAILABLE"))
+		_, err := m.Status()
+		require.EqualError(t, err, "failed to pull the config block of channel system-channel: SERVICE_UNAVAILABLE")
+	})
+}
@@ -0,0 +1,185 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/internal/osnadmin/migration"
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+type Admin struct {
+	ChannelInfoStub        func(string) (types.ChannelInfo, error)
+	channelInfoMutex       sync.RWMutex
+	channelInfoArgsForCall []struct {
+		arg1 string
+	}
+	channelInfoReturns struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
+	channelInfoReturnsOnCall map[int]struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
+	ChannelListStub        func() (types.ChannelList, error)
+	channelListMutex       sync.RWMutex
+	channelListArgsForCall []struct {
+	}
+	channelListReturns struct {
+		result1 types.ChannelList
+		result2 error
+	}
+	channelListReturnsOnCall map[int]struct {
+		result1 types.ChannelList
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Admin) ChannelInfo(arg1 string) (types.ChannelInfo, error) {
+	fake.channelInfoMutex.Lock()
+	ret, specificReturn := fake.channelInfoReturnsOnCall[len(fake.channelInfoArgsForCall)]
+	fake.channelInfoArgsForCall = append(fake.channelInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ChannelInfo", []interface{}{arg1})
+	fake.channelInfoMutex.Unlock()
+	if fake.ChannelInfoStub != nil {
+		return fake.ChannelInfoStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.channelInfoReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Admin) ChannelInfoCallCount() int {
+	fake.channelInfoMutex.RLock()
+	defer fake.channelInfoMutex.RUnlock()
+	return len(fake.channelInfoArgsForCall)
+}
+
+func (fake *Admin) ChannelInfoCalls(stub func(string) (types.ChannelInfo, error)) {
+	fake.channelInfoMutex.Lock()
+	defer fake.channelInfoMutex.Unlock()
+	fake.ChannelInfoStub = stub
+}
+
+func (fake *Admin) ChannelInfoArgsForCall(i int) string {
+	fake.channelInfoMutex.RLock()
+	defer fake.channelInfoMutex.RUnlock()
+	argsForCall := fake.channelInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Admin) ChannelInfoReturns(result1 types.ChannelInfo, result2 error) {
+	fake.channelInfoMutex.Lock()
+	defer fake.channelInfoMutex.Unlock()
+	fake.ChannelInfoStub = nil
+	fake.channelInfoReturns = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Admin) ChannelInfoReturnsOnCall(i int, result1 types.ChannelInfo, result2 error) {
+	fake.channelInfoMutex.Lock()
+	defer fake.channelInfoMutex.Unlock()
+	fake.ChannelInfoStub = nil
+	if fake.channelInfoReturnsOnCall == nil {
+		fake.channelInfoReturnsOnCall = make(map[int]struct {
+			result1 types.ChannelInfo
+			result2 error
+		})
+	}
+	fake.channelInfoReturnsOnCall[i] = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Admin) ChannelList() (types.ChannelList, error) {
+	fake.channelListMutex.Lock()
+	ret, specificReturn := fake.channelListReturnsOnCall[len(fake.channelListArgsForCall)]
+	fake.channelListArgsForCall = append(fake.channelListArgsForCall, struct {
+	}{})
+	fake.recordInvocation("ChannelList", []interface{}{})
+	fake.channelListMutex.Unlock()
+	if fake.ChannelListStub != nil {
+		return fake.ChannelListStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.channelListReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Admin) ChannelListCallCount() int {
+	fake.channelListMutex.RLock()
+	defer fake.channelListMutex.RUnlock()
+	return len(fake.channelListArgsForCall)
+}
+
+func (fake *Admin) ChannelListCalls(stub func() (types.ChannelList, error)) {
+	fake.channelListMutex.Lock()
+	defer fake.channelListMutex.Unlock()
+	fake.ChannelListStub = stub
+}
+
+func (fake *Admin) ChannelListReturns(result1 types.ChannelList, result2 error) {
+	fake.channelListMutex.Lock()
+	defer fake.channelListMutex.Unlock()
+	fake.ChannelListStub = nil
+	fake.channelListReturns = struct {
+		result1 types.ChannelList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Admin) ChannelListReturnsOnCall(i int, result1 types.ChannelList, result2 error) {
+	fake.channelListMutex.Lock()
+	defer fake.channelListMutex.Unlock()
+	fake.ChannelListStub = nil
+	if fake.channelListReturnsOnCall == nil {
+		fake.channelListReturnsOnCall = make(map[int]struct {
+			result1 types.ChannelList
+			result2 error
+		})
+	}
+	fake.channelListReturnsOnCall[i] = struct {
+		result1 types.ChannelList
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Admin) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.channelInfoMutex.RLock()
+	defer fake.channelInfoMutex.RUnlock()
+	fake.channelListMutex.RLock()
+	defer fake.channelListMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Admin) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ migration.Admin = new(Admin)
@@ -0,0 +1,189 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mocks
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/internal/osnadmin/migration"
+)
+
+type Orderer struct {
+	BroadcastStub        func(*common.Envelope) error
+	broadcastMutex       sync.RWMutex
+	broadcastArgsForCall []struct {
+		arg1 *common.Envelope
+	}
+	broadcastReturns struct {
+		result1 error
+	}
+	broadcastReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ConfigBlockStub        func(string) (*common.Block, error)
+	configBlockMutex       sync.RWMutex
+	configBlockArgsForCall []struct {
+		arg1 string
+	}
+	configBlockReturns struct {
+		result1 *common.Block
+		result2 error
+	}
+	configBlockReturnsOnCall map[int]struct {
+		result1 *common.Block
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Orderer) Broadcast(arg1 *common.Envelope) error {
+	fake.broadcastMutex.Lock()
+	ret, specificReturn := fake.broadcastReturnsOnCall[len(fake.broadcastArgsForCall)]
+	fake.broadcastArgsForCall = append(fake.broadcastArgsForCall, struct {
+		arg1 *common.Envelope
+	}{arg1})
+	fake.recordInvocation("Broadcast", []interface{}{arg1})
+	fake.broadcastMutex.Unlock()
+	if fake.BroadcastStub != nil {
+		return fake.BroadcastStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.broadcastReturns
+	return fakeReturns.result1
+}
+
+func (fake *Orderer) BroadcastCallCount() int {
+	fake.broadcastMutex.RLock()
+	defer fake.broadcastMutex.RUnlock()
+	return len(fake.broadcastArgsForCall)
+}
+
+func (fake *Orderer) BroadcastCalls(stub func(*common.Envelope) error) {
+	fake.broadcastMutex.Lock()
+	defer fake.broadcastMutex.Unlock()
+	fake.BroadcastStub = stub
+}
+
+func (fake *Orderer) BroadcastArgsForCall(i int) *common.Envelope {
+	fake.broadcastMutex.RLock()
+	defer fake.broadcastMutex.RUnlock()
+	argsForCall := fake.broadcastArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Orderer) BroadcastReturns(result1 error) {
+	fake.broadcastMutex.Lock()
+	defer fake.broadcastMutex.Unlock()
+	fake.BroadcastStub = nil
+	fake.broadcastReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Orderer) BroadcastReturnsOnCall(i int, result1 error) {
+	fake.broadcastMutex.Lock()
+	defer fake.broadcastMutex.Unlock()
+	fake.BroadcastStub = nil
+	if fake.broadcastReturnsOnCall == nil {
+		fake.broadcastReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.broadcastReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Orderer) ConfigBlock(arg1 string) (*common.Block, error) {
+	fake.configBlockMutex.Lock()
+	ret, specificReturn := fake.configBlockReturnsOnCall[len(fake.configBlockArgsForCall)]
+	fake.configBlockArgsForCall = append(fake.configBlockArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	fake.recordInvocation("ConfigBlock", []interface{}{arg1})
+	fake.configBlockMutex.Unlock()
+	if fake.ConfigBlockStub != nil {
+		return fake.ConfigBlockStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.configBlockReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Orderer) ConfigBlockCallCount() int {
+	fake.configBlockMutex.RLock()
+	defer fake.configBlockMutex.RUnlock()
+	return len(fake.configBlockArgsForCall)
+}
+
+func (fake *Orderer) ConfigBlockCalls(stub func(string) (*common.Block, error)) {
+	fake.configBlockMutex.Lock()
+	defer fake.configBlockMutex.Unlock()
+	fake.ConfigBlockStub = stub
+}
+
+func (fake *Orderer) ConfigBlockArgsForCall(i int) string {
+	fake.configBlockMutex.RLock()
+	defer fake.configBlockMutex.RUnlock()
+	argsForCall := fake.configBlockArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Orderer) ConfigBlockReturns(result1 *common.Block, result2 error) {
+	fake.configBlockMutex.Lock()
+	defer fake.configBlockMutex.Unlock()
+	fake.ConfigBlockStub = nil
+	fake.configBlockReturns = struct {
+		result1 *common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Orderer) ConfigBlockReturnsOnCall(i int, result1 *common.Block, result2 error) {
+	fake.configBlockMutex.Lock()
+	defer fake.configBlockMutex.Unlock()
+	fake.ConfigBlockStub = nil
+	if fake.configBlockReturnsOnCall == nil {
+		fake.configBlockReturnsOnCall = make(map[int]struct {
+			result1 *common.Block
+			result2 error
+		})
+	}
+	fake.configBlockReturnsOnCall[i] = struct {
+		result1 *common.Block
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Orderer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.broadcastMutex.RLock()
+	defer fake.broadcastMutex.RUnlock()
+	fake.configBlockMutex.RLock()
+	defer fake.configBlockMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Orderer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ migration.Orderer = new(Orderer)
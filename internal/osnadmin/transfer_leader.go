@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// Transfers the leadership of a channel to the consenter with the given ID. The request is sent to the OSN at
+// osnURL, unless that OSN neither leads the channel nor is the transferee: it is then sent again to the leader, if
+// the admin endpoint of the leader is found among leaderURLs, keyed by consenter ID.
+func TransferLeader(osnURL, channelID string, transferee uint64, leaderURLs map[uint64]string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	resp, err := transferLeader(osnURL, channelID, transferee, caCertPool, tlsClientCert)
+	if err != nil || resp.StatusCode != http.StatusMisdirectedRequest {
+		return resp, err
+	}
+
+	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(bodyBytes))
+
+	notLeader := &types.NotLeaderResponse{}
+	if err := json.Unmarshal(bodyBytes, notLeader); err != nil {
+		return resp, nil
+	}
+	leaderURL, ok := leaderURLs[notLeader.Leader]
+	if !ok {
+		return resp, nil
+	}
+	return transferLeader(leaderURL, channelID, transferee, caCertPool, tlsClientCert)
+}
+
+func transferLeader(osnURL, channelID string, transferee uint64, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/channels/%s/leader", osnURL, channelID)
+
+	body, err := json.Marshal(&types.LeadershipTransferRequest{Transferee: transferee})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return httpDo(req, caCertPool, tlsClientCert)
+}
@@ -14,6 +14,7 @@ import (
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -217,3 +218,30 @@ func GetSnapshotClient(address, tlsRootCertFile string) (pb.SnapshotClient, erro
 	}
 	return peerClient.SnapshotClient()
 }
+
+// SnapshotStatusClient returns a client for the snapshot status service
+func (pc *PeerClient) SnapshotStatusClient() (snapshotgrpc.SnapshotStatusClient, error) {
+	conn, err := pc.CommonClient.NewConnection(pc.Address, comm.ServerNameOverride(pc.sn))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "snapshot status client failed to connect to %s", pc.Address)
+	}
+	return snapshotgrpc.NewSnapshotStatusClient(conn), nil
+}
+
+// GetSnapshotStatusClient returns a new snapshot status client. If both the address and
+// tlsRootCertFile are not provided, the target values for the client are taken
+// from the configuration settings for "peer.address" and
+// "peer.tls.rootcert.file"
+func GetSnapshotStatusClient(address, tlsRootCertFile string) (snapshotgrpc.SnapshotStatusClient, error) {
+	var peerClient *PeerClient
+	var err error
+	if address != "" {
+		peerClient, err = NewPeerClientForAddress(address, tlsRootCertFile)
+	} else {
+		peerClient, err = NewPeerClientFromEnv()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return peerClient.SnapshotStatusClient()
+}
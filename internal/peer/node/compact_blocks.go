@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+var compression string
+
+func compactBlocksCmd() *cobra.Command {
+	nodeCompactBlocksCmd.ResetFlags()
+	flags := nodeCompactBlocksCmd.Flags()
+	flags.StringVarP(&compression, "compression", "", "",
+		"Compression to apply to the stored blocks, \"none\" or \"snappy\". Defaults to the value of ledger.blockchain.compression.")
+
+	return nodeCompactBlocksCmd
+}
+
+var nodeCompactBlocksCmd = &cobra.Command{
+	Use:   "compact-blocks",
+	Short: "Rewrites the block files with the configured compression.",
+	Long: "Rewrites the existing block files of all the channels such that each block is stored with the specified compression." +
+		" When the command is executed, the peer must be offline. If the command is interrupted, it must be executed again" +
+		" before the peer is started.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config := ledgerConfig()
+		if compression == "" {
+			compression = config.BlockStoreConfig.Compression
+		}
+		return kvledger.CompactBlockStore(config.RootFSPath, compression)
+	},
+}
@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompactBlocksCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "compact-blocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	t.Run("when the compression is not supported", func(t *testing.T) {
+		cmd := compactBlocksCmd()
+		cmd.SetArgs([]string{"--compression", "zstd"})
+		err := cmd.Execute()
+		require.EqualError(t, err, "unsupported block compression [zstd], supported values are [none, snappy]")
+	})
+
+	t.Run("when the compression is taken from the config", func(t *testing.T) {
+		viper.Set("ledger.blockchain.compression", "lz4")
+		defer viper.Set("ledger.blockchain.compression", "")
+		cmd := compactBlocksCmd()
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+		require.EqualError(t, err, "unsupported block compression [lz4], supported values are [none, snappy]")
+	})
+
+	t.Run("when no ledger has been set up", func(t *testing.T) {
+		cmd := compactBlocksCmd()
+		cmd.SetArgs([]string{"--compression", "snappy"})
+		require.NoError(t, cmd.Execute())
+	})
+}
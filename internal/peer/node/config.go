@@ -70,6 +70,9 @@ func ledgerConfig() *ledger.Config {
 		SnapshotsConfig: &ledger.SnapshotsConfig{
 			RootDir: snapshotsRootDir,
 		},
+		BlockStoreConfig: &ledger.BlockStoreConfig{
+			Compression: viper.GetString("ledger.blockchain.compression"),
+		},
 	}
 
 	if conf.StateDBConfig.StateDatabase == ledger.CouchDB {
@@ -88,5 +91,37 @@ func ledgerConfig() *ledger.Config {
 			UserCacheSizeMBs:        viper.GetInt("ledger.state.couchDBConfig.cacheSize"),
 		}
 	}
+
+	snapshotPolicy := &ledger.SnapshotPolicyConfig{
+		TimeInterval: viper.GetDuration("ledger.snapshots.policy.timeInterval"),
+		Retain:       viper.GetInt("ledger.snapshots.policy.retain"),
+	}
+	if blockInterval := viper.GetInt("ledger.snapshots.policy.blockInterval"); blockInterval > 0 {
+		snapshotPolicy.BlockInterval = uint64(blockInterval)
+	}
+	if *snapshotPolicy != (ledger.SnapshotPolicyConfig{}) {
+		conf.SnapshotsConfig.Policy = snapshotPolicy
+	}
+
+	if archiveType := viper.GetString("ledger.blockchain.archive.type"); archiveType != "" {
+		conf.BlockStoreConfig.Archive = &ledger.BlockArchiveConfig{
+			Type:       archiveType,
+			LocalFiles: viper.GetInt("ledger.blockchain.archive.localFiles"),
+			CacheDir:   coreconfig.GetPath("ledger.blockchain.archive.cacheDir"),
+			CacheSize:  viper.GetInt("ledger.blockchain.archive.cacheSize"),
+			LocalDir:   coreconfig.GetPath("ledger.blockchain.archive.localDir"),
+		}
+		if archiveType == "s3" {
+			conf.BlockStoreConfig.Archive.S3 = &ledger.BlockArchiveS3Config{
+				Endpoint:        viper.GetString("ledger.blockchain.archive.s3.endpoint"),
+				Region:          viper.GetString("ledger.blockchain.archive.s3.region"),
+				Bucket:          viper.GetString("ledger.blockchain.archive.s3.bucket"),
+				Prefix:          viper.GetString("ledger.blockchain.archive.s3.prefix"),
+				AccessKeyID:     viper.GetString("ledger.blockchain.archive.s3.accessKeyID"),
+				SecretAccessKey: viper.GetString("ledger.blockchain.archive.s3.secretAccessKey"),
+				Timeout:         viper.GetDuration("ledger.blockchain.archive.s3.timeout"),
+			}
+		}
+	}
 	return conf
 }
@@ -46,6 +46,7 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
+				BlockStoreConfig: &ledger.BlockStoreConfig{},
 			},
 		},
 		{
@@ -93,6 +94,7 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
+				BlockStoreConfig: &ledger.BlockStoreConfig{},
 			},
 		},
 		{
@@ -117,6 +119,7 @@ func TestLedgerConfig(t *testing.T) {
 				"ledger.pvtdataStore.deprioritizedDataReconcilerInterval": "180m",
 				"ledger.history.enableHistoryDatabase":                    true,
 				"ledger.snapshots.rootDir":                                "/peerfs/customLocationForsnapshots",
+				"ledger.blockchain.compression":                           "snappy",
 			},
 			expected: &ledger.Config{
 				RootFSPath: "/peerfs/ledgersData",
@@ -149,6 +152,96 @@ func TestLedgerConfig(t *testing.T) {
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/customLocationForsnapshots",
 				},
+				BlockStoreConfig: &ledger.BlockStoreConfig{
+					Compression: "snappy",
+				},
+			},
+		},
+		{
+			name: "Block Archive",
+			config: map[string]interface{}{
+				"peer.fileSystemPath":                          "/peerfs",
+				"ledger.blockchain.archive.type":               "s3",
+				"ledger.blockchain.archive.localFiles":         2,
+				"ledger.blockchain.archive.cacheDir":           "/peerfs/archivecache",
+				"ledger.blockchain.archive.cacheSize":          8,
+				"ledger.blockchain.archive.s3.endpoint":        "http://minio:9000",
+				"ledger.blockchain.archive.s3.region":          "eu-west-1",
+				"ledger.blockchain.archive.s3.bucket":          "blocks",
+				"ledger.blockchain.archive.s3.prefix":          "peer0/",
+				"ledger.blockchain.archive.s3.accessKeyID":     "access-key",
+				"ledger.blockchain.archive.s3.secretAccessKey": "secret-key",
+				"ledger.blockchain.archive.s3.timeout":         "10m",
+			},
+			expected: &ledger.Config{
+				RootFSPath: "/peerfs/ledgersData",
+				StateDBConfig: &ledger.StateDBConfig{
+					StateDatabase: "",
+					CouchDB:       &ledger.CouchDBConfig{},
+				},
+				PrivateDataConfig: &ledger.PrivateDataConfig{
+					MaxBatchSize:                        5000,
+					BatchesInterval:                     1000,
+					PurgeInterval:                       100,
+					DeprioritizedDataReconcilerInterval: 60 * time.Minute,
+				},
+				HistoryDBConfig: &ledger.HistoryDBConfig{
+					Enabled: false,
+				},
+				SnapshotsConfig: &ledger.SnapshotsConfig{
+					RootDir: "/peerfs/snapshots",
+				},
+				BlockStoreConfig: &ledger.BlockStoreConfig{
+					Archive: &ledger.BlockArchiveConfig{
+						Type:       "s3",
+						LocalFiles: 2,
+						CacheDir:   "/peerfs/archivecache",
+						CacheSize:  8,
+						S3: &ledger.BlockArchiveS3Config{
+							Endpoint:        "http://minio:9000",
+							Region:          "eu-west-1",
+							Bucket:          "blocks",
+							Prefix:          "peer0/",
+							AccessKeyID:     "access-key",
+							SecretAccessKey: "secret-key",
+							Timeout:         10 * time.Minute,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Snapshot Policy",
+			config: map[string]interface{}{
+				"peer.fileSystemPath":                   "/peerfs",
+				"ledger.snapshots.policy.blockInterval": 1000,
+				"ledger.snapshots.policy.timeInterval":  "24h",
+				"ledger.snapshots.policy.retain":        3,
+			},
+			expected: &ledger.Config{
+				RootFSPath: "/peerfs/ledgersData",
+				StateDBConfig: &ledger.StateDBConfig{
+					StateDatabase: "",
+					CouchDB:       &ledger.CouchDBConfig{},
+				},
+				PrivateDataConfig: &ledger.PrivateDataConfig{
+					MaxBatchSize:                        5000,
+					BatchesInterval:                     1000,
+					PurgeInterval:                       100,
+					DeprioritizedDataReconcilerInterval: 60 * time.Minute,
+				},
+				HistoryDBConfig: &ledger.HistoryDBConfig{
+					Enabled: false,
+				},
+				SnapshotsConfig: &ledger.SnapshotsConfig{
+					RootDir: "/peerfs/snapshots",
+					Policy: &ledger.SnapshotPolicyConfig{
+						BlockInterval: 1000,
+						TimeInterval:  24 * time.Hour,
+						Retain:        3,
+					},
+				},
+				BlockStoreConfig: &ledger.BlockStoreConfig{},
 			},
 		},
 	}
@@ -156,6 +249,7 @@ func TestLedgerConfig(t *testing.T) {
 	for _, test := range tests {
 		_test := test
 		t.Run(_test.name, func(t *testing.T) {
+			defer viper.Reset()
 			for k, v := range _test.config {
 				viper.Set(k, v)
 			}
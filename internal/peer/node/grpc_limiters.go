@@ -36,6 +36,37 @@ func initGrpcSemaphores(config *peer.Config) map[string]semaphore.Semaphore {
 	return semaphores
 }
 
+// checkEndorserLimits checks that the proposals of a single channel or chaincode,
+// which hold a slot of the endorser service while they are queued by the
+// endorser, cannot take all the slots of the endorser service.
+func checkEndorserLimits(config *peer.Config) error {
+	serviceConcurrency := config.LimitsConcurrencyEndorserService
+	if serviceConcurrency <= 0 {
+		return nil
+	}
+
+	limits := []struct {
+		name        string
+		concurrency int
+		queueDepth  int
+	}{
+		{"channel", config.LimitsEndorserChannelConcurrency, config.LimitsEndorserChannelQueueDepth},
+		{"chaincode", config.LimitsEndorserChaincodeConcurrency, config.LimitsEndorserChaincodeQueueDepth},
+	}
+	for _, limit := range limits {
+		if limit.concurrency <= 0 {
+			continue
+		}
+		if limit.concurrency+limit.queueDepth >= serviceConcurrency {
+			return errors.Errorf(
+				"peer.limits.endorser.%s.concurrency plus queueDepth (%d) must be lower than peer.limits.concurrency.endorserService (%d), so that a busy %s does not take all the endorser service slots",
+				limit.name, limit.concurrency+limit.queueDepth, serviceConcurrency, limit.name,
+			)
+		}
+	}
+	return nil
+}
+
 func unaryGrpcLimiter(semaphores map[string]semaphore.Semaphore) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		serviceName := getServiceName(info.FullMethod)
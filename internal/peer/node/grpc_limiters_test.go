@@ -35,6 +35,62 @@ func TestInitGrpcNoSemaphores(t *testing.T) {
 	require.Equal(t, 0, len(semaphores))
 }
 
+func TestCheckEndorserLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      peer.Config
+		expectedErr string
+	}{
+		{
+			name:   "no endorser service limit",
+			config: peer.Config{LimitsEndorserChaincodeConcurrency: 100, LimitsEndorserChaincodeQueueDepth: 100},
+		},
+		{
+			name: "no endorser limits",
+			config: peer.Config{
+				LimitsConcurrencyEndorserService: 10,
+			},
+		},
+		{
+			name: "below the endorser service limit",
+			config: peer.Config{
+				LimitsConcurrencyEndorserService:   10,
+				LimitsEndorserChannelConcurrency:   5,
+				LimitsEndorserChannelQueueDepth:    4,
+				LimitsEndorserChaincodeConcurrency: 2,
+				LimitsEndorserChaincodeQueueDepth:  2,
+			},
+		},
+		{
+			name: "channel reaches the endorser service limit",
+			config: peer.Config{
+				LimitsConcurrencyEndorserService: 10,
+				LimitsEndorserChannelConcurrency: 5,
+				LimitsEndorserChannelQueueDepth:  5,
+			},
+			expectedErr: "peer.limits.endorser.channel.concurrency plus queueDepth (10) must be lower than peer.limits.concurrency.endorserService (10), so that a busy channel does not take all the endorser service slots",
+		},
+		{
+			name: "chaincode exceeds the endorser service limit",
+			config: peer.Config{
+				LimitsConcurrencyEndorserService:   10,
+				LimitsEndorserChaincodeConcurrency: 20,
+			},
+			expectedErr: "peer.limits.endorser.chaincode.concurrency plus queueDepth (20) must be lower than peer.limits.concurrency.endorserService (10), so that a busy chaincode does not take all the endorser service slots",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkEndorserLimits(&test.config)
+			if test.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expectedErr)
+		})
+	}
+}
+
 func TestInitGrpcSemaphoresPanic(t *testing.T) {
 	config := peer.Config{
 		LimitsConcurrencyEndorserService: -1,
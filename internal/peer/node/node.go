@@ -29,6 +29,8 @@ func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(resumeCmd())
 	nodeCmd.AddCommand(rebuildDBsCmd())
 	nodeCmd.AddCommand(upgradeDBsCmd())
+	nodeCmd.AddCommand(compactBlocksCmd())
+	nodeCmd.AddCommand(pruneBlocksCmd())
 	return nodeCmd
 }
 
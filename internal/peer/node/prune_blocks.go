@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func pruneBlocksCmd() *cobra.Command {
+	nodePruneBlocksCmd.ResetFlags()
+	flags := nodePruneBlocksCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel whose blocks are to be pruned.")
+
+	return nodePruneBlocksCmd
+}
+
+var nodePruneBlocksCmd = &cobra.Command{
+	Use:   "prune-blocks",
+	Short: "Prunes the blocks of a channel below the last snapshot.",
+	Long: "Deletes the block files of a channel that contain only the blocks below the height of the last snapshot" +
+		" generated for the channel, along with their entries in the block index. When the command is executed, the peer must be offline." +
+		" The pruned blocks can no longer be served by the peer, the queries and the deliver requests for these blocks return an error." +
+		" After the blocks are pruned, the peer does not support rollback, reset and rebuild-dbs commands.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+
+		config := ledgerConfig()
+		return kvledger.PruneBlockStore(config, channelID)
+	},
+}
@@ -49,6 +49,8 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/committer/txstatus"
 	"github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
@@ -71,6 +73,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/peer/ccevents"
 	"github.com/hyperledger/fabric/core/policy"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/scc/cscc"
@@ -93,6 +96,8 @@ import (
 	peergossip "github.com/hyperledger/fabric/internal/peer/gossip"
 	"github.com/hyperledger/fabric/internal/peer/version"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/gateway"
+	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
@@ -173,6 +178,73 @@ func (e endorserChannelAdapter) Channel(channelID string) *endorser.Channel {
 	return nil
 }
 
+type gatewayChannelAdapter struct {
+	peer *peer.Peer
+}
+
+func (g gatewayChannelAdapter) channel(channelID string) (*peer.Channel, error) {
+	channel := g.peer.Channel(channelID)
+	if channel == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return channel, nil
+}
+
+func (g gatewayChannelAdapter) Ledger(channelID string) (gateway.Ledger, error) {
+	channel, err := g.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return channel.Ledger(), nil
+}
+
+func (g gatewayChannelAdapter) CommitNotifier(channelID string) (*committer.CommitNotifier, error) {
+	channel, err := g.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return channel.CommitNotifier(), nil
+}
+
+func (g gatewayChannelAdapter) OrdererEndpoint(channelID string) (*orderers.Endpoint, error) {
+	channel, err := g.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	return channel.OrdererSource().RandomEndpoint()
+}
+
+func (g gatewayChannelAdapter) TLSRootCerts(channelID, mspID string) ([][]byte, error) {
+	channel, err := g.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+	msps, err := channel.MSPManager().GetMSPs()
+	if err != nil {
+		return nil, err
+	}
+	orgMSP, ok := msps[mspID]
+	if !ok {
+		return nil, errors.Errorf("organization %s not found in channel %s", mspID, channelID)
+	}
+	var certs [][]byte
+	certs = append(certs, orgMSP.GetTLSRootCerts()...)
+	certs = append(certs, orgMSP.GetTLSIntermediateCerts()...)
+	return certs, nil
+}
+
+type txStatusChannelAdapter struct {
+	peer *peer.Peer
+}
+
+func (t txStatusChannelAdapter) Channel(channelID string) (txstatus.Ledger, *committer.CommitNotifier, error) {
+	channel := t.peer.Channel(channelID)
+	if channel == nil {
+		return nil, nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return channel.Ledger(), channel.CommitNotifier(), nil
+}
+
 type custodianLauncherAdapter struct {
 	launcher      chaincode.Launcher
 	streamHandler extcc.StreamHandler
@@ -262,6 +334,9 @@ func serve(args []string) error {
 		grpclogging.StreamServerInterceptor(flogging.MustGetLogger("comm.grpc.server").Zap()),
 	)
 
+	if err := checkEndorserLimits(coreConfig); err != nil {
+		return err
+	}
 	semaphores := initGrpcSemaphores(coreConfig)
 	if len(semaphores) != 0 {
 		serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, unaryGrpcLimiter(semaphores))
@@ -430,6 +505,14 @@ func serve(args []string) error {
 		common.HeaderType_CONFIG: &peer.ConfigTxProcessor{},
 	}
 
+	stateListeners := []ledger.StateListener{lifecycleCache}
+	var queryCache *endorser.QueryCache
+	if len(coreConfig.QueryCacheChaincodes) > 0 {
+		logger.Infof("Caching the queries of chaincodes %v", coreConfig.QueryCacheChaincodes)
+		queryCache = endorser.NewQueryCache(coreConfig.QueryCacheChaincodes, coreConfig.QueryCacheSize)
+		stateListeners = append(stateListeners, queryCache)
+	}
+
 	peerInstance.LedgerMgr = ledgermgmt.NewLedgerMgr(
 		&ledgermgmt.Initializer{
 			CustomTxProcessors:              txProcessors,
@@ -438,7 +521,7 @@ func serve(args []string) error {
 			ChaincodeLifecycleEventProvider: lifecycleCache,
 			MetricsProvider:                 metricsProvider,
 			HealthCheckRegistry:             opsSystem,
-			StateListeners:                  []ledger.StateListener{lifecycleCache},
+			StateListeners:                  stateListeners,
 			Config:                          ledgerConfig(),
 			HashProvider:                    factory.GetDefault(),
 			EbMetadataProvider:              ebMetadataProvider,
@@ -502,6 +585,7 @@ func serve(args []string) error {
 		PolicyCheckerProvider: policyCheckerProvider,
 	}
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
+	ccevents.RegisterChaincodeEventsServer(peerServer.Server(), abServer)
 
 	// Create a self-signed CA for chaincode service
 	ca, err := tlsgen.NewCA()
@@ -745,6 +829,18 @@ func serve(args []string) error {
 		LocalMSP:               localMSP,
 		Support:                endorserSupport,
 		Metrics:                endorser.NewMetrics(metricsProvider),
+		QueryCache:             queryCache,
+	}
+	if coreConfig.LimitsEndorserChannelConcurrency > 0 || coreConfig.LimitsEndorserChaincodeConcurrency > 0 {
+		serverEndorser.AdmissionController = endorser.NewAdmissionController(
+			endorser.AdmissionLimits{
+				ChannelConcurrency:   coreConfig.LimitsEndorserChannelConcurrency,
+				ChannelQueueDepth:    coreConfig.LimitsEndorserChannelQueueDepth,
+				ChaincodeConcurrency: coreConfig.LimitsEndorserChaincodeConcurrency,
+				ChaincodeQueueDepth:  coreConfig.LimitsEndorserChaincodeQueueDepth,
+			},
+			serverEndorser.Metrics,
+		)
 	}
 
 	// deploy system chaincodes
@@ -802,17 +898,19 @@ func serve(args []string) error {
 		coreConfig.ValidatorPoolSize,
 	)
 
+	metadataProvider := lifecycle.NewMetadataProvider(
+		lifecycleCache,
+		legacyMetadataManager,
+		peerInstance,
+	)
+
 	if coreConfig.DiscoveryEnabled {
 		registerDiscoveryService(
 			coreConfig,
 			peerInstance,
 			peerServer,
 			policyMgr,
-			lifecycle.NewMetadataProvider(
-				lifecycleCache,
-				legacyMetadataManager,
-				peerInstance,
-			),
+			metadataProvider,
 			gossipService,
 		)
 	}
@@ -879,6 +977,29 @@ func serve(args []string) error {
 	// register the snapshot server
 	snapshotSvc := &snapshotgrpc.SnapshotService{LedgerGetter: peerInstance, ACLProvider: aclProvider}
 	pb.RegisterSnapshotServer(peerServer.Server(), snapshotSvc)
+	snapshotgrpc.RegisterSnapshotStatusServer(peerServer.Server(), snapshotSvc)
+
+	// register the transaction status server
+	txStatusSvc := &txstatus.Service{
+		ChannelProvider: txStatusChannelAdapter{peer: peerInstance},
+		ACLProvider:     aclProvider,
+		MaxTimeout:      coreConfig.TxStatusMaxTimeout,
+	}
+	txstatus.RegisterTxStatusServer(peerServer.Server(), txStatusSvc)
+
+	if coreConfig.GatewayEnabled {
+		registerGatewayService(
+			coreConfig,
+			peerInstance,
+			peerServer,
+			auth,
+			signingIdentityBytes,
+			aclProvider,
+			metadataProvider,
+			gossipService,
+			deliverGRPCClient,
+		)
+	}
 
 	go func() {
 		var grpcErr error
@@ -978,6 +1099,39 @@ func registerDiscoveryService(
 	discprotos.RegisterDiscoveryServer(peerServer.Server(), svc)
 }
 
+func registerGatewayService(
+	coreConfig *peer.Config,
+	peerInstance *peer.Peer,
+	peerServer *comm.GRPCServer,
+	localEndorser pb.EndorserServer,
+	localIdentity []byte,
+	aclProvider aclmgmt.ACLProvider,
+	metadataProvider *lifecycle.MetadataProvider,
+	gossipService *gossipservice.GossipService,
+	client *comm.GRPCClient,
+) {
+	// The endorsement analyzer only evaluates the principals of the endorsement
+	// policies, the access of the clients is checked by the endorsers.
+	principalEvaluator := discacl.NewDiscoverySupport(nil, nil, discacl.ChannelConfigGetterFunc(peerInstance.GetStableChannelConfig))
+	ea := endorsement.NewEndorsementAnalyzer(
+		gossip.NewDiscoverySupport(gossipService),
+		ccsupport.NewDiscoverySupport(metadataProvider),
+		principalEvaluator,
+		metadataProvider,
+	)
+	svc := gateway.NewServer(
+		localEndorser,
+		localIdentity,
+		ea,
+		gatewayChannelAdapter{peer: peerInstance},
+		aclProvider,
+		client,
+		gateway.Options{EndorsementTimeout: coreConfig.GatewayEndorsementTimeout},
+	)
+	logger.Info("Gateway service activated")
+	gateway.RegisterGatewayServer(peerServer.Server(), svc)
+}
+
 // create a CC listener using peer.chaincodeListenAddress (and if that's not set use peer.peerAddress)
 func createChaincodeServer(coreConfig *peer.Config, ca tlsgen.CA, peerHostname string) (srv *comm.GRPCServer, ccEndpoint string, err error) {
 	// before potentially setting chaincodeListenAddress, compute chaincode endpoint at first
@@ -12,6 +12,7 @@ import (
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/internal/peer/common"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -20,6 +21,7 @@ import (
 // client holds client side dependency for the snapshot commands
 type client struct {
 	snapshotClient pb.SnapshotClient
+	statusClient   snapshotgrpc.SnapshotStatusClient
 	signer         common.Signer
 	writer         io.Writer
 }
@@ -35,6 +37,11 @@ func newClient(cryptoProvider bccsp.BCCSP) (*client, error) {
 		return nil, errors.WithMessagef(err, "failed to retrieve snapshot client")
 	}
 
+	statusClient, err := common.GetSnapshotStatusClient(peerAddress, tlsRootCertFile)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to retrieve snapshot status client")
+	}
+
 	signer, err := common.GetDefaultSigner()
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to retrieve default signer")
@@ -43,6 +50,7 @@ func newClient(cryptoProvider bccsp.BCCSP) (*client, error) {
 	return &client{
 		signer:         signer,
 		snapshotClient: snapshotClient,
+		statusClient:   statusClient,
 		writer:         os.Stdout,
 	}, nil
 }
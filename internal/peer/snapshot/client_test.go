@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/internal/peer/common"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -21,6 +22,12 @@ type snapshotClient interface {
 	pb.SnapshotClient
 }
 
+//go:generate counterfeiter -o mock/snapshot_status_client.go -fake-name SnapshotStatusClient . snapshotStatusClient
+
+type snapshotStatusClient interface {
+	snapshotgrpc.SnapshotStatusClient
+}
+
 //go:generate counterfeiter -o mock/signer.go -fake-name Signer . signer
 
 type signer interface {
@@ -52,6 +59,12 @@ func TestValidatePeerConnectionParameters(t *testing.T) {
 	err = cmd.Execute()
 	require.EqualError(t, err, expectedErrMsg)
 
+	resetFlags()
+	cmd = statusCmd(nil, nil)
+	cmd.SetArgs(args)
+	err = cmd.Execute()
+	require.EqualError(t, err, expectedErrMsg)
+
 	resetFlags()
 	cmd = cancelRequestCmd(nil, nil)
 	// append required block number parameter for cancel request
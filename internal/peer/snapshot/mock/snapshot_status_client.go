@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"google.golang.org/grpc"
+)
+
+type SnapshotStatusClient struct {
+	QueryStatusStub        func(context.Context, *snapshotgrpc.SignedSnapshotQuery, ...grpc.CallOption) (*snapshotgrpc.QuerySnapshotStatusResponse, error)
+	queryStatusMutex       sync.RWMutex
+	queryStatusArgsForCall []struct {
+		arg1 context.Context
+		arg2 *snapshotgrpc.SignedSnapshotQuery
+		arg3 []grpc.CallOption
+	}
+	queryStatusReturns struct {
+		result1 *snapshotgrpc.QuerySnapshotStatusResponse
+		result2 error
+	}
+	queryStatusReturnsOnCall map[int]struct {
+		result1 *snapshotgrpc.QuerySnapshotStatusResponse
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *SnapshotStatusClient) QueryStatus(arg1 context.Context, arg2 *snapshotgrpc.SignedSnapshotQuery, arg3 ...grpc.CallOption) (*snapshotgrpc.QuerySnapshotStatusResponse, error) {
+	fake.queryStatusMutex.Lock()
+	ret, specificReturn := fake.queryStatusReturnsOnCall[len(fake.queryStatusArgsForCall)]
+	fake.queryStatusArgsForCall = append(fake.queryStatusArgsForCall, struct {
+		arg1 context.Context
+		arg2 *snapshotgrpc.SignedSnapshotQuery
+		arg3 []grpc.CallOption
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("QueryStatus", []interface{}{arg1, arg2, arg3})
+	fake.queryStatusMutex.Unlock()
+	if fake.QueryStatusStub != nil {
+		return fake.QueryStatusStub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.queryStatusReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *SnapshotStatusClient) QueryStatusCallCount() int {
+	fake.queryStatusMutex.RLock()
+	defer fake.queryStatusMutex.RUnlock()
+	return len(fake.queryStatusArgsForCall)
+}
+
+func (fake *SnapshotStatusClient) QueryStatusCalls(stub func(context.Context, *snapshotgrpc.SignedSnapshotQuery, ...grpc.CallOption) (*snapshotgrpc.QuerySnapshotStatusResponse, error)) {
+	fake.queryStatusMutex.Lock()
+	defer fake.queryStatusMutex.Unlock()
+	fake.QueryStatusStub = stub
+}
+
+func (fake *SnapshotStatusClient) QueryStatusArgsForCall(i int) (context.Context, *snapshotgrpc.SignedSnapshotQuery, []grpc.CallOption) {
+	fake.queryStatusMutex.RLock()
+	defer fake.queryStatusMutex.RUnlock()
+	argsForCall := fake.queryStatusArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *SnapshotStatusClient) QueryStatusReturns(result1 *snapshotgrpc.QuerySnapshotStatusResponse, result2 error) {
+	fake.queryStatusMutex.Lock()
+	defer fake.queryStatusMutex.Unlock()
+	fake.QueryStatusStub = nil
+	fake.queryStatusReturns = struct {
+		result1 *snapshotgrpc.QuerySnapshotStatusResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SnapshotStatusClient) QueryStatusReturnsOnCall(i int, result1 *snapshotgrpc.QuerySnapshotStatusResponse, result2 error) {
+	fake.queryStatusMutex.Lock()
+	defer fake.queryStatusMutex.Unlock()
+	fake.QueryStatusStub = nil
+	if fake.queryStatusReturnsOnCall == nil {
+		fake.queryStatusReturnsOnCall = make(map[int]struct {
+			result1 *snapshotgrpc.QuerySnapshotStatusResponse
+			result2 error
+		})
+	}
+	fake.queryStatusReturnsOnCall[i] = struct {
+		result1 *snapshotgrpc.QuerySnapshotStatusResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *SnapshotStatusClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.queryStatusMutex.RLock()
+	defer fake.queryStatusMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *SnapshotStatusClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
@@ -25,6 +25,7 @@ func Cmd(cryptoProvider bccsp.BCCSP) *cobra.Command {
 	snapshotCmd.AddCommand(submitRequestCmd(nil, cryptoProvider))
 	snapshotCmd.AddCommand(cancelRequestCmd(nil, cryptoProvider))
 	snapshotCmd.AddCommand(listPendingCmd(nil, cryptoProvider))
+	snapshotCmd.AddCommand(statusCmd(nil, cryptoProvider))
 
 	return snapshotCmd
 }
@@ -39,8 +40,8 @@ var (
 
 var snapshotCmd = &cobra.Command{
 	Use:   "snapshot",
-	Short: "Manage snapshot requests: submitrequest|cancelrequest|listpending",
-	Long:  "Manage snapshot requests: submitrequest|cancelrequest|listpending",
+	Short: "Manage snapshot requests: submitrequest|cancelrequest|listpending|status",
+	Long:  "Manage snapshot requests: submitrequest|cancelrequest|listpending|status",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		common.InitCmd(cmd, args)
 	},
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd returns the cobra command for snapshot status command
+func statusCmd(cl *client, cryptoProvider bccsp.BCCSP) *cobra.Command {
+	snapshotStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the snapshot requests.",
+		Long:  "Show the status of the snapshot requests, including the progress and the estimated completion time of the snapshot being generated.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return status(cmd, cl, cryptoProvider)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"peerAddress",
+		"tlsRootCertFile",
+	}
+	attachFlags(snapshotStatusCmd, flagList)
+
+	return snapshotStatusCmd
+}
+
+func status(cmd *cobra.Command, cl *client, cryptoProvider bccsp.BCCSP) error {
+	if err := validateStatus(); err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	// create a client if not provided
+	if cl == nil {
+		var err error
+		cl, err = newClient(cryptoProvider)
+		if err != nil {
+			return err
+		}
+	}
+
+	signatureHdr, err := createSignatureHeader(cl.signer)
+	if err != nil {
+		return err
+	}
+
+	query := &pb.SnapshotQuery{
+		SignatureHeader: signatureHdr,
+		ChannelId:       channelID,
+	}
+	signedRequest, err := signSnapshotRequest(cl.signer, query)
+	if err != nil {
+		return err
+	}
+
+	resp, err := cl.statusClient.QueryStatus(
+		context.Background(),
+		&snapshotgrpc.SignedSnapshotQuery{
+			Request:   signedRequest.Request,
+			Signature: signedRequest.Signature,
+		},
+	)
+	if err != nil {
+		return errors.WithMessage(err, "failed to query snapshot status")
+	}
+
+	fmt.Fprintf(cl.writer, "Successfully got snapshot status:\n%s", formatStatus(resp))
+	return nil
+}
+
+func formatStatus(resp *snapshotgrpc.QuerySnapshotStatusResponse) string {
+	if len(resp.Requests) == 0 {
+		return "No pending snapshot requests\n"
+	}
+	var b strings.Builder
+	for _, request := range resp.Requests {
+		progress := request.Progress
+		if progress == nil {
+			fmt.Fprintf(&b, "Block number %d: pending\n", request.BlockNumber)
+			continue
+		}
+		fmt.Fprintf(&b, "Block number %d: in progress, started at %s\n", request.BlockNumber, formatTimestamp(progress.StartTime))
+		for _, phase := range progress.Phases {
+			fmt.Fprintf(&b, "  %s: %s, records written: %d, bytes written: %d\n",
+				phase.Phase, strings.ToLower(phase.Status.String()), phase.RecordsWritten, phase.BytesWritten)
+		}
+		fmt.Fprintf(&b, "  total: records written: %d, bytes written: %d", progress.RecordsWritten, progress.BytesWritten)
+		if progress.EstimatedTotalBytes > 0 {
+			fmt.Fprintf(&b, " of an estimated %d", progress.EstimatedTotalBytes)
+		}
+		b.WriteString("\n")
+		if progress.EstimatedCompletionTime != nil {
+			fmt.Fprintf(&b, "  estimated completion time: %s\n", formatTimestamp(progress.EstimatedCompletionTime))
+		}
+	}
+	return b.String()
+}
+
+func formatTimestamp(ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return "unknown"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func validateStatus() error {
+	if channelID == "" {
+		return errors.New("the required parameter 'channelID' is empty. Rerun the command with -c flag")
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
+	"github.com/hyperledger/fabric/internal/peer/snapshot/mock"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCmd(t *testing.T) {
+	mockSigner := &mock.Signer{}
+	mockSigner.SignReturns([]byte("snapshot-request-signature"), nil)
+	mockStatusClient := &mock.SnapshotStatusClient{}
+	mockStatusClient.QueryStatusReturns(&snapshotgrpc.QuerySnapshotStatusResponse{
+		Requests: []*snapshotgrpc.SnapshotRequestStatus{
+			{
+				BlockNumber: 100,
+				Progress: &snapshotgrpc.SnapshotProgress{
+					StartTime: &timestamp.Timestamp{Seconds: 1600000000},
+					Phases: []*snapshotgrpc.SnapshotPhaseProgress{
+						{Phase: "txids", Status: snapshotgrpc.SnapshotPhaseProgress_DONE, RecordsWritten: 10, BytesWritten: 100},
+						{Phase: "public_state", Status: snapshotgrpc.SnapshotPhaseProgress_IN_PROGRESS, RecordsWritten: 5, BytesWritten: 50},
+					},
+					RecordsWritten:          15,
+					BytesWritten:            150,
+					EstimatedTotalBytes:     300,
+					EstimatedCompletionTime: &timestamp.Timestamp{Seconds: 1600000060},
+				},
+			},
+			{BlockNumber: 200},
+		},
+	}, nil)
+	buffer := gbytes.NewBuffer()
+	mockClient := &client{nil, mockStatusClient, mockSigner, buffer}
+
+	resetFlags()
+	cmd := statusCmd(mockClient, nil)
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	err := cmd.Execute()
+	require.NoError(t, err)
+	require.Equal(t, "Successfully got snapshot status:\n"+
+		"Block number 100: in progress, started at 2020-09-13T12:26:40Z\n"+
+		"  txids: done, records written: 10, bytes written: 100\n"+
+		"  public_state: in_progress, records written: 5, bytes written: 50\n"+
+		"  total: records written: 15, bytes written: 150 of an estimated 300\n"+
+		"  estimated completion time: 2020-09-13T12:27:40Z\n"+
+		"Block number 200: pending\n",
+		string(buffer.Contents()),
+	)
+
+	_, signedQuery, _ := mockStatusClient.QueryStatusArgsForCall(0)
+	query := &pb.SnapshotQuery{}
+	require.NoError(t, proto.Unmarshal(signedQuery.Request, query))
+	require.Equal(t, "mychannel", query.ChannelId)
+	require.Equal(t, []byte("snapshot-request-signature"), signedQuery.Signature)
+
+	mockStatusClient.QueryStatusReturns(&snapshotgrpc.QuerySnapshotStatusResponse{}, nil)
+	buffer = gbytes.NewBuffer()
+	mockClient.writer = buffer
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "Successfully got snapshot status:\nNo pending snapshot requests\n", string(buffer.Contents()))
+
+	// error tests
+	mockStatusClient.QueryStatusReturns(nil, fmt.Errorf("fake-querystatus-error"))
+	require.EqualError(t, cmd.Execute(), "failed to query snapshot status: fake-querystatus-error")
+
+	mockSigner.SignReturns(nil, fmt.Errorf("fake-sign-error"))
+	require.EqualError(t, cmd.Execute(), "fake-sign-error")
+
+	mockSigner.SerializeReturns(nil, fmt.Errorf("fake-serialize-error"))
+	require.EqualError(t, cmd.Execute(), "fake-serialize-error")
+
+	resetFlags()
+	cmd.SetArgs([]string{})
+	require.EqualError(t, cmd.Execute(), "the required parameter 'channelID' is empty. Rerun the command with -c flag")
+}
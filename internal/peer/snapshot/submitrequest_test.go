@@ -22,7 +22,7 @@ func TestSubmitRequestCmd(t *testing.T) {
 	mockSnapshotClient := &mock.SnapshotClient{}
 	mockSnapshotClient.GenerateReturns(&empty.Empty{}, nil)
 	buffer := gbytes.NewBuffer()
-	mockClient := &client{mockSnapshotClient, mockSigner, buffer}
+	mockClient := &client{mockSnapshotClient, nil, mockSigner, buffer}
 
 	resetFlags()
 	cmd := submitRequestCmd(mockClient, nil)
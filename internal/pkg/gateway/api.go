@@ -0,0 +1,293 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Endorse collects the endorsements of the proposal from the peers of one of
+// the layouts of the endorsement plan of the chaincode, and returns the
+// transaction assembled from them, to be signed by the client. The local peer
+// and then the peers with the highest ledger height are preferred. A peer which
+// fails to endorse is replaced by another peer of its group, and when a group
+// runs out of peers, the next layout of the plan is tried.
+func (s *Server) Endorse(ctx context.Context, request *EndorseRequest) (*EndorseResponse, error) {
+	signedProposal := request.GetProposedTransaction()
+	if signedProposal == nil {
+		return nil, status.Error(codes.InvalidArgument, "a signed proposal is required")
+	}
+	proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack the proposal: %s", err)
+	}
+	channelID, chaincodeName, err := proposalTarget(proposal)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack the proposal: %s", err)
+	}
+
+	plan, err := s.planEndorsement(channelID, chaincodeName, request.GetEndorsingOrganizations())
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to plan the endorsement of chaincode %s on channel %s: %s", chaincodeName, channelID, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.options.EndorsementTimeout)
+	defer cancel()
+	responses, err := s.collectEndorsements(ctx, channelID, signedProposal, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := protoutil.CreateTx(proposal, responses...)
+	if err != nil {
+		return nil, status.Errorf(codes.Aborted, "failed to assemble the transaction: %s", err)
+	}
+	return &EndorseResponse{
+		Result:              responses[0].Response,
+		PreparedTransaction: env,
+	}, nil
+}
+
+// Submit sends the transaction signed by the client to an orderer of the
+// channel, and returns once it is accepted for ordering.
+func (s *Server) Submit(ctx context.Context, request *SubmitRequest) (*SubmitResponse, error) {
+	txn := request.GetPreparedTransaction()
+	if txn == nil {
+		return nil, status.Error(codes.InvalidArgument, "a signed transaction is required")
+	}
+	chdr, err := protoutil.ChannelHeader(txn)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack the transaction: %s", err)
+	}
+
+	endpoint, err := s.channels.OrdererEndpoint(chdr.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "no orderer is available for channel %s: %s", chdr.ChannelId, err)
+	}
+	client, err := s.dialOrderer(endpoint)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to orderer %s: %s", endpoint.Address, err)
+	}
+	stream, err := client.Broadcast(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to broadcast to orderer %s: %s", endpoint.Address, err)
+	}
+	defer stream.CloseSend()
+
+	if err := stream.Send(txn); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to send transaction %s to orderer %s: %s", chdr.TxId, endpoint.Address, err)
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to receive the response of orderer %s: %s", endpoint.Address, err)
+	}
+	if response.Status != common.Status_SUCCESS {
+		return nil, status.Errorf(codes.Aborted, "orderer %s rejected transaction %s with status %s: %s", endpoint.Address, chdr.TxId, response.Status, response.Info)
+	}
+
+	logger.Debugf("Submitted transaction %s to orderer %s", chdr.TxId, endpoint.Address)
+	return &SubmitResponse{}, nil
+}
+
+// CommitStatus returns the validation code of the transaction once it is
+// committed by the peer. The client must be allowed to receive the filtered
+// blocks of the channel.
+func (s *Server) CommitStatus(ctx context.Context, signedRequest *SignedCommitStatusRequest) (*CommitStatusResponse, error) {
+	request := &CommitStatusRequest{}
+	if err := proto.Unmarshal(signedRequest.GetRequest(), request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unpack the request: %s", err)
+	}
+
+	signedData := []*protoutil.SignedData{{
+		Data:      signedRequest.Request,
+		Identity:  request.Identity,
+		Signature: signedRequest.Signature,
+	}}
+	if err := s.aclProvider.CheckACL(resources.Event_FilteredBlock, request.ChannelId, signedData); err != nil {
+		return nil, status.Errorf(codes.PermissionDenied, "access denied to the commit status of channel %s: %s", request.ChannelId, err)
+	}
+
+	ledger, err := s.channels.Ledger(request.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s", err)
+	}
+	notifier, err := s.channels.CommitNotifier(request.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%s", err)
+	}
+	code, blockNumber, err := waitForCommit(ctx, ledger, notifier, request.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitStatusResponse{
+		Result:      code,
+		BlockNumber: blockNumber,
+	}, nil
+}
+
+// proposalTarget returns the channel and the chaincode of the proposal.
+func proposalTarget(proposal *pb.Proposal) (string, string, error) {
+	hdr, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return "", "", err
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return "", "", err
+	}
+	ext, err := protoutil.UnmarshalChaincodeHeaderExtension(chdr.Extension)
+	if err != nil {
+		return "", "", err
+	}
+	if ext.GetChaincodeId().GetName() == "" {
+		return "", "", errors.New("the proposal does not name a chaincode")
+	}
+	return chdr.ChannelId, ext.ChaincodeId.Name, nil
+}
+
+// collectEndorsements endorses the proposal with the endorsers of the first
+// layout of the plan which can be satisfied.
+func (s *Server) collectEndorsements(ctx context.Context, channelID string, signedProposal *pb.SignedProposal, plan *plan) ([]*pb.ProposalResponse, error) {
+	responses := map[*endorser]*pb.ProposalResponse{}
+	failures := map[*endorser]error{}
+
+	for _, layout := range plan.layouts {
+		for ctx.Err() == nil {
+			pending, satisfied := plan.pending(layout, responses, failures)
+			if satisfied {
+				return plan.responses(layout, responses), nil
+			}
+			if len(pending) == 0 {
+				break
+			}
+			s.endorse(ctx, channelID, signedProposal, pending, responses, failures)
+		}
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, status.Errorf(codes.DeadlineExceeded, "timed out collecting the endorsements: %s", describeFailures(failures))
+	}
+	if ctx.Err() != nil {
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return nil, status.Errorf(codes.Aborted, "failed to collect enough endorsements: %s", describeFailures(failures))
+}
+
+// endorse sends the proposal to the endorsers in parallel, and records their
+// responses or failures.
+func (s *Server) endorse(
+	ctx context.Context,
+	channelID string,
+	signedProposal *pb.SignedProposal,
+	endorsers []*endorser,
+	responses map[*endorser]*pb.ProposalResponse,
+	failures map[*endorser]error,
+) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, e := range endorsers {
+		wg.Add(1)
+		go func(e *endorser) {
+			defer wg.Done()
+			response, err := s.processProposal(ctx, channelID, e, signedProposal)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				logger.Warningf("Failed to endorse with %s: %s", e, err)
+				failures[e] = err
+				return
+			}
+			responses[e] = response
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (s *Server) processProposal(ctx context.Context, channelID string, e *endorser, signedProposal *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	var response *pb.ProposalResponse
+	var err error
+	if e.local {
+		response, err = s.localEndorser.ProcessProposal(ctx, signedProposal)
+	} else {
+		var rootCerts [][]byte
+		rootCerts, err = s.channels.TLSRootCerts(channelID, e.mspID)
+		if err != nil {
+			return nil, err
+		}
+		var client pb.EndorserClient
+		client, err = s.dialEndorser(e.address, rootCerts)
+		if err != nil {
+			return nil, err
+		}
+		response, err = client.ProcessProposal(ctx, signedProposal)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if response.GetResponse().GetStatus() < 200 || response.GetResponse().GetStatus() >= 400 {
+		return nil, errors.Errorf("endorsement failed with status %d: %s", response.GetResponse().GetStatus(), response.GetResponse().GetMessage())
+	}
+	return response, nil
+}
+
+// waitForCommit returns the validation code of the transaction, and the number
+// of the block which contains it, once it is committed.
+func waitForCommit(ctx context.Context, ledger Ledger, notifier *committer.CommitNotifier, txID string) (pb.TxValidationCode, uint64, error) {
+	// The notifier is registered with before the committed transactions are
+	// looked up, so a transaction committed in between is not missed.
+	statuses, done := notifier.Register(txID)
+	defer done()
+
+	exists, err := ledger.TxIDExists(txID)
+	if err != nil {
+		return 0, 0, status.Errorf(codes.Unavailable, "failed to look up transaction %s: %s", txID, err)
+	}
+	if exists {
+		block, err := ledger.GetBlockByTxID(txID)
+		if err != nil {
+			return 0, 0, status.Errorf(codes.Unavailable, "failed to get the block of transaction %s: %s", txID, err)
+		}
+		if txStatus := committer.FindTxStatus(block, txID); txStatus != nil {
+			return txStatus.ValidationCode, txStatus.BlockNumber, nil
+		}
+		return 0, 0, status.Errorf(codes.Internal, "transaction %s is not in block [%d] which is indexed for it", txID, block.Header.Number)
+	}
+
+	select {
+	case txStatus := <-statuses:
+		return txStatus.ValidationCode, txStatus.BlockNumber, nil
+	case <-ctx.Done():
+		return 0, 0, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func describeFailures(failures map[*endorser]error) string {
+	if len(failures) == 0 {
+		return "no endorser is available"
+	}
+	var descriptions []string
+	for e, err := range failures {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", e, err))
+	}
+	sort.Strings(descriptions)
+	return strings.Join(descriptions, "; ")
+}
@@ -0,0 +1,462 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/hyperledger/fabric/internal/pkg/gateway/mocks"
+	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testChannels struct {
+	ledger   Ledger
+	notifier *committer.CommitNotifier
+	endpoint *orderers.Endpoint
+}
+
+func (c *testChannels) Ledger(channelID string) (Ledger, error) {
+	if channelID != "mychannel" {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return c.ledger, nil
+}
+
+func (c *testChannels) CommitNotifier(channelID string) (*committer.CommitNotifier, error) {
+	if channelID != "mychannel" {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	return c.notifier, nil
+}
+
+func (c *testChannels) OrdererEndpoint(channelID string) (*orderers.Endpoint, error) {
+	if c.endpoint == nil {
+		return nil, errors.New("no endpoints currently defined")
+	}
+	return c.endpoint, nil
+}
+
+func (c *testChannels) TLSRootCerts(channelID, mspID string) ([][]byte, error) {
+	return [][]byte{[]byte(mspID + " root")}, nil
+}
+
+type testEndorser struct {
+	mspID   string
+	address string
+	height  uint64
+}
+
+var (
+	localPeer = testEndorser{mspID: "Org1MSP", address: "localhost:7051", height: 5}
+	peer1     = testEndorser{mspID: "Org1MSP", address: "peer1:7051", height: 10}
+	peer2     = testEndorser{mspID: "Org2MSP", address: "peer2:7051", height: 3}
+	peer3     = testEndorser{mspID: "Org2MSP", address: "peer3:7051", height: 7}
+)
+
+func (e testEndorser) identity() []byte {
+	return protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: e.mspID, IdBytes: []byte(e.address)})
+}
+
+func (e testEndorser) peer(t *testing.T) *dp.Peer {
+	alive, err := protoext.NoopSign(&gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{Membership: &gossip.Member{Endpoint: e.address}},
+		},
+	})
+	require.NoError(t, err)
+	stateInfo, err := protoext.NoopSign(&gossip.GossipMessage{
+		Content: &gossip.GossipMessage_StateInfo{
+			StateInfo: &gossip.StateInfo{Properties: &gossip.Properties{LedgerHeight: e.height}},
+		},
+	})
+	require.NoError(t, err)
+	return &dp.Peer{
+		Identity:       e.identity(),
+		MembershipInfo: alive.Envelope,
+		StateInfo:      stateInfo.Envelope,
+	}
+}
+
+func proposalResponse(endorser string, payload string) *pb.ProposalResponse {
+	return &pb.ProposalResponse{
+		Payload:     []byte(payload),
+		Endorsement: &pb.Endorsement{Endorser: []byte(endorser)},
+		Response:    &pb.Response{Status: 200, Payload: []byte("result")},
+	}
+}
+
+type testGateway struct {
+	server        *Server
+	localEndorser *mocks.EndorserServer
+	endorsers     map[string]*mocks.EndorserClient
+	discovery     *mocks.Discovery
+	aclProvider   *mocks.ACLProvider
+	ledger        *mocks.Ledger
+	channels      *testChannels
+	broadcast     *mocks.BroadcastClient
+}
+
+func newTestGateway(t *testing.T) *testGateway {
+	tg := &testGateway{
+		localEndorser: &mocks.EndorserServer{},
+		endorsers:     map[string]*mocks.EndorserClient{},
+		discovery:     &mocks.Discovery{},
+		aclProvider:   &mocks.ACLProvider{},
+		ledger:        &mocks.Ledger{},
+		broadcast:     &mocks.BroadcastClient{},
+	}
+	tg.localEndorser.ProcessProposalReturns(proposalResponse("local", "payload"), nil)
+	for _, e := range []testEndorser{peer1, peer2, peer3} {
+		client := &mocks.EndorserClient{}
+		client.ProcessProposalReturns(proposalResponse(e.address, "payload"), nil)
+		tg.endorsers[e.address] = client
+	}
+	tg.discovery.PeersForEndorsementReturns(&dp.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*dp.Peers{
+			"G1": {Peers: []*dp.Peer{peer1.peer(t), localPeer.peer(t)}},
+			"G2": {Peers: []*dp.Peer{peer2.peer(t), peer3.peer(t)}},
+		},
+		Layouts: []*dp.Layout{
+			{QuantitiesByGroup: map[string]uint32{"G1": 1, "G2": 1}},
+		},
+	}, nil)
+	tg.channels = &testChannels{
+		ledger:   tg.ledger,
+		notifier: committer.NewCommitNotifier(),
+		endpoint: &orderers.Endpoint{Address: "orderer:7050"},
+	}
+	tg.broadcast.RecvReturns(&ab.BroadcastResponse{Status: common.Status_SUCCESS}, nil)
+
+	tg.server = NewServer(tg.localEndorser, localPeer.identity(), tg.discovery, tg.channels, tg.aclProvider, nil, Options{EndorsementTimeout: time.Second})
+	tg.server.dialEndorser = func(address string, rootCerts [][]byte) (pb.EndorserClient, error) {
+		client, ok := tg.endorsers[address]
+		if !ok {
+			return nil, errors.Errorf("failed to connect to %s", address)
+		}
+		return client, nil
+	}
+	tg.server.dialOrderer = func(endpoint *orderers.Endpoint) (ab.AtomicBroadcastClient, error) {
+		client := &mocks.AtomicBroadcastClient{}
+		client.BroadcastReturns(tg.broadcast, nil)
+		return client, nil
+	}
+	return tg
+}
+
+func newEndorseRequest(t *testing.T, organizations ...string) *EndorseRequest {
+	proposal, txID, err := protoutil.CreateChaincodeProposal(
+		common.HeaderType_ENDORSER_TRANSACTION,
+		"mychannel",
+		&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}},
+		[]byte("creator"),
+	)
+	require.NoError(t, err)
+	return &EndorseRequest{
+		TransactionId:          txID,
+		ChannelId:              "mychannel",
+		ProposedTransaction:    &pb.SignedProposal{ProposalBytes: protoutil.MarshalOrPanic(proposal)},
+		EndorsingOrganizations: organizations,
+	}
+}
+
+func endorsersOf(t *testing.T, env *common.Envelope) []string {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	require.NoError(t, err)
+	cap, err := protoutil.UnmarshalChaincodeActionPayload(tx.Actions[0].Payload)
+	require.NoError(t, err)
+	var endorsers []string
+	for _, endorsement := range cap.Action.Endorsements {
+		endorsers = append(endorsers, string(endorsement.Endorser))
+	}
+	return endorsers
+}
+
+func requireStatus(t *testing.T, err error, code codes.Code, message string) {
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %s", err)
+	require.Equal(t, code, s.Code(), s.Message())
+	require.Contains(t, s.Message(), message)
+}
+
+func TestEndorse(t *testing.T) {
+	t.Run("prefers the local peer and the highest ledger", func(t *testing.T) {
+		tg := newTestGateway(t)
+		response, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		require.NoError(t, err)
+		require.Equal(t, []byte("result"), response.Result.Payload)
+		require.Nil(t, response.PreparedTransaction.Signature)
+		require.Equal(t, []string{"local", "peer3:7051"}, endorsersOf(t, response.PreparedTransaction))
+
+		channelID, interest := tg.discovery.PeersForEndorsementArgsForCall(0)
+		require.Equal(t, "mychannel", string(channelID))
+		require.Equal(t, "mycc", interest.Chaincodes[0].Name)
+		require.Equal(t, 0, tg.endorsers[peer1.address].ProcessProposalCallCount())
+		require.Equal(t, 0, tg.endorsers[peer2.address].ProcessProposalCallCount())
+	})
+
+	t.Run("retries a failed endorser with another peer of its group", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.endorsers[peer3.address].ProcessProposalReturns(nil, errors.New("connection refused"))
+		tg.localEndorser.ProcessProposalReturns(&pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "chaincode not installed"}}, nil)
+
+		response, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"peer1:7051", "peer2:7051"}, endorsersOf(t, response.PreparedTransaction))
+	})
+
+	t.Run("tries the next layout", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.discovery.PeersForEndorsementReturns(&dp.EndorsementDescriptor{
+			EndorsersByGroups: map[string]*dp.Peers{
+				"G1": {Peers: []*dp.Peer{peer1.peer(t), localPeer.peer(t)}},
+				"G2": {Peers: []*dp.Peer{peer2.peer(t), peer3.peer(t)}},
+			},
+			Layouts: []*dp.Layout{
+				{QuantitiesByGroup: map[string]uint32{"G2": 2}},
+				{QuantitiesByGroup: map[string]uint32{"G1": 2}},
+			},
+		}, nil)
+		tg.endorsers[peer2.address].ProcessProposalReturns(nil, errors.New("connection refused"))
+
+		response, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		require.NoError(t, err)
+		require.Equal(t, []string{"local", "peer1:7051"}, endorsersOf(t, response.PreparedTransaction))
+	})
+
+	t.Run("not enough endorsers", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.endorsers[peer2.address].ProcessProposalReturns(nil, errors.New("connection refused"))
+		tg.endorsers[peer3.address].ProcessProposalReturns(&pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: "boom"}}, nil)
+
+		_, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		requireStatus(t, err, codes.Aborted, "failed to collect enough endorsements: peer2:7051 (Org2MSP): connection refused; peer3:7051 (Org2MSP): endorsement failed with status 500: boom")
+	})
+
+	t.Run("endorsing organizations", func(t *testing.T) {
+		tg := newTestGateway(t)
+		response, err := tg.server.Endorse(context.Background(), newEndorseRequest(t, "Org2MSP"))
+		require.NoError(t, err)
+		require.Equal(t, []string{"peer3:7051"}, endorsersOf(t, response.PreparedTransaction))
+		require.Equal(t, 0, tg.localEndorser.ProcessProposalCallCount())
+
+		_, err = tg.server.Endorse(context.Background(), newEndorseRequest(t, "Org1MSP", "Org3MSP"))
+		requireStatus(t, err, codes.FailedPrecondition, "no peer of organization Org3MSP can endorse")
+	})
+
+	t.Run("mismatching responses", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.endorsers[peer3.address].ProcessProposalReturns(proposalResponse(peer3.address, "other payload"), nil)
+
+		_, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		requireStatus(t, err, codes.Aborted, "failed to assemble the transaction: ProposalResponsePayloads do not match")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.server.options.EndorsementTimeout = 10 * time.Millisecond
+		tg.endorsers[peer3.address].ProcessProposalStub = func(ctx context.Context, _ *pb.SignedProposal, _ ...grpc.CallOption) (*pb.ProposalResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		_, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		requireStatus(t, err, codes.DeadlineExceeded, "timed out collecting the endorsements")
+	})
+
+	t.Run("discovery fails", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.discovery.PeersForEndorsementReturns(nil, errors.New("chaincode mycc not found"))
+
+		_, err := tg.server.Endorse(context.Background(), newEndorseRequest(t))
+		requireStatus(t, err, codes.FailedPrecondition, "failed to plan the endorsement of chaincode mycc on channel mychannel: chaincode mycc not found")
+	})
+
+	t.Run("invalid proposal", func(t *testing.T) {
+		tg := newTestGateway(t)
+		_, err := tg.server.Endorse(context.Background(), &EndorseRequest{})
+		requireStatus(t, err, codes.InvalidArgument, "a signed proposal is required")
+
+		_, err = tg.server.Endorse(context.Background(), &EndorseRequest{ProposedTransaction: &pb.SignedProposal{ProposalBytes: []byte("garbage")}})
+		requireStatus(t, err, codes.InvalidArgument, "failed to unpack the proposal")
+	})
+}
+
+func TestSubmit(t *testing.T) {
+	env := &common.Envelope{
+		Payload: protoutil.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{ChannelId: "mychannel", TxId: "tx1"}),
+			},
+		}),
+		Signature: []byte("signature"),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		tg := newTestGateway(t)
+		_, err := tg.server.Submit(context.Background(), &SubmitRequest{TransactionId: "tx1", ChannelId: "mychannel", PreparedTransaction: env})
+		require.NoError(t, err)
+		require.Equal(t, 1, tg.broadcast.SendCallCount())
+		require.Equal(t, env, tg.broadcast.SendArgsForCall(0))
+		require.Equal(t, 1, tg.broadcast.CloseSendCallCount())
+	})
+
+	t.Run("rejected by the orderer", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.broadcast.RecvReturns(&ab.BroadcastResponse{Status: common.Status_BAD_REQUEST, Info: "duplicate"}, nil)
+		_, err := tg.server.Submit(context.Background(), &SubmitRequest{PreparedTransaction: env})
+		requireStatus(t, err, codes.Aborted, "orderer orderer:7050 rejected transaction tx1 with status BAD_REQUEST: duplicate")
+	})
+
+	t.Run("orderer unavailable", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.broadcast.SendReturns(errors.New("EOF"))
+		_, err := tg.server.Submit(context.Background(), &SubmitRequest{PreparedTransaction: env})
+		requireStatus(t, err, codes.Unavailable, "failed to send transaction tx1 to orderer orderer:7050: EOF")
+
+		tg.channels.endpoint = nil
+		_, err = tg.server.Submit(context.Background(), &SubmitRequest{PreparedTransaction: env})
+		requireStatus(t, err, codes.Unavailable, "no orderer is available for channel mychannel")
+	})
+
+	t.Run("missing transaction", func(t *testing.T) {
+		tg := newTestGateway(t)
+		_, err := tg.server.Submit(context.Background(), &SubmitRequest{})
+		requireStatus(t, err, codes.InvalidArgument, "a signed transaction is required")
+	})
+}
+
+func committedBlock(number uint64, validationCodes map[string]pb.TxValidationCode, txIDs ...string) *common.Block {
+	block := protoutil.NewBlock(number, nil)
+	flags := txflags.New(len(txIDs))
+	for i, txID := range txIDs {
+		env := &common.Envelope{
+			Payload: protoutil.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{
+					ChannelHeader: protoutil.MarshalOrPanic(&common.ChannelHeader{ChannelId: "mychannel", TxId: txID}),
+				},
+			}),
+		}
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+		flags.SetFlag(i, validationCodes[txID])
+	}
+	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = flags
+	return block
+}
+
+func TestCommitStatus(t *testing.T) {
+	request := &SignedCommitStatusRequest{
+		Request: protoutil.MarshalOrPanic(&CommitStatusRequest{
+			TransactionId: "tx1",
+			ChannelId:     "mychannel",
+			Identity:      []byte("client"),
+		}),
+		Signature: []byte("signature"),
+	}
+
+	t.Run("already committed", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.ledger.TxIDExistsReturns(true, nil)
+		tg.ledger.GetBlockByTxIDReturns(committedBlock(5, map[string]pb.TxValidationCode{"tx1": pb.TxValidationCode_MVCC_READ_CONFLICT}, "tx0", "tx1"), nil)
+
+		response, err := tg.server.CommitStatus(context.Background(), request)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, response.Result)
+		require.Equal(t, uint64(5), response.BlockNumber)
+		require.Equal(t, "tx1", tg.ledger.GetBlockByTxIDArgsForCall(0))
+
+		resName, channelID, idinfo := tg.aclProvider.CheckACLArgsForCall(0)
+		require.Equal(t, resources.Event_FilteredBlock, resName)
+		require.Equal(t, "mychannel", channelID)
+		require.Equal(t, []*protoutil.SignedData{{Data: request.Request, Identity: []byte("client"), Signature: []byte("signature")}}, idinfo)
+	})
+
+	t.Run("not in the indexed block", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.ledger.TxIDExistsReturns(true, nil)
+		tg.ledger.GetBlockByTxIDReturns(committedBlock(5, nil, "tx0"), nil)
+
+		_, err := tg.server.CommitStatus(context.Background(), request)
+		requireStatus(t, err, codes.Internal, "transaction tx1 is not in block [5] which is indexed for it")
+	})
+
+	t.Run("ledger failure", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.ledger.TxIDExistsReturns(false, errors.New("leveldb: closed"))
+
+		_, err := tg.server.CommitStatus(context.Background(), request)
+		requireStatus(t, err, codes.Unavailable, "failed to look up transaction tx1: leveldb: closed")
+	})
+
+	t.Run("waits for the commit", func(t *testing.T) {
+		tg := newTestGateway(t)
+		go func() {
+			// The notifier is registered with before the ledger is looked up.
+			for tg.ledger.TxIDExistsCallCount() == 0 {
+				time.Sleep(10 * time.Millisecond)
+			}
+			tg.channels.notifier.Notify(committedBlock(8, nil, "tx0"))
+			tg.channels.notifier.Notify(committedBlock(9, map[string]pb.TxValidationCode{"tx1": pb.TxValidationCode_VALID}, "tx1"))
+		}()
+
+		response, err := tg.server.CommitStatus(context.Background(), request)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxValidationCode_VALID, response.Result)
+		require.Equal(t, uint64(9), response.BlockNumber)
+	})
+
+	t.Run("gives up when the client goes away", func(t *testing.T) {
+		tg := newTestGateway(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go cancel()
+		_, err := tg.server.CommitStatus(ctx, request)
+		requireStatus(t, err, codes.Canceled, "context canceled")
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		tg := newTestGateway(t)
+		tg.aclProvider.CheckACLReturns(errors.New("signature set did not satisfy policy"))
+		_, err := tg.server.CommitStatus(context.Background(), request)
+		requireStatus(t, err, codes.PermissionDenied, "access denied to the commit status of channel mychannel: signature set did not satisfy policy")
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		tg := newTestGateway(t)
+		_, err := tg.server.CommitStatus(context.Background(), &SignedCommitStatusRequest{
+			Request: protoutil.MarshalOrPanic(&CommitStatusRequest{TransactionId: "tx1", ChannelId: "otherchannel"}),
+		})
+		requireStatus(t, err, codes.NotFound, "channel otherchannel not found")
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		tg := newTestGateway(t)
+		_, err := tg.server.CommitStatus(context.Background(), &SignedCommitStatusRequest{Request: []byte("garbage")})
+		requireStatus(t, err, codes.InvalidArgument, "failed to unpack the request")
+	})
+}
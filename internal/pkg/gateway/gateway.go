@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/committer"
+	gossipcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
+)
+
+var logger = flogging.MustGetLogger("gateway")
+
+// Options configures the gateway service.
+type Options struct {
+	// EndorsementTimeout is the time allowed to collect the endorsements of a
+	// proposal.
+	EndorsementTimeout time.Duration
+}
+
+// Discovery computes the endorsement plans of the chaincodes.
+type Discovery interface {
+	PeersForEndorsement(channelID gossipcommon.ChannelID, interest *dp.ChaincodeInterest) (*dp.EndorsementDescriptor, error)
+}
+
+// ACLProvider checks the access of the clients to the resources of a channel.
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// Ledger is the part of the ledger of a channel used to find the status of
+// the transactions already committed.
+type Ledger interface {
+	TxIDExists(txID string) (bool, error)
+	GetBlockByTxID(txID string) (*common.Block, error)
+}
+
+// ChannelSupport gives access to the resources of the channels joined by the
+// peer.
+type ChannelSupport interface {
+	// Ledger returns the ledger of the channel, or an error if the peer has not
+	// joined the channel.
+	Ledger(channelID string) (Ledger, error)
+	// CommitNotifier returns the notifier of the transactions committed on the
+	// channel, or an error if the peer has not joined the channel.
+	CommitNotifier(channelID string) (*committer.CommitNotifier, error)
+	// OrdererEndpoint returns an endpoint of the ordering service of the channel.
+	OrdererEndpoint(channelID string) (*orderers.Endpoint, error)
+	// TLSRootCerts returns the TLS root and intermediate certificates of an
+	// organization of the channel.
+	TLSRootCerts(channelID, mspID string) ([][]byte, error)
+}
+
+// Server implements the Gateway service. It endorses the proposals of the
+// clients on the peers chosen from the endorsement plans of the chaincodes,
+// submits the transactions to the ordering service, and waits for them to be
+// committed by the local peer.
+type Server struct {
+	localEndorser pb.EndorserServer
+	localIdentity []byte
+	discovery     Discovery
+	channels      ChannelSupport
+	aclProvider   ACLProvider
+	options       Options
+
+	dialEndorser func(address string, rootCerts [][]byte) (pb.EndorserClient, error)
+	dialOrderer  func(endpoint *orderers.Endpoint) (ab.AtomicBroadcastClient, error)
+}
+
+// NewServer creates a gateway Server. The local endorser endorses the
+// proposals planned on the peer with the local identity, and the gRPC client
+// connects to the other peers and to the orderers.
+func NewServer(
+	localEndorser pb.EndorserServer,
+	localIdentity []byte,
+	discovery Discovery,
+	channels ChannelSupport,
+	aclProvider ACLProvider,
+	client *comm.GRPCClient,
+	options Options,
+) *Server {
+	registry := newRegistry(client)
+	return &Server{
+		localEndorser: localEndorser,
+		localIdentity: localIdentity,
+		discovery:     discovery,
+		channels:      channels,
+		aclProvider:   aclProvider,
+		options:       options,
+		dialEndorser:  registry.endorser,
+		dialOrderer:   registry.orderer,
+	}
+}
@@ -0,0 +1,586 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: gateway.proto
+
+package gateway
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	common "github.com/hyperledger/fabric-protos-go/common"
+	peer "github.com/hyperledger/fabric-protos-go/peer"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type EndorseRequest struct {
+	TransactionId       string               `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ChannelId           string               `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	ProposedTransaction *peer.SignedProposal `protobuf:"bytes,3,opt,name=proposed_transaction,json=proposedTransaction,proto3" json:"proposed_transaction,omitempty"`
+	// The MSP IDs of the organizations whose peers endorse the transaction, one peer each.
+	// If empty, the endorsers are chosen from the endorsement plan of the chaincode.
+	EndorsingOrganizations []string `protobuf:"bytes,4,rep,name=endorsing_organizations,json=endorsingOrganizations,proto3" json:"endorsing_organizations,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
+}
+
+func (m *EndorseRequest) Reset()         { *m = EndorseRequest{} }
+func (m *EndorseRequest) String() string { return proto.CompactTextString(m) }
+func (*EndorseRequest) ProtoMessage()    {}
+func (*EndorseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{0}
+}
+
+func (m *EndorseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorseRequest.Unmarshal(m, b)
+}
+func (m *EndorseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorseRequest.Marshal(b, m, deterministic)
+}
+func (m *EndorseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorseRequest.Merge(m, src)
+}
+func (m *EndorseRequest) XXX_Size() int {
+	return xxx_messageInfo_EndorseRequest.Size(m)
+}
+func (m *EndorseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorseRequest proto.InternalMessageInfo
+
+func (m *EndorseRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *EndorseRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *EndorseRequest) GetProposedTransaction() *peer.SignedProposal {
+	if m != nil {
+		return m.ProposedTransaction
+	}
+	return nil
+}
+
+func (m *EndorseRequest) GetEndorsingOrganizations() []string {
+	if m != nil {
+		return m.EndorsingOrganizations
+	}
+	return nil
+}
+
+type EndorseResponse struct {
+	Result               *peer.Response   `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	PreparedTransaction  *common.Envelope `protobuf:"bytes,2,opt,name=prepared_transaction,json=preparedTransaction,proto3" json:"prepared_transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *EndorseResponse) Reset()         { *m = EndorseResponse{} }
+func (m *EndorseResponse) String() string { return proto.CompactTextString(m) }
+func (*EndorseResponse) ProtoMessage()    {}
+func (*EndorseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{1}
+}
+
+func (m *EndorseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorseResponse.Unmarshal(m, b)
+}
+func (m *EndorseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorseResponse.Marshal(b, m, deterministic)
+}
+func (m *EndorseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorseResponse.Merge(m, src)
+}
+func (m *EndorseResponse) XXX_Size() int {
+	return xxx_messageInfo_EndorseResponse.Size(m)
+}
+func (m *EndorseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorseResponse proto.InternalMessageInfo
+
+func (m *EndorseResponse) GetResult() *peer.Response {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *EndorseResponse) GetPreparedTransaction() *common.Envelope {
+	if m != nil {
+		return m.PreparedTransaction
+	}
+	return nil
+}
+
+type SubmitRequest struct {
+	TransactionId        string           `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ChannelId            string           `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	PreparedTransaction  *common.Envelope `protobuf:"bytes,3,opt,name=prepared_transaction,json=preparedTransaction,proto3" json:"prepared_transaction,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SubmitRequest) Reset()         { *m = SubmitRequest{} }
+func (m *SubmitRequest) String() string { return proto.CompactTextString(m) }
+func (*SubmitRequest) ProtoMessage()    {}
+func (*SubmitRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{2}
+}
+
+func (m *SubmitRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubmitRequest.Unmarshal(m, b)
+}
+func (m *SubmitRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubmitRequest.Marshal(b, m, deterministic)
+}
+func (m *SubmitRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitRequest.Merge(m, src)
+}
+func (m *SubmitRequest) XXX_Size() int {
+	return xxx_messageInfo_SubmitRequest.Size(m)
+}
+func (m *SubmitRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitRequest proto.InternalMessageInfo
+
+func (m *SubmitRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetPreparedTransaction() *common.Envelope {
+	if m != nil {
+		return m.PreparedTransaction
+	}
+	return nil
+}
+
+type SubmitResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubmitResponse) Reset()         { *m = SubmitResponse{} }
+func (m *SubmitResponse) String() string { return proto.CompactTextString(m) }
+func (*SubmitResponse) ProtoMessage()    {}
+func (*SubmitResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{3}
+}
+
+func (m *SubmitResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubmitResponse.Unmarshal(m, b)
+}
+func (m *SubmitResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubmitResponse.Marshal(b, m, deterministic)
+}
+func (m *SubmitResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubmitResponse.Merge(m, src)
+}
+func (m *SubmitResponse) XXX_Size() int {
+	return xxx_messageInfo_SubmitResponse.Size(m)
+}
+func (m *SubmitResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubmitResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubmitResponse proto.InternalMessageInfo
+
+type SignedCommitStatusRequest struct {
+	Request              []byte   `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignedCommitStatusRequest) Reset()         { *m = SignedCommitStatusRequest{} }
+func (m *SignedCommitStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SignedCommitStatusRequest) ProtoMessage()    {}
+func (*SignedCommitStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{4}
+}
+
+func (m *SignedCommitStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedCommitStatusRequest.Unmarshal(m, b)
+}
+func (m *SignedCommitStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedCommitStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *SignedCommitStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedCommitStatusRequest.Merge(m, src)
+}
+func (m *SignedCommitStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_SignedCommitStatusRequest.Size(m)
+}
+func (m *SignedCommitStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedCommitStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedCommitStatusRequest proto.InternalMessageInfo
+
+func (m *SignedCommitStatusRequest) GetRequest() []byte {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (m *SignedCommitStatusRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type CommitStatusRequest struct {
+	TransactionId        string   `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ChannelId            string   `protobuf:"bytes,2,opt,name=channel_id,json=channelId,proto3" json:"channel_id,omitempty"`
+	Identity             []byte   `protobuf:"bytes,3,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitStatusRequest) Reset()         { *m = CommitStatusRequest{} }
+func (m *CommitStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*CommitStatusRequest) ProtoMessage()    {}
+func (*CommitStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{5}
+}
+
+func (m *CommitStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitStatusRequest.Unmarshal(m, b)
+}
+func (m *CommitStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *CommitStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitStatusRequest.Merge(m, src)
+}
+func (m *CommitStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_CommitStatusRequest.Size(m)
+}
+func (m *CommitStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitStatusRequest proto.InternalMessageInfo
+
+func (m *CommitStatusRequest) GetTransactionId() string {
+	if m != nil {
+		return m.TransactionId
+	}
+	return ""
+}
+
+func (m *CommitStatusRequest) GetChannelId() string {
+	if m != nil {
+		return m.ChannelId
+	}
+	return ""
+}
+
+func (m *CommitStatusRequest) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+type CommitStatusResponse struct {
+	Result               peer.TxValidationCode `protobuf:"varint,1,opt,name=result,proto3,enum=protos.TxValidationCode" json:"result,omitempty"`
+	BlockNumber          uint64                `protobuf:"varint,2,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *CommitStatusResponse) Reset()         { *m = CommitStatusResponse{} }
+func (m *CommitStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*CommitStatusResponse) ProtoMessage()    {}
+func (*CommitStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f1a937782ebbded5, []int{6}
+}
+
+func (m *CommitStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitStatusResponse.Unmarshal(m, b)
+}
+func (m *CommitStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *CommitStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitStatusResponse.Merge(m, src)
+}
+func (m *CommitStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_CommitStatusResponse.Size(m)
+}
+func (m *CommitStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitStatusResponse proto.InternalMessageInfo
+
+func (m *CommitStatusResponse) GetResult() peer.TxValidationCode {
+	if m != nil {
+		return m.Result
+	}
+	return peer.TxValidationCode_VALID
+}
+
+func (m *CommitStatusResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*EndorseRequest)(nil), "gateway.EndorseRequest")
+	proto.RegisterType((*EndorseResponse)(nil), "gateway.EndorseResponse")
+	proto.RegisterType((*SubmitRequest)(nil), "gateway.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "gateway.SubmitResponse")
+	proto.RegisterType((*SignedCommitStatusRequest)(nil), "gateway.SignedCommitStatusRequest")
+	proto.RegisterType((*CommitStatusRequest)(nil), "gateway.CommitStatusRequest")
+	proto.RegisterType((*CommitStatusResponse)(nil), "gateway.CommitStatusResponse")
+}
+
+func init() { proto.RegisterFile("gateway.proto", fileDescriptor_f1a937782ebbded5) }
+
+var fileDescriptor_f1a937782ebbded5 = []byte{
+	// 536 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0x95, 0xdb, 0xaa, 0xf9, 0x72, 0xf3, 0xf3, 0x55, 0x93, 0x2a, 0x31, 0x56, 0x2b, 0x05, 0x4b,
+	0x48, 0x59, 0xc5, 0x28, 0x20, 0x21, 0x24, 0x56, 0x44, 0x15, 0xca, 0x86, 0x1f, 0xa7, 0x62, 0xc1,
+	0x26, 0x1a, 0xc7, 0x17, 0x67, 0x14, 0x7b, 0xc6, 0xcc, 0x8c, 0x29, 0x61, 0xc5, 0x73, 0xf0, 0x5e,
+	0x6c, 0x78, 0x1a, 0x94, 0xf1, 0x38, 0x71, 0x68, 0xbb, 0x40, 0xea, 0x2a, 0x99, 0x73, 0xce, 0x1d,
+	0x9f, 0x7b, 0xef, 0x19, 0xe8, 0x24, 0x54, 0xe3, 0x0d, 0xdd, 0x8c, 0x73, 0x29, 0xb4, 0x20, 0x0d,
+	0x7b, 0xf4, 0x7a, 0x4b, 0x91, 0x65, 0x82, 0x07, 0xe5, 0x4f, 0xc9, 0x7a, 0xbd, 0x1c, 0x51, 0x06,
+	0xb9, 0x14, 0xb9, 0x50, 0x34, 0xb5, 0xe0, 0xc5, 0x01, 0xb8, 0x90, 0xa8, 0x72, 0xc1, 0x15, 0x5a,
+	0xb6, 0x6f, 0x58, 0x2d, 0x29, 0x57, 0x74, 0xa9, 0x59, 0x75, 0x95, 0xff, 0xdb, 0x81, 0xee, 0x15,
+	0x8f, 0x85, 0x54, 0x18, 0xe2, 0x97, 0x02, 0x95, 0x26, 0x4f, 0xa0, 0x5b, 0xd3, 0x2d, 0x58, 0xec,
+	0x3a, 0x43, 0x67, 0xd4, 0x0c, 0x3b, 0x35, 0x74, 0x16, 0x93, 0x4b, 0x80, 0xe5, 0x8a, 0x72, 0x8e,
+	0xe9, 0x56, 0x72, 0x64, 0x24, 0x4d, 0x8b, 0xcc, 0x62, 0x32, 0x83, 0xf3, 0xd2, 0x0b, 0xc6, 0x8b,
+	0x5a, 0xa1, 0x7b, 0x3c, 0x74, 0x46, 0xad, 0x49, 0xbf, 0xfc, 0xbc, 0x1a, 0xcf, 0x59, 0xc2, 0x31,
+	0x7e, 0x6f, 0x5d, 0x87, 0xbd, 0xaa, 0xe6, 0x7a, 0x5f, 0x42, 0x5e, 0xc0, 0x00, 0x8d, 0x45, 0xc6,
+	0x93, 0x85, 0x90, 0x09, 0xe5, 0xec, 0x3b, 0xdd, 0x32, 0xca, 0x3d, 0x19, 0x1e, 0x8f, 0x9a, 0x61,
+	0x7f, 0x47, 0xbf, 0xab, 0xb3, 0xfe, 0x0f, 0x07, 0xfe, 0xdf, 0x35, 0x57, 0x8e, 0x83, 0x8c, 0xe0,
+	0x54, 0xa2, 0x2a, 0x52, 0x6d, 0xba, 0x6a, 0x4d, 0xce, 0x2a, 0x27, 0x95, 0x22, 0xb4, 0x3c, 0x99,
+	0x6e, 0x3b, 0xc0, 0x9c, 0xca, 0xbf, 0x3a, 0x38, 0xb2, 0x75, 0x76, 0x25, 0x57, 0xfc, 0x2b, 0xa6,
+	0x22, 0xc7, 0xb0, 0x57, 0xa9, 0x6b, 0xde, 0xfd, 0x9f, 0x0e, 0x74, 0xe6, 0x45, 0x94, 0x31, 0xfd,
+	0xb0, 0xe3, 0xbd, 0xcf, 0xdc, 0xf1, 0xbf, 0x98, 0x3b, 0x83, 0x6e, 0xe5, 0xad, 0xec, 0xdd, 0x9f,
+	0xc3, 0xa3, 0x72, 0x23, 0x53, 0x91, 0x65, 0x4c, 0xcf, 0x35, 0xd5, 0x85, 0xaa, 0x9c, 0xbb, 0xd0,
+	0x90, 0xe5, 0x5f, 0x63, 0xb9, 0x1d, 0x56, 0x47, 0x72, 0x01, 0x4d, 0xc5, 0x12, 0x4e, 0x75, 0x21,
+	0xd1, 0x78, 0x6d, 0x87, 0x7b, 0xc0, 0xbf, 0x81, 0xde, 0x5d, 0xd7, 0x3d, 0xcc, 0x20, 0x3c, 0xf8,
+	0x8f, 0xc5, 0xc8, 0x35, 0xd3, 0x1b, 0xd3, 0x7c, 0x3b, 0xdc, 0x9d, 0xfd, 0x35, 0x9c, 0x1f, 0x7e,
+	0xd8, 0x66, 0xe0, 0xe9, 0x41, 0x06, 0xba, 0x13, 0xb7, 0xca, 0xc0, 0xf5, 0xb7, 0x8f, 0x34, 0x65,
+	0xb1, 0x89, 0xcf, 0x54, 0xc4, 0xfb, 0x2c, 0x3c, 0x86, 0x76, 0x94, 0x8a, 0xe5, 0x7a, 0xc1, 0x8b,
+	0x2c, 0x42, 0x69, 0x6c, 0x9c, 0x84, 0x2d, 0x83, 0xbd, 0x35, 0xd0, 0xe4, 0x97, 0x03, 0x8d, 0x37,
+	0xe5, 0xab, 0x25, 0xaf, 0xa0, 0x61, 0x73, 0x47, 0x06, 0xe3, 0xea, 0x65, 0x1f, 0x3e, 0x33, 0xcf,
+	0xbd, 0x4d, 0x58, 0x7b, 0x2f, 0xe1, 0xb4, 0x5c, 0x0b, 0xe9, 0xef, 0x34, 0x07, 0x19, 0xf2, 0x06,
+	0xb7, 0x70, 0x5b, 0xfa, 0x01, 0xda, 0xf5, 0x8e, 0x89, 0xbf, 0x17, 0xde, 0xb7, 0x56, 0xef, 0x72,
+	0xa7, 0xb9, 0x6b, 0x58, 0xaf, 0x9f, 0x7f, 0x9a, 0x24, 0x4c, 0xaf, 0x8a, 0x68, 0x9b, 0xa9, 0x60,
+	0xb5, 0xc9, 0x51, 0xa6, 0x18, 0x27, 0x28, 0x83, 0xcf, 0x34, 0x92, 0x6c, 0x19, 0x30, 0xae, 0x51,
+	0x72, 0x9a, 0x06, 0xf9, 0x3a, 0x09, 0xec, 0x55, 0xd1, 0xa9, 0x99, 0xe8, 0xb3, 0x3f, 0x03, 0x00,
+	0x47, 0x74, 0xf7, 0x37, 0xd8, 0x04, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// GatewayClient is the client API for Gateway service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GatewayClient interface {
+	// Endorse collects the endorsements of a proposal from peers which satisfy the endorsement
+	// policy of the chaincode, and returns the transaction to be signed by the client
+	Endorse(ctx context.Context, in *EndorseRequest, opts ...grpc.CallOption) (*EndorseResponse, error)
+	// Submit sends a signed transaction to the ordering service of the channel
+	Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	// CommitStatus waits until the transaction is committed by the peer, and returns its
+	// validation code
+	CommitStatus(ctx context.Context, in *SignedCommitStatusRequest, opts ...grpc.CallOption) (*CommitStatusResponse, error)
+}
+
+type gatewayClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGatewayClient(cc grpc.ClientConnInterface) GatewayClient {
+	return &gatewayClient{cc}
+}
+
+func (c *gatewayClient) Endorse(ctx context.Context, in *EndorseRequest, opts ...grpc.CallOption) (*EndorseResponse, error) {
+	out := new(EndorseResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/Endorse", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) Submit(ctx context.Context, in *SubmitRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/Submit", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gatewayClient) CommitStatus(ctx context.Context, in *SignedCommitStatusRequest, opts ...grpc.CallOption) (*CommitStatusResponse, error) {
+	out := new(CommitStatusResponse)
+	err := c.cc.Invoke(ctx, "/gateway.Gateway/CommitStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GatewayServer is the server API for Gateway service.
+type GatewayServer interface {
+	// Endorse collects the endorsements of a proposal from peers which satisfy the endorsement
+	// policy of the chaincode, and returns the transaction to be signed by the client
+	Endorse(context.Context, *EndorseRequest) (*EndorseResponse, error)
+	// Submit sends a signed transaction to the ordering service of the channel
+	Submit(context.Context, *SubmitRequest) (*SubmitResponse, error)
+	// CommitStatus waits until the transaction is committed by the peer, and returns its
+	// validation code
+	CommitStatus(context.Context, *SignedCommitStatusRequest) (*CommitStatusResponse, error)
+}
+
+// UnimplementedGatewayServer can be embedded to have forward compatible implementations.
+type UnimplementedGatewayServer struct {
+}
+
+func (*UnimplementedGatewayServer) Endorse(ctx context.Context, req *EndorseRequest) (*EndorseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Endorse not implemented")
+}
+func (*UnimplementedGatewayServer) Submit(ctx context.Context, req *SubmitRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Submit not implemented")
+}
+func (*UnimplementedGatewayServer) CommitStatus(ctx context.Context, req *SignedCommitStatusRequest) (*CommitStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitStatus not implemented")
+}
+
+func RegisterGatewayServer(s *grpc.Server, srv GatewayServer) {
+	s.RegisterService(&_Gateway_serviceDesc, srv)
+}
+
+func _Gateway_Endorse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EndorseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Endorse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/Endorse",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Endorse(ctx, req.(*EndorseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_Submit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).Submit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/Submit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).Submit(ctx, req.(*SubmitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gateway_CommitStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedCommitStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GatewayServer).CommitStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/gateway.Gateway/CommitStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GatewayServer).CommitStatus(ctx, req.(*SignedCommitStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Gateway_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "gateway.Gateway",
+	HandlerType: (*GatewayServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Endorse",
+			Handler:    _Gateway_Endorse_Handler,
+		},
+		{
+			MethodName: "Submit",
+			Handler:    _Gateway_Submit_Handler,
+		},
+		{
+			MethodName: "CommitStatus",
+			Handler:    _Gateway_CommitStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "gateway.proto",
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+)
+
+//go:generate counterfeiter -o mocks/endorser_server.go --fake-name EndorserServer . endorserServer
+
+type endorserServer interface {
+	pb.EndorserServer
+}
+
+//go:generate counterfeiter -o mocks/endorser_client.go --fake-name EndorserClient . endorserClient
+
+type endorserClient interface {
+	pb.EndorserClient
+}
+
+//go:generate counterfeiter -o mocks/atomic_broadcast_client.go --fake-name AtomicBroadcastClient . atomicBroadcastClient
+
+type atomicBroadcastClient interface {
+	ab.AtomicBroadcastClient
+}
+
+//go:generate counterfeiter -o mocks/broadcast_client.go --fake-name BroadcastClient . broadcastClient
+
+type broadcastClient interface {
+	ab.AtomicBroadcast_BroadcastClient
+}
+
+//go:generate counterfeiter -o mocks/discovery.go --fake-name Discovery . discovery
+
+type discovery interface {
+	Discovery
+}
+
+//go:generate counterfeiter -o mocks/acl_provider.go --fake-name ACLProvider . aclProvider
+
+type aclProvider interface {
+	ACLProvider
+}
+
+//go:generate counterfeiter -o mocks/ledger.go --fake-name Ledger . ledger
+
+type ledger interface {
+	Ledger
+}
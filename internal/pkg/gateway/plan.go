@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	dp "github.com/hyperledger/fabric-protos-go/discovery"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	gossipcommon "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// endorser is a peer which can endorse the proposals of a chaincode.
+type endorser struct {
+	address string
+	mspID   string
+	height  uint64
+	local   bool
+}
+
+func (e *endorser) String() string {
+	return fmt.Sprintf("%s (%s)", e.address, e.mspID)
+}
+
+// layout is a combination of groups of endorsers which satisfies the
+// endorsement policy, with the number of endorsements needed from each group.
+type layout map[string]int
+
+// plan lists the layouts which satisfy the endorsement policy of a chaincode,
+// and the endorsers of their groups in order of preference.
+type plan struct {
+	groups  map[string][]*endorser
+	layouts []layout
+}
+
+// planEndorsement computes the plan of the chaincode from its endorsement
+// descriptor. If organizations are requested, the plan has a single layout
+// with one endorser of each of them.
+func (s *Server) planEndorsement(channelID, chaincodeName string, organizations []string) (*plan, error) {
+	interest := &dp.ChaincodeInterest{
+		Chaincodes: []*dp.ChaincodeCall{{Name: chaincodeName}},
+	}
+	descriptor, err := s.discovery.PeersForEndorsement(gossipcommon.ChannelID(channelID), interest)
+	if err != nil {
+		return nil, err
+	}
+
+	// The same peer may be in several groups.
+	endorsers := map[string]*endorser{}
+	p := &plan{groups: map[string][]*endorser{}}
+	for group, peers := range descriptor.EndorsersByGroups {
+		for _, peer := range peers.Peers {
+			e, ok := endorsers[string(peer.Identity)]
+			if !ok {
+				e, err = s.endorserOf(peer)
+				if err != nil {
+					logger.Warningf("Ignoring an endorser of chaincode %s on channel %s: %s", chaincodeName, channelID, err)
+					continue
+				}
+				endorsers[string(peer.Identity)] = e
+			}
+			p.groups[group] = append(p.groups[group], e)
+		}
+	}
+
+	if len(organizations) > 0 {
+		p.groups = map[string][]*endorser{}
+		for _, e := range endorsers {
+			p.groups[e.mspID] = append(p.groups[e.mspID], e)
+		}
+		l := layout{}
+		for _, mspID := range organizations {
+			if len(p.groups[mspID]) == 0 {
+				return nil, errors.Errorf("no peer of organization %s can endorse", mspID)
+			}
+			l[mspID] = 1
+		}
+		p.layouts = []layout{l}
+	} else {
+		for _, l := range descriptor.Layouts {
+			quantities := layout{}
+			for group, quantity := range l.QuantitiesByGroup {
+				quantities[group] = int(quantity)
+			}
+			p.layouts = append(p.layouts, quantities)
+		}
+	}
+
+	for _, group := range p.groups {
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].local != group[j].local {
+				return group[i].local
+			}
+			return group[i].height > group[j].height
+		})
+	}
+	return p, nil
+}
+
+func (s *Server) endorserOf(peer *dp.Peer) (*endorser, error) {
+	identity, err := protoutil.UnmarshalSerializedIdentity(peer.Identity)
+	if err != nil {
+		return nil, err
+	}
+	aliveMsg, err := protoext.EnvelopeToGossipMessage(peer.MembershipInfo)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid membership of a peer of %s", identity.Mspid)
+	}
+	e := &endorser{
+		address: aliveMsg.GetAliveMsg().GetMembership().GetEndpoint(),
+		mspID:   identity.Mspid,
+		local:   bytes.Equal(peer.Identity, s.localIdentity),
+	}
+	if e.address == "" && !e.local {
+		return nil, errors.Errorf("a peer of %s has no endpoint", identity.Mspid)
+	}
+	if peer.StateInfo != nil {
+		stateInfoMsg, err := protoext.EnvelopeToGossipMessage(peer.StateInfo)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid state of peer %s", e)
+		}
+		e.height = stateInfoMsg.GetStateInfo().GetProperties().GetLedgerHeight()
+	}
+	return e, nil
+}
+
+// pending returns the endorsers to request in order to satisfy the layout,
+// given the endorsers which already endorsed or failed to. It returns true if
+// the layout is already satisfied, and no endorser if it cannot be.
+func (p *plan) pending(l layout, responses map[*endorser]*pb.ProposalResponse, failures map[*endorser]error) ([]*endorser, bool) {
+	var pending []*endorser
+	selected := map[*endorser]bool{}
+	for group, quantity := range l {
+		needed := quantity
+		for _, e := range p.groups[group] {
+			if _, ok := responses[e]; ok {
+				needed--
+			}
+		}
+		for _, e := range p.groups[group] {
+			if needed <= 0 {
+				break
+			}
+			if _, ok := responses[e]; ok {
+				continue
+			}
+			if _, ok := failures[e]; ok {
+				continue
+			}
+			needed--
+			if !selected[e] {
+				selected[e] = true
+				pending = append(pending, e)
+			}
+		}
+		if needed > 0 {
+			return nil, false
+		}
+	}
+	return pending, len(pending) == 0
+}
+
+// responses returns the responses of the endorsers satisfying the layout, the
+// response of the local peer first if it endorsed.
+func (p *plan) responses(l layout, responses map[*endorser]*pb.ProposalResponse) []*pb.ProposalResponse {
+	var endorsers []*endorser
+	selected := map[*endorser]bool{}
+	for group, quantity := range l {
+		for _, e := range p.groups[group] {
+			if quantity == 0 {
+				break
+			}
+			if _, ok := responses[e]; !ok {
+				continue
+			}
+			quantity--
+			if !selected[e] {
+				selected[e] = true
+				endorsers = append(endorsers, e)
+			}
+		}
+	}
+	sort.SliceStable(endorsers, func(i, j int) bool {
+		return endorsers[i].local && !endorsers[j].local
+	})
+
+	var result []*pb.ProposalResponse
+	for _, e := range endorsers {
+		result = append(result, responses[e])
+	}
+	return result
+}
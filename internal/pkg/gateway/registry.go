@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/x509"
+	"sync"
+
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/internal/pkg/comm"
+	"github.com/hyperledger/fabric/internal/pkg/peer/orderers"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// registry keeps the connections of the gateway to the peers and the orderers,
+// so that they are reused across the requests of the clients.
+type registry struct {
+	dial func(address string, certPool *x509.CertPool) (*grpc.ClientConn, error)
+
+	mutex       sync.Mutex
+	connections map[string]*grpc.ClientConn
+	dials       map[string]*pendingDial
+}
+
+// pendingDial is a connection being established, which the requests for the
+// same address wait for rather than dialing again.
+type pendingDial struct {
+	done chan struct{}
+	conn *grpc.ClientConn
+	err  error
+}
+
+func newRegistry(client *comm.GRPCClient) *registry {
+	return &registry{
+		dial: func(address string, certPool *x509.CertPool) (*grpc.ClientConn, error) {
+			return client.NewConnection(address, comm.CertPoolOverride(certPool))
+		},
+		connections: map[string]*grpc.ClientConn{},
+		dials:       map[string]*pendingDial{},
+	}
+}
+
+// endorser returns a client of the endorser service of the peer at the
+// address, whose TLS certificate is verified with the root certificates of
+// its organization.
+func (r *registry) endorser(address string, rootCerts [][]byte) (pb.EndorserClient, error) {
+	certPool := x509.NewCertPool()
+	for _, cert := range rootCerts {
+		certPool.AppendCertsFromPEM(cert)
+	}
+	conn, err := r.connection(address, certPool)
+	if err != nil {
+		return nil, err
+	}
+	return pb.NewEndorserClient(conn), nil
+}
+
+// orderer returns a client of the broadcast service of the orderer at the
+// endpoint.
+func (r *registry) orderer(endpoint *orderers.Endpoint) (ab.AtomicBroadcastClient, error) {
+	conn, err := r.connection(endpoint.Address, endpoint.CertPool)
+	if err != nil {
+		return nil, err
+	}
+	return ab.NewAtomicBroadcastClient(conn), nil
+}
+
+// connection returns the connection to the address, dialing it unless it is
+// already established. The address is dialed without holding the lock, so that
+// an unreachable node only delays the requests sent to it, and only once at a
+// time. A connection which failed or was shut down is dialed again.
+func (r *registry) connection(address string, certPool *x509.CertPool) (*grpc.ClientConn, error) {
+	r.mutex.Lock()
+	if conn, ok := r.connections[address]; ok {
+		switch conn.GetState() {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			logger.Debugf("Dropping the connection to %s in state %s", address, conn.GetState())
+			delete(r.connections, address)
+			conn.Close()
+		default:
+			r.mutex.Unlock()
+			return conn, nil
+		}
+	}
+	if dial, ok := r.dials[address]; ok {
+		r.mutex.Unlock()
+		<-dial.done
+		return dial.conn, dial.err
+	}
+	dial := &pendingDial{done: make(chan struct{})}
+	r.dials[address] = dial
+	r.mutex.Unlock()
+
+	logger.Debugf("Connecting to %s", address)
+	dial.conn, dial.err = r.dial(address, certPool)
+
+	r.mutex.Lock()
+	delete(r.dials, address)
+	if dial.err == nil {
+		r.connections[address] = dial.conn
+	}
+	r.mutex.Unlock()
+	close(dial.done)
+
+	return dial.conn, dial.err
+}
@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"crypto/x509"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testDialer struct {
+	mutex   sync.Mutex
+	dialed  []string
+	blocked map[string]chan struct{}
+	err     error
+}
+
+func (d *testDialer) dial(address string, certPool *x509.CertPool) (*grpc.ClientConn, error) {
+	d.mutex.Lock()
+	d.dialed = append(d.dialed, address)
+	blocked, err := d.blocked[address], d.err
+	d.mutex.Unlock()
+
+	if blocked != nil {
+		<-blocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return grpc.Dial(address, grpc.WithInsecure())
+}
+
+func (d *testDialer) dialCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return len(d.dialed)
+}
+
+func newTestRegistry(d *testDialer) *registry {
+	r := newRegistry(nil)
+	r.dial = d.dial
+	return r
+}
+
+func TestRegistryConnection(t *testing.T) {
+	t.Run("reuses the connection", func(t *testing.T) {
+		d := &testDialer{}
+		r := newTestRegistry(d)
+
+		conn1, err := r.connection("peer1:7051", nil)
+		require.NoError(t, err)
+		defer conn1.Close()
+		conn2, err := r.connection("peer1:7051", nil)
+		require.NoError(t, err)
+		require.Same(t, conn1, conn2)
+		require.Equal(t, 1, d.dialCount())
+	})
+
+	t.Run("dials an address once at a time", func(t *testing.T) {
+		release := make(chan struct{})
+		d := &testDialer{blocked: map[string]chan struct{}{"peer1:7051": release}}
+		r := newTestRegistry(d)
+
+		conns := make(chan *grpc.ClientConn, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				conn, err := r.connection("peer1:7051", nil)
+				require.NoError(t, err)
+				conns <- conn
+			}()
+		}
+		require.Eventually(t, func() bool { return d.dialCount() == 1 }, time.Minute, 10*time.Millisecond)
+
+		// The blocked dial does not hold up the other addresses.
+		conn, err := r.connection("peer2:7051", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		close(release)
+		conn1, conn2 := <-conns, <-conns
+		defer conn1.Close()
+		require.Same(t, conn1, conn2)
+		require.Equal(t, 2, d.dialCount())
+	})
+
+	t.Run("dials again after a failure", func(t *testing.T) {
+		d := &testDialer{err: errors.New("connection refused")}
+		r := newTestRegistry(d)
+
+		_, err := r.connection("peer1:7051", nil)
+		require.EqualError(t, err, "connection refused")
+
+		d.err = nil
+		conn, err := r.connection("peer1:7051", nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		require.Equal(t, 2, d.dialCount())
+	})
+
+	t.Run("dials again once the connection is shut down", func(t *testing.T) {
+		d := &testDialer{}
+		r := newTestRegistry(d)
+
+		conn1, err := r.connection("peer1:7051", nil)
+		require.NoError(t, err)
+		conn1.Close()
+
+		conn2, err := r.connection("peer1:7051", nil)
+		require.NoError(t, err)
+		defer conn2.Close()
+		require.NotSame(t, conn1, conn2)
+		require.Equal(t, 2, d.dialCount())
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"math"
+	"time"
+)
+
+// arrivalSmoothing is the weight of the latest observation in the exponentially
+// weighted moving averages of the inter-arrival times and of the envelope sizes.
+const arrivalSmoothing = 0.2
+
+// AdaptiveConfig configures the adaptive block cutting mode, in which the
+// maximum message count of a batch is raised or lowered between MinMessageCount
+// and the BatchSize.MaxMessageCount of the channel, so that a batch fills up in
+// about TargetBlockInterval at the observed envelope arrival rate. The preferred
+// size in bytes of a batch follows, up to the BatchSize.PreferredMaxBytes of
+// the channel.
+type AdaptiveConfig struct {
+	MinMessageCount     uint32
+	TargetBlockInterval time.Duration
+}
+
+// adaptiveBatcher estimates the envelope arrival rate and derives the maximum
+// message count and the preferred size of the batches from it.
+type adaptiveBatcher struct {
+	config AdaptiveConfig
+	now    func() time.Time
+
+	lastArrival  time.Time
+	interArrival float64 // the moving average of the inter-arrival times, in seconds
+	observed     bool
+	size         float64 // the moving average of the envelope sizes, in bytes
+	chosen       uint32
+	chosenBytes  uint32
+}
+
+func newAdaptiveBatcher(config AdaptiveConfig) *adaptiveBatcher {
+	return &adaptiveBatcher{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// observe records the arrival of an envelope of the given size.
+func (a *adaptiveBatcher) observe(sizeBytes uint32) {
+	if a.lastArrival.IsZero() {
+		a.size = float64(sizeBytes)
+	} else {
+		a.size += arrivalSmoothing * (float64(sizeBytes) - a.size)
+	}
+
+	now := a.now()
+	if !a.lastArrival.IsZero() {
+		gap := now.Sub(a.lastArrival).Seconds()
+		if a.observed {
+			a.interArrival += arrivalSmoothing * (gap - a.interArrival)
+		} else {
+			a.interArrival = gap
+			a.observed = true
+		}
+	}
+	a.lastArrival = now
+}
+
+// arrivalRate returns the estimated arrival rate in envelopes per second, or
+// zero if it is not known yet.
+func (a *adaptiveBatcher) arrivalRate() float64 {
+	if !a.observed || a.interArrival <= 0 {
+		return 0
+	}
+	return 1 / a.interArrival
+}
+
+// maxMessageCount returns the maximum message count of the pending batch,
+// which is bounded by the maximum message count of the channel.
+func (a *adaptiveBatcher) maxMessageCount(upperBound uint32) uint32 {
+	lowerBound := a.config.MinMessageCount
+	if lowerBound == 0 {
+		lowerBound = 1
+	}
+	if lowerBound > upperBound {
+		lowerBound = upperBound
+	}
+
+	count := lowerBound
+	switch {
+	case !a.observed:
+		// Until the arrival rate is known, favor latency.
+	case a.interArrival <= 0:
+		count = upperBound
+	default:
+		target := a.config.TargetBlockInterval.Seconds() / a.interArrival
+		if target >= float64(upperBound) {
+			count = upperBound
+		} else if target > float64(lowerBound) {
+			count = uint32(target + 0.5)
+		}
+	}
+
+	a.chosen = count
+	return count
+}
+
+// preferredMaxBytes returns the preferred size in bytes of the pending batch,
+// i.e. the expected size of a batch of the chosen maximum message count, which
+// is bounded by the preferred size of the channel. It must be called after
+// maxMessageCount.
+func (a *adaptiveBatcher) preferredMaxBytes(maxMessageCount, upperBound uint32) uint32 {
+	bytes := upperBound
+	if a.chosen < maxMessageCount {
+		if expected := float64(a.chosen) * a.size; expected < float64(upperBound) {
+			bytes = uint32(math.Ceil(expected))
+		}
+	}
+	if bytes == 0 {
+		bytes = 1
+	}
+
+	a.chosenBytes = bytes
+	return bytes
+}
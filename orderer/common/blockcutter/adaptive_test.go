@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptiveBatcher(t *testing.T) {
+	now := time.Unix(0, 0)
+	a := newAdaptiveBatcher(AdaptiveConfig{
+		MinMessageCount:     10,
+		TargetBlockInterval: time.Second,
+	})
+	a.now = func() time.Time { return now }
+
+	// The rate is unknown until two envelopes arrived.
+	a.observe(100)
+	require.Equal(t, float64(0), a.arrivalRate())
+	require.Equal(t, uint32(10), a.maxMessageCount(500))
+
+	// 100 envelopes per second fill 100 envelopes in the target interval.
+	for i := 0; i < 20; i++ {
+		now = now.Add(10 * time.Millisecond)
+		a.observe(100)
+	}
+	require.InDelta(t, 100, a.arrivalRate(), 0.001)
+	require.Equal(t, uint32(100), a.maxMessageCount(500))
+	require.Equal(t, uint32(100), a.chosen)
+
+	// The upper bound is the max message count of the channel.
+	require.Equal(t, uint32(50), a.maxMessageCount(50))
+
+	// The batch size is lowered gradually as the rate drops.
+	now = now.Add(100 * time.Millisecond)
+	a.observe(100)
+	require.Equal(t, uint32(36), a.maxMessageCount(500))
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Second)
+		a.observe(100)
+	}
+	require.Equal(t, uint32(10), a.maxMessageCount(500))
+
+	// The lower bound does not exceed the upper bound.
+	require.Equal(t, uint32(5), a.maxMessageCount(5))
+}
+
+func TestAdaptiveBatcherSimultaneousArrivals(t *testing.T) {
+	now := time.Unix(0, 0)
+	a := newAdaptiveBatcher(AdaptiveConfig{TargetBlockInterval: time.Second})
+	a.now = func() time.Time { return now }
+
+	a.observe(100)
+	a.observe(100)
+	require.Equal(t, float64(0), a.arrivalRate())
+	require.Equal(t, uint32(500), a.maxMessageCount(500))
+}
+
+func TestAdaptiveBatcherPreferredMaxBytes(t *testing.T) {
+	now := time.Unix(0, 0)
+	a := newAdaptiveBatcher(AdaptiveConfig{
+		MinMessageCount:     10,
+		TargetBlockInterval: time.Second,
+	})
+	a.now = func() time.Time { return now }
+
+	// Until the rate is known, a batch of the min message count is expected.
+	a.observe(100)
+	require.Equal(t, uint32(10), a.maxMessageCount(500))
+	require.Equal(t, uint32(1000), a.preferredMaxBytes(500, 1<<20))
+	require.Equal(t, uint32(1000), a.chosenBytes)
+
+	// 100 envelopes per second of 100 bytes fill 10000 bytes in the target interval.
+	for i := 0; i < 20; i++ {
+		now = now.Add(10 * time.Millisecond)
+		a.observe(100)
+	}
+	require.Equal(t, uint32(100), a.maxMessageCount(500))
+	require.Equal(t, uint32(10000), a.preferredMaxBytes(500, 1<<20))
+
+	// The size follows the envelope sizes.
+	now = now.Add(10 * time.Millisecond)
+	a.observe(600)
+	require.Equal(t, uint32(100), a.maxMessageCount(500))
+	require.Equal(t, uint32(20000), a.preferredMaxBytes(500, 1<<20))
+
+	// The upper bound is the preferred size of the channel.
+	require.Equal(t, uint32(5000), a.preferredMaxBytes(500, 5000))
+
+	// At the max message count of the channel, the preferred size of the channel applies.
+	require.Equal(t, uint32(50), a.maxMessageCount(50))
+	require.Equal(t, uint32(1<<20), a.preferredMaxBytes(50, 1<<20))
+}
@@ -35,6 +35,7 @@ type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	adaptive              *adaptiveBatcher
 
 	PendingBatchStartTime time.Time
 	ChannelID             string
@@ -50,6 +51,18 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 	}
 }
 
+// NewAdaptiveReceiverImpl creates a Receiver implementation which cuts the batches in the adaptive mode,
+// i.e. with a maximum message count and a preferred size derived from the envelope arrival rate, instead of
+// BatchSize.MaxMessageCount and BatchSize.PreferredMaxBytes.
+func NewAdaptiveReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher, metrics *Metrics, config AdaptiveConfig) Receiver {
+	return &receiver{
+		sharedConfigFetcher: sharedConfigFetcher,
+		adaptive:            newAdaptiveBatcher(config),
+		Metrics:             metrics,
+		ChannelID:           channelID,
+	}
+}
+
 // Ordered should be invoked sequentially as messages are ordered
 //
 // messageBatches length: 0, pending: false
@@ -57,9 +70,10 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 // messageBatches length: 0, pending: true
 //   - no batch is cut and there are messages pending
 // messageBatches length: 1, pending: false
-//   - the message count reaches BatchSize.MaxMessageCount
+//   - the message count reaches BatchSize.MaxMessageCount, or the adaptive maximum message count
 // messageBatches length: 1, pending: true
-//   - the current message will cause the pending batch size in bytes to exceed BatchSize.PreferredMaxBytes.
+//   - the current message will cause the pending batch size in bytes to exceed BatchSize.PreferredMaxBytes,
+//     or the adaptive preferred size
 // messageBatches length: 2, pending: false
 //   - the current message size in bytes exceeds BatchSize.PreferredMaxBytes, therefore isolated in its own batch.
 // messageBatches length: 2, pending: true
@@ -80,6 +94,16 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	batchSize := ordererConfig.BatchSize()
 
 	messageSizeBytes := messageSizeBytes(msg)
+
+	maxMessageCount := batchSize.MaxMessageCount
+	preferredMaxBytes := batchSize.PreferredMaxBytes
+	if r.adaptive != nil {
+		r.adaptive.observe(messageSizeBytes)
+		maxMessageCount = r.adaptive.maxMessageCount(batchSize.MaxMessageCount)
+		preferredMaxBytes = r.adaptive.preferredMaxBytes(batchSize.MaxMessageCount, batchSize.PreferredMaxBytes)
+		r.Metrics.AdaptiveArrivalRate.With("channel", r.ChannelID).Set(r.adaptive.arrivalRate())
+	}
+
 	if messageSizeBytes > batchSize.PreferredMaxBytes {
 		logger.Debugf("The current message, with %v bytes, is larger than the preferred batch size of %v bytes and will be isolated.", messageSizeBytes, batchSize.PreferredMaxBytes)
 
@@ -98,7 +122,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 		return
 	}
 
-	messageWillOverflowBatchSizeBytes := r.pendingBatchSizeBytes+messageSizeBytes > batchSize.PreferredMaxBytes
+	messageWillOverflowBatchSizeBytes := r.pendingBatchSizeBytes+messageSizeBytes > preferredMaxBytes
 
 	if messageWillOverflowBatchSizeBytes {
 		logger.Debugf("The current message, with %v bytes, will overflow the pending batch of %v bytes.", messageSizeBytes, r.pendingBatchSizeBytes)
@@ -113,7 +137,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	r.pendingBatchSizeBytes += messageSizeBytes
 	pending = true
 
-	if uint32(len(r.pendingBatch)) >= batchSize.MaxMessageCount {
+	if uint32(len(r.pendingBatch)) >= maxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
 		messageBatch := r.Cut()
 		messageBatches = append(messageBatches, messageBatch)
@@ -127,6 +151,10 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 func (r *receiver) Cut() []*cb.Envelope {
 	if r.pendingBatch != nil {
 		r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(time.Since(r.PendingBatchStartTime).Seconds())
+		if r.adaptive != nil {
+			r.Metrics.AdaptiveMaxMessageCount.With("channel", r.ChannelID).Observe(float64(r.adaptive.chosen))
+			r.Metrics.AdaptivePreferredMaxBytes.With("channel", r.ChannelID).Observe(float64(r.adaptive.chosenBytes))
+		}
 	}
 	r.PendingBatchStartTime = time.Time{}
 	batch := r.pendingBatch
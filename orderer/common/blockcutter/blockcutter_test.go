@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter_test
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
@@ -184,6 +186,102 @@ var _ = Describe("Blockcutter", func() {
 		})
 	})
 
+	Describe("Adaptive", func() {
+		var (
+			message                       *cb.Envelope
+			fakeAdaptiveMaxMessageCount   *mock.MetricsHistogram
+			fakeAdaptivePreferredMaxBytes *mock.MetricsHistogram
+			fakeAdaptiveArrivalRate       *mock.MetricsGauge
+		)
+
+		BeforeEach(func() {
+			fakeAdaptiveMaxMessageCount = &mock.MetricsHistogram{}
+			fakeAdaptiveMaxMessageCount.WithReturns(fakeAdaptiveMaxMessageCount)
+			fakeAdaptivePreferredMaxBytes = &mock.MetricsHistogram{}
+			fakeAdaptivePreferredMaxBytes.WithReturns(fakeAdaptivePreferredMaxBytes)
+			fakeAdaptiveArrivalRate = &mock.MetricsGauge{}
+			fakeAdaptiveArrivalRate.WithReturns(fakeAdaptiveArrivalRate)
+			metrics.AdaptiveMaxMessageCount = fakeAdaptiveMaxMessageCount
+			metrics.AdaptivePreferredMaxBytes = fakeAdaptivePreferredMaxBytes
+			metrics.AdaptiveArrivalRate = fakeAdaptiveArrivalRate
+
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   3,
+				PreferredMaxBytes: 1000,
+			})
+
+			message = &cb.Envelope{Payload: []byte("Twenty Bytes of Data"), Signature: []byte("Twenty Bytes of Data")}
+		})
+
+		Context("when the arrival rate is high for the target block interval", func() {
+			BeforeEach(func() {
+				bc = blockcutter.NewAdaptiveReceiverImpl("mychannel", fakeConfigFetcher, metrics, blockcutter.AdaptiveConfig{
+					MinMessageCount:     2,
+					TargetBlockInterval: time.Hour,
+				})
+			})
+
+			It("cuts the batch at the max message count of the channel", func() {
+				for i := 0; i < 2; i++ {
+					batches, pending := bc.Ordered(message)
+					Expect(batches).To(BeEmpty())
+					Expect(pending).To(BeTrue())
+				}
+				batches, pending := bc.Ordered(message)
+				Expect(len(batches)).To(Equal(1))
+				Expect(len(batches[0])).To(Equal(3))
+				Expect(pending).To(BeFalse())
+
+				Expect(fakeAdaptiveMaxMessageCount.ObserveCallCount()).To(Equal(1))
+				Expect(fakeAdaptiveMaxMessageCount.ObserveArgsForCall(0)).To(Equal(float64(3)))
+				Expect(fakeAdaptivePreferredMaxBytes.ObserveCallCount()).To(Equal(1))
+				Expect(fakeAdaptivePreferredMaxBytes.ObserveArgsForCall(0)).To(Equal(float64(1000)))
+				Expect(fakeAdaptivePreferredMaxBytes.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+				Expect(fakeAdaptiveMaxMessageCount.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+				Expect(fakeAdaptiveArrivalRate.SetCallCount()).To(Equal(3))
+				Expect(fakeAdaptiveArrivalRate.SetArgsForCall(0)).To(Equal(float64(0)))
+				Expect(fakeAdaptiveArrivalRate.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+			})
+		})
+
+		Context("when the arrival rate is low for the target block interval", func() {
+			BeforeEach(func() {
+				bc = blockcutter.NewAdaptiveReceiverImpl("mychannel", fakeConfigFetcher, metrics, blockcutter.AdaptiveConfig{
+					MinMessageCount:     2,
+					TargetBlockInterval: time.Nanosecond,
+				})
+			})
+
+			It("cuts the batch at the min message count", func() {
+				batches, pending := bc.Ordered(message)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeTrue())
+				batches, pending = bc.Ordered(message)
+				Expect(len(batches)).To(Equal(1))
+				Expect(len(batches[0])).To(Equal(2))
+				Expect(pending).To(BeFalse())
+
+				Expect(fakeAdaptiveMaxMessageCount.ObserveCallCount()).To(Equal(1))
+				Expect(fakeAdaptiveMaxMessageCount.ObserveArgsForCall(0)).To(Equal(float64(2)))
+				Expect(fakeAdaptivePreferredMaxBytes.ObserveCallCount()).To(Equal(1))
+				Expect(fakeAdaptivePreferredMaxBytes.ObserveArgsForCall(0)).To(Equal(float64(80)))
+			})
+
+			It("cuts the batch at the expected size of a batch of the min message count", func() {
+				batches, pending := bc.Ordered(message)
+				Expect(batches).To(BeEmpty())
+				Expect(pending).To(BeTrue())
+
+				// The second message overflows the 2 messages of 40 bytes expected.
+				large := &cb.Envelope{Payload: []byte("Sixty Bytes of Data, Sixty Bytes of Data, Sixty Bytes of Da")}
+				batches, pending = bc.Ordered(large)
+				Expect(len(batches)).To(Equal(1))
+				Expect(batches[0]).To(Equal([]*cb.Envelope{message}))
+				Expect(pending).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("Cut", func() {
 		It("cuts an empty batch", func() {
 			batch := bc.Cut()
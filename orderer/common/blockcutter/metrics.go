@@ -16,14 +16,43 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+	adaptiveMaxMessageCount = metrics.HistogramOpts{
+		Namespace:    "blockcutter",
+		Name:         "adaptive_max_message_count",
+		Help:         "The maximum message count chosen by the adaptive block cutting for the batches that were cut.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+		Buckets:      []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000},
+	}
+	adaptivePreferredMaxBytes = metrics.HistogramOpts{
+		Namespace:    "blockcutter",
+		Name:         "adaptive_preferred_max_bytes",
+		Help:         "The preferred size in bytes chosen by the adaptive block cutting for the batches that were cut.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+		Buckets:      []float64{1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864},
+	}
+	adaptiveArrivalRate = metrics.GaugeOpts{
+		Namespace:    "blockcutter",
+		Name:         "adaptive_arrival_rate",
+		Help:         "The envelope arrival rate per second estimated by the adaptive block cutting.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 type Metrics struct {
-	BlockFillDuration metrics.Histogram
+	BlockFillDuration         metrics.Histogram
+	AdaptiveMaxMessageCount   metrics.Histogram
+	AdaptivePreferredMaxBytes metrics.Histogram
+	AdaptiveArrivalRate       metrics.Gauge
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
 	return &Metrics{
-		BlockFillDuration: p.NewHistogram(blockFillDuration),
+		BlockFillDuration:         p.NewHistogram(blockFillDuration),
+		AdaptiveMaxMessageCount:   p.NewHistogram(adaptiveMaxMessageCount),
+		AdaptivePreferredMaxBytes: p.NewHistogram(adaptivePreferredMaxBytes),
+		AdaptiveArrivalRate:       p.NewGauge(adaptiveArrivalRate),
 	}
 }
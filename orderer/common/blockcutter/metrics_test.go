@@ -23,6 +23,7 @@ var _ = Describe("Metrics", func() {
 		BeforeEach(func() {
 			fakeProvider = &mock.MetricsProvider{}
 			fakeProvider.NewHistogramReturns(&mock.MetricsHistogram{})
+			fakeProvider.NewGaugeReturns(&mock.MetricsGauge{})
 		})
 
 		It("uses the provider to initialize its field", func() {
@@ -30,7 +31,11 @@ var _ = Describe("Metrics", func() {
 			Expect(metrics).NotTo(BeNil())
 			Expect(metrics.BlockFillDuration).To(Equal(&mock.MetricsHistogram{}))
 
-			Expect(fakeProvider.NewHistogramCallCount()).To(Equal(1))
+			Expect(metrics.AdaptiveMaxMessageCount).To(Equal(&mock.MetricsHistogram{}))
+			Expect(metrics.AdaptivePreferredMaxBytes).To(Equal(&mock.MetricsHistogram{}))
+			Expect(metrics.AdaptiveArrivalRate).To(Equal(&mock.MetricsGauge{}))
+			Expect(fakeProvider.NewHistogramCallCount()).To(Equal(3))
+			Expect(fakeProvider.NewGaugeCallCount()).To(Equal(1))
 		})
 	})
 })
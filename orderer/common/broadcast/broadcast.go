@@ -11,10 +11,12 @@ import (
 	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +62,8 @@ type Consenter interface {
 type Handler struct {
 	SupportRegistrar ChannelSupportRegistrar
 	Metrics          *Metrics
+	// RateLimiter, if not nil, limits the rate at which the messages of the clients are accepted
+	RateLimiter RateLimiter
 }
 
 // Handle reads requests from a Broadcast stream, processes them, and returns the responses to the stream
@@ -97,6 +101,8 @@ type MetricsTracker struct {
 	ValidateDuration  time.Duration
 	ChannelID         string
 	TxType            string
+	MSPID             string
+	Throttled         bool
 	Metrics           *Metrics
 }
 
@@ -118,6 +124,14 @@ func (mt *MetricsTracker) Record(resp *ab.BroadcastResponse) {
 	}
 
 	mt.Metrics.ProcessedCount.With(labels...).Add(1)
+
+	if mt.Throttled {
+		mt.Metrics.ThrottledCount.With(
+			"channel", mt.ChannelID,
+			"type", mt.TxType,
+			"mspid", mt.MSPID,
+		).Add(1)
+	}
 }
 
 func (mt *MetricsTracker) BeginValidate() {
@@ -167,6 +181,10 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		}
 		tracker.EndValidate()
 
+		if rejection := bh.checkRateLimit(msg, chdr, tracker, addr); rejection != nil {
+			return rejection
+		}
+
 		tracker.BeginEnqueue()
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
@@ -188,6 +206,10 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		}
 		tracker.EndValidate()
 
+		if rejection := bh.checkRateLimit(msg, chdr, tracker, addr); rejection != nil {
+			return rejection
+		}
+
 		tracker.BeginEnqueue()
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
@@ -206,6 +228,43 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
 
+// checkRateLimit returns a response rejecting the message if its creator is over the rate limit, or nil otherwise.
+// It is invoked after the message is validated, so that a client cannot consume the quota of another identity.
+func (bh *Handler) checkRateLimit(msg *cb.Envelope, chdr *cb.ChannelHeader, tracker *MetricsTracker, addr string) *ab.BroadcastResponse {
+	if bh.RateLimiter == nil {
+		return nil
+	}
+
+	creator, err := messageCreator(msg)
+	if err != nil {
+		logger.Warningf("[channel: %s] Rejecting broadcast of message from %s because of error: %s", chdr.ChannelId, addr, err)
+		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
+	}
+	tracker.MSPID = creator.Mspid
+
+	if !bh.RateLimiter.Allow(chdr.ChannelId, creator) {
+		tracker.Throttled = true
+		logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: client of MSP %s exceeded the rate limit", chdr.ChannelId, addr, creator.Mspid)
+		return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "rate limit exceeded"}
+	}
+	return nil
+}
+
+func messageCreator(msg *cb.Envelope) (*msp.SerializedIdentity, error) {
+	payload, err := protoutil.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing header in the message payload")
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return protoutil.UnmarshalSerializedIdentity(shdr.Creator)
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -16,10 +16,13 @@ import (
 	. "github.com/onsi/gomega"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/broadcast/mock"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 )
 
 var _ = Describe("Broadcast", func() {
@@ -29,6 +32,7 @@ var _ = Describe("Broadcast", func() {
 		fakeValidateHistogram *mock.MetricsHistogram
 		fakeEnqueueHistogram  *mock.MetricsHistogram
 		fakeProcessedCounter  *mock.MetricsCounter
+		fakeThrottledCounter  *mock.MetricsCounter
 	)
 
 	BeforeEach(func() {
@@ -43,12 +47,16 @@ var _ = Describe("Broadcast", func() {
 		fakeProcessedCounter = &mock.MetricsCounter{}
 		fakeProcessedCounter.WithReturns(fakeProcessedCounter)
 
+		fakeThrottledCounter = &mock.MetricsCounter{}
+		fakeThrottledCounter.WithReturns(fakeThrottledCounter)
+
 		handler = &broadcast.Handler{
 			SupportRegistrar: fakeSupportRegistrar,
 			Metrics: &broadcast.Metrics{
 				ValidateDuration: fakeValidateHistogram,
 				EnqueueDuration:  fakeEnqueueHistogram,
 				ProcessedCount:   fakeProcessedCounter,
+				ThrottledCount:   fakeThrottledCounter,
 			},
 		}
 	})
@@ -274,6 +282,132 @@ var _ = Describe("Broadcast", func() {
 					)).To(BeTrue())
 				})
 			})
+
+			Context("when the error cause is msgprocessor.ErrDuplicateTxID", func() {
+				BeforeEach(func() {
+					fakeSupport.ProcessNormalMsgReturns(0, errors.WithMessage(msgprocessor.ErrDuplicateTxID, "transaction tx1 was already ordered in block [4]"))
+				})
+
+				It("returns the error and a bad request status", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeABServer.SendCallCount()).To(Equal(1))
+					Expect(proto.Equal(
+						fakeABServer.SendArgsForCall(0),
+						&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: "transaction tx1 was already ordered in block [4]: duplicate transaction ID"},
+					)).To(BeTrue())
+				})
+			})
+		})
+
+		Context("when a rate limiter is configured", func() {
+			var fakeRateLimiter *mock.RateLimiter
+
+			BeforeEach(func() {
+				fakeMsg = &cb.Envelope{
+					Payload: protoutil.MarshalOrPanic(&cb.Payload{
+						Header: &cb.Header{
+							SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{
+								Creator: protoutil.MarshalOrPanic(&msp.SerializedIdentity{
+									Mspid:   "fake-msp",
+									IdBytes: []byte("fake-cert"),
+								}),
+							}),
+						},
+					}),
+				}
+				fakeABServer.RecvReturns(fakeMsg, nil)
+
+				fakeRateLimiter = &mock.RateLimiter{}
+				fakeRateLimiter.AllowReturns(true)
+				handler.RateLimiter = fakeRateLimiter
+			})
+
+			It("enqueues the message of a client within the limit", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeRateLimiter.AllowCallCount()).To(Equal(1))
+				channelID, client := fakeRateLimiter.AllowArgsForCall(0)
+				Expect(channelID).To(Equal("fake-channel"))
+				Expect(proto.Equal(client, &msp.SerializedIdentity{Mspid: "fake-msp", IdBytes: []byte("fake-cert")})).To(BeTrue())
+
+				Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+				Expect(fakeThrottledCounter.AddCallCount()).To(Equal(0))
+				Expect(proto.Equal(fakeABServer.SendArgsForCall(0), &ab.BroadcastResponse{Status: cb.Status_SUCCESS})).To(BeTrue())
+			})
+
+			Context("when the client exceeds the limit", func() {
+				BeforeEach(func() {
+					fakeRateLimiter.AllowReturns(false)
+				})
+
+				It("rejects the message with a service unavailable status", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(1))
+					Expect(fakeSupport.WaitReadyCallCount()).To(Equal(0))
+					Expect(fakeSupport.OrderCallCount()).To(Equal(0))
+
+					Expect(fakeABServer.SendCallCount()).To(Equal(1))
+					Expect(proto.Equal(
+						fakeABServer.SendArgsForCall(0),
+						&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "rate limit exceeded"}),
+					).To(BeTrue())
+
+					Expect(fakeProcessedCounter.WithArgsForCall(0)).To(Equal([]string{
+						"status", "SERVICE_UNAVAILABLE",
+						"channel", "fake-channel",
+						"type", "ENDORSER_TRANSACTION",
+					}))
+					Expect(fakeThrottledCounter.WithCallCount()).To(Equal(1))
+					Expect(fakeThrottledCounter.WithArgsForCall(0)).To(Equal([]string{
+						"channel", "fake-channel",
+						"type", "ENDORSER_TRANSACTION",
+						"mspid", "fake-msp",
+					}))
+					Expect(fakeThrottledCounter.AddCallCount()).To(Equal(1))
+					Expect(fakeThrottledCounter.AddArgsForCall(0)).To(Equal(float64(1)))
+				})
+			})
+
+			Context("when the message is a config message", func() {
+				BeforeEach(func() {
+					fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
+						Type:      1,
+						ChannelId: "fake-channel",
+					}, true, fakeSupport, nil)
+					fakeSupport.ProcessConfigUpdateMsgReturns(&cb.Envelope{}, 3, nil)
+					fakeRateLimiter.AllowReturns(false)
+				})
+
+				It("rejects the message of a client exceeding the limit", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ConfigureCallCount()).To(Equal(0))
+					Expect(proto.Equal(
+						fakeABServer.SendArgsForCall(0),
+						&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: "rate limit exceeded"}),
+					).To(BeTrue())
+				})
+			})
+
+			Context("when the creator of the message cannot be extracted", func() {
+				BeforeEach(func() {
+					fakeABServer.RecvReturns(&cb.Envelope{Payload: []byte("garbage")}, nil)
+				})
+
+				It("rejects the message with a bad request status", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeRateLimiter.AllowCallCount()).To(Equal(0))
+					Expect(fakeABServer.SendArgsForCall(0).Status).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
 		})
 
 		Context("when the message is a config message", func() {
@@ -30,12 +30,20 @@ var (
 		LabelNames:   []string{"channel", "type", "status"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{type}.%{status}",
 	}
+	throttledCount = metrics.CounterOpts{
+		Namespace:    "broadcast",
+		Name:         "throttled_count",
+		Help:         "The number of transactions rejected by the rate limit.",
+		LabelNames:   []string{"channel", "type", "mspid"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{type}.%{mspid}",
+	}
 )
 
 type Metrics struct {
 	ValidateDuration metrics.Histogram
 	EnqueueDuration  metrics.Histogram
 	ProcessedCount   metrics.Counter
+	ThrottledCount   metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -43,5 +51,6 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		ValidateDuration: p.NewHistogram(validateDuration),
 		EnqueueDuration:  p.NewHistogram(enqueueDuration),
 		ProcessedCount:   p.NewCounter(processedCount),
+		ThrottledCount:   p.NewCounter(throttledCount),
 	}
 }
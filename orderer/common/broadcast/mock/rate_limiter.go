@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+)
+
+type RateLimiter struct {
+	AllowStub        func(string, *msp.SerializedIdentity) bool
+	allowMutex       sync.RWMutex
+	allowArgsForCall []struct {
+		arg1 string
+		arg2 *msp.SerializedIdentity
+	}
+	allowReturns struct {
+		result1 bool
+	}
+	allowReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *RateLimiter) Allow(arg1 string, arg2 *msp.SerializedIdentity) bool {
+	fake.allowMutex.Lock()
+	ret, specificReturn := fake.allowReturnsOnCall[len(fake.allowArgsForCall)]
+	fake.allowArgsForCall = append(fake.allowArgsForCall, struct {
+		arg1 string
+		arg2 *msp.SerializedIdentity
+	}{arg1, arg2})
+	fake.recordInvocation("Allow", []interface{}{arg1, arg2})
+	fake.allowMutex.Unlock()
+	if fake.AllowStub != nil {
+		return fake.AllowStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.allowReturns
+	return fakeReturns.result1
+}
+
+func (fake *RateLimiter) AllowCallCount() int {
+	fake.allowMutex.RLock()
+	defer fake.allowMutex.RUnlock()
+	return len(fake.allowArgsForCall)
+}
+
+func (fake *RateLimiter) AllowCalls(stub func(string, *msp.SerializedIdentity) bool) {
+	fake.allowMutex.Lock()
+	defer fake.allowMutex.Unlock()
+	fake.AllowStub = stub
+}
+
+func (fake *RateLimiter) AllowArgsForCall(i int) (string, *msp.SerializedIdentity) {
+	fake.allowMutex.RLock()
+	defer fake.allowMutex.RUnlock()
+	argsForCall := fake.allowArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *RateLimiter) AllowReturns(result1 bool) {
+	fake.allowMutex.Lock()
+	defer fake.allowMutex.Unlock()
+	fake.AllowStub = nil
+	fake.allowReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *RateLimiter) AllowReturnsOnCall(i int, result1 bool) {
+	fake.allowMutex.Lock()
+	defer fake.allowMutex.Unlock()
+	fake.AllowStub = nil
+	if fake.allowReturnsOnCall == nil {
+		fake.allowReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.allowReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *RateLimiter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.allowMutex.RLock()
+	defer fake.allowMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *RateLimiter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ broadcast.RateLimiter = new(RateLimiter)
@@ -0,0 +1,174 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"crypto/sha256"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+)
+
+// bucketSweepInterval is the interval at which the buckets that are full again are discarded,
+// so that the buckets of the clients that stopped sending messages do not accumulate
+const bucketSweepInterval = time.Minute
+
+//go:generate counterfeiter -o mock/rate_limiter.go --fake-name RateLimiter . RateLimiter
+
+// RateLimiter limits the rate at which the messages of the clients are accepted
+type RateLimiter interface {
+	// Allow returns true if a message of the client may be accepted on the channel, in which
+	// case the message counts against the quotas of the client and of the channel
+	Allow(channelID string, client *msp.SerializedIdentity) bool
+}
+
+// TokenBucketRateLimiter is a RateLimiter that keeps a token bucket for each client identity on each
+// channel and a token bucket for each channel. A message is accepted only if both the buckets hold a token
+type TokenBucketRateLimiter struct {
+	clientLimit          tokenBucketLimit
+	clientLimitOverrides map[string]tokenBucketLimit
+	channelLimit         tokenBucketLimit
+	now                  func() time.Time
+
+	mutex          sync.Mutex
+	clientBuckets  map[clientBucketKey]*tokenBucket
+	channelBuckets map[string]*tokenBucket
+	lastSweep      time.Time
+}
+
+type clientBucketKey struct {
+	channelID string
+	mspID     string
+	idHash    [sha256.Size]byte
+}
+
+// NewTokenBucketRateLimiter constructs a TokenBucketRateLimiter as per the given configuration
+func NewTokenBucketRateLimiter(conf localconfig.BroadcastRateLimit) *TokenBucketRateLimiter {
+	r := &TokenBucketRateLimiter{
+		clientLimit:          newTokenBucketLimit(conf.Client),
+		clientLimitOverrides: map[string]tokenBucketLimit{},
+		channelLimit:         newTokenBucketLimit(conf.Channel),
+		now:                  time.Now,
+		clientBuckets:        map[clientBucketKey]*tokenBucket{},
+		channelBuckets:       map[string]*tokenBucket{},
+	}
+	for mspID, override := range conf.ClientOverrides {
+		r.clientLimitOverrides[mspID] = newTokenBucketLimit(override)
+	}
+	return r
+}
+
+// Allow implements the function in the interface `RateLimiter`
+func (r *TokenBucketRateLimiter) Allow(channelID string, client *msp.SerializedIdentity) bool {
+	clientLimit, ok := r.clientLimitOverrides[client.Mspid]
+	if !ok {
+		clientLimit = r.clientLimit
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := r.now()
+	r.sweep(now)
+
+	var clientBucket, channelBucket *tokenBucket
+	if clientLimit.enabled() {
+		key := clientBucketKey{
+			channelID: channelID,
+			mspID:     client.Mspid,
+			idHash:    sha256.Sum256(client.IdBytes),
+		}
+		if clientBucket = r.clientBuckets[key]; clientBucket == nil {
+			clientBucket = newTokenBucket(clientLimit, now)
+			r.clientBuckets[key] = clientBucket
+		}
+		clientBucket.refill(now)
+	}
+	if r.channelLimit.enabled() {
+		if channelBucket = r.channelBuckets[channelID]; channelBucket == nil {
+			channelBucket = newTokenBucket(r.channelLimit, now)
+			r.channelBuckets[channelID] = channelBucket
+		}
+		channelBucket.refill(now)
+	}
+
+	// a token is taken only when both the buckets hold one, so that the messages
+	// rejected for the channel do not count against the quota of the client
+	if clientBucket != nil && clientBucket.tokens < 1 || channelBucket != nil && channelBucket.tokens < 1 {
+		return false
+	}
+	if clientBucket != nil {
+		clientBucket.tokens--
+	}
+	if channelBucket != nil {
+		channelBucket.tokens--
+	}
+	return true
+}
+
+// sweep discards the buckets that are full, as a full bucket is equivalent to a new one
+func (r *TokenBucketRateLimiter) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < bucketSweepInterval {
+		return
+	}
+	r.lastSweep = now
+	for key, bucket := range r.clientBuckets {
+		if bucket.refill(now); bucket.full() {
+			delete(r.clientBuckets, key)
+		}
+	}
+	for key, bucket := range r.channelBuckets {
+		if bucket.refill(now); bucket.full() {
+			delete(r.channelBuckets, key)
+		}
+	}
+}
+
+type tokenBucketLimit struct {
+	rate  float64 // tokens per second
+	burst float64
+}
+
+func newTokenBucketLimit(conf localconfig.TokenBucket) tokenBucketLimit {
+	l := tokenBucketLimit{rate: conf.Rate, burst: float64(conf.Burst)}
+	if l.burst < 1 {
+		// without a burst, the bucket holds the tokens of a second, but at least one token
+		l.burst = math.Max(1, math.Ceil(l.rate))
+	}
+	return l
+}
+
+func (l tokenBucketLimit) enabled() bool {
+	return l.rate > 0
+}
+
+type tokenBucket struct {
+	limit      tokenBucketLimit
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTokenBucket(limit tokenBucketLimit, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		limit:      limit,
+		tokens:     limit.burst,
+		lastRefill: now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens = math.Min(b.limit.burst, b.tokens+elapsed.Seconds()*b.limit.rate)
+		b.lastRefill = now
+	}
+}
+
+func (b *tokenBucket) full() bool {
+	return b.tokens >= b.limit.burst
+}
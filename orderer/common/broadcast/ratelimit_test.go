@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TokenBucketRateLimiter", func() {
+	var (
+		conf        localconfig.BroadcastRateLimit
+		rateLimiter *TokenBucketRateLimiter
+		now         time.Time
+		client1     *msp.SerializedIdentity
+		client2     *msp.SerializedIdentity
+		admin       *msp.SerializedIdentity
+	)
+
+	BeforeEach(func() {
+		conf = localconfig.BroadcastRateLimit{
+			Enabled: true,
+			Client:  localconfig.TokenBucket{Rate: 1, Burst: 2},
+		}
+		now = time.Unix(1000, 0)
+		client1 = &msp.SerializedIdentity{Mspid: "org1", IdBytes: []byte("client-1")}
+		client2 = &msp.SerializedIdentity{Mspid: "org1", IdBytes: []byte("client-2")}
+		admin = &msp.SerializedIdentity{Mspid: "ordererorg", IdBytes: []byte("admin")}
+	})
+
+	JustBeforeEach(func() {
+		rateLimiter = NewTokenBucketRateLimiter(conf)
+		rateLimiter.now = func() time.Time { return now }
+	})
+
+	It("allows a burst of messages and refills the tokens over time", func() {
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+
+		now = now.Add(500 * time.Millisecond)
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+		now = now.Add(500 * time.Millisecond)
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+
+		// the tokens do not accumulate beyond the burst
+		now = now.Add(time.Hour)
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+	})
+
+	It("keeps a bucket for each client on each channel", func() {
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+
+		Expect(rateLimiter.Allow("channel1", client2)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel2", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", &msp.SerializedIdentity{Mspid: "org2", IdBytes: []byte("client-1")})).To(BeTrue())
+	})
+
+	Context("when the limit of an MSP is overridden", func() {
+		BeforeEach(func() {
+			conf.ClientOverrides = map[string]localconfig.TokenBucket{
+				"ordererorg": {Rate: 10},
+			}
+		})
+
+		It("applies the override to the clients of the MSP", func() {
+			for i := 0; i < 10; i++ {
+				Expect(rateLimiter.Allow("channel1", admin)).To(BeTrue())
+			}
+			Expect(rateLimiter.Allow("channel1", admin)).To(BeFalse())
+
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+		})
+	})
+
+	Context("when the client limit is disabled", func() {
+		BeforeEach(func() {
+			conf.Client = localconfig.TokenBucket{}
+		})
+
+		It("allows all the messages", func() {
+			for i := 0; i < 100; i++ {
+				Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+			}
+		})
+	})
+
+	Context("when the channel is limited", func() {
+		BeforeEach(func() {
+			conf.Channel = localconfig.TokenBucket{Rate: 3}
+		})
+
+		It("limits the messages of all the clients on the channel", func() {
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+			Expect(rateLimiter.Allow("channel1", client2)).To(BeTrue())
+			Expect(rateLimiter.Allow("channel1", client2)).To(BeTrue())
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeFalse())
+			Expect(rateLimiter.Allow("channel2", client1)).To(BeTrue())
+
+			// the message rejected for the channel did not take a token of the client
+			now = now.Add(time.Second)
+			Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		})
+	})
+
+	It("discards the buckets that are full again", func() {
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.Allow("channel1", client2)).To(BeTrue())
+		Expect(rateLimiter.clientBuckets).To(HaveLen(2))
+
+		now = now.Add(bucketSweepInterval)
+		Expect(rateLimiter.Allow("channel1", client1)).To(BeTrue())
+		Expect(rateLimiter.clientBuckets).To(HaveLen(1))
+	})
+})
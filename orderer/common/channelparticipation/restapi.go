@@ -8,12 +8,14 @@ package channelparticipation
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"mime"
 	"mime/multipart"
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/mux"
@@ -28,12 +30,25 @@ import (
 const (
 	URLBaseV1              = "/participation/v1/"
 	URLBaseV1Channels      = URLBaseV1 + "channels"
+	URLBaseV1BulkChannels  = URLBaseV1 + "bulk/channels"
 	FormDataConfigBlockKey = "config-block"
 
+	// The types of the server-sent events streamed by the events endpoint of a channel.
+	EventTypeChannel = "channel"
+	EventTypeError   = "error"
+
 	channelIDKey        = "channelID"
 	urlWithChannelIDKey = URLBaseV1Channels + "/{" + channelIDKey + "}"
+	urlWithLeaderSuffix = urlWithChannelIDKey + "/leader"
+	urlWithEventsSuffix = urlWithChannelIDKey + "/events"
+
+	// The maximal number of config blocks in a bulk join request.
+	maxBulkJoinBlocks = 1000
 )
 
+// The interval at which the channel info is polled, while streaming the events of a channel.
+var eventsPollInterval = 500 * time.Millisecond
+
 //go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
 
 type ChannelManagement interface {
@@ -52,6 +67,10 @@ type ChannelManagement interface {
 
 	// RemoveChannel instructs the orderer to remove a channel.
 	RemoveChannel(channelID string) error
+
+	// TransferLeadership instructs the orderer to transfer the leadership of the consensus cluster of a channel
+	// to the given consenter. It returns the ID of the new leader.
+	TransferLeadership(channelID string, transferee uint64) (uint64, error)
 }
 
 // HTTPHandler handles all the HTTP requests to the channel participation API.
@@ -70,6 +89,19 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 		router:    mux.NewRouter(),
 	}
 
+	handler.router.HandleFunc(urlWithLeaderSuffix, handler.serveTransferLeadership).Methods(http.MethodPost).HeadersRegexp(
+		"Content-Type", "application/json*")
+	handler.router.HandleFunc(urlWithLeaderSuffix, handler.serveBadContentType).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlWithLeaderSuffix, handler.serveNotAllowed)
+
+	handler.router.HandleFunc(urlWithEventsSuffix, handler.serveChannelEvents).Methods(http.MethodGet)
+	handler.router.HandleFunc(urlWithEventsSuffix, handler.serveNotAllowed)
+
+	handler.router.HandleFunc(URLBaseV1BulkChannels, handler.serveBulkJoin).Methods(http.MethodPost).HeadersRegexp(
+		"Content-Type", "multipart/form-data*")
+	handler.router.HandleFunc(URLBaseV1BulkChannels, handler.serveBadContentType).Methods(http.MethodPost)
+	handler.router.HandleFunc(URLBaseV1BulkChannels, handler.serveNotAllowed)
+
 	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveListOne).Methods(http.MethodGet)
 
 	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveRemove).Methods(http.MethodDelete)
@@ -181,11 +213,126 @@ func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
 	h.sendResponseCreated(resp, info.URL, info)
 }
 
+// Join several channels.
+// Expect multipart/form-data, with a part for each config block.
+// The blocks are validated before any channel is joined. The channels are then joined one at a time, and the result
+// of each join is reported as if the channel was joined on its own.
+func (h *HTTPHandler) serveBulkJoin(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot parse Mime media type"))
+		return
+	}
+
+	blocks := h.multipartFormDataBodyToBlocks(params, req, resp)
+	if blocks == nil {
+		return
+	}
+
+	channelIDs := make([]string, len(blocks))
+	isAppChannels := make([]bool, len(blocks))
+	for i, block := range blocks {
+		channelIDs[i], isAppChannels[i], err = ValidateJoinBlock(block)
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessagef(err, "invalid join block in file part %d", i))
+			return
+		}
+	}
+
+	bulkResp := &types.BulkJoinResponse{}
+	for i, block := range blocks {
+		result := types.BulkJoinResult{Name: channelIDs[i]}
+		info, err := h.registrar.JoinChannel(channelIDs[i], block, isAppChannels[i])
+		if err != nil {
+			h.logger.Debugf("Failed to JoinChannel %s: %s", channelIDs[i], err)
+			result.StatusCode = joinErrorStatusCode(err)
+			result.Error = errors.WithMessage(err, "cannot join").Error()
+		} else {
+			info.URL = path.Join(URLBaseV1Channels, info.Name)
+			result.StatusCode = http.StatusCreated
+			result.Info = &info
+		}
+		bulkResp.Channels = append(bulkResp.Channels, result)
+	}
+
+	h.logger.Debugf("Joined %d channels in bulk", len(blocks))
+	h.sendResponseOK(resp, bulkResp)
+}
+
 // Expect a multipart/form-data with a single part, of type file, with key FormDataConfigBlockKey.
 func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req *http.Request, resp http.ResponseWriter) *cb.Block {
+	form := h.multipartFormDataBodyToForm(params, req, resp, int64(h.config.MaxRequestBodySize))
+	if form == nil {
+		return nil
+	}
+	defer form.RemoveAll()
+
+	if len(form.File) != 1 || len(form.Value) != 0 || len(form.File[FormDataConfigBlockKey]) != 1 {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("form contains too many parts"))
+		return nil
+	}
+
+	block, err := h.fileHeaderToBlock(form.File[FormDataConfigBlockKey][0])
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+		return nil
+	}
+
+	return block
+}
+
+// Expect a multipart/form-data with one or more parts, of type file, all with key FormDataConfigBlockKey.
+func (h *HTTPHandler) multipartFormDataBodyToBlocks(params map[string]string, req *http.Request, resp http.ResponseWriter) []*cb.Block {
+	// The body is capped as a whole, since all the blocks are held in memory
+	// until they are validated.
+	maxBodySize := h.config.MaxBulkRequestBodySize
+	if maxBodySize == 0 {
+		maxBodySize = h.config.MaxRequestBodySize
+	}
+	form := h.multipartFormDataBodyToForm(params, req, resp, int64(maxBodySize))
+	if form == nil {
+		return nil
+	}
+	defer form.RemoveAll()
+
+	if len(form.File) != 1 || len(form.Value) != 0 {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("form contains parts with keys other than "+FormDataConfigBlockKey))
+		return nil
+	}
+
+	fileHeaders := form.File[FormDataConfigBlockKey]
+	if len(fileHeaders) > maxBulkJoinBlocks {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("form contains %d parts, more than the maximum of %d", len(fileHeaders), maxBulkJoinBlocks))
+		return nil
+	}
+
+	var blocks []*cb.Block
+	for i, fileHeader := range fileHeaders {
+		if fileHeader.Size > int64(h.config.MaxRequestBodySize) {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("file part %d is larger than the maximal request body size", i))
+			return nil
+		}
+		block, err := h.fileHeaderToBlock(fileHeader)
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessagef(err, "file part %d", i))
+			return nil
+		}
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+func (h *HTTPHandler) multipartFormDataBodyToForm(params map[string]string, req *http.Request, resp http.ResponseWriter, maxBodySize int64) *multipart.Form {
 	boundary := params["boundary"]
 	reader := multipart.NewReader(
-		http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)),
+		http.MaxBytesReader(resp, req.Body, maxBodySize),
 		boundary,
 	)
 	form, err := reader.ReadForm(2 * int64(h.config.MaxRequestBodySize))
@@ -195,37 +342,34 @@ func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req
 	}
 
 	if _, exist := form.File[FormDataConfigBlockKey]; !exist {
+		form.RemoveAll()
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("form does not contains part key: %s", FormDataConfigBlockKey))
 		return nil
 	}
 
-	if len(form.File) != 1 || len(form.Value) != 0 {
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("form contains too many parts"))
-		return nil
-	}
+	return form
+}
 
-	fileHeader := form.File[FormDataConfigBlockKey][0]
+func (h *HTTPHandler) fileHeaderToBlock(fileHeader *multipart.FileHeader) (*cb.Block, error) {
 	file, err := fileHeader.Open()
 	if err != nil {
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot open file part %s from request body", FormDataConfigBlockKey))
-		return nil
+		return nil, errors.Wrapf(err, "cannot open file part %s from request body", FormDataConfigBlockKey)
 	}
+	defer file.Close()
 
 	blockBytes, err := ioutil.ReadAll(file)
 	if err != nil {
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot read file part %s from request body", FormDataConfigBlockKey))
-		return nil
+		return nil, errors.Wrapf(err, "cannot read file part %s from request body", FormDataConfigBlockKey)
 	}
 
 	block := &cb.Block{}
 	err = proto.Unmarshal(blockBytes, block)
 	if err != nil {
 		h.logger.Debugf("Failed to unmarshal blockBytes: %s", err)
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot unmarshal file part %s into a block", FormDataConfigBlockKey))
-		return nil
+		return nil, errors.Wrapf(err, "cannot unmarshal file part %s into a block", FormDataConfigBlockKey)
 	}
 
-	return block
+	return block, nil
 }
 
 func (h *HTTPHandler) extractChannelID(req *http.Request, resp http.ResponseWriter) (string, error) {
@@ -254,16 +398,25 @@ func (h *HTTPHandler) sendJoinError(err error, resp http.ResponseWriter) {
 		// The client is trying to join an app-channel that exists, but the system channel does not;
 		// The client is trying to join the system-channel, and it exists. GET & DELETE are allowed on the channel.
 		h.sendResponseNotAllowed(resp, errors.WithMessage(err, "cannot join"), http.MethodGet, http.MethodDelete)
+	default:
+		h.sendResponseJsonError(resp, joinErrorStatusCode(err), errors.WithMessage(err, "cannot join"))
+	}
+}
+
+func joinErrorStatusCode(err error) int {
+	switch err {
+	case types.ErrSystemChannelExists, types.ErrChannelAlreadyExists:
+		return http.StatusMethodNotAllowed
 	case types.ErrAppChannelsAlreadyExists:
 		// The client is trying to join the system-channel that does not exist, but app channels exist.
-		h.sendResponseJsonError(resp, http.StatusForbidden, errors.WithMessage(err, "cannot join"))
+		return http.StatusForbidden
 	case types.ErrChannelPendingRemoval:
 		// The client is trying to join a channel that is currently being removed.
-		h.sendResponseJsonError(resp, http.StatusConflict, errors.WithMessage(err, "cannot join"))
+		return http.StatusConflict
 	case types.ErrChannelRemovalFailure:
-		h.sendResponseJsonError(resp, http.StatusInternalServerError, errors.WithMessage(err, "cannot join"))
+		return http.StatusInternalServerError
 	default:
-		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "cannot join"))
+		return http.StatusBadRequest
 	}
 }
 
@@ -301,6 +454,127 @@ func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Transfer the leadership of a channel.
+// Expect application/json.
+func (h *HTTPHandler) serveTransferLeadership(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	transferReq := &types.LeadershipTransferRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(transferReq); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot decode request body"))
+		return
+	}
+	if transferReq.Transferee == 0 {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("missing transferee"))
+		return
+	}
+
+	leader, err := h.registrar.TransferLeadership(channelID, transferReq.Transferee)
+	if err == nil {
+		h.logger.Infof("Transferred leadership of channel %s to %d", channelID, leader)
+		h.sendResponseOK(resp, &types.LeadershipTransferResponse{Name: channelID, Leader: leader})
+		return
+	}
+
+	h.logger.Debugf("Failed to transfer leadership of channel: %s, err: %s", channelID, err)
+
+	if notLeader, ok := err.(*types.NotLeaderError); ok {
+		encoder := json.NewEncoder(resp)
+		resp.Header().Set("Content-Type", "application/json")
+		resp.WriteHeader(http.StatusMisdirectedRequest)
+		if err := encoder.Encode(&types.NotLeaderResponse{
+			Error:  errors.WithMessage(err, "cannot transfer leadership").Error(),
+			Leader: notLeader.Leader,
+		}); err != nil {
+			h.logger.Errorf("failed to encode error, err: %s", err)
+		}
+		return
+	}
+
+	switch err {
+	case types.ErrChannelNotExist:
+		h.sendResponseJsonError(resp, http.StatusNotFound, errors.WithMessage(err, "cannot transfer leadership"))
+	case types.ErrChannelPendingRemoval:
+		h.sendResponseJsonError(resp, http.StatusConflict, errors.WithMessage(err, "cannot transfer leadership"))
+	default:
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.WithMessage(err, "cannot transfer leadership"))
+	}
+}
+
+// Stream the changes of the status and height of a channel, as server-sent events.
+// Expect text/event-stream to be accepted.
+// An event of type EventTypeChannel carries the channel info, and is sent upon connection and whenever the status,
+// the consensus relation or the height change. The stream ends once the channel is active, or if it fails. An event
+// of type EventTypeError ends the stream if the channel info can no longer be retrieved, e.g. the channel was removed.
+func (h *HTTPHandler) serveChannelEvents(resp http.ResponseWriter, req *http.Request) {
+	if err := negotiateEventStream(req); err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	flusher, ok := resp.(http.Flusher)
+	if !ok {
+		h.sendResponseJsonError(resp, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+		return
+	}
+
+	resp.Header().Set("Content-Type", "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(eventsPollInterval)
+	defer ticker.Stop()
+
+	var last types.ChannelInfo
+	sent := false
+	for {
+		if !sent || last.Status != info.Status || last.ConsensusRelation != info.ConsensusRelation || last.Height != info.Height {
+			info.URL = path.Join(URLBaseV1Channels, info.Name)
+			h.sendEvent(resp, EventTypeChannel, info)
+			flusher.Flush()
+			last, sent = info, true
+		}
+		if info.Status == types.StatusActive || info.Status == types.StatusFailed {
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-req.Context().Done():
+			return
+		}
+
+		info, err = h.registrar.ChannelInfo(channelID)
+		if err != nil {
+			h.sendEvent(resp, EventTypeError, &types.ErrorResponse{Error: err.Error()})
+			flusher.Flush()
+			return
+		}
+	}
+}
+
 func (h *HTTPHandler) serveBadContentType(resp http.ResponseWriter, req *http.Request) {
 	err := errors.Errorf("unsupported Content-Type: %s", req.Header.Values("Content-Type"))
 	h.sendResponseJsonError(resp, http.StatusBadRequest, err)
@@ -309,6 +583,16 @@ func (h *HTTPHandler) serveBadContentType(resp http.ResponseWriter, req *http.Re
 func (h *HTTPHandler) serveNotAllowed(resp http.ResponseWriter, req *http.Request) {
 	err := errors.Errorf("invalid request method: %s", req.Method)
 
+	if strings.HasSuffix(req.URL.Path, "/leader") || req.URL.Path == URLBaseV1BulkChannels {
+		h.sendResponseNotAllowed(resp, err, http.MethodPost)
+		return
+	}
+
+	if strings.HasSuffix(req.URL.Path, "/events") {
+		h.sendResponseNotAllowed(resp, err, http.MethodGet)
+		return
+	}
+
 	if _, ok := mux.Vars(req)[channelIDKey]; ok {
 		h.sendResponseNotAllowed(resp, err, http.MethodGet, http.MethodDelete)
 		return
@@ -335,6 +619,33 @@ func negotiateContentType(req *http.Request) (string, error) {
 	return "", errors.New("response Content-Type is application/json only")
 }
 
+func negotiateEventStream(req *http.Request) error {
+	acceptReq := req.Header.Get("Accept")
+	if len(acceptReq) == 0 {
+		return nil
+	}
+
+	options := strings.Split(acceptReq, ",")
+	for _, opt := range options {
+		if strings.Contains(opt, "text/event-stream") ||
+			strings.Contains(opt, "text/*") ||
+			strings.Contains(opt, "*/*") {
+			return nil
+		}
+	}
+
+	return errors.New("response Content-Type is text/event-stream only")
+}
+
+func (h *HTTPHandler) sendEvent(resp http.ResponseWriter, eventType string, content interface{}) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		h.logger.Errorf("failed to encode event, err: %s", err)
+		return
+	}
+	fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", eventType, data)
+}
+
 func (h *HTTPHandler) sendResponseJsonError(resp http.ResponseWriter, code int, err error) {
 	encoder := json.NewEncoder(resp)
 	resp.Header().Set("Content-Type", "application/json")
@@ -71,6 +71,39 @@ func TestHTTPHandler_ServeHTTP_InvalidMethods(t *testing.T) {
 			require.Equal(t, "GET, POST", resp.Result().Header.Get("Allow"), "%s", method)
 		}
 	})
+
+	t.Run("on /channels/ch-id/leader", func(t *testing.T) {
+		invalidMethodsExt := append(invalidMethods, http.MethodGet, http.MethodDelete)
+		for _, method := range invalidMethodsExt {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "leader"), nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
+			require.Equal(t, "POST", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
+
+	t.Run("on /channels/ch-id/events", func(t *testing.T) {
+		invalidMethodsExt := append(invalidMethods, http.MethodPost, http.MethodDelete)
+		for _, method := range invalidMethodsExt {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(method, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "events"), nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
+			require.Equal(t, "GET", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
+
+	t.Run("on /bulk/channels", func(t *testing.T) {
+		invalidMethodsExt := append(invalidMethods, http.MethodGet, http.MethodDelete)
+		for _, method := range invalidMethodsExt {
+			resp := httptest.NewRecorder()
+			req := httptest.NewRequest(method, channelparticipation.URLBaseV1BulkChannels, nil)
+			h.ServeHTTP(resp, req)
+			checkErrorResponse(t, http.StatusMethodNotAllowed, fmt.Sprintf("invalid request method: %s", method), resp)
+			require.Equal(t, "POST", resp.Result().Header.Get("Allow"), "%s", method)
+		}
+	})
 }
 
 func TestHTTPHandler_ServeHTTP_ListErrors(t *testing.T) {
@@ -490,6 +523,351 @@ func TestHTTPHandler_ServeHTTP_Remove(t *testing.T) {
 
 }
 
+func TestHTTPHandler_ServeHTTP_TransferLeadership(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024,
+	}
+
+	genTransferRequest := func(channelID, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path.Join(channelparticipation.URLBaseV1Channels, channelID, "leader"), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("transferred ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.TransferLeadershipReturns(3, nil)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genTransferRequest("my-channel", `{"transferee":3}`))
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+
+		transferResp := types.LeadershipTransferResponse{}
+		err := json.Unmarshal(resp.Body.Bytes(), &transferResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, types.LeadershipTransferResponse{Name: "my-channel", Leader: 3}, transferResp)
+
+		require.Equal(t, 1, fakeManager.TransferLeadershipCallCount())
+		channelID, transferee := fakeManager.TransferLeadershipArgsForCall(0)
+		require.Equal(t, "my-channel", channelID)
+		require.Equal(t, uint64(3), transferee)
+	})
+
+	t.Run("not leader", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.TransferLeadershipReturns(0, &types.NotLeaderError{Leader: 1})
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genTransferRequest("my-channel", `{"transferee":3}`))
+		require.Equal(t, http.StatusMisdirectedRequest, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+
+		notLeaderResp := types.NotLeaderResponse{}
+		err := json.Unmarshal(resp.Body.Bytes(), &notLeaderResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, types.NotLeaderResponse{Error: "cannot transfer leadership: not leader, leader is 1", Leader: 1}, notLeaderResp)
+	})
+
+	type testDef struct {
+		name         string
+		channel      string
+		body         string
+		fakeReturns  error
+		expectedCode int
+		expectedErr  string
+	}
+
+	testCases := []testDef{
+		{
+			name:         "bad channel ID",
+			channel:      "My-Channel",
+			body:         `{"transferee":3}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "invalid channel ID: 'My-Channel' contains illegal characters",
+		},
+		{
+			name:         "bad body - not json",
+			channel:      "my-channel",
+			body:         `transferee=3`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "cannot decode request body: invalid character 'a' in literal true (expecting 'u')",
+		},
+		{
+			name:         "bad body - unknown field",
+			channel:      "my-channel",
+			body:         `{"to":3}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  `cannot decode request body: json: unknown field "to"`,
+		},
+		{
+			name:         "bad body - missing transferee",
+			channel:      "my-channel",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "missing transferee",
+		},
+		{
+			name:         "channel does not exist",
+			channel:      "my-channel",
+			body:         `{"transferee":3}`,
+			fakeReturns:  types.ErrChannelNotExist,
+			expectedCode: http.StatusNotFound,
+			expectedErr:  "cannot transfer leadership: channel does not exist",
+		},
+		{
+			name:         "channel pending removal",
+			channel:      "my-channel",
+			body:         `{"transferee":3}`,
+			fakeReturns:  types.ErrChannelPendingRemoval,
+			expectedCode: http.StatusConflict,
+			expectedErr:  "cannot transfer leadership: channel pending removal",
+		},
+		{
+			name:         "not supported",
+			channel:      "my-channel",
+			body:         `{"transferee":3}`,
+			fakeReturns:  types.ErrLeadershipTransferNotSupported,
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "cannot transfer leadership: leadership transfer not supported",
+		},
+		{
+			name:         "some other error",
+			channel:      "my-channel",
+			body:         `{"transferee":3}`,
+			fakeReturns:  errors.New("timed out waiting for leadership to be transferred to 3"),
+			expectedCode: http.StatusBadRequest,
+			expectedErr:  "cannot transfer leadership: timed out waiting for leadership to be transferred to 3",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeManager, h := setup(config, t)
+			fakeManager.TransferLeadershipReturns(0, testCase.fakeReturns)
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, genTransferRequest(testCase.channel, testCase.body))
+			checkErrorResponse(t, testCase.expectedCode, testCase.expectedErr, resp)
+		})
+	}
+
+	t.Run("content type mismatch", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genTransferRequest("my-channel", `{"transferee":3}`)
+		req.Header.Set("Content-Type", "text/plain")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unsupported Content-Type: [text/plain]", resp)
+	})
+
+	t.Run("body larger that MaxRequestBodySize", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, genTransferRequest("my-channel", fmt.Sprintf(`{"transferee":3%s}`, bytes.Repeat([]byte(" "), 1024))))
+		checkErrorResponse(t, http.StatusBadRequest, "cannot decode request body: http: request body too large", resp)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_BulkJoin(t *testing.T) {
+	config := localconfig.ChannelParticipation{
+		Enabled:            true,
+		MaxRequestBodySize: 1024 * 1024,
+	}
+
+	t.Run("joined with partial failure", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelStub = func(channelID string, _ *common.Block, isAppChannel bool) (types.ChannelInfo, error) {
+			require.True(t, isAppChannel)
+			if channelID == "ch-exists" {
+				return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+			}
+			return types.ChannelInfo{
+				Name:              channelID,
+				ConsensusRelation: "follower",
+				Status:            "onboarding",
+			}, nil
+		}
+
+		resp := httptest.NewRecorder()
+		req := genBulkJoinRequestFormData(t, validBlockBytes("ch-1"), validBlockBytes("ch-exists"), validBlockBytes("ch-2"))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, 3, fakeManager.JoinChannelCallCount())
+
+		bulkResp := types.BulkJoinResponse{}
+		err := json.Unmarshal(resp.Body.Bytes(), &bulkResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, types.BulkJoinResponse{
+			Channels: []types.BulkJoinResult{
+				{
+					Name:       "ch-1",
+					StatusCode: http.StatusCreated,
+					Info: &types.ChannelInfo{
+						Name:              "ch-1",
+						URL:               channelparticipation.URLBaseV1Channels + "/ch-1",
+						ConsensusRelation: "follower",
+						Status:            "onboarding",
+					},
+				},
+				{
+					Name:       "ch-exists",
+					StatusCode: http.StatusMethodNotAllowed,
+					Error:      "cannot join: channel already exists",
+				},
+				{
+					Name:       "ch-2",
+					StatusCode: http.StatusCreated,
+					Info: &types.ChannelInfo{
+						Name:              "ch-2",
+						URL:               channelparticipation.URLBaseV1Channels + "/ch-2",
+						ConsensusRelation: "follower",
+						Status:            "onboarding",
+					},
+				},
+			},
+		}, bulkResp)
+	})
+
+	t.Run("Error: invalid block", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genBulkJoinRequestFormData(t, validBlockBytes("ch-1"), []byte{1, 2, 3, 4})
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "file part 1: cannot unmarshal file part config-block into a block: proto: common.Block: illegal tag 0 (wire type 1)", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("Error: invalid join block", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genBulkJoinRequestFormData(t, validBlockBytes("ch-1"), protoutil.MarshalOrPanic(&common.Block{}))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "invalid join block in file part 1: block is not a config block", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("Error: block larger than the maximal request body size", func(t *testing.T) {
+		fakeManager, h := setup(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 10, MaxBulkRequestBodySize: 1024 * 1024}, t)
+		resp := httptest.NewRecorder()
+		req := genBulkJoinRequestFormData(t, validBlockBytes("ch-1"))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "file part 0 is larger than the maximal request body size", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("Error: blocks larger than the maximal bulk request body size", func(t *testing.T) {
+		block := validBlockBytes("ch-1")
+		fakeManager, h := setup(localconfig.ChannelParticipation{
+			Enabled:                true,
+			MaxRequestBodySize:     uint32(2 * len(block)),
+			MaxBulkRequestBodySize: uint32(3 * len(block)),
+		}, t)
+		resp := httptest.NewRecorder()
+		req := genBulkJoinRequestFormData(t, block, validBlockBytes("ch-2"), validBlockBytes("ch-3"))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot read form from request body: http: request body too large", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("Error: form with other parts", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		joinBody := new(bytes.Buffer)
+		writer := multipart.NewWriter(joinBody)
+		part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, "join-config.block")
+		require.NoError(t, err)
+		part.Write(validBlockBytes("ch-1"))
+		require.NoError(t, writer.WriteField("something", "else"))
+		require.NoError(t, writer.Close())
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1BulkChannels, joinBody)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "form contains parts with keys other than config-block", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+	})
+
+	t.Run("Error: bad content type", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1BulkChannels, nil)
+		req.Header.Set("Content-Type", "application/json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "unsupported Content-Type: [application/json]", resp)
+	})
+}
+
+func TestHTTPHandler_ServeHTTP_ChannelEvents(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+
+	t.Run("streams until active", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		infos := []types.ChannelInfo{
+			{Name: "ch-id", ConsensusRelation: "follower", Status: "onboarding", Height: 1},
+			{Name: "ch-id", ConsensusRelation: "follower", Status: "onboarding", Height: 1},
+			{Name: "ch-id", ConsensusRelation: "follower", Status: "onboarding", Height: 7},
+			{Name: "ch-id", ConsensusRelation: "consenter", Status: "active", Height: 10},
+		}
+		fakeManager.ChannelInfoStub = func(string) (types.ChannelInfo, error) {
+			return infos[fakeManager.ChannelInfoCallCount()-1], nil
+		}
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "events"), nil)
+		req.Header.Set("Accept", "text/event-stream")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "text/event-stream", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, 4, fakeManager.ChannelInfoCallCount())
+		require.Equal(t, "ch-id", fakeManager.ChannelInfoArgsForCall(0))
+		require.Equal(t,
+			"event: channel\n"+
+				`data: {"name":"ch-id","url":"/participation/v1/channels/ch-id","consensusRelation":"follower","status":"onboarding","height":1}`+"\n\n"+
+				"event: channel\n"+
+				`data: {"name":"ch-id","url":"/participation/v1/channels/ch-id","consensusRelation":"follower","status":"onboarding","height":7}`+"\n\n"+
+				"event: channel\n"+
+				`data: {"name":"ch-id","url":"/participation/v1/channels/ch-id","consensusRelation":"consenter","status":"active","height":10}`+"\n\n",
+			resp.Body.String())
+	})
+
+	t.Run("streams until the channel is removed", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturnsOnCall(0, types.ChannelInfo{Name: "ch-id", ConsensusRelation: "follower", Status: "onboarding", Height: 1}, nil)
+		fakeManager.ChannelInfoReturnsOnCall(1, types.ChannelInfo{}, types.ErrChannelNotExist)
+
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "events"), nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t,
+			"event: channel\n"+
+				`data: {"name":"ch-id","url":"/participation/v1/channels/ch-id","consensusRelation":"follower","status":"onboarding","height":1}`+"\n\n"+
+				"event: error\n"+
+				`data: {"error":"channel does not exist"}`+"\n\n",
+			resp.Body.String())
+	})
+
+	t.Run("Error: channel does not exist", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "events"), nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotFound, "channel does not exist", resp)
+	})
+
+	t.Run("Error: bad accept header", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path.Join(channelparticipation.URLBaseV1Channels, "ch-id", "events"), nil)
+		req.Header.Set("Accept", "application/json")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusNotAcceptable, "response Content-Type is text/event-stream only", resp)
+	})
+}
+
 func setup(config localconfig.ChannelParticipation, t *testing.T) (*mocks.ChannelManagement, *channelparticipation.HTTPHandler) {
 	fakeManager := &mocks.ChannelManagement{}
 	h := channelparticipation.NewHTTPHandler(config, fakeManager)
@@ -527,6 +905,23 @@ func genJoinRequestFormData(t *testing.T, blockBytes []byte) *http.Request {
 	return req
 }
 
+func genBulkJoinRequestFormData(t *testing.T, blocksBytes ...[]byte) *http.Request {
+	joinBody := new(bytes.Buffer)
+	writer := multipart.NewWriter(joinBody)
+	for i, blockBytes := range blocksBytes {
+		part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, fmt.Sprintf("join-config-%d.block", i))
+		require.NoError(t, err)
+		part.Write(blockBytes)
+	}
+	err := writer.Close()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1BulkChannels, joinBody)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
 func validBlockBytes(channelID string) []byte {
 	blockBytes := protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{
 		"Application": {},
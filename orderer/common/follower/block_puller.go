@@ -8,6 +8,8 @@ package follower
 
 import (
 	"encoding/pem"
+	"os"
+	"path/filepath"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp"
@@ -42,6 +44,10 @@ type BlockPullerCreator struct {
 	der                     *pem.Block
 	stdDialer               *cluster.StandardDialer
 	ClusterVerifyBlocks     ClusterVerifyBlocksFunc // Default: cluster.VerifyBlocks, or a mock for testing
+
+	seedIndexed bool             // The local block source was looked up and indexed
+	seed        *FileBlockPuller // The local block source of the channel, if any
+	seedHeight  uint64           // The height of the local block source
 }
 
 // ClusterVerifyBlocksFunc is a function that matches the signature of cluster.VerifyBlocks, and allows mocks for testing.
@@ -87,6 +93,9 @@ func NewBlockPullerCreator(
 }
 
 // BlockPuller creates a block puller on demand, taking the endpoints from the config block.
+// If a local block source for the channel is found in the ReplicationSeedDir, the block puller reads the blocks from it
+// first, and pulls the blocks it does not contain from the other orderers. The local block source is indexed on the
+// first call and shared by the block pullers created afterwards, which skip it once the ledger reaches its height.
 func (creator *BlockPullerCreator) BlockPuller(configBlock *common.Block, stopChannel chan struct{}) (ChannelPuller, error) {
 	// Extract the TLS CA certs and endpoints from the join-block
 	endpoints, err := cluster.EndpointconfigFromConfigBlock(configBlock, creator.bccsp)
@@ -109,7 +118,59 @@ func (creator *BlockPullerCreator) BlockPuller(configBlock *common.Block, stopCh
 		StopChannel:         stopChannel,
 	}
 
-	return bp, nil
+	creator.indexSeed(bp.Logger)
+	if creator.seed == nil {
+		return bp, nil
+	}
+
+	return &SeededBlockPuller{Seed: creator.seed, SeedHeight: creator.seedHeight, Puller: bp, Logger: bp.Logger}, nil
+}
+
+// indexSeed opens the local block source of the channel and reads its height, once.
+func (creator *BlockPullerCreator) indexSeed(logger *flogging.FabricLogger) {
+	if creator.seedIndexed {
+		return
+	}
+	creator.seedIndexed = true
+
+	seedPath := creator.seedPath()
+	if seedPath == "" {
+		return
+	}
+	seed, err := NewFileBlockPuller(seedPath, creator.VerifyBlockSequence, creator.clusterConfig.ReplicationBufferSize, logger)
+	if err != nil {
+		logger.Warningf("Ignoring the local block source %s: %s", seedPath, err)
+		return
+	}
+	heights, err := seed.HeightsByEndpoints()
+	if err != nil {
+		logger.Warningf("Ignoring the local block source %s: %s", seedPath, err)
+		seed.Close()
+		return
+	}
+	if heights[seedPath] == 0 {
+		logger.Warningf("Ignoring the local block source %s: it contains no blocks", seedPath)
+		seed.Close()
+		return
+	}
+	logger.Infof("Reading blocks [0-%d] from the local block source %s", heights[seedPath]-1, seedPath)
+
+	creator.seed = seed
+	creator.seedHeight = heights[seedPath]
+}
+
+// seedPath returns the path of the directory or tar archive of the channel blocks in the ReplicationSeedDir, if any.
+func (creator *BlockPullerCreator) seedPath() string {
+	if creator.clusterConfig.ReplicationSeedDir == "" {
+		return ""
+	}
+	for _, name := range []string{creator.channelID, creator.channelID + ".tar", creator.channelID + ".tar.gz", creator.channelID + ".tgz"} {
+		path := filepath.Join(creator.clusterConfig.ReplicationSeedDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
 }
 
 // UpdateVerifierFromConfigBlock creates a new block signature verifier from the config block and updates the internal
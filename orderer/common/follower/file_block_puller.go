@@ -0,0 +1,370 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package follower
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/pkg/errors"
+)
+
+// blockFileName matches the names of the exported block files, e.g. 42.block or mychannel_42.block,
+// and captures the block number.
+var blockFileName = regexp.MustCompile(`(?:^|[^0-9])([0-9]+)\.block$`)
+
+var errBlockNotFound = errors.New("block not found")
+
+// blockSource reads the exported blocks by their number.
+type blockSource interface {
+	read(seq uint64) ([]byte, error)
+	height() (uint64, error)
+	close()
+}
+
+// FileBlockPuller is a ChannelPuller that reads the blocks of a channel from a directory or a tar archive of exported
+// blocks, instead of pulling them from other orderers. This allows to seed the ledger of a new orderer offline.
+// Each block is stored in a file named after its number, e.g. 42.block or mychannel_42.block. A tar archive is read
+// sequentially, so its entries should be sorted by block number. The blocks are verified in batches with
+// VerifyBlockSequence, like the blocks pulled by the cluster.BlockPuller.
+type FileBlockPuller struct {
+	Path                string
+	VerifyBlockSequence cluster.BlockSequenceVerifier
+	MaxTotalBufferBytes int
+	Logger              *flogging.FabricLogger
+
+	source    blockSource
+	blockBuff []*common.Block
+}
+
+// NewFileBlockPuller creates a FileBlockPuller that reads the blocks from the directory or the tar archive at the
+// given path. Archives compressed with gzip must have the .tar.gz or .tgz extension.
+func NewFileBlockPuller(
+	path string,
+	verifyBlockSequence cluster.BlockSequenceVerifier,
+	maxTotalBufferBytes int,
+	logger *flogging.FabricLogger,
+) (*FileBlockPuller, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open block source")
+	}
+
+	var source blockSource
+	switch {
+	case info.IsDir():
+		source, err = newDirBlockSource(path)
+	case strings.HasSuffix(path, ".tar"):
+		source = &tarBlockSource{path: path}
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"):
+		source = &tarBlockSource{path: path, gzipped: true}
+	default:
+		err = errors.Errorf("%s is neither a directory nor a tar archive", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileBlockPuller{
+		Path:                path,
+		VerifyBlockSequence: verifyBlockSequence,
+		MaxTotalBufferBytes: maxTotalBufferBytes,
+		Logger:              logger,
+		source:              source,
+	}, nil
+}
+
+// PullBlock returns the block with the given sequence, or nil if the block source does not contain it, or if it
+// is invalid.
+func (p *FileBlockPuller) PullBlock(seq uint64) *common.Block {
+	if len(p.blockBuff) > 0 && p.blockBuff[0].Header.Number == seq {
+		block := p.blockBuff[0]
+		p.blockBuff = p.blockBuff[1:]
+		return block
+	}
+
+	blocks, err := p.readBlocks(seq)
+	if err != nil {
+		p.Logger.Warningf("Failed reading block [%d] from %s: %s", seq, p.Path, err)
+		p.blockBuff = nil
+		return nil
+	}
+	if err := p.VerifyBlockSequence(blocks, p.Path); err != nil {
+		p.Logger.Errorf("Failed verifying blocks [%d-%d] read from %s: %s", seq, seq+uint64(len(blocks))-1, p.Path, err)
+		p.blockBuff = nil
+		return nil
+	}
+
+	p.Logger.Debugf("Read blocks [%d-%d] from %s", seq, seq+uint64(len(blocks))-1, p.Path)
+	p.blockBuff = blocks[1:]
+	return blocks[0]
+}
+
+// readBlocks reads the consecutive blocks starting from the given sequence, until the buffer is full or a block
+// is missing.
+func (p *FileBlockPuller) readBlocks(seq uint64) ([]*common.Block, error) {
+	var blocks []*common.Block
+	var totalSize int
+	for next := seq; len(blocks) == 0 || totalSize < p.MaxTotalBufferBytes; next++ {
+		blockBytes, err := p.source.read(next)
+		if err == errBlockNotFound && len(blocks) > 0 {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		block := &common.Block{}
+		if err := proto.Unmarshal(blockBytes, block); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal block [%d]", next)
+		}
+		if block.Header == nil || block.Header.Number != next {
+			return nil, errors.Errorf("the file of block [%d] contains another block", next)
+		}
+		blocks = append(blocks, block)
+		totalSize += len(blockBytes)
+	}
+	return blocks, nil
+}
+
+// HeightsByEndpoints returns the height of the block source, i.e. the number of its last block + 1, mapped by its path.
+func (p *FileBlockPuller) HeightsByEndpoints() (map[string]uint64, error) {
+	height, err := p.source.height()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]uint64{p.Path: height}, nil
+}
+
+// UpdateEndpoints does nothing, as the blocks are read from the local block source.
+func (p *FileBlockPuller) UpdateEndpoints(endpoints []cluster.EndpointCriteria) {}
+
+// Close closes the block source.
+func (p *FileBlockPuller) Close() {
+	p.source.close()
+	p.blockBuff = nil
+}
+
+func parseBlockFileName(name string) (uint64, bool) {
+	match := blockFileName.FindStringSubmatch(filepath.Base(name))
+	if match == nil {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(match[1], 10, 64)
+	return seq, err == nil
+}
+
+// dirBlockSource reads the blocks from the files of a directory.
+type dirBlockSource struct {
+	files map[uint64]string
+	last  uint64
+}
+
+func newDirBlockSource(dir string) (*dirBlockSource, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list block source directory")
+	}
+	s := &dirBlockSource{files: map[uint64]string{}}
+	for _, info := range infos {
+		seq, ok := parseBlockFileName(info.Name())
+		if info.IsDir() || !ok {
+			continue
+		}
+		if existing, ok := s.files[seq]; ok {
+			return nil, errors.Errorf("block [%d] is exported to both %s and %s", seq, filepath.Base(existing), info.Name())
+		}
+		s.files[seq] = filepath.Join(dir, info.Name())
+		if seq > s.last {
+			s.last = seq
+		}
+	}
+	return s, nil
+}
+
+func (s *dirBlockSource) read(seq uint64) ([]byte, error) {
+	file, ok := s.files[seq]
+	if !ok {
+		return nil, errBlockNotFound
+	}
+	return ioutil.ReadFile(file)
+}
+
+func (s *dirBlockSource) height() (uint64, error) {
+	if len(s.files) == 0 {
+		return 0, nil
+	}
+	return s.last + 1, nil
+}
+
+func (s *dirBlockSource) close() {}
+
+// tarBlockSource reads the blocks from the entries of a tar archive, sequentially.
+type tarBlockSource struct {
+	path    string
+	gzipped bool
+
+	file      *os.File
+	reader    *tar.Reader
+	fromStart bool   // No entry was read or skipped since the archive was opened
+	scanned   bool   // All the entries of the archive were read or skipped once
+	last      uint64 // The highest block number seen so far
+}
+
+func (s *tarBlockSource) open() error {
+	s.close()
+	file, err := os.Open(s.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open block archive")
+	}
+	var r io.Reader = file
+	if s.gzipped {
+		if r, err = gzip.NewReader(file); err != nil {
+			file.Close()
+			return errors.Wrap(err, "failed to decompress block archive")
+		}
+	}
+	s.file = file
+	s.reader = tar.NewReader(r)
+	s.fromStart = true
+	return nil
+}
+
+// next advances the reader to the next block entry and returns its block number. It returns io.EOF at the end of
+// the archive.
+func (s *tarBlockSource) next() (uint64, error) {
+	for {
+		header, err := s.reader.Next()
+		if err != nil {
+			return 0, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if seq, ok := parseBlockFileName(header.Name); ok {
+			return seq, nil
+		}
+	}
+}
+
+func (s *tarBlockSource) read(seq uint64) ([]byte, error) {
+	if s.scanned && seq > s.last {
+		return nil, errBlockNotFound
+	}
+	if s.reader == nil {
+		if err := s.open(); err != nil {
+			return nil, err
+		}
+	}
+	// The archive is scanned from the position of the reader to its end, and then from its start if needed, as
+	// the block may precede the position of the reader in an unsorted archive.
+	wrapped := s.fromStart
+	for {
+		current, err := s.next()
+		if err == io.EOF {
+			s.scanned = true
+		}
+		if err == io.EOF && !wrapped && seq <= s.last {
+			if err := s.open(); err != nil {
+				return nil, err
+			}
+			wrapped = true
+			continue
+		}
+		if err == io.EOF {
+			return nil, errBlockNotFound
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read block archive")
+		}
+		s.fromStart = false
+		if current > s.last {
+			s.last = current
+		}
+		if current == seq {
+			return ioutil.ReadAll(s.reader)
+		}
+	}
+}
+
+func (s *tarBlockSource) height() (uint64, error) {
+	if err := s.open(); err != nil {
+		return 0, err
+	}
+	defer s.close()
+
+	var height uint64
+	for {
+		seq, err := s.next()
+		if err == io.EOF {
+			return height, nil
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to read block archive")
+		}
+		if seq+1 > height {
+			height = seq + 1
+		}
+	}
+}
+
+func (s *tarBlockSource) close() {
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = nil
+	s.reader = nil
+}
+
+// SeededBlockPuller is a ChannelPuller that reads the blocks from a local block source, the Seed, until the Seed
+// does not provide the next block, and then pulls the remaining blocks with the Puller. The blocks from SeedHeight
+// are pulled with the Puller without reading the Seed.
+type SeededBlockPuller struct {
+	Seed       ChannelPuller
+	SeedHeight uint64
+	Puller     ChannelPuller
+	Logger     *flogging.FabricLogger
+
+	exhausted bool
+}
+
+func (p *SeededBlockPuller) PullBlock(seq uint64) *common.Block {
+	if !p.exhausted {
+		if seq < p.SeedHeight {
+			if block := p.Seed.PullBlock(seq); block != nil {
+				return block
+			}
+		}
+		p.Logger.Infof("Block [%d] is not available in the local block source, pulling the remaining blocks from other orderers", seq)
+		p.exhausted = true
+		p.Seed.Close()
+	}
+	return p.Puller.PullBlock(seq)
+}
+
+func (p *SeededBlockPuller) HeightsByEndpoints() (map[string]uint64, error) {
+	return p.Puller.HeightsByEndpoints()
+}
+
+func (p *SeededBlockPuller) UpdateEndpoints(endpoints []cluster.EndpointCriteria) {
+	p.Puller.UpdateEndpoints(endpoints)
+}
+
+func (p *SeededBlockPuller) Close() {
+	p.Seed.Close()
+	p.Puller.Close()
+}
@@ -0,0 +1,289 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package follower_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/follower"
+	"github.com/hyperledger/fabric/orderer/common/follower/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBlockPuller(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "file-block-puller-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpdir)
+
+	var blocks []*cb.Block
+	for i := uint64(0); i < 5; i++ {
+		blocks = append(blocks, protoutil.NewBlock(i, []byte{}))
+	}
+	order := []int{0, 1, 2, 3, 4}
+
+	blockDir := filepath.Join(tmpdir, "blocks")
+	require.NoError(t, os.Mkdir(blockDir, 0755))
+	for _, block := range blocks {
+		writeBlockFile(t, filepath.Join(blockDir, fmt.Sprintf("mychannel_%d.block", block.Header.Number)), block)
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(blockDir, "README"), []byte("not a block"), 0644))
+
+	sources := []struct {
+		name string
+		path string
+	}{
+		{name: "directory", path: blockDir},
+		{name: "tar archive", path: writeBlockArchive(t, filepath.Join(tmpdir, "blocks.tar"), blocks, order, false)},
+		{name: "gzipped tar archive", path: writeBlockArchive(t, filepath.Join(tmpdir, "blocks.tgz"), blocks, order, true)},
+		{name: "unsorted tar archive", path: writeBlockArchive(t, filepath.Join(tmpdir, "unsorted.tar.gz"), blocks, []int{3, 1, 4, 0, 2}, true)},
+	}
+
+	for _, source := range sources {
+		source := source
+		t.Run(source.name, func(t *testing.T) {
+			var verified [][]*cb.Block
+			verify := func(blocks []*cb.Block, channel string) error {
+				require.Equal(t, source.path, channel)
+				verified = append(verified, blocks)
+				return nil
+			}
+
+			// A buffer of 1 byte holds a single block.
+			puller, err := follower.NewFileBlockPuller(source.path, verify, 1, testLogger)
+			require.NoError(t, err)
+			defer puller.Close()
+
+			heights, err := puller.HeightsByEndpoints()
+			require.NoError(t, err)
+			require.Equal(t, map[string]uint64{source.path: 5}, heights)
+
+			for i := uint64(2); i < 5; i++ {
+				block := puller.PullBlock(i)
+				require.True(t, proto.Equal(blocks[i], block), "block %d", i)
+			}
+			require.Len(t, verified, 3)
+
+			// Blocks preceding the previous one are read again.
+			require.True(t, proto.Equal(blocks[0], puller.PullBlock(0)))
+			require.Nil(t, puller.PullBlock(5))
+			require.Len(t, verified, 4)
+		})
+	}
+
+	t.Run("batches are verified together", func(t *testing.T) {
+		var verified [][]*cb.Block
+		verify := func(blocks []*cb.Block, _ string) error {
+			verified = append(verified, blocks)
+			return nil
+		}
+
+		puller, err := follower.NewFileBlockPuller(sources[1].path, verify, 1024*1024, testLogger)
+		require.NoError(t, err)
+		defer puller.Close()
+
+		for i := uint64(1); i < 5; i++ {
+			require.True(t, proto.Equal(blocks[i], puller.PullBlock(i)), "block %d", i)
+		}
+		require.Len(t, verified, 1)
+		require.Len(t, verified[0], 4)
+		require.Nil(t, puller.PullBlock(5))
+	})
+
+	t.Run("verification fails", func(t *testing.T) {
+		verify := func([]*cb.Block, string) error {
+			return errors.New("bad signature")
+		}
+
+		puller, err := follower.NewFileBlockPuller(blockDir, verify, 1024*1024, testLogger)
+		require.NoError(t, err)
+		defer puller.Close()
+
+		require.Nil(t, puller.PullBlock(0))
+	})
+
+	t.Run("block file contains another block", func(t *testing.T) {
+		dir := filepath.Join(tmpdir, "mismatch")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		writeBlockFile(t, filepath.Join(dir, "7.block"), blocks[4])
+
+		puller, err := follower.NewFileBlockPuller(dir, func([]*cb.Block, string) error { return nil }, 1024*1024, testLogger)
+		require.NoError(t, err)
+		defer puller.Close()
+
+		require.Nil(t, puller.PullBlock(7))
+	})
+
+	t.Run("block exported twice", func(t *testing.T) {
+		dir := filepath.Join(tmpdir, "duplicate")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		writeBlockFile(t, filepath.Join(dir, "4.block"), blocks[4])
+		writeBlockFile(t, filepath.Join(dir, "mychannel_4.block"), blocks[4])
+
+		_, err := follower.NewFileBlockPuller(dir, nil, 1024*1024, testLogger)
+		require.EqualError(t, err, "block [4] is exported to both 4.block and mychannel_4.block")
+	})
+
+	t.Run("missing block source", func(t *testing.T) {
+		_, err := follower.NewFileBlockPuller(filepath.Join(tmpdir, "missing"), nil, 1024*1024, testLogger)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open block source")
+	})
+
+	t.Run("not an archive", func(t *testing.T) {
+		path := filepath.Join(blockDir, "README")
+		_, err := follower.NewFileBlockPuller(path, nil, 1024*1024, testLogger)
+		require.EqualError(t, err, path+" is neither a directory nor a tar archive")
+	})
+}
+
+func TestSeededBlockPuller(t *testing.T) {
+	seed := &mocks.ChannelPuller{}
+	seed.PullBlockStub = func(seq uint64) *cb.Block {
+		if seq < 3 {
+			return protoutil.NewBlock(seq, []byte{})
+		}
+		return nil
+	}
+	puller := &mocks.ChannelPuller{}
+	puller.PullBlockStub = func(seq uint64) *cb.Block {
+		return protoutil.NewBlock(seq, []byte("pulled"))
+	}
+	puller.HeightsByEndpointsReturns(map[string]uint64{"orderer1:7050": 10}, nil)
+
+	p := &follower.SeededBlockPuller{Seed: seed, SeedHeight: 5, Puller: puller, Logger: testLogger}
+	for i := uint64(0); i < 3; i++ {
+		require.Equal(t, i, p.PullBlock(i).Header.Number)
+	}
+	require.Equal(t, 0, puller.PullBlockCallCount())
+
+	require.Equal(t, uint64(3), p.PullBlock(3).Header.Number)
+	require.Equal(t, uint64(4), p.PullBlock(4).Header.Number)
+	require.Equal(t, 4, seed.PullBlockCallCount(), "the seed is not used after it is exhausted")
+	require.Equal(t, 2, puller.PullBlockCallCount())
+	require.Equal(t, 1, seed.CloseCallCount())
+
+	heights, err := p.HeightsByEndpoints()
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"orderer1:7050": 10}, heights)
+
+	p.UpdateEndpoints([]cluster.EndpointCriteria{{Endpoint: "orderer2:7050"}})
+	require.Equal(t, 1, puller.UpdateEndpointsCallCount())
+
+	p.Close()
+	require.Equal(t, 1, puller.CloseCallCount())
+
+	t.Run("ledger at the seed height", func(t *testing.T) {
+		seed := &mocks.ChannelPuller{}
+		puller := &mocks.ChannelPuller{}
+		puller.PullBlockStub = func(seq uint64) *cb.Block {
+			return protoutil.NewBlock(seq, []byte("pulled"))
+		}
+
+		p := &follower.SeededBlockPuller{Seed: seed, SeedHeight: 3, Puller: puller, Logger: testLogger}
+		require.Equal(t, uint64(3), p.PullBlock(3).Header.Number)
+		require.Equal(t, 0, seed.PullBlockCallCount(), "the seed is not read beyond its height")
+		require.Equal(t, 1, puller.PullBlockCallCount())
+	})
+}
+
+func TestBlockPullerFactory_BlockPullerWithSeed(t *testing.T) {
+	setupBlockPullerTest(t)
+
+	seedDir, err := ioutil.TempDir("", "block-puller-seed-")
+	require.NoError(t, err)
+	defer os.RemoveAll(seedDir)
+
+	genesisBlock := generateJoinBlock(t, tlsCA, channelID, 0)
+	block1 := protoutil.NewBlock(1, protoutil.BlockHeaderHash(genesisBlock.Header))
+	writeBlockArchive(t, filepath.Join(seedDir, channelID+".tar.gz"), []*cb.Block{genesisBlock, block1}, []int{0, 1}, true)
+
+	factory, err := follower.NewBlockPullerCreator(channelID, testLogger, mockSigner, dialer, localconfig.Cluster{ReplicationSeedDir: seedDir}, cryptoProv)
+	require.NoError(t, err)
+	var verified int
+	factory.ClusterVerifyBlocks = func(blockBuff []*cb.Block, signatureVerifier cluster.BlockVerifier) error {
+		require.NotNil(t, signatureVerifier)
+		verified += len(blockBuff)
+		return nil
+	}
+
+	bp, err := factory.BlockPuller(generateJoinBlock(t, tlsCA, channelID, 10), make(chan struct{}))
+	require.NoError(t, err)
+	defer bp.Close()
+	require.IsType(t, &follower.SeededBlockPuller{}, bp)
+
+	require.Equal(t, uint64(2), bp.(*follower.SeededBlockPuller).SeedHeight)
+	require.True(t, proto.Equal(genesisBlock, bp.PullBlock(0)))
+	require.True(t, proto.Equal(block1, bp.PullBlock(1)))
+	require.Equal(t, 1, verified, "the genesis block bootstraps the verifier")
+
+	// the seed is indexed once, and shared by the next block pullers
+	require.NoError(t, os.Remove(filepath.Join(seedDir, channelID+".tar.gz")))
+	nextBP, err := factory.BlockPuller(generateJoinBlock(t, tlsCA, channelID, 10), make(chan struct{}))
+	require.NoError(t, err)
+	defer nextBP.Close()
+	require.IsType(t, &follower.SeededBlockPuller{}, nextBP)
+	require.Equal(t, uint64(2), nextBP.(*follower.SeededBlockPuller).SeedHeight)
+
+	t.Run("no seed for the channel", func(t *testing.T) {
+		factory, err := follower.NewBlockPullerCreator("otherchannel", testLogger, mockSigner, dialer, localconfig.Cluster{ReplicationSeedDir: seedDir}, cryptoProv)
+		require.NoError(t, err)
+		bp, err := factory.BlockPuller(generateJoinBlock(t, tlsCA, "otherchannel", 10), make(chan struct{}))
+		require.NoError(t, err)
+		defer bp.Close()
+		require.IsType(t, &cluster.BlockPuller{}, bp)
+	})
+}
+
+func writeBlockFile(t *testing.T, path string, block *cb.Block) {
+	blockBytes, err := proto.Marshal(block)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, blockBytes, 0644))
+}
+
+func writeBlockArchive(t *testing.T, path string, blocks []*cb.Block, order []int, gzipped bool) string {
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var w io.Writer = file
+	if gzipped {
+		gw := gzip.NewWriter(file)
+		defer gw.Close()
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "blocks/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, i := range order {
+		blockBytes, err := proto.Marshal(blocks[i])
+		require.NoError(t, err)
+		header := &tar.Header{
+			Name:     fmt.Sprintf("blocks/%d.block", blocks[i].Header.Number),
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(blockBytes)),
+		}
+		require.NoError(t, tw.WriteHeader(header))
+		_, err = tw.Write(blockBytes)
+		require.NoError(t, err)
+	}
+	return path
+}
@@ -25,6 +25,8 @@ type TopLevel struct {
 	FileLedger           FileLedger
 	Kafka                Kafka
 	Debug                Debug
+	Broadcast            Broadcast
+	BlockCutter          BlockCutter
 	Consensus            interface{}
 	Operations           Operations
 	Metrics              Metrics
@@ -66,6 +68,7 @@ type Cluster struct {
 	ReplicationRetryTimeout              time.Duration
 	ReplicationBackgroundRefreshInterval time.Duration
 	ReplicationMaxRetries                int
+	ReplicationSeedDir                   string
 	SendBufferSize                       int
 	CertExpirationWarningThreshold       time.Duration
 	TLSHandshakeTimeShift                time.Duration
@@ -179,6 +182,88 @@ type Debug struct {
 	DeliverTraceDir   string
 }
 
+// Broadcast contains configuration for the Broadcast service.
+type Broadcast struct {
+	RateLimit BroadcastRateLimit
+	Filters   BroadcastFilters
+	Dedup     BroadcastDedup
+}
+
+// BroadcastRateLimit configures the token buckets that limit the rate at which
+// the messages are accepted by the Broadcast service. The limit of a client
+// applies to each client identity on each channel, and the limit of a channel
+// applies to all the clients on the channel. A rate of zero disables the limit.
+type BroadcastRateLimit struct {
+	Enabled bool
+	Client  TokenBucket
+	// ClientOverrides replaces the limit of a client for the clients of the
+	// MSPs, keyed by MSP ID.
+	ClientOverrides map[string]TokenBucket
+	Channel         TokenBucket
+}
+
+// TokenBucket configures a token bucket, which is refilled at Rate tokens per
+// second up to Burst tokens.
+type TokenBucket struct {
+	Rate  float64
+	Burst int
+}
+
+// BroadcastFilters configures the custom rules which are applied to the
+// messages of the standard channels, after the built-in checks.
+type BroadcastFilters struct {
+	Plugins []FilterPlugin
+	Reject  []RejectRule
+}
+
+// FilterPlugin configures a rule loaded from a Go plugin, which exports a
+// NewRule function creating the rule for a channel.
+type FilterPlugin struct {
+	Library  string
+	Channels []string // The channels the rule applies to, or all if empty
+}
+
+// RejectRule configures a rule which rejects the messages matching all of the
+// given criteria. An empty criterion matches any message.
+type RejectRule struct {
+	Name           string
+	Channels       []string // The channels the rule applies to, or all if empty
+	HeaderTypes    []string
+	MSPIDs         []string
+	PayloadPattern string
+}
+
+// BroadcastDedup configures the rejection of the messages of the standard
+// channels whose TxID was already ordered in the last WindowBlocks blocks, with
+// status BAD_REQUEST. The window is a number of blocks rather than a period of
+// time, so that all the orderers of a channel reach the same decision. At most
+// MaxEntries TxIDs are remembered on each channel.
+type BroadcastDedup struct {
+	Enabled      bool
+	WindowBlocks uint64
+	MaxEntries   int
+	Channels     []string // The channels the deduplication applies to, or all if empty
+}
+
+// BlockCutter contains configuration for cutting the ordered messages into batches.
+type BlockCutter struct {
+	Adaptive AdaptiveBlockCutter
+}
+
+// AdaptiveBlockCutter configures the adaptive block cutting, which raises or
+// lowers the maximum message count of the batches between MinMessageCount and
+// the BatchSize.MaxMessageCount of the channel, so that a batch fills up in
+// about TargetBlockInterval at the observed message arrival rate. The preferred
+// size of the batches follows, up to the BatchSize.PreferredMaxBytes of the
+// channel. It is a local setting, which applies to the channels this orderer
+// leads, so the orderers of a channel should be configured alike.
+type AdaptiveBlockCutter struct {
+	Enabled             bool
+	MinMessageCount     uint32
+	TargetBlockInterval time.Duration
+	Channels            []string // The channels the adaptive mode applies to, or all if empty
+}
+
 // Operations configures the operations endpoint for the orderer.
 type Operations struct {
 	ListenAddress string
@@ -208,8 +293,9 @@ type Admin struct {
 // ChannelParticipation provides the channel participation API configuration for the orderer.
 // Channel participation uses the same ListenAddress and TLS settings of the Operations service.
 type ChannelParticipation struct {
-	Enabled            bool
-	MaxRequestBodySize uint32
+	Enabled                bool
+	MaxRequestBodySize     uint32
+	MaxBulkRequestBodySize uint32
 }
 
 // Defaults carries the default orderer configuration values.
@@ -280,6 +366,20 @@ var Defaults = TopLevel{
 		BroadcastTraceDir: "",
 		DeliverTraceDir:   "",
 	},
+	Broadcast: Broadcast{
+		Dedup: BroadcastDedup{
+			Enabled:      false,
+			WindowBlocks: 300,
+			MaxEntries:   100000,
+		},
+	},
+	BlockCutter: BlockCutter{
+		Adaptive: AdaptiveBlockCutter{
+			Enabled:             false,
+			MinMessageCount:     1,
+			TargetBlockInterval: time.Second,
+		},
+	},
 	Operations: Operations{
 		ListenAddress: "127.0.0.1:0",
 	},
@@ -287,8 +387,9 @@ var Defaults = TopLevel{
 		Provider: "disabled",
 	},
 	ChannelParticipation: ChannelParticipation{
-		Enabled:            false,
-		MaxRequestBodySize: 1024 * 1024,
+		Enabled:                false,
+		MaxRequestBodySize:     1024 * 1024,
+		MaxBulkRequestBodySize: 10 * 1024 * 1024,
 	},
 	Admin: Admin{
 		ListenAddress: "127.0.0.1:0",
@@ -361,6 +462,9 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			coreconfig.TranslatePathInPlace(configDir, &c.General.Cluster.ClientCertificate)
 		}
 		c.General.Cluster.RootCAs = translateCAs(configDir, c.General.Cluster.RootCAs)
+		if c.General.Cluster.ReplicationSeedDir != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.General.Cluster.ReplicationSeedDir)
+		}
 		// Translate any paths for general TLS configuration
 		c.General.TLS.RootCAs = translateCAs(configDir, c.General.TLS.RootCAs)
 		c.General.TLS.ClientRootCAs = translateCAs(configDir, c.General.TLS.ClientRootCAs)
@@ -486,6 +590,20 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", Defaults.Kafka.Version)
 			c.Kafka.Version = Defaults.Kafka.Version
 
+		case c.Broadcast.Dedup.Enabled && c.Broadcast.Dedup.WindowBlocks == 0:
+			logger.Infof("Broadcast.Dedup.WindowBlocks unset, setting to %v", Defaults.Broadcast.Dedup.WindowBlocks)
+			c.Broadcast.Dedup.WindowBlocks = Defaults.Broadcast.Dedup.WindowBlocks
+		case c.Broadcast.Dedup.Enabled && c.Broadcast.Dedup.MaxEntries == 0:
+			logger.Infof("Broadcast.Dedup.MaxEntries unset, setting to %v", Defaults.Broadcast.Dedup.MaxEntries)
+			c.Broadcast.Dedup.MaxEntries = Defaults.Broadcast.Dedup.MaxEntries
+
+		case c.BlockCutter.Adaptive.Enabled && c.BlockCutter.Adaptive.MinMessageCount == 0:
+			logger.Infof("BlockCutter.Adaptive.MinMessageCount unset, setting to %v", Defaults.BlockCutter.Adaptive.MinMessageCount)
+			c.BlockCutter.Adaptive.MinMessageCount = Defaults.BlockCutter.Adaptive.MinMessageCount
+		case c.BlockCutter.Adaptive.Enabled && c.BlockCutter.Adaptive.TargetBlockInterval == 0:
+			logger.Infof("BlockCutter.Adaptive.TargetBlockInterval unset, setting to %v", Defaults.BlockCutter.Adaptive.TargetBlockInterval)
+			c.BlockCutter.Adaptive.TargetBlockInterval = Defaults.BlockCutter.Adaptive.TargetBlockInterval
+
 		case c.Admin.TLS.Enabled && !c.Admin.TLS.ClientAuthRequired:
 			logger.Panic("Admin.TLS.ClientAuthRequired must be set to true if Admin.TLS.Enabled is set to true")
 
@@ -314,3 +314,106 @@ func TestChannelParticipationDefaults(t *testing.T) {
 	require.Equal(t, cfg.ChannelParticipation.Enabled, Defaults.ChannelParticipation.Enabled)
 	require.Equal(t, cfg.ChannelParticipation.MaxRequestBodySize, Defaults.ChannelParticipation.MaxRequestBodySize)
 }
+
+func TestBroadcastRateLimitConfig(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	cc := &configCache{}
+	cfg, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, BroadcastRateLimit{
+		Enabled: false,
+		Client:  TokenBucket{Rate: 100, Burst: 200},
+	}, cfg.Broadcast.RateLimit)
+}
+
+func TestBroadcastFiltersConfig(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	require.NoError(t, err)
+	defer os.RemoveAll(name)
+
+	config := `
+Broadcast:
+    Filters:
+        Plugins:
+            - Library: /path/to/rule.so
+              Channels: [mychannel]
+        Reject:
+            - Name: rule1
+              HeaderTypes: [CONFIG_UPDATE, ENDORSER_TRANSACTION]
+              MSPIDs: [Org3MSP]
+              PayloadPattern: ^data
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(name, "orderer.yaml"), []byte(config), 0600))
+	os.Setenv("FABRIC_CFG_PATH", name)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	cc := &configCache{}
+	cfg, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, BroadcastFilters{
+		Plugins: []FilterPlugin{
+			{Library: "/path/to/rule.so", Channels: []string{"mychannel"}},
+		},
+		Reject: []RejectRule{
+			{
+				Name:           "rule1",
+				HeaderTypes:    []string{"CONFIG_UPDATE", "ENDORSER_TRANSACTION"},
+				MSPIDs:         []string{"Org3MSP"},
+				PayloadPattern: "^data",
+			},
+		},
+	}, cfg.Broadcast.Filters)
+}
+
+func TestBroadcastDedupDefaults(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	require.NoError(t, err)
+	defer os.RemoveAll(name)
+
+	config := `
+Broadcast:
+    Dedup:
+        Enabled: true
+        WindowBlocks: 50
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(name, "orderer.yaml"), []byte(config), 0600))
+	os.Setenv("FABRIC_CFG_PATH", name)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	cc := &configCache{}
+	cfg, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, BroadcastDedup{
+		Enabled:      true,
+		WindowBlocks: 50,
+		MaxEntries:   100000,
+	}, cfg.Broadcast.Dedup)
+}
+
+func TestAdaptiveBlockCutterDefaults(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	require.NoError(t, err)
+	defer os.RemoveAll(name)
+
+	config := `
+BlockCutter:
+    Adaptive:
+        Enabled: true
+        Channels: [mychannel]
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(name, "orderer.yaml"), []byte(config), 0600))
+	os.Setenv("FABRIC_CFG_PATH", name)
+	defer os.Unsetenv("FABRIC_CFG_PATH")
+
+	cc := &configCache{}
+	cfg, err := cc.load()
+	require.NoError(t, err)
+	require.Equal(t, AdaptiveBlockCutter{
+		Enabled:             true,
+		MinMessageCount:     1,
+		TargetBlockInterval: time.Second,
+		Channels:            []string{"mychannel"},
+	}, cfg.BlockCutter.Adaptive)
+}
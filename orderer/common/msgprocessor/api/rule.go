@@ -0,0 +1,19 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rule
+
+import (
+	"github.com/hyperledger/fabric-protos-go/common"
+)
+
+// Rule accepts or rejects the messages broadcast to a channel.
+// A rule plugin exports a function "NewRule" of type func(channelID string) Rule,
+// which creates the Rule for a channel, or returns nil if the rule does not apply to the channel.
+type Rule interface {
+	// Apply returns an error if the message is rejected
+	Apply(message *common.Envelope) error
+}
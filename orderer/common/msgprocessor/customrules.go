@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"os"
+	"plugin"
+	"regexp"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	rule "github.com/hyperledger/fabric/orderer/common/msgprocessor/api"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// rulePluginFactory is the name of the function a rule plugin must export
+const rulePluginFactory = "NewRule"
+
+// CustomRules creates the rules configured by the operator for the standard channels
+type CustomRules struct {
+	rejectRules []*MatchRejectRule
+	plugins     []*rulePlugin
+}
+
+type rulePlugin struct {
+	factory  func(channelID string) rule.Rule
+	channels []string
+}
+
+// NewCustomRules loads the rule plugins and compiles the reject rules of the given configuration
+func NewCustomRules(conf localconfig.BroadcastFilters) (*CustomRules, error) {
+	c := &CustomRules{}
+	for _, ruleConf := range conf.Reject {
+		r, err := NewMatchRejectRule(ruleConf)
+		if err != nil {
+			return nil, err
+		}
+		c.rejectRules = append(c.rejectRules, r)
+	}
+	for _, pluginConf := range conf.Plugins {
+		factory, err := loadRulePlugin(pluginConf.Library)
+		if err != nil {
+			return nil, err
+		}
+		c.plugins = append(c.plugins, &rulePlugin{factory: factory, channels: pluginConf.Channels})
+	}
+	return c, nil
+}
+
+// ForChannel returns the custom rules that apply to the given channel, the reject rules first
+func (c *CustomRules) ForChannel(channelID string) []Rule {
+	var rules []Rule
+	for _, r := range c.rejectRules {
+		if appliesToChannel(r.channels, channelID) {
+			rules = append(rules, r)
+		}
+	}
+	for _, p := range c.plugins {
+		if !appliesToChannel(p.channels, channelID) {
+			continue
+		}
+		if r := p.factory(channelID); r != nil {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func loadRulePlugin(pluginPath string) (func(channelID string) rule.Rule, error) {
+	if _, err := os.Stat(pluginPath); err != nil {
+		return nil, errors.Wrapf(err, "could not find rule plugin at path %s", pluginPath)
+	}
+	p, err := plugin.Open(pluginPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening rule plugin at path %s", pluginPath)
+	}
+	factorySymbol, err := p.Lookup(rulePluginFactory)
+	if err != nil {
+		return nil, errors.Wrapf(err, "rule plugin at path %s must contain a function with name %s", pluginPath, rulePluginFactory)
+	}
+	factory, ok := factorySymbol.(func(string) rule.Rule)
+	if !ok {
+		return nil, errors.Errorf("function %s of rule plugin at path %s does not match the expected definition", rulePluginFactory, pluginPath)
+	}
+	return factory, nil
+}
+
+func appliesToChannel(channels []string, channelID string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, c := range channels {
+		if c == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchRejectRule implements the Rule interface. It rejects the messages that match all of its criteria
+type MatchRejectRule struct {
+	name           string
+	channels       []string
+	headerTypes    map[int32]struct{}
+	mspIDs         map[string]struct{}
+	payloadPattern *regexp.Regexp
+}
+
+// NewMatchRejectRule creates a rule which rejects the messages as per the given configuration
+func NewMatchRejectRule(conf localconfig.RejectRule) (*MatchRejectRule, error) {
+	r := &MatchRejectRule{
+		name:     conf.Name,
+		channels: conf.Channels,
+	}
+	if len(conf.HeaderTypes) > 0 {
+		r.headerTypes = map[int32]struct{}{}
+		for _, headerType := range conf.HeaderTypes {
+			value, ok := cb.HeaderType_value[headerType]
+			if !ok {
+				return nil, errors.Errorf("reject rule [%s]: unknown header type [%s]", conf.Name, headerType)
+			}
+			r.headerTypes[value] = struct{}{}
+		}
+	}
+	if len(conf.MSPIDs) > 0 {
+		r.mspIDs = map[string]struct{}{}
+		for _, mspID := range conf.MSPIDs {
+			r.mspIDs[mspID] = struct{}{}
+		}
+	}
+	if conf.PayloadPattern != "" {
+		pattern, err := regexp.Compile(conf.PayloadPattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reject rule [%s]: invalid payload pattern", conf.Name)
+		}
+		r.payloadPattern = pattern
+	}
+	if r.headerTypes == nil && r.mspIDs == nil && r.payloadPattern == nil {
+		// a rule without criteria would reject all the messages
+		return nil, errors.Errorf("reject rule [%s]: no criteria specified", conf.Name)
+	}
+	return r, nil
+}
+
+// Apply returns ErrPermissionDenied if the message matches all the criteria of the rule
+func (r *MatchRejectRule) Apply(message *cb.Envelope) error {
+	payload, err := protoutil.UnmarshalPayload(message.Payload)
+	if err != nil {
+		return err
+	}
+	if payload.Header == nil {
+		return errors.New("missing header")
+	}
+
+	if r.headerTypes != nil {
+		chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			return err
+		}
+		if _, ok := r.headerTypes[chdr.Type]; !ok {
+			return nil
+		}
+	}
+
+	if r.mspIDs != nil {
+		shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+		if err != nil {
+			return err
+		}
+		creator, err := protoutil.UnmarshalSerializedIdentity(shdr.Creator)
+		if err != nil {
+			return err
+		}
+		if _, ok := r.mspIDs[creator.Mspid]; !ok {
+			return nil
+		}
+	}
+
+	if r.payloadPattern != nil && !r.payloadPattern.Match(payload.Data) {
+		return nil
+	}
+
+	return errors.WithMessagef(ErrPermissionDenied, "message rejected by rule [%s]", r.name)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor/mocks"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func makeMatchingEnvelope(headerType cb.HeaderType, mspID string, data []byte) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(headerType),
+					ChannelId: testChannelID,
+				}),
+				SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{
+					Creator: protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID}),
+				}),
+			},
+			Data: data,
+		}),
+	}
+}
+
+func TestMatchRejectRule(t *testing.T) {
+	rule, err := NewMatchRejectRule(localconfig.RejectRule{
+		Name:           "rule1",
+		HeaderTypes:    []string{"ENDORSER_TRANSACTION", "CONFIG_UPDATE"},
+		MSPIDs:         []string{"Org3MSP"},
+		PayloadPattern: "^forbidden",
+	})
+	require.NoError(t, err)
+
+	err = rule.Apply(makeMatchingEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org3MSP", []byte("forbidden-data")))
+	require.EqualError(t, err, "message rejected by rule [rule1]: permission denied")
+	require.Equal(t, ErrPermissionDenied, errors.Cause(err))
+	require.Error(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_CONFIG_UPDATE, "Org3MSP", []byte("forbidden-data"))))
+
+	require.NoError(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_MESSAGE, "Org3MSP", []byte("forbidden-data"))))
+	require.NoError(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", []byte("forbidden-data"))))
+	require.NoError(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "Org3MSP", []byte("allowed-data"))))
+
+	t.Run("single criterion", func(t *testing.T) {
+		rule, err := NewMatchRejectRule(localconfig.RejectRule{Name: "rule2", MSPIDs: []string{"Org3MSP"}})
+		require.NoError(t, err)
+		require.Error(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_MESSAGE, "Org3MSP", nil)))
+		require.NoError(t, rule.Apply(makeMatchingEnvelope(cb.HeaderType_MESSAGE, "Org1MSP", nil)))
+	})
+
+	t.Run("malformed message", func(t *testing.T) {
+		require.Error(t, rule.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+		require.EqualError(t, rule.Apply(&cb.Envelope{Payload: protoutil.MarshalOrPanic(&cb.Payload{})}), "missing header")
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := NewMatchRejectRule(localconfig.RejectRule{Name: "rule3"})
+		require.EqualError(t, err, "reject rule [rule3]: no criteria specified")
+		_, err = NewMatchRejectRule(localconfig.RejectRule{Name: "rule3", HeaderTypes: []string{"UNKNOWN"}})
+		require.EqualError(t, err, "reject rule [rule3]: unknown header type [UNKNOWN]")
+		_, err = NewMatchRejectRule(localconfig.RejectRule{Name: "rule3", PayloadPattern: "("})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "reject rule [rule3]: invalid payload pattern")
+	})
+}
+
+func TestCustomRules(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "ruleplugin")
+	require.NoError(t, err)
+	defer os.RemoveAll(testDir)
+	pluginPath := filepath.Join(testDir, "rule.so")
+	cmd := exec.Command("go", "build", "-o", pluginPath, "-buildmode=plugin", "./testdata/ruleplugin")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "Could not build plugin: "+string(output))
+
+	customRules, err := NewCustomRules(localconfig.BroadcastFilters{
+		Plugins: []localconfig.FilterPlugin{
+			{Library: pluginPath},
+			{Library: pluginPath, Channels: []string{"other"}},
+		},
+		Reject: []localconfig.RejectRule{
+			{Name: "rule1", MSPIDs: []string{"Org3MSP"}},
+			{Name: "rule2", MSPIDs: []string{"Org2MSP"}, Channels: []string{"other"}},
+		},
+	})
+	require.NoError(t, err)
+
+	rules := customRules.ForChannel(testChannelID)
+	require.Len(t, rules, 2)
+	require.Error(t, rules[0].Apply(makeMatchingEnvelope(cb.HeaderType_MESSAGE, "Org3MSP", nil)))
+	require.EqualError(t, rules[1].Apply(makeMatchingEnvelope(cb.HeaderType_MESSAGE, "Org1MSP", nil)), "unsigned message rejected on channel foo")
+
+	require.Len(t, customRules.ForChannel("other"), 4)
+	// the plugin does not create a rule for this channel
+	require.Len(t, customRules.ForChannel("exempt"), 1)
+
+	t.Run("standard channel filters", func(t *testing.T) {
+		configtxValidator := &mocks.ConfigTXValidator{}
+		configtxValidator.ChannelIDReturns(testChannelID)
+		resources := &mocks.Resources{}
+		resources.ConfigtxValidatorReturns(configtxValidator)
+		config := localconfig.TopLevel{}
+		config.Broadcast.Filters.Plugins = []localconfig.FilterPlugin{{Library: pluginPath}}
+
+		ruleSet := CreateStandardChannelFilters(resources, config)
+		require.Len(t, ruleSet.rules, 5)
+		require.EqualError(t, ruleSet.rules[4].Apply(&cb.Envelope{}), "unsigned message rejected on channel foo")
+	})
+
+	t.Run("plugin not found", func(t *testing.T) {
+		_, err := NewCustomRules(localconfig.BroadcastFilters{
+			Plugins: []localconfig.FilterPlugin{{Library: filepath.Join(testDir, "missing.so")}},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not find rule plugin at path")
+	})
+
+	t.Run("invalid reject rule", func(t *testing.T) {
+		_, err := NewCustomRules(localconfig.BroadcastFilters{
+			Reject: []localconfig.RejectRule{{Name: "rule1"}},
+		})
+		require.EqualError(t, err, "reject rule [rule1]: no criteria specified")
+	})
+}
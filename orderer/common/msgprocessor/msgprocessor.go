@@ -35,6 +35,10 @@ var ErrPermissionDenied = errors.New("permission denied")
 // as defined by ConsensusType.State != NORMAL. This typically happens during consensus-type migration.
 var ErrMaintenanceMode = errors.New("maintenance mode")
 
+// ErrDuplicateTxID is returned when transactions are rejected because a transaction with the
+// same TxID was recently ordered on the channel.
+var ErrDuplicateTxID = errors.New("duplicate transaction ID")
+
 // Classification represents the possible message types for the system.
 type Classification int
 
@@ -46,8 +46,9 @@ type StandardChannelSupport interface {
 // StandardChannel implements the Processor interface for standard extant channels
 type StandardChannel struct {
 	support           StandardChannelSupport
-	filters           *RuleSet // Rules applicable to both normal and config messages
-	maintenanceFilter Rule     // Rule applicable only to config messages
+	filters           *RuleSet   // Rules applicable to both normal and config messages
+	maintenanceFilter Rule       // Rule applicable only to config messages
+	txIDCache         *TxIDCache // Rejects the normal messages whose TxID was recently ordered, if not nil
 }
 
 // NewStandardChannel creates a new standard message processor
@@ -59,6 +60,12 @@ func NewStandardChannel(support StandardChannelSupport, filters *RuleSet, bccsp
 	}
 }
 
+// WithTxIDCache makes the processor reject the normal messages whose TxID is in the given cache.
+func (s *StandardChannel) WithTxIDCache(cache *TxIDCache) *StandardChannel {
+	s.txIDCache = cache
+	return s
+}
+
 // CreateStandardChannelFilters creates the set of filters for a normal (non-system) chain.
 //
 // In maintenance mode, require the signature of /Channel/Orderer/Writer. This will filter out configuration
@@ -76,6 +83,16 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources, config
 		rules = append(rules[:2], append([]Rule{expirationRule}, rules[2:]...)...)
 	}
 
+	// The custom rules are evaluated last, so that they see only the messages of authorized creators
+	filters := config.Broadcast.Filters
+	if len(filters.Reject) > 0 || len(filters.Plugins) > 0 {
+		customRules, err := NewCustomRules(filters)
+		if err != nil {
+			logger.Panicf("Failed creating the custom message filters: %s", err)
+		}
+		rules = append(rules, customRules.ForChannel(filterSupport.ConfigtxValidator().ChannelID())...)
+	}
+
 	return NewRuleSet(rules)
 }
 
@@ -111,6 +128,9 @@ func (s *StandardChannel) ProcessNormalMsg(env *cb.Envelope) (configSeq uint64,
 
 	configSeq = s.support.Sequence()
 	err = s.filters.Apply(env)
+	if err == nil && s.txIDCache != nil {
+		err = s.txIDCache.Apply(env)
+	}
 	return
 }
 
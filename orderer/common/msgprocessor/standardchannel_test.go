@@ -94,6 +94,23 @@ func TestProcessNormalMsg(t *testing.T) {
 		_, err = NewStandardChannel(ms, NewRuleSet([]Rule{AcceptRule}), cryptoProvider).ProcessNormalMsg(nil)
 		require.EqualError(t, err, "normal transactions are rejected: maintenance mode")
 	})
+	t.Run("DuplicateTxID", func(t *testing.T) {
+		ms := &mockSystemChannelFilterSupport{
+			SequenceVal:      7,
+			OrdererConfigVal: newMockOrdererConfig(true, orderer.ConsensusType_STATE_NORMAL),
+		}
+		cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+		require.NoError(t, err)
+		cache := NewTxIDCache(10, 10)
+		cache.Record(makeTxBlock(3, makeTxEnvelope(t, "tx1")))
+		stdChan := NewStandardChannel(ms, NewRuleSet([]Rule{AcceptRule}), cryptoProvider).WithTxIDCache(cache)
+
+		_, err = stdChan.ProcessNormalMsg(makeTxEnvelope(t, "tx1"))
+		require.EqualError(t, err, "duplicate transaction ID: transaction tx1 was already ordered in block [3]")
+		cs, err := stdChan.ProcessNormalMsg(makeTxEnvelope(t, "tx2"))
+		require.NoError(t, err)
+		require.Equal(t, ms.SequenceVal, cs)
+	})
 }
 
 func TestConfigUpdateMsg(t *testing.T) {
@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	rule "github.com/hyperledger/fabric/orderer/common/msgprocessor/api"
+	"github.com/pkg/errors"
+)
+
+// NewRule creates a rule which rejects the messages with an empty signature,
+// except on the channel "exempt"
+func NewRule(channelID string) rule.Rule {
+	if channelID == "exempt" {
+		return nil
+	}
+	return &unsignedRejectRule{channelID: channelID}
+}
+
+type unsignedRejectRule struct {
+	channelID string
+}
+
+func (r *unsignedRejectRule) Apply(message *cb.Envelope) error {
+	if len(message.Signature) == 0 {
+		return errors.Errorf("unsigned message rejected on channel %s", r.channelID)
+	}
+	return nil
+}
+
+func main() {
+}
@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// TxIDCache remembers the TxIDs of the transactions recently ordered on a channel, so that the messages reusing
+// them, e.g. the retries of clients which timed out, are rejected before they are ordered again. A TxID is
+// remembered while its block is among the window of the most recent blocks, and the number of remembered TxIDs
+// is bounded, the oldest being forgotten first.
+//
+// The cache is fed with the blocks written to the ledger, so all the orderers of a channel remember the same TxIDs
+// regardless of which of them ordered the transactions, and the cache of a new leader is up to date. When the
+// chain is created, the cache is rebuilt from the most recent blocks of the ledger. As the TxIDs expire by block
+// height rather than by the clock of the orderer, the orderers which re-validate the ordered messages, as the
+// Kafka consenter does, reach the same decision for a message ordered after the same blocks.
+//
+// The messages which are still being ordered are not in the cache, as the consenters re-validate them with the
+// same processor when the config sequence advances, so a duplicate submitted before its original is written may
+// still be ordered, and is then invalidated by the peers.
+type TxIDCache struct {
+	windowBlocks uint64
+	maxEntries   int
+
+	mutex     sync.Mutex
+	lastBlock uint64 // The number of the most recent block recorded
+	entries   map[string]*list.Element
+	order     *list.List // The entries in the order they were added
+}
+
+type txIDEntry struct {
+	txID  string
+	block uint64
+}
+
+// NewTxIDCache creates an empty TxIDCache, which remembers the TxIDs of the last windowBlocks blocks.
+func NewTxIDCache(windowBlocks uint64, maxEntries int) *TxIDCache {
+	return &TxIDCache{
+		windowBlocks: windowBlocks,
+		maxEntries:   maxEntries,
+		entries:      map[string]*list.Element{},
+		order:        list.New(),
+	}
+}
+
+// Apply returns an error caused by ErrDuplicateTxID if the TxID of the message was ordered in one of the blocks of
+// the window.
+func (c *TxIDCache) Apply(message *cb.Envelope) error {
+	chdr, err := protoutil.ChannelHeader(message)
+	if err != nil {
+		return errors.WithMessage(err, "could not get channel header")
+	}
+	if chdr.TxId == "" {
+		return nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[chdr.TxId]
+	if !ok {
+		return nil
+	}
+	return &duplicateTxIDError{txID: chdr.TxId, block: element.Value.(*txIDEntry).block}
+}
+
+// duplicateTxIDError is caused by ErrDuplicateTxID, and starts with it, so that the clients can tell a duplicate
+// from the other messages rejected with status BAD_REQUEST by the prefix of the info of the response.
+type duplicateTxIDError struct {
+	txID  string
+	block uint64
+}
+
+func (e *duplicateTxIDError) Error() string {
+	return fmt.Sprintf("%s: transaction %s was already ordered in block [%d]", ErrDuplicateTxID, e.txID, e.block)
+}
+
+func (e *duplicateTxIDError) Cause() error {
+	return ErrDuplicateTxID
+}
+
+// Record adds the TxIDs of the transactions of a block written to the ledger.
+func (c *TxIDCache) Record(block *cb.Block) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.record(block)
+	c.expire()
+}
+
+// Rebuild adds the TxIDs of the transactions of the blocks of the ledger which are within the window.
+func (c *TxIDCache) Rebuild(reader blockledger.Reader) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The blocks are read from the newest one, until a block is out of the window or the cache is full,
+	// and are then recorded from the oldest one, so that the oldest TxIDs are forgotten first.
+	var blocks []*cb.Block
+	var count int
+	height := reader.Height()
+	for seq := height; seq > 1 && height-seq < c.windowBlocks && count < c.maxEntries; seq-- {
+		block := blockledger.GetBlock(reader, seq-1)
+		if block == nil {
+			logger.Warningf("Could not read block [%d] to rebuild the TxID cache", seq-1)
+			break
+		}
+		blocks = append(blocks, block)
+		count += len(c.txIDs(block))
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		c.record(blocks[i])
+	}
+	c.expire()
+
+	logger.Debugf("Rebuilt the TxID cache with %d TxIDs from %d blocks", c.order.Len(), len(blocks))
+}
+
+// Len returns the number of TxIDs in the cache.
+func (c *TxIDCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+func (c *TxIDCache) record(block *cb.Block) {
+	if number := block.GetHeader().GetNumber(); number > c.lastBlock {
+		c.lastBlock = number
+	}
+	for _, entry := range c.txIDs(block) {
+		if element, ok := c.entries[entry.txID]; ok {
+			// The TxID is already known, e.g. if the same transaction was ordered twice before the cache was fed.
+			element.Value.(*txIDEntry).block = entry.block
+			c.order.MoveToBack(element)
+			continue
+		}
+		c.entries[entry.txID] = c.order.PushBack(entry)
+	}
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Front())
+	}
+}
+
+// txIDs returns the TxIDs of the transactions of the block.
+func (c *TxIDCache) txIDs(block *cb.Block) []*txIDEntry {
+	var entries []*txIDEntry
+	for _, envBytes := range block.GetData().GetData() {
+		env, err := protoutil.UnmarshalEnvelope(envBytes)
+		if err != nil {
+			continue
+		}
+		chdr, err := protoutil.ChannelHeader(env)
+		if err != nil || chdr.TxId == "" {
+			continue
+		}
+		entries = append(entries, &txIDEntry{txID: chdr.TxId, block: block.GetHeader().GetNumber()})
+	}
+	return entries
+}
+
+// expire removes the TxIDs of the blocks out of the window. The entries are added in the order of their blocks,
+// so it stops at the first entry within the window.
+func (c *TxIDCache) expire() {
+	for element := c.order.Front(); element != nil && c.lastBlock-element.Value.(*txIDEntry).block >= c.windowBlocks; element = c.order.Front() {
+		c.remove(element)
+	}
+}
+
+func (c *TxIDCache) remove(element *list.Element) {
+	delete(c.entries, element.Value.(*txIDEntry).txID)
+	c.order.Remove(element)
+}
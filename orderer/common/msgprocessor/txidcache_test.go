@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTxEnvelope(t *testing.T, txID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: testChannelID,
+					TxId:      txID,
+				}),
+			},
+		}),
+	}
+}
+
+func makeTxBlock(number uint64, envs ...*cb.Envelope) *cb.Block {
+	block := protoutil.NewBlock(number, nil)
+	for _, env := range envs {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(env))
+	}
+	return block
+}
+
+func TestTxIDCache(t *testing.T) {
+	cache := NewTxIDCache(10, 3)
+
+	cache.Record(makeTxBlock(1, makeTxEnvelope(t, "tx1"), makeTxEnvelope(t, "tx2")))
+	require.Equal(t, 2, cache.Len())
+
+	err := cache.Apply(makeTxEnvelope(t, "tx1"))
+	require.EqualError(t, err, "duplicate transaction ID: transaction tx1 was already ordered in block [1]")
+	require.Equal(t, ErrDuplicateTxID, errors.Cause(err))
+	require.NoError(t, cache.Apply(makeTxEnvelope(t, "tx3")))
+	require.NoError(t, cache.Apply(makeTxEnvelope(t, "")), "a message without TxID is not checked")
+	require.Error(t, cache.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+
+	t.Run("bounded", func(t *testing.T) {
+		cache.Record(makeTxBlock(2, makeTxEnvelope(t, "tx3"), makeTxEnvelope(t, "tx4")))
+		require.Equal(t, 3, cache.Len())
+		require.NoError(t, cache.Apply(makeTxEnvelope(t, "tx1")), "the oldest TxID is forgotten first")
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx2")))
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx4")))
+	})
+
+	t.Run("windowed by block height", func(t *testing.T) {
+		cache := NewTxIDCache(2, 10)
+		cache.Record(makeTxBlock(1, makeTxEnvelope(t, "tx1")))
+		cache.Record(makeTxBlock(2, makeTxEnvelope(t, "tx2")))
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx1")))
+
+		cache.Record(makeTxBlock(3))
+		require.NoError(t, cache.Apply(makeTxEnvelope(t, "tx1")), "block [1] is out of the window")
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx2")))
+		require.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("ordered again", func(t *testing.T) {
+		cache := NewTxIDCache(2, 10)
+		cache.Record(makeTxBlock(1, makeTxEnvelope(t, "tx1")))
+		cache.Record(makeTxBlock(2, makeTxEnvelope(t, "tx1")))
+		cache.Record(makeTxBlock(3))
+		err := cache.Apply(makeTxEnvelope(t, "tx1"))
+		require.EqualError(t, err, "duplicate transaction ID: transaction tx1 was already ordered in block [2]")
+	})
+}
+
+func TestTxIDCacheRebuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txid-cache-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	lf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+	ledger, err := lf.GetOrCreate(testChannelID)
+	require.NoError(t, err)
+
+	blocks := []*cb.Block{
+		protoutil.NewBlock(0, nil),
+		makeTxBlock(1, makeTxEnvelope(t, "tx1")),
+		makeTxBlock(2, makeTxEnvelope(t, "tx2"), makeTxEnvelope(t, "tx3")),
+		makeTxBlock(3, makeTxEnvelope(t, "tx4")),
+	}
+	for i, block := range blocks {
+		if i > 0 {
+			block.Header.PreviousHash = protoutil.BlockHeaderHash(blocks[i-1].Header)
+		}
+		require.NoError(t, ledger.Append(block))
+	}
+
+	t.Run("within the window", func(t *testing.T) {
+		cache := NewTxIDCache(2, 10)
+		cache.Rebuild(ledger)
+		require.Equal(t, 3, cache.Len())
+		require.NoError(t, cache.Apply(makeTxEnvelope(t, "tx1")))
+		for _, txID := range []string{"tx2", "tx3", "tx4"} {
+			require.Error(t, cache.Apply(makeTxEnvelope(t, txID)), txID)
+		}
+
+		// the rebuilt cache expires the TxIDs as the cache which recorded the blocks
+		cache.Record(makeTxBlock(4))
+		require.Equal(t, 1, cache.Len())
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx4")))
+	})
+
+	t.Run("up to the maximum number of entries", func(t *testing.T) {
+		cache := NewTxIDCache(10, 2)
+		cache.Rebuild(ledger)
+		require.Equal(t, 2, cache.Len())
+		require.NoError(t, cache.Apply(makeTxEnvelope(t, "tx2")), "the oldest TxID is forgotten first")
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx3")))
+		require.Error(t, cache.Apply(makeTxEnvelope(t, "tx4")))
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/protoutil"
 )
 
@@ -39,6 +40,7 @@ type BlockWriter struct {
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
 	committingBlock    sync.Mutex
+	txIDCache          *msgprocessor.TxIDCache // Records the TxIDs of the written blocks, if not nil
 }
 
 func newBlockWriter(lastBlock *cb.Block, r *Registrar, support blockWriterSupport) *BlockWriter {
@@ -168,6 +170,11 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 func (bw *BlockWriter) WriteBlock(block *cb.Block, encodedMetadataValue []byte) {
 	bw.committingBlock.Lock()
 	bw.lastBlock = block
+	// The TxIDs are recorded before returning, so that the messages the consenter validates next are checked
+	// against the same blocks regardless of how long the block takes to commit.
+	if bw.txIDCache != nil {
+		bw.txIDCache.Record(block)
+	}
 
 	go func() {
 		defer bw.committingBlock.Unlock()
@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/multichannel/mocks"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
@@ -112,6 +113,47 @@ func TestBlockSignature(t *testing.T) {
 	require.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestWriteBlockRecordsTxIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "file-ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	rlf, err := fileledger.New(dir, &disabled.Provider{})
+	require.NoError(t, err)
+
+	l, err := rlf.GetOrCreate("mychannel")
+	require.NoError(t, err)
+	lastBlock := protoutil.NewBlock(0, nil)
+	l.Append(lastBlock)
+
+	txIDCache := msgprocessor.NewTxIDCache(10, 10)
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			SignerSerializer:  mockCrypto(),
+			ConfigTXValidator: &mocks.ConfigTXValidator{},
+			ReadWriter:        l,
+		},
+		lastBlock: lastBlock,
+		txIDCache: txIDCache,
+	}
+
+	chdr := protoutil.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "mychannel", 0)
+	chdr.TxId = "tx1"
+	env := &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{ChannelHeader: protoutil.MarshalOrPanic(chdr)},
+		}),
+	}
+
+	bw.WriteBlock(bw.CreateNextBlock([]*cb.Envelope{env}), nil)
+	err = txIDCache.Apply(env)
+	require.EqualError(t, err, "duplicate transaction ID: transaction tx1 was already ordered in block [1]")
+
+	bw.committingBlock.Lock()
+	defer bw.committingBlock.Unlock()
+	require.Equal(t, uint64(2), l.Height())
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
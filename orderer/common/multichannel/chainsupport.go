@@ -63,19 +63,17 @@ func newChainSupport(
 	cs := &ChainSupport{
 		ledgerResources:  ledgerResources,
 		SignerSerializer: signer,
-		cutter: blockcutter.NewReceiverImpl(
-			ledgerResources.ConfigtxValidator().ChannelID(),
-			ledgerResources,
-			blockcutterMetrics,
-		),
-		BCCSP: bccsp,
+		cutter:           newBlockCutter(registrar.config.BlockCutter.Adaptive, ledgerResources, blockcutterMetrics),
+		BCCSP:            bccsp,
 	}
 
 	// Set up the msgprocessor
-	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp)
+	txIDCache := newTxIDCache(registrar.config.Broadcast.Dedup, ledgerResources)
+	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp).WithTxIDCache(txIDCache)
 
 	// Set up the block writer
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
+	cs.BlockWriter.txIDCache = txIDCache
 
 	// Set up the consenter
 	consenterType := ledgerResources.SharedConfig().ConsensusType()
@@ -107,6 +105,55 @@ func newChainSupport(
 	return cs, nil
 }
 
+// newBlockCutter returns the block cutter of the channel, which is adaptive if the adaptive mode is
+// enabled for the channel and its consensus type cuts the blocks on a single node.
+func newBlockCutter(config localconfig.AdaptiveBlockCutter, ledgerResources *ledgerResources, metrics *blockcutter.Metrics) blockcutter.Receiver {
+	channelID := ledgerResources.ConfigtxValidator().ChannelID()
+	if !config.Enabled || !includesChannel(config.Channels, channelID) {
+		return blockcutter.NewReceiverImpl(channelID, ledgerResources, metrics)
+	}
+
+	// Each Kafka-based orderer cuts the blocks of the channel independently, so the batches must
+	// not depend on the local arrival times of the messages.
+	if consensusType := ledgerResources.SharedConfig().ConsensusType(); consensusType == "kafka" {
+		logger.Warningf("[channel: %s] Adaptive block cutting is not supported by consensus type %s, using the BatchSize of the channel", channelID, consensusType)
+		return blockcutter.NewReceiverImpl(channelID, ledgerResources, metrics)
+	}
+
+	logger.Infof("[channel: %s] Adaptive block cutting enabled, min message count: %d, target block interval: %s; "+
+		"these local settings apply while this orderer leads the channel, and should match on its other orderers", channelID, config.MinMessageCount, config.TargetBlockInterval)
+	return blockcutter.NewAdaptiveReceiverImpl(channelID, ledgerResources, metrics, blockcutter.AdaptiveConfig{
+		MinMessageCount:     config.MinMessageCount,
+		TargetBlockInterval: config.TargetBlockInterval,
+	})
+}
+
+// newTxIDCache returns the TxID cache of the channel, rebuilt from the most recent blocks of its ledger,
+// or nil if the deduplication is not enabled for the channel.
+func newTxIDCache(config localconfig.BroadcastDedup, ledgerResources *ledgerResources) *msgprocessor.TxIDCache {
+	channelID := ledgerResources.ConfigtxValidator().ChannelID()
+	if !config.Enabled || !includesChannel(config.Channels, channelID) {
+		return nil
+	}
+
+	cache := msgprocessor.NewTxIDCache(config.WindowBlocks, config.MaxEntries)
+	cache.Rebuild(ledgerResources)
+	logger.Infof("[channel: %s] TxID deduplication enabled, window: %d blocks, remembering %d recent TxIDs", channelID, config.WindowBlocks, cache.Len())
+	return cache
+}
+
+func includesChannel(channels []string, channelID string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for _, channel := range channels {
+		if channel == channelID {
+			return true
+		}
+	}
+	return false
+}
+
 func (cs *ChainSupport) Reader() blockledger.Reader {
 	return cs
 }
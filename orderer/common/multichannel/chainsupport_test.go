@@ -8,12 +8,16 @@ package multichannel
 
 import (
 	"testing"
+	"time"
 
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/types"
 
 	"github.com/hyperledger/fabric-protos-go/common"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	msgprocessormocks "github.com/hyperledger/fabric/orderer/common/msgprocessor/mocks"
 	"github.com/hyperledger/fabric/orderer/common/multichannel/mocks"
@@ -115,3 +119,53 @@ func TestNewOnboardingChainSupport(t *testing.T) {
 			ChannelId: "mychannel",
 		}), "Message processor is initialized")
 }
+
+func TestNewBlockCutter(t *testing.T) {
+	mockValidator := &mocks.ConfigTXValidator{}
+	mockValidator.ChannelIDReturns("mychannel")
+	mockOrderer := &mocks.OrdererConfig{}
+	mockOrderer.BatchSizeReturns(&ab.BatchSize{MaxMessageCount: 10, PreferredMaxBytes: 1000})
+	mockOrderer.ConsensusTypeReturns("etcdraft")
+	mockResources := &mocks.Resources{}
+	mockResources.ConfigtxValidatorReturns(mockValidator)
+	mockResources.OrdererConfigReturns(mockOrderer, true)
+	ledgerRes := &ledgerResources{
+		configResources: &configResources{
+			mutableResources: &mutableResourcesMock{Resources: mockResources},
+		},
+	}
+	metrics := blockcutter.NewMetrics(&disabled.Provider{})
+	adaptive := localconfig.AdaptiveBlockCutter{
+		Enabled:             true,
+		MinMessageCount:     1,
+		TargetBlockInterval: time.Nanosecond,
+	}
+
+	// A batch is cut for each message while the arrival rate is unknown in the adaptive mode.
+	isAdaptive := func(config localconfig.AdaptiveBlockCutter) bool {
+		batches, _ := newBlockCutter(config, ledgerRes, metrics).Ordered(&common.Envelope{Payload: []byte("payload")})
+		return len(batches) == 1
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		require.False(t, isAdaptive(localconfig.AdaptiveBlockCutter{}))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		require.True(t, isAdaptive(adaptive))
+	})
+
+	t.Run("enabled for other channels", func(t *testing.T) {
+		config := adaptive
+		config.Channels = []string{"otherchannel"}
+		require.False(t, isAdaptive(config))
+		config.Channels = []string{"otherchannel", "mychannel"}
+		require.True(t, isAdaptive(config))
+	})
+
+	t.Run("kafka", func(t *testing.T) {
+		mockOrderer.ConsensusTypeReturns("kafka")
+		defer mockOrderer.ConsensusTypeReturns("etcdraft")
+		require.False(t, isAdaptive(adaptive))
+	})
+}
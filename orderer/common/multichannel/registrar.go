@@ -694,6 +694,9 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	if c, ok := r.chains[channelID]; ok {
 		info.Height = c.Height()
 		info.ConsensusRelation, info.Status = c.StatusReport()
+		if cir, ok := c.Chain.(consensus.ClusterInfoReporter); ok {
+			info.Cluster = cir.ClusterInfo()
+		}
 		return info, nil
 	}
 
@@ -950,6 +953,32 @@ func (r *Registrar) RemoveChannel(channelID string) error {
 	return types.ErrChannelNotExist
 }
 
+// TransferLeadership instructs the orderer to transfer the leadership of the consensus cluster of a channel
+// to the given consenter. It returns the ID of the new leader.
+func (r *Registrar) TransferLeadership(channelID string, transferee uint64) (uint64, error) {
+	r.lock.RLock()
+	cs, isMember := r.chains[channelID]
+	_, isFollower := r.followers[channelID]
+	_, isPendingRemoval := r.pendingRemoval[channelID]
+	r.lock.RUnlock()
+
+	switch {
+	case isPendingRemoval:
+		return 0, types.ErrChannelPendingRemoval
+	case isFollower:
+		return 0, types.ErrLeadershipTransferNotSupported
+	case !isMember:
+		return 0, types.ErrChannelNotExist
+	}
+
+	// The lock is not held while the leadership is transferred, as it takes up to an election timeout
+	lt, ok := cs.Chain.(consensus.LeadershipTransferrer)
+	if !ok {
+		return 0, types.ErrLeadershipTransferNotSupported
+	}
+	return lt.TransferLeadership(transferee)
+}
+
 func (r *Registrar) removeMember(channelID string, cs *ChainSupport) {
 	relation, status := cs.StatusReport()
 	r.pendingRemoval[channelID] = consensus.StaticStatusReporter{ConsensusRelation: relation, Status: status}
@@ -34,6 +34,7 @@ import (
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/follower"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/multichannel/mocks"
 	"github.com/hyperledger/fabric/orderer/common/types"
@@ -1756,6 +1757,117 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 	})
 }
 
+type mockLeadershipTransferrer struct {
+	consensus.Chain
+	leader uint64
+	err    error
+}
+
+func (m *mockLeadershipTransferrer) TransferLeadership(transferee uint64) (uint64, error) {
+	return m.leader, m.err
+}
+
+func TestRegistrar_TransferLeadership(t *testing.T) {
+	registrar := &Registrar{
+		chains: map[string]*ChainSupport{
+			"raft-channel": {Chain: &mockLeadershipTransferrer{leader: 2}},
+			"failing-raft-channel": {
+				Chain: &mockLeadershipTransferrer{err: errors.New("timed out waiting for leadership to be transferred to 2")},
+			},
+			"solo-channel": {Chain: &mockChain{}},
+		},
+		followers: map[string]*follower.Chain{
+			"follower-channel": {},
+		},
+		pendingRemoval: map[string]consensus.StaticStatusReporter{
+			"removed-channel": {ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusInactive},
+		},
+	}
+
+	leader, err := registrar.TransferLeadership("raft-channel", 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), leader)
+
+	_, err = registrar.TransferLeadership("failing-raft-channel", 2)
+	require.EqualError(t, err, "timed out waiting for leadership to be transferred to 2")
+
+	_, err = registrar.TransferLeadership("solo-channel", 2)
+	require.Equal(t, types.ErrLeadershipTransferNotSupported, err)
+
+	_, err = registrar.TransferLeadership("follower-channel", 2)
+	require.Equal(t, types.ErrLeadershipTransferNotSupported, err)
+
+	_, err = registrar.TransferLeadership("removed-channel", 2)
+	require.Equal(t, types.ErrChannelPendingRemoval, err)
+
+	_, err = registrar.TransferLeadership("missing-channel", 2)
+	require.Equal(t, types.ErrChannelNotExist, err)
+}
+
+type mockClusterInfoReporter struct {
+	consensus.Chain
+	consensus.StaticStatusReporter
+	clusterInfo *types.ClusterInfo
+}
+
+func (m *mockClusterInfoReporter) ClusterInfo() *types.ClusterInfo {
+	return m.clusterInfo
+}
+
+func TestRegistrar_ChannelInfoCluster(t *testing.T) {
+	clusterInfo := &types.ClusterInfo{
+		ID:             1,
+		Leader:         2,
+		Term:           3,
+		CommittedIndex: 7,
+		AppliedIndex:   7,
+		Consenters: []types.ConsenterInfo{
+			{ID: 1, Host: "orderer1", Port: 7050, Active: true},
+			{ID: 2, Host: "orderer2", Port: 7050, Active: true},
+		},
+	}
+	reporter := &mockClusterInfoReporter{
+		StaticStatusReporter: consensus.StaticStatusReporter{ConsensusRelation: types.ConsensusRelationConsenter, Status: types.StatusActive},
+		clusterInfo:          clusterInfo,
+	}
+	ledger := &mocks.ReadWriter{}
+	ledger.HeightReturns(5)
+
+	registrar := &Registrar{
+		chains: map[string]*ChainSupport{
+			"raft-channel": {
+				ledgerResources: &ledgerResources{ReadWriter: ledger},
+				Chain:           reporter,
+				StatusReporter:  reporter,
+			},
+			"solo-channel": {
+				ledgerResources: &ledgerResources{ReadWriter: ledger},
+				Chain:           &mockChain{},
+				StatusReporter:  consensus.StaticStatusReporter{ConsensusRelation: types.ConsensusRelationOther, Status: types.StatusActive},
+			},
+		},
+	}
+
+	info, err := registrar.ChannelInfo("raft-channel")
+	require.NoError(t, err)
+	require.Equal(t, types.ChannelInfo{
+		Name:              "raft-channel",
+		ConsensusRelation: types.ConsensusRelationConsenter,
+		Status:            types.StatusActive,
+		Height:            5,
+		Cluster:           clusterInfo,
+	}, info)
+
+	info, err = registrar.ChannelInfo("solo-channel")
+	require.NoError(t, err)
+	require.Equal(t, types.ChannelInfo{
+		Name:              "solo-channel",
+		ConsensusRelation: types.ConsensusRelationOther,
+		Status:            types.StatusActive,
+		Height:            5,
+	}, info)
+}
+
 func generateCertificates(t *testing.T, confAppRaft *genesisconfig.Profile, tlsCA tlsgen.CA, certDir string) {
 	for i, c := range confAppRaft.Orderer.EtcdRaft.Consenters {
 		srvC, err := tlsCA.NewServerCertKeyPair(c.Host)
@@ -41,10 +41,12 @@ import (
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/common/onboarding"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -65,6 +67,7 @@ var (
 
 	_       = app.Command("start", "Start the orderer node").Default() // preserved for cli compatibility
 	version = app.Command("version", "Show version information")
+	raftCmd = newRaftCommands(app)
 
 	clusterTypes = map[string]struct{}{"etcdraft": {}}
 )
@@ -79,6 +82,15 @@ func Main() {
 		return
 	}
 
+	// "raft" commands
+	if raftCmd.handles(fullCmd) {
+		if err := raftCmd.execute(fullCmd, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	conf, err := localconfig.Load()
 	if err != nil {
 		logger.Error("failed to parse config: ", err)
@@ -112,6 +124,11 @@ func Main() {
 		clientRootCAs:         serverConfig.SecOpts.ClientRootCAs,
 	}
 
+	// The custom message filters are created with each channel, so the configuration is validated upfront
+	if _, err := msgprocessor.NewCustomRules(conf.Broadcast.Filters); err != nil {
+		logger.Panicf("Failed creating the custom message filters: %v", err)
+	}
+
 	lf, err := createLedgerFactory(conf, metricsProvider)
 	if err != nil {
 		logger.Panicf("Failed to create ledger factory: %v", err)
@@ -269,6 +286,11 @@ func Main() {
 	defer adminServer.Stop()
 
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
+	var broadcastRateLimiter broadcast.RateLimiter
+	if conf.Broadcast.RateLimit.Enabled {
+		logger.Infof("Broadcast rate limit enabled: client %+v, channel %+v", conf.Broadcast.RateLimit.Client, conf.Broadcast.RateLimit.Channel)
+		broadcastRateLimiter = broadcast.NewTokenBucketRateLimiter(conf.Broadcast.RateLimit)
+	}
 	server := NewServer(
 		manager,
 		metricsProvider,
@@ -276,6 +298,7 @@ func Main() {
 		conf.General.Authentication.TimeWindow,
 		mutualTLS,
 		conf.General.Authentication.NoExpirationChecks,
+		broadcastRateLimiter,
 	)
 
 	logger.Infof("Starting %s", metadata.GetVersionInfo())
@@ -0,0 +1,187 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"go.etcd.io/etcd/raft/raftpb"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// raftCommands are the "raft" subcommands, which inspect and repair the etcdraft WAL and snapshots of
+// a channel while the orderer is stopped. The directories are those of the channel, i.e., the WALDir
+// and SnapDir of the etcdraft configuration joined with the channel name.
+type raftCommands struct {
+	raft *kingpin.CmdClause
+
+	snapshots     *kingpin.CmdClause
+	snapshotsSnap *string
+
+	dump     *kingpin.CmdClause
+	dumpWAL  *string
+	dumpSnap *string
+	dumpFrom *uint64
+
+	state     *kingpin.CmdClause
+	stateWAL  *string
+	stateSnap *string
+
+	repair     *kingpin.CmdClause
+	repairWAL  *string
+	repairSnap *string
+}
+
+func newRaftCommands(app *kingpin.Application) *raftCommands {
+	c := &raftCommands{}
+	c.raft = app.Command("raft", "Inspect and repair the etcdraft WAL and snapshots of a channel, while the orderer is stopped")
+
+	c.snapshots = c.raft.Command("snapshots", "List the snapshots of the channel")
+	c.snapshotsSnap = c.snapshots.Flag("snapdir", "The snapshot directory of the channel").Required().String()
+
+	c.dump = c.raft.Command("dump", "Dump the WAL entries that follow the latest snapshot, with the headers of the blocks")
+	c.dumpWAL = c.dump.Flag("waldir", "The WAL directory of the channel").Required().String()
+	c.dumpSnap = c.dump.Flag("snapdir", "The snapshot directory of the channel").Required().String()
+	c.dumpFrom = c.dump.Flag("from", "The index of the first entry to dump").Uint64()
+
+	c.state = c.raft.Command("state", "Show the HardState and the ConfState of the channel")
+	c.stateWAL = c.state.Flag("waldir", "The WAL directory of the channel").Required().String()
+	c.stateSnap = c.state.Flag("snapdir", "The snapshot directory of the channel").Required().String()
+
+	c.repair = c.raft.Command("repair", "Truncate a torn write at the tail of the WAL")
+	c.repairWAL = c.repair.Flag("waldir", "The WAL directory of the channel").Required().String()
+	c.repairSnap = c.repair.Flag("snapdir", "The snapshot directory of the channel").Required().String()
+	return c
+}
+
+// handles returns true if the given command is a "raft" subcommand
+func (c *raftCommands) handles(fullCmd string) bool {
+	switch fullCmd {
+	case c.snapshots.FullCommand(), c.dump.FullCommand(), c.state.FullCommand(), c.repair.FullCommand():
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *raftCommands) execute(fullCmd string, out io.Writer) error {
+	lg := flogging.MustGetLogger("orderer.consensus.etcdraft")
+	switch fullCmd {
+	case c.snapshots.FullCommand():
+		return listRaftSnapshots(lg, *c.snapshotsSnap, out)
+	case c.dump.FullCommand():
+		return dumpRaftEntries(lg, *c.dumpWAL, *c.dumpSnap, *c.dumpFrom, out)
+	case c.state.FullCommand():
+		return showRaftState(lg, *c.stateWAL, *c.stateSnap, out)
+	case c.repair.FullCommand():
+		return repairRaftWAL(lg, *c.repairWAL, *c.repairSnap, out)
+	default:
+		return errors.Errorf("unknown command %s", fullCmd)
+	}
+}
+
+func listRaftSnapshots(lg *flogging.FabricLogger, snapDir string, out io.Writer) error {
+	snapshots, corrupted, err := etcdraft.ReadSnapshotsMetadata(lg, snapDir)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Fprintf(out, "No snapshots found at %s\n", snapDir)
+	}
+	for _, s := range snapshots {
+		fmt.Fprintf(out, "Snapshot at index %d, term %d, %s\n", s.Index, s.Term, formatConfState(s.ConfState))
+	}
+	for _, snapFile := range corrupted {
+		fmt.Fprintf(out, "Snapshot file %s is corrupted\n", snapFile)
+	}
+	return nil
+}
+
+func dumpRaftEntries(lg *flogging.FabricLogger, walDir, snapDir string, from uint64, out io.Writer) error {
+	data, err := etcdraft.ReadRaftData(lg, walDir, snapDir)
+	if err != nil {
+		return err
+	}
+	for _, e := range data.Entries {
+		if e.Index < from {
+			continue
+		}
+		committed := ""
+		if e.Index > data.HardState.Commit {
+			committed = " (not committed)"
+		}
+		fmt.Fprintf(out, "Index %d, term %d%s: %s\n", e.Index, e.Term, committed, describeEntry(e))
+	}
+	return nil
+}
+
+func describeEntry(e raftpb.Entry) string {
+	switch e.Type {
+	case raftpb.EntryConfChange:
+		var cc raftpb.ConfChange
+		if err := cc.Unmarshal(e.Data); err != nil {
+			return fmt.Sprintf("malformed conf change: %s", err)
+		}
+		return fmt.Sprintf("conf change %s of node %d", cc.Type, cc.NodeID)
+	default:
+		if len(e.Data) == 0 {
+			return "empty entry"
+		}
+		block, err := protoutil.UnmarshalBlock(e.Data)
+		if err != nil || block.Header == nil {
+			return fmt.Sprintf("entry of %d bytes that is not a block", len(e.Data))
+		}
+		txCount := 0
+		if block.Data != nil {
+			txCount = len(block.Data.Data)
+		}
+		return fmt.Sprintf("block [%d], previous hash %x, data hash %x, %d transactions",
+			block.Header.Number, block.Header.PreviousHash, block.Header.DataHash, txCount)
+	}
+}
+
+func showRaftState(lg *flogging.FabricLogger, walDir, snapDir string, out io.Writer) error {
+	data, err := etcdraft.ReadRaftData(lg, walDir, snapDir)
+	if err != nil {
+		return err
+	}
+	if data.Snapshot != nil {
+		fmt.Fprintf(out, "Snapshot: index %d, term %d\n", data.Snapshot.Metadata.Index, data.Snapshot.Metadata.Term)
+	} else {
+		fmt.Fprintln(out, "Snapshot: none")
+	}
+	fmt.Fprintf(out, "HardState: term %d, vote %d, commit %d\n", data.HardState.Term, data.HardState.Vote, data.HardState.Commit)
+	fmt.Fprintf(out, "ConfState: %s\n", formatConfState(data.ConfState()))
+	if len(data.Entries) == 0 {
+		fmt.Fprintln(out, "Entries: none")
+	} else {
+		fmt.Fprintf(out, "Entries: %d, from index %d to %d\n", len(data.Entries), data.Entries[0].Index, data.Entries[len(data.Entries)-1].Index)
+	}
+	return nil
+}
+
+func repairRaftWAL(lg *flogging.FabricLogger, walDir, snapDir string, out io.Writer) error {
+	repaired, err := etcdraft.RepairWAL(lg, walDir, snapDir)
+	if err != nil {
+		return err
+	}
+	if repaired {
+		fmt.Fprintln(out, "Truncated the torn write at the tail of the WAL, the truncated file is backed up with the suffix .broken")
+	} else {
+		fmt.Fprintln(out, "The WAL is intact, no repair needed")
+	}
+	return nil
+}
+
+func formatConfState(cs raftpb.ConfState) string {
+	return fmt.Sprintf("nodes %v, learners %v", cs.Nodes, cs.Learners)
+}
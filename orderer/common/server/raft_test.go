@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package server
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft"
+	"go.etcd.io/etcd/raft/raftpb"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func TestRaftCommands(t *testing.T) {
+	dataDir, err := ioutil.TempDir("", "raft-commands")
+	require.NoError(t, err)
+	defer os.RemoveAll(dataDir)
+	walDir, snapDir := filepath.Join(dataDir, "wal", "mychannel"), filepath.Join(dataDir, "snapshot", "mychannel")
+
+	lg := flogging.MustGetLogger("test")
+	storage, err := etcdraft.CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage())
+	require.NoError(t, err)
+	confChange, err := (&raftpb.ConfChange{Type: raftpb.ConfChangeRemoveNode, NodeID: 2}).Marshal()
+	require.NoError(t, err)
+	block1 := protoutil.NewBlock(1, []byte("prev-hash"))
+	block1.Header.DataHash = []byte("data-hash")
+	block1.Data.Data = [][]byte{[]byte("tx1"), []byte("tx2")}
+	entries := []raftpb.Entry{
+		{Term: 1, Index: 1, Data: protoutil.MarshalOrPanic(protoutil.NewBlock(0, nil))},
+		{Term: 1, Index: 2},
+		{Term: 2, Index: 3, Data: protoutil.MarshalOrPanic(block1)},
+		{Term: 2, Index: 4, Type: raftpb.EntryConfChange, Data: confChange},
+		{Term: 2, Index: 5, Data: []byte("garbage")},
+	}
+	require.NoError(t, storage.Store(entries, raftpb.HardState{Term: 2, Vote: 1, Commit: 4}, raftpb.Snapshot{}))
+	require.NoError(t, storage.TakeSnapshot(1, raftpb.ConfState{Nodes: []uint64{1, 2, 3}}, nil))
+	require.NoError(t, storage.Close())
+
+	execute := func(args ...string) string {
+		app := kingpin.New("orderer", "")
+		cmds := newRaftCommands(app)
+		fullCmd, err := app.Parse(args)
+		require.NoError(t, err)
+		require.True(t, cmds.handles(fullCmd))
+		out := &bytes.Buffer{}
+		require.NoError(t, cmds.execute(fullCmd, out))
+		return out.String()
+	}
+
+	require.Equal(t,
+		"Snapshot at index 1, term 1, nodes [1 2 3], learners []\n",
+		execute("raft", "snapshots", "--snapdir", snapDir),
+	)
+
+	require.Equal(t,
+		"Snapshot: index 1, term 1\n"+
+			"HardState: term 2, vote 1, commit 4\n"+
+			"ConfState: nodes [1 3], learners []\n"+
+			"Entries: 4, from index 2 to 5\n",
+		execute("raft", "state", "--waldir", walDir, "--snapdir", snapDir),
+	)
+
+	require.Equal(t,
+		"Index 2, term 1: empty entry\n"+
+			"Index 3, term 2: block [1], previous hash 707265762d68617368, data hash 646174612d68617368, 2 transactions\n"+
+			"Index 4, term 2: conf change ConfChangeRemoveNode of node 2\n"+
+			"Index 5, term 2 (not committed): entry of 7 bytes that is not a block\n",
+		execute("raft", "dump", "--waldir", walDir, "--snapdir", snapDir),
+	)
+	require.Equal(t,
+		"Index 4, term 2: conf change ConfChangeRemoveNode of node 2\n"+
+			"Index 5, term 2 (not committed): entry of 7 bytes that is not a block\n",
+		execute("raft", "dump", "--waldir", walDir, "--snapdir", snapDir, "--from", "4"),
+	)
+
+	require.Equal(t,
+		"The WAL is intact, no repair needed\n",
+		execute("raft", "repair", "--waldir", walDir, "--snapdir", snapDir),
+	)
+
+	t.Run("corrupted snapshot", func(t *testing.T) {
+		corrupted := filepath.Join(snapDir, "0000000000000002-0000000000000004.snap")
+		require.NoError(t, ioutil.WriteFile(corrupted, []byte("corrupted"), 0o644))
+		defer os.Remove(corrupted)
+
+		require.Equal(t,
+			"Snapshot at index 1, term 1, nodes [1 2 3], learners []\n"+
+				"Snapshot file "+corrupted+" is corrupted\n",
+			execute("raft", "snapshots", "--snapdir", snapDir),
+		)
+		_, err := os.Stat(corrupted)
+		require.NoError(t, err)
+	})
+
+	t.Run("missing WAL", func(t *testing.T) {
+		app := kingpin.New("orderer", "")
+		cmds := newRaftCommands(app)
+		fullCmd, err := app.Parse([]string{"raft", "state", "--waldir", filepath.Join(dataDir, "missing"), "--snapdir", snapDir})
+		require.NoError(t, err)
+		err = cmds.execute(fullCmd, &bytes.Buffer{})
+		require.EqualError(t, err, "no WAL data found at "+filepath.Join(dataDir, "missing"))
+	})
+
+	t.Run("other commands", func(t *testing.T) {
+		app := kingpin.New("orderer", "")
+		cmds := newRaftCommands(app)
+		version := app.Command("version", "")
+		fullCmd, err := app.Parse([]string{"version"})
+		require.NoError(t, err)
+		require.Equal(t, version.FullCommand(), fullCmd)
+		require.False(t, cmds.handles(fullCmd))
+	})
+}
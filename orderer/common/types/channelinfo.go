@@ -6,6 +6,8 @@ SPDX-License-Identifier: Apache-2.0
 
 package types
 
+import "time"
+
 // ErrorResponse carries the error response an HTTP request.
 // This is marshaled into the body of the HTTP response.
 type ErrorResponse struct {
@@ -81,4 +83,102 @@ type ChannelInfo struct {
 	Status Status `json:"status"`
 	// Current block height.
 	Height uint64 `json:"height"`
+	// The details of the consensus cluster, as seen by the orderer.
+	// Only reported by a consenter of a cluster consensus type (etcdraft), nil otherwise.
+	Cluster *ClusterInfo `json:"cluster,omitempty"`
+}
+
+// ClusterInfo carries the details of the consensus cluster of a channel, as seen by a consenter of the channel.
+type ClusterInfo struct {
+	// The consenter ID of the orderer.
+	ID uint64 `json:"id"`
+	// The consenter ID of the leader, 0 if the orderer does not know of any leader.
+	Leader uint64 `json:"leader"`
+	// The current Raft term.
+	Term uint64 `json:"term"`
+	// The index of the last Raft entry known to be committed.
+	CommittedIndex uint64 `json:"committedIndex"`
+	// The index of the last Raft entry applied by the orderer.
+	AppliedIndex uint64 `json:"appliedIndex"`
+	// Whether the orderer suspects it was evicted from the channel, since it has not known of any leader for longer
+	// than the eviction suspicion threshold.
+	EvictionSuspected bool `json:"evictionSuspected"`
+	// The change of the consenters set that is in progress, nil if there is none.
+	PendingConfigChange *ConfigChange `json:"pendingConfigChange,omitempty"`
+	// The consenters of the channel, ordered by ID.
+	Consenters []ConsenterInfo `json:"consenters"`
+}
+
+// ConsenterInfo carries the state of a consenter of a channel, as seen by another consenter of the channel.
+type ConsenterInfo struct {
+	// The consenter ID.
+	ID uint64 `json:"id"`
+	// The host and port of the consenter.
+	Host string `json:"host"`
+	Port uint32 `json:"port"`
+	// Whether the consenter is active, i.e., it recently communicated with the leader.
+	Active bool `json:"active"`
+	// The last time the orderer received a consensus message from the consenter, nil if it never did.
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// ConfigChangeType is the type of a change of the consenters set of a channel.
+type ConfigChangeType string
+
+const (
+	// A consenter is added to the consenters set.
+	ConfigChangeAddNode ConfigChangeType = "add-node"
+	// A consenter is removed from the consenters set.
+	ConfigChangeRemoveNode ConfigChangeType = "remove-node"
+)
+
+// ConfigChange carries a change of the consenters set of a channel.
+type ConfigChange struct {
+	// The type of change, "add-node" or "remove-node".
+	Type ConfigChangeType `json:"type"`
+	// The consenter ID of the added or removed consenter.
+	ID uint64 `json:"id"`
+}
+
+// LeadershipTransferRequest carries the body of an HTTP request to transfer the leadership of a channel.
+type LeadershipTransferRequest struct {
+	// The consenter ID of the orderer the leadership is transferred to.
+	Transferee uint64 `json:"transferee"`
+}
+
+// LeadershipTransferResponse carries the response to an HTTP request to transfer the leadership of a channel.
+// This is marshaled into the body of the HTTP response.
+type LeadershipTransferResponse struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The consenter ID of the leader after the transfer.
+	Leader uint64 `json:"leader"`
+}
+
+// NotLeaderResponse carries the error response to an HTTP request to transfer the leadership of a channel sent to
+// an orderer which neither leads the channel nor is the transferee, along with the leader to send it to instead.
+// This is marshaled into the body of the HTTP response.
+type NotLeaderResponse struct {
+	Error string `json:"error"`
+	// The consenter ID of the leader.
+	Leader uint64 `json:"leader"`
+}
+
+// BulkJoinResponse carries the response to an HTTP request to join several channels.
+// This is marshaled into the body of the HTTP response.
+type BulkJoinResponse struct {
+	// The results of joining the channels, in the order of the config blocks in the request.
+	Channels []BulkJoinResult `json:"channels"`
+}
+
+// BulkJoinResult carries the result of joining a single channel, as part of a bulk join.
+type BulkJoinResult struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The HTTP status code the join would have got, had the channel been joined on its own.
+	StatusCode int `json:"statusCode"`
+	// The channel info if the join succeeded, nil otherwise.
+	Info *ChannelInfo `json:"info,omitempty"`
+	// The error if the join failed.
+	Error string `json:"error,omitempty"`
 }
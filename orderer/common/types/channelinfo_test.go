@@ -9,6 +9,7 @@ package types_test
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/stretchr/testify/require"
@@ -89,3 +90,53 @@ func TestChannelInfo(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, info.Height, info2.Height)
 }
+
+func TestLeadershipTransfer(t *testing.T) {
+	var req types.LeadershipTransferRequest
+	err := json.Unmarshal([]byte(`{"transferee":3}`), &req)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), req.Transferee)
+
+	buff, err := json.Marshal(types.LeadershipTransferResponse{Name: "a", Leader: 3})
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"a","leader":3}`, string(buff))
+}
+
+func TestChannelInfoCluster(t *testing.T) {
+	lastSeen := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	info := types.ChannelInfo{
+		Name:              "a",
+		URL:               "/api/channels/a",
+		ConsensusRelation: types.ConsensusRelationConsenter,
+		Status:            types.StatusActive,
+		Height:            5,
+		Cluster: &types.ClusterInfo{
+			ID:             1,
+			Leader:         2,
+			Term:           3,
+			CommittedIndex: 10,
+			AppliedIndex:   9,
+			PendingConfigChange: &types.ConfigChange{
+				Type: types.ConfigChangeRemoveNode,
+				ID:   3,
+			},
+			Consenters: []types.ConsenterInfo{
+				{ID: 1, Host: "orderer1", Port: 7050, Active: true},
+				{ID: 2, Host: "orderer2", Port: 7050, Active: true, LastSeen: &lastSeen},
+			},
+		},
+	}
+
+	buff, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"a","url":"/api/channels/a","consensusRelation":"consenter","status":"active","height":5,`+
+		`"cluster":{"id":1,"leader":2,"term":3,"committedIndex":10,"appliedIndex":9,"evictionSuspected":false,`+
+		`"pendingConfigChange":{"type":"remove-node","id":3},"consenters":[`+
+		`{"id":1,"host":"orderer1","port":7050,"active":true},`+
+		`{"id":2,"host":"orderer2","port":7050,"active":true,"lastSeen":"2021-03-04T05:06:07Z"}]}}`, string(buff))
+
+	var info2 types.ChannelInfo
+	err = json.Unmarshal(buff, &info2)
+	require.NoError(t, err)
+	require.Equal(t, info, info2)
+}
@@ -6,7 +6,11 @@ SPDX-License-Identifier: Apache-2.0
 
 package types
 
-import "github.com/pkg/errors"
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
 
 // ErrSystemChannelExists is returned when trying to join or remove an application channel when the system channel exists.
 var ErrSystemChannelExists = errors.New("system channel exists")
@@ -27,3 +31,18 @@ var ErrChannelPendingRemoval = errors.New("channel pending removal")
 
 // ErrChannelRemovalFailure is returned when a removal attempt failure has been recorded.
 var ErrChannelRemovalFailure = errors.New("channel removal failure")
+
+// ErrLeadershipTransferNotSupported is returned when trying to transfer the leadership of a channel whose consensus
+// type does not support it, or on which the orderer is not a consenter.
+var ErrLeadershipTransferNotSupported = errors.New("leadership transfer not supported")
+
+// NotLeaderError is returned when trying to transfer the leadership of a channel through an orderer which neither
+// leads the channel nor is the transferee. The request should be sent to the leader instead.
+type NotLeaderError struct {
+	// The consenter ID of the leader.
+	Leader uint64
+}
+
+func (e *NotLeaderError) Error() string {
+	return fmt.Sprintf("not leader, leader is %d", e.Leader)
+}
@@ -30,3 +30,11 @@ type StaticStatusReporter struct {
 func (s StaticStatusReporter) StatusReport() (types.ConsensusRelation, types.Status) {
 	return s.ConsensusRelation, s.Status
 }
+
+// ClusterInfoReporter is implemented by cluster-type Chain implementations that can report the details of their
+// consensus cluster (etcdraft). This information is added to the channelparticipation.ChannelInfo in response
+// to a "List" request on a particular channel.
+type ClusterInfoReporter interface {
+	// ClusterInfo provides the details of the consensus cluster, or nil if the chain is not running.
+	ClusterInfo() *types.ClusterInfo
+}
@@ -49,6 +49,15 @@ type MetadataValidator interface {
 	ValidateConsensusMetadata(oldOrdererConfig, newOrdererConfig channelconfig.Orderer, newChannel bool) error
 }
 
+// LeadershipTransferrer is implemented by Chain implementations of leader based cluster consensus types (etcdraft).
+// NOTE: We expect the LeadershipTransferrer interface to be optionally implemented by the Chain implementation.
+//       If a Chain does not implement LeadershipTransferrer, leadership transfer is not supported on the channel.
+type LeadershipTransferrer interface {
+	// TransferLeadership asks the leader of the cluster to transfer the leadership to the given consenter,
+	// and waits until a leader is elected or the election timeout expires. It returns the ID of the new leader.
+	TransferLeadership(transferee uint64) (uint64, error)
+}
+
 // Chain defines a way to inject messages for ordering.
 // Note, that in order to allow flexibility in the implementation, it is the responsibility of the implementer
 // to take the ordered messages, send them through the blockcutter.Receiver supplied via HandleChain to cut blocks,
@@ -10,6 +10,7 @@ import (
 	"context"
 	"encoding/pem"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -148,8 +149,10 @@ type Chain struct {
 	raftID    uint64
 	channelID string
 
-	lastKnownLeader uint64
-	ActiveNodes     atomic.Value
+	lastKnownLeader   uint64
+	evictionSuspected uint32 // set to 1 while the eviction of this node is suspected, accessed atomically
+	ActiveNodes       atomic.Value
+	pendingConfChange atomic.Value // copy of confChangeInProgress, which can be loaded outside of run
 
 	submitC  chan *submit
 	applyC   chan apply
@@ -506,6 +509,7 @@ func (c *Chain) Consensus(req *orderer.ConsensusRequest, sender uint64) error {
 	if err := c.Node.Step(context.TODO(), *stepMsg); err != nil {
 		return fmt.Errorf("failed to process Raft Step message: %s", err)
 	}
+	c.Node.tracker.Seen(sender, c.clock.Now())
 
 	if len(req.Metadata) == 0 || atomic.LoadUint64(&c.lastKnownLeader) != sender { // ignore metadata from non-leader
 		return nil
@@ -616,7 +620,7 @@ func (c *Chain) run() {
 				}
 			}()
 
-			c.confChangeInProgress = cc
+			c.setConfChangeInProgress(cc)
 			c.configInflight = true
 		}
 
@@ -1062,7 +1066,7 @@ func (c *Chain) apply(ents []raftpb.Entry) {
 				c.confChangeInProgress.Type == cc.Type {
 
 				configureComm = true
-				c.confChangeInProgress = nil
+				c.setConfChangeInProgress(nil)
 				c.configInflight = false
 				// report the new cluster size
 				c.Metrics.ClusterSize.Set(float64(len(c.opts.BlockMetadata.ConsenterIds)))
@@ -1233,7 +1237,7 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 				}
 			}()
 
-			c.confChangeInProgress = configMembership.ConfChange
+			c.setConfChangeInProgress(configMembership.ConfChange)
 
 			switch configMembership.ConfChange.Type {
 			case raftpb.ConfChangeAddNode:
@@ -1276,6 +1280,12 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 	}
 }
 
+// setConfChangeInProgress sets the ConfChange in-flight, and the copy of it reported by ClusterInfo
+func (c *Chain) setConfChangeInProgress(cc *raftpb.ConfChange) {
+	c.confChangeInProgress = cc
+	c.pendingConfChange.Store(cc)
+}
+
 // getInFlightConfChange returns ConfChange in-flight if any.
 // It returns confChangeInProgress if it is not nil. Otherwise
 // it returns ConfChange from the last committed block (might be nil).
@@ -1403,6 +1413,71 @@ func (c *Chain) StatusReport() (types.ConsensusRelation, types.Status) {
 	return c.consensusRelation, c.status
 }
 
+// TransferLeadership transfers the leadership of the cluster to the given consenter, and waits until
+// a leader is elected or the election timeout expires. It returns the ID of the new leader.
+func (c *Chain) TransferLeadership(transferee uint64) (uint64, error) {
+	if err := c.isRunning(); err != nil {
+		return raft.None, err
+	}
+
+	c.raftMetadataLock.RLock()
+	_, exists := c.opts.Consenters[transferee]
+	c.raftMetadataLock.RUnlock()
+	if !exists {
+		return raft.None, errors.Errorf("node %d is not a consenter of the channel", transferee)
+	}
+
+	return c.Node.transferLeadership(transferee)
+}
+
+// ClusterInfo returns the details of the Raft cluster as seen by this node, or nil if the chain is not running
+func (c *Chain) ClusterInfo() *types.ClusterInfo {
+	if c.isRunning() != nil {
+		return nil
+	}
+
+	status := c.Node.Status()
+	info := &types.ClusterInfo{
+		ID:             c.raftID,
+		Leader:         status.Lead,
+		Term:           status.Term,
+		CommittedIndex: status.Commit,
+		AppliedIndex:   status.Applied,
+		// the suspicion is cleared by the periodic check only, which may not have run since a leader was elected
+		EvictionSuspected: status.Lead == raft.None && atomic.LoadUint32(&c.evictionSuspected) == 1,
+	}
+
+	if cc, _ := c.pendingConfChange.Load().(*raftpb.ConfChange); cc != nil {
+		info.PendingConfigChange = &types.ConfigChange{ID: cc.NodeID, Type: types.ConfigChangeAddNode}
+		if cc.Type == raftpb.ConfChangeRemoveNode {
+			info.PendingConfigChange.Type = types.ConfigChangeRemoveNode
+		}
+	}
+
+	active := map[uint64]bool{}
+	for _, id := range c.ActiveNodes.Load().([]uint64) {
+		active[id] = true
+	}
+
+	c.raftMetadataLock.RLock()
+	for id, consenter := range c.opts.Consenters {
+		consenterInfo := types.ConsenterInfo{
+			ID:     id,
+			Host:   consenter.Host,
+			Port:   consenter.Port,
+			Active: active[id],
+		}
+		if lastSeen, ok := c.Node.tracker.LastSeen(id); ok {
+			consenterInfo.LastSeen = &lastSeen
+		}
+		info.Consenters = append(info.Consenters, consenterInfo)
+	}
+	c.raftMetadataLock.RUnlock()
+
+	sort.Slice(info.Consenters, func(i, j int) bool { return info.Consenters[i].ID < info.Consenters[j].ID })
+	return info
+}
+
 func (c *Chain) suspectEviction() bool {
 	if c.isRunning() != nil {
 		return false
@@ -1426,6 +1501,7 @@ func (c *Chain) newEvictionSuspector() *evictionSuspector {
 		height:                     c.support.Height,
 		triggerCatchUp:             c.triggerCatchup,
 		logger:                     c.logger,
+		suspected:                  &c.evictionSuspected,
 		halt: func() {
 			c.halt()
 		},
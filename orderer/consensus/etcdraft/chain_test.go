@@ -2640,6 +2640,105 @@ var _ = Describe("Chain", func() {
 				})
 			})
 
+			It("reports the state of the cluster", func() {
+				By("ticking the leader until it tracks the followers as active")
+				Eventually(func() []uint64 {
+					c1.clock.Increment(interval)
+					return c1.ActiveNodes.Load().([]uint64)
+				}, LongEventualTimeout).Should(HaveLen(3))
+
+				info := c1.ClusterInfo()
+				Expect(info).NotTo(BeNil())
+				Expect(info.ID).To(Equal(uint64(1)))
+				Expect(info.Leader).To(Equal(uint64(1)))
+				Expect(info.Term).To(Equal(c1.Node.Status().Term))
+				Expect(info.CommittedIndex).To(BeNumerically(">", 0))
+				Expect(info.AppliedIndex).To(BeNumerically("<=", info.CommittedIndex))
+				Expect(info.EvictionSuspected).To(BeFalse())
+				Expect(info.PendingConfigChange).To(BeNil())
+				Expect(info.Consenters).To(HaveLen(3))
+				for i, consenter := range info.Consenters {
+					Expect(consenter.ID).To(Equal(uint64(i + 1)))
+					Expect(consenter.Host).To(Equal("localhost"))
+					Expect(consenter.Port).To(Equal(uint32(7051)))
+					Expect(consenter.Active).To(BeTrue())
+				}
+				Expect(info.Consenters[0].LastSeen).To(BeNil())
+				Expect(info.Consenters[1].LastSeen).NotTo(BeNil())
+				Expect(info.Consenters[2].LastSeen).NotTo(BeNil())
+
+				By("reporting the leader on a follower")
+				info = c2.ClusterInfo()
+				Expect(info).NotTo(BeNil())
+				Expect(info.ID).To(Equal(uint64(2)))
+				Expect(info.Leader).To(Equal(uint64(1)))
+				Expect(info.Consenters[0].LastSeen).NotTo(BeNil())
+
+				By("reporting nothing once halted")
+				c2.Halt()
+				Expect(c2.ClusterInfo()).To(BeNil())
+			})
+
+			When("leadership transfer is requested", func() {
+				It("rejects the request on a follower which is not the transferee", func() {
+					_, err := c2.TransferLeadership(3)
+					Expect(err).To(Equal(&orderer_types.NotLeaderError{Leader: 1}))
+					Expect(err).To(MatchError("not leader, leader is 1"))
+					Consistently(c1.observe).ShouldNot(Receive())
+				})
+
+				It("transfers leadership when requested on the leader", func() {
+					leader, err := c1.TransferLeadership(3)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(leader).To(Equal(uint64(3)))
+
+					Eventually(c3.observe, LongEventualTimeout).Should(Receive(StateEqual(3, raft.StateLeader)))
+					Eventually(c1.observe, LongEventualTimeout).Should(Receive(StateEqual(3, raft.StateFollower)))
+
+					By("ordering an envelope on the new leader")
+					c3.cutter.CutNext = true
+					Expect(c3.Order(env, 0)).To(Succeed())
+					network.exec(func(c *chain) {
+						Eventually(c.support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+					})
+				})
+
+				It("transfers leadership when requested on the transferee", func() {
+					leader, err := c2.TransferLeadership(2)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(leader).To(Equal(uint64(2)))
+					Eventually(c2.observe, LongEventualTimeout).Should(Receive(StateEqual(2, raft.StateLeader)))
+				})
+
+				It("does nothing when the transferee is the leader", func() {
+					leader, err := c2.TransferLeadership(1)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(leader).To(Equal(uint64(1)))
+					Consistently(c1.observe).ShouldNot(Receive())
+				})
+
+				It("rejects a transferee that is not a consenter", func() {
+					_, err := c1.TransferLeadership(4)
+					Expect(err).To(MatchError("node 4 is not a consenter of the channel"))
+				})
+
+				It("times out when the transferee is disconnected", func() {
+					network.disconnect(3)
+
+					errC := make(chan error, 1)
+					go func() {
+						_, err := c1.TransferLeadership(3)
+						errC <- err
+					}()
+
+					Eventually(func() <-chan error {
+						c1.clock.Increment(interval)
+						return errC
+					}, LongEventualTimeout).Should(Receive(MatchError("timed out waiting for leadership to be transferred to 3")))
+					Expect(c1.Node.Status().Lead).To(Equal(uint64(1)))
+				})
+			})
+
 			When("leader is disconnected", func() {
 				It("proactively steps down to follower", func() {
 					network.disconnect(1)
@@ -86,10 +86,12 @@ type evictionSuspector struct {
 	triggerCatchUp             func(sn *raftpb.Snapshot)
 	halted                     bool
 	timesTriggered             int
+	suspected                  *uint32 // set to 1 while the eviction is suspected, accessed atomically
 }
 
 func (es *evictionSuspector) clearSuspicion() {
 	es.timesTriggered = 0
+	atomic.StoreUint32(es.suspected, 0)
 }
 
 func (es *evictionSuspector) confirmSuspicion(cumulativeSuspicion time.Duration) {
@@ -98,6 +100,7 @@ func (es *evictionSuspector) confirmSuspicion(cumulativeSuspicion time.Duration)
 		return
 	}
 	es.timesTriggered++
+	atomic.StoreUint32(es.suspected, 1)
 
 	es.logger.Infof("Suspecting our own eviction from the channel for %v", cumulativeSuspicion)
 	puller, err := es.createPuller()
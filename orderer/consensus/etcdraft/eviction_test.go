@@ -238,6 +238,7 @@ func TestEvictionSuspector(t *testing.T) {
 				logger:         flogging.MustGetLogger("test"),
 				triggerCatchUp: func(sn *raftpb.Snapshot) {},
 				timesTriggered: testCase.timesTriggered,
+				suspected:      new(uint32),
 			}
 
 			foundExpectedLog := testCase.expectedLog == ""
@@ -266,6 +267,10 @@ func TestEvictionSuspector(t *testing.T) {
 
 			require.True(t, foundExpectedLog, "expected to find %s but didn't", testCase.expectedLog)
 			require.Equal(t, testCase.expectedCommittedBlockCount, len(committedBlocks))
+			// the eviction is reported as suspected once the threshold is exceeded, until the suspicion is cleared
+			require.Equal(t, es.timesTriggered > testCase.timesTriggered, atomic.LoadUint32(es.suspected) == 1)
+			es.clearSuspicion()
+			require.Equal(t, uint32(0), atomic.LoadUint32(es.suspected))
 		})
 	}
 }
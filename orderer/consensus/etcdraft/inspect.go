@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"go.etcd.io/etcd/etcdserver/api/snap"
+	"go.etcd.io/etcd/raft/raftpb"
+	"go.etcd.io/etcd/wal"
+	"go.etcd.io/etcd/wal/walpb"
+)
+
+// The functions in this file inspect and repair the etcd/raft data of a channel offline,
+// i.e., while the orderer is stopped.
+
+// RaftData is the etcd/raft data of a channel as loaded by the orderer on start,
+// i.e., the latest snapshot and the WAL entries that follow it
+type RaftData struct {
+	Snapshot  *raftpb.Snapshot // nil if there is no snapshot
+	HardState raftpb.HardState
+	Entries   []raftpb.Entry
+}
+
+// ReadSnapshotsMetadata returns the metadata of the snapshots stored on disk, ordered by index,
+// and the paths of the snapshot files that cannot be decoded. Unlike ListSnapshots, it leaves
+// the corrupted snapshot files as they are.
+func ReadSnapshotsMetadata(lg *flogging.FabricLogger, snapDir string) ([]raftpb.SnapshotMetadata, []string, error) {
+	snapshots, corrupted, err := readSnapshots(lg, snapDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var metadata []raftpb.SnapshotMetadata
+	for _, s := range snapshots {
+		metadata = append(metadata, s.Metadata)
+	}
+	return metadata, corrupted, nil
+}
+
+// readSnapshots decodes the snapshot files stored on disk and returns the snapshots ordered by
+// index, and the paths of the snapshot files that cannot be decoded.
+func readSnapshots(lg *flogging.FabricLogger, snapDir string) ([]*raftpb.Snapshot, []string, error) {
+	snapFiles, err := filepath.Glob(filepath.Join(snapDir, "*.snap"))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to list snapshot files in %s", snapDir)
+	}
+	var snapshots []*raftpb.Snapshot
+	var corrupted []string
+	for _, snapFile := range snapFiles {
+		s, err := snap.Read(lg.Zap(), snapFile)
+		if err != nil {
+			lg.Warnf("Snapshot file %s is corrupted: %s", snapFile, err)
+			corrupted = append(corrupted, snapFile)
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Metadata.Index < snapshots[j].Metadata.Index })
+	return snapshots, corrupted, nil
+}
+
+// ReadRaftData reads the latest snapshot and the WAL entries that follow it, without modifying the WAL.
+// A torn write at the tail of the WAL is ignored, in which case the entries read stop before it.
+func ReadRaftData(lg *flogging.FabricLogger, walDir, snapDir string) (*RaftData, error) {
+	if !wal.Exist(walDir) {
+		return nil, errors.Errorf("no WAL data found at %s", walDir)
+	}
+	snapshot, walsnap, err := loadLatestSnapshot(lg, snapDir)
+	if err != nil {
+		return nil, err
+	}
+	w, err := wal.OpenForRead(lg.Zap(), walDir, walsnap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open WAL")
+	}
+	defer w.Close()
+
+	_, st, ents, err := w.ReadAll()
+	if err != nil && err != wal.ErrSnapshotNotFound {
+		return nil, errors.Wrapf(err, "failed to read WAL")
+	}
+	return &RaftData{
+		Snapshot:  snapshot,
+		HardState: st,
+		Entries:   ents,
+	}, nil
+}
+
+// ConfState returns the membership of the cluster after the committed entries are applied to the snapshot
+func (d *RaftData) ConfState() raftpb.ConfState {
+	nodes, learners := map[uint64]bool{}, map[uint64]bool{}
+	if d.Snapshot != nil {
+		for _, n := range d.Snapshot.Metadata.ConfState.Nodes {
+			nodes[n] = true
+		}
+		for _, n := range d.Snapshot.Metadata.ConfState.Learners {
+			learners[n] = true
+		}
+	}
+
+	for _, e := range d.Entries {
+		if e.Index > d.HardState.Commit {
+			break
+		}
+		if e.Type != raftpb.EntryConfChange {
+			continue
+		}
+		var cc raftpb.ConfChange
+		if err := cc.Unmarshal(e.Data); err != nil {
+			continue
+		}
+		switch cc.Type {
+		case raftpb.ConfChangeAddNode:
+			nodes[cc.NodeID] = true
+			delete(learners, cc.NodeID)
+		case raftpb.ConfChangeAddLearnerNode:
+			if !nodes[cc.NodeID] {
+				learners[cc.NodeID] = true
+			}
+		case raftpb.ConfChangeRemoveNode:
+			delete(nodes, cc.NodeID)
+			delete(learners, cc.NodeID)
+		}
+	}
+
+	return raftpb.ConfState{Nodes: sortedIDs(nodes), Learners: sortedIDs(learners)}
+}
+
+// RepairWAL truncates the WAL at the start of a torn write at its tail. The truncated WAL
+// file is backed up with the suffix ".broken". It returns false if the WAL needs no repair,
+// or an error if the WAL is corrupted in a way that truncating its tail cannot repair.
+func RepairWAL(lg *flogging.FabricLogger, walDir, snapDir string) (bool, error) {
+	if !wal.Exist(walDir) {
+		return false, errors.Errorf("no WAL data found at %s", walDir)
+	}
+	_, walsnap, err := loadLatestSnapshot(lg, snapDir)
+	if err != nil {
+		return false, err
+	}
+
+	// the WAL is opened for writing, as only then its entries must be read to the end
+	w, err := wal.Open(lg.Zap(), walDir, walsnap)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to open WAL")
+	}
+	_, _, _, err = w.ReadAll()
+	if errc := w.Close(); errc != nil {
+		return false, errors.Wrapf(errc, "failed to close WAL")
+	}
+	switch err {
+	case nil, wal.ErrSnapshotNotFound:
+		return false, nil
+	case io.ErrUnexpectedEOF:
+		if !wal.Repair(lg.Zap(), walDir) {
+			return false, errors.New("failed to repair WAL")
+		}
+		return true, nil
+	default:
+		return false, errors.Wrapf(err, "failed to read WAL and cannot repair")
+	}
+}
+
+// loadLatestSnapshot returns the latest snapshot that can be decoded. As it skips the corrupted
+// snapshot files rather than renaming them, the snapshot directory is left as it is.
+func loadLatestSnapshot(lg *flogging.FabricLogger, snapDir string) (*raftpb.Snapshot, walpb.Snapshot, error) {
+	snapshots, _, err := readSnapshots(lg, snapDir)
+	if err != nil {
+		return nil, walpb.Snapshot{}, err
+	}
+	if len(snapshots) == 0 {
+		return nil, walpb.Snapshot{}, nil
+	}
+	snapshot := snapshots[len(snapshots)-1]
+	return snapshot, walpb.Snapshot{Index: snapshot.Metadata.Index, Term: snapshot.Metadata.Term}, nil
+}
+
+func sortedIDs(ids map[uint64]bool) []uint64 {
+	var sorted []uint64
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/etcd/raft"
+	"go.etcd.io/etcd/raft/raftpb"
+)
+
+func storeTestEntries(t *testing.T) {
+	var entries []raftpb.Entry
+	for i := uint64(1); i <= 6; i++ {
+		e := raftpb.Entry{Term: 1, Index: i, Data: protoutil.MarshalOrPanic(protoutil.NewBlock(i, nil))}
+		if i == 4 {
+			e.Type = raftpb.EntryConfChange
+			e.Data, err = (&raftpb.ConfChange{Type: raftpb.ConfChangeAddNode, NodeID: 3}).Marshal()
+			require.NoError(t, err)
+		}
+		entries = append(entries, e)
+	}
+	require.NoError(t, store.Store(entries, raftpb.HardState{Term: 1, Vote: 1, Commit: 5}, raftpb.Snapshot{}))
+	require.NoError(t, store.TakeSnapshot(2, raftpb.ConfState{Nodes: []uint64{1, 2}}, make([]byte, 10)))
+}
+
+func TestReadRaftData(t *testing.T) {
+	setup(t)
+	defer clean(t)
+	storeTestEntries(t)
+
+	data, err := ReadRaftData(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), data.Snapshot.Metadata.Index)
+	require.Equal(t, raftpb.HardState{Term: 1, Vote: 1, Commit: 5}, data.HardState)
+	require.Len(t, data.Entries, 4)
+	require.Equal(t, uint64(3), data.Entries[0].Index)
+	require.Equal(t, raftpb.ConfState{Nodes: []uint64{1, 2, 3}}, data.ConfState())
+
+	// the uncommitted conf changes are not applied
+	data.HardState.Commit = 3
+	require.Equal(t, raftpb.ConfState{Nodes: []uint64{1, 2}}, data.ConfState())
+
+	metadata, corrupted, err := ReadSnapshotsMetadata(logger, snapDir)
+	require.NoError(t, err)
+	require.Empty(t, corrupted)
+	require.Len(t, metadata, 1)
+	require.Equal(t, uint64(2), metadata[0].Index)
+	require.Equal(t, uint64(1), metadata[0].Term)
+	require.Equal(t, []uint64{1, 2}, metadata[0].ConfState.Nodes)
+
+	t.Run("with a corrupted snapshot", func(t *testing.T) {
+		corruptedFile := filepath.Join(snapDir, fmt.Sprintf("%016x-%016x.snap", 1, 4))
+		require.NoError(t, ioutil.WriteFile(corruptedFile, []byte("corrupted"), 0o644))
+		defer os.Remove(corruptedFile)
+
+		metadata, corrupted, err := ReadSnapshotsMetadata(logger, snapDir)
+		require.NoError(t, err)
+		require.Equal(t, []string{corruptedFile}, corrupted)
+		require.Len(t, metadata, 1)
+		require.Equal(t, uint64(2), metadata[0].Index)
+
+		data, err := ReadRaftData(logger, walDir, snapDir)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), data.Snapshot.Metadata.Index)
+
+		// the corrupted snapshot file is left as it is
+		_, err = os.Stat(corruptedFile)
+		require.NoError(t, err)
+		_, err = os.Stat(corruptedFile + ".broken")
+		require.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("without a snapshot", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(snapDir))
+		data, err := ReadRaftData(logger, walDir, snapDir)
+		require.NoError(t, err)
+		require.Nil(t, data.Snapshot)
+		require.Len(t, data.Entries, 6)
+		require.Equal(t, raftpb.ConfState{Nodes: []uint64{3}}, data.ConfState())
+	})
+
+	t.Run("without a WAL", func(t *testing.T) {
+		_, err := ReadRaftData(logger, filepath.Join(dataDir, "missing"), snapDir)
+		require.EqualError(t, err, "no WAL data found at "+filepath.Join(dataDir, "missing"))
+	})
+}
+
+func TestRepairWAL(t *testing.T) {
+	setup(t)
+	defer clean(t)
+	storeTestEntries(t)
+	// the last record of the WAL is the HardState that commits the entry
+	entry := raftpb.Entry{Term: 1, Index: 7, Data: protoutil.MarshalOrPanic(protoutil.NewBlock(7, nil))}
+	require.NoError(t, store.Store([]raftpb.Entry{entry}, raftpb.HardState{Term: 1, Vote: 1, Commit: 6}, raftpb.Snapshot{}))
+	require.NoError(t, store.Close())
+
+	repaired, err := RepairWAL(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.False(t, repaired)
+
+	// tear the last entry
+	walFiles, err := filepath.Glob(filepath.Join(walDir, "*.wal"))
+	require.NoError(t, err)
+	lastWALFile := walFiles[len(walFiles)-1]
+	data, err := ReadRaftData(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.Len(t, data.Entries, 5)
+	require.Equal(t, uint64(6), data.HardState.Commit)
+	walEnd := walEndOffset(t, lastWALFile)
+	require.NoError(t, os.Truncate(lastWALFile, walEnd-10))
+
+	repaired, err = RepairWAL(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.True(t, repaired)
+	_, err = os.Stat(lastWALFile + ".broken")
+	require.NoError(t, err)
+
+	data, err = ReadRaftData(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.Len(t, data.Entries, 5)
+	require.Equal(t, uint64(5), data.HardState.Commit)
+
+	repaired, err = RepairWAL(logger, walDir, snapDir)
+	require.NoError(t, err)
+	require.False(t, repaired)
+
+	// the orderer is able to start with the repaired WAL
+	store, err = CreateStorage(logger, walDir, snapDir, raft.NewMemoryStorage())
+	require.NoError(t, err)
+}
+
+// walEndOffset returns the offset at which the records end in a WAL file, which is preallocated with zeros
+func walEndOffset(t *testing.T, walFile string) int64 {
+	content, err := ioutil.ReadFile(walFile)
+	require.NoError(t, err)
+	end := int64(len(content))
+	for end > 0 && content[end-1] == 0 {
+		end--
+	}
+	return end
+}
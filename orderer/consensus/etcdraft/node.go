@@ -18,7 +18,9 @@ import (
 	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 	"go.etcd.io/etcd/raft"
 	"go.etcd.io/etcd/raft/raftpb"
 )
@@ -119,7 +121,9 @@ func (n *node) run(campaign bool) {
 		}()
 	}
 
-	var notifyLeaderChangeC chan uint64
+	// the channels of the callers waiting for the next leader, e.g. both an abdication on a config change and
+	// a leadership transfer requested by an administrator
+	var leaderChangeSubscribers []chan uint64
 
 	for {
 		select {
@@ -146,14 +150,16 @@ func (n *node) run(campaign bool) {
 				n.chain.snapC <- &rd.Snapshot
 			}
 
-			if notifyLeaderChangeC != nil && rd.SoftState != nil {
+			if len(leaderChangeSubscribers) != 0 && rd.SoftState != nil {
 				if l := atomic.LoadUint64(&rd.SoftState.Lead); l != raft.None {
-					select {
-					case notifyLeaderChangeC <- l:
-					default:
+					for _, notifyLeaderChangeC := range leaderChangeSubscribers {
+						select {
+						case notifyLeaderChangeC <- l:
+						default:
+						}
 					}
 
-					notifyLeaderChangeC = nil
+					leaderChangeSubscribers = nil
 				}
 			}
 
@@ -177,7 +183,8 @@ func (n *node) run(campaign bool) {
 			// to the followers and them writing to their disks. Check 10.2.1 in thesis
 			n.send(rd.Messages)
 
-		case notifyLeaderChangeC = <-n.subscriberC:
+		case notifyLeaderChangeC := <-n.subscriberC:
+			leaderChangeSubscribers = append(leaderChangeSubscribers, notifyLeaderChangeC)
 
 		case <-n.chain.haltC:
 			raftTicker.Stop()
@@ -276,6 +283,53 @@ func (n *node) abdicateLeader(currentLead uint64) {
 	}
 }
 
+// transferLeadership transfers the leadership to the given node, and waits until a leader is elected or the
+// election timeout expires. It returns the ID of the new leader. Only the leader and the transferee can request
+// the transfer: etcd/raft takes the sender of a MsgTransferLeader as the transferee, hence a follower can only
+// ask the leader on its own behalf, and any other node returns a NotLeaderError naming the leader instead.
+func (n *node) transferLeadership(transferee uint64) (uint64, error) {
+	status := n.Status()
+	if status.Lead == raft.None {
+		return raft.None, errors.Errorf("no raft leader")
+	}
+	if status.Lead == transferee {
+		n.logger.Infof("Node %d is already the leader", transferee)
+		return transferee, nil
+	}
+	if status.RaftState != raft.StateLeader && status.ID != transferee {
+		return raft.None, &types.NotLeaderError{Leader: status.Lead}
+	}
+
+	// register a leader subscriberC
+	notifyc := make(chan uint64, 1)
+	select {
+	case n.subscriberC <- notifyc:
+	case <-n.chain.doneC:
+		return raft.None, errors.Errorf("chain is stopped")
+	}
+
+	n.logger.Infof("Transferring leadership from %d to %d", status.Lead, transferee)
+	// On the transferee, etcd/raft forwards the request to the leader on behalf of this node
+	n.TransferLeadership(context.TODO(), status.Lead, transferee)
+
+	timer := n.clock.NewTimer(time.Duration(n.config.ElectionTick) * n.tickInterval)
+	defer timer.Stop() // prevent timer leak
+
+	select {
+	case <-timer.C():
+		n.logger.Warnf("Leader transfer to %d timed out", transferee)
+		return raft.None, errors.Errorf("timed out waiting for leadership to be transferred to %d", transferee)
+	case l := <-notifyc:
+		if l != transferee {
+			return l, errors.Errorf("leadership was transferred to %d instead of %d", l, transferee)
+		}
+		n.logger.Infof("Leader has been transferred from %d to %d", status.Lead, l)
+		return l, nil
+	case <-n.chain.doneC:
+		return raft.None, errors.Errorf("chain is stopped")
+	}
+}
+
 func (n *node) logSendFailure(dest uint64, err error) {
 	if _, ok := n.unreachable[dest]; ok {
 		n.logger.Debugf("Failed to send StepRequest to %d, because: %s", dest, err)
@@ -7,7 +7,9 @@
 package etcdraft
 
 import (
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/orderer/etcdraft"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -26,9 +28,33 @@ type Tracker struct {
 
 	counter int
 
+	lastSeenLock sync.RWMutex
+	lastSeen     map[uint64]time.Time
+
 	logger *flogging.FabricLogger
 }
 
+// Seen records that a consensus message was received from the given node at the given time.
+func (t *Tracker) Seen(id uint64, at time.Time) {
+	t.lastSeenLock.Lock()
+	defer t.lastSeenLock.Unlock()
+
+	if t.lastSeen == nil {
+		t.lastSeen = map[uint64]time.Time{}
+	}
+	t.lastSeen[id] = at
+}
+
+// LastSeen returns the last time a consensus message was received from the given node,
+// and false if none was received.
+func (t *Tracker) LastSeen(id uint64) (time.Time, bool) {
+	t.lastSeenLock.RLock()
+	defer t.lastSeenLock.RUnlock()
+
+	at, ok := t.lastSeen[id]
+	return at, ok
+}
+
 func (t *Tracker) Check(status *raft.Status) {
 	// leaderless
 	if status.Lead == raft.None {
@@ -548,3 +548,35 @@ func createSampleTxEnvelopeBytes(txID string) []byte {
 	return protoutil.MarshalOrPanic(envelope)
 
 }
+
+func TestCreateTx(t *testing.T) {
+	prop, _, err := protoutil.CreateChaincodeProposal(cb.HeaderType_ENDORSER_TRANSACTION, "mychannel", &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}},
+	}, []byte("creator"))
+	require.NoError(t, err)
+
+	responses := []*pb.ProposalResponse{
+		{Payload: []byte("payload"), Endorsement: &pb.Endorsement{Endorser: []byte("peer1")}, Response: &pb.Response{Status: 200}},
+		{Payload: []byte("payload"), Endorsement: &pb.Endorsement{Endorser: []byte("peer2")}, Response: &pb.Response{Status: 200}},
+	}
+	env, err := protoutil.CreateTx(prop, responses...)
+	require.NoError(t, err)
+	require.Nil(t, env.Signature, "the transaction is not signed")
+
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	require.Equal(t, prop.Header, protoutil.MarshalOrPanic(payload.Header))
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	require.NoError(t, err)
+	cap, err := protoutil.UnmarshalChaincodeActionPayload(tx.Actions[0].Payload)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), cap.Action.ProposalResponsePayload)
+	require.Len(t, cap.Action.Endorsements, 2)
+
+	_, err = protoutil.CreateTx(prop)
+	require.EqualError(t, err, "at least one proposal response is required")
+
+	responses[1].Payload = []byte("other payload")
+	_, err = protoutil.CreateTx(prop, responses...)
+	require.EqualError(t, err, "ProposalResponsePayloads do not match")
+}
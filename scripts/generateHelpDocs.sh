@@ -100,11 +100,18 @@ generateHelpText \
         docs/wrappers/configtxlator_postscript.md \
         "${commands[@]}"
 
-commands=("osnadmin channel" "osnadmin channel join" "osnadmin channel list" "osnadmin channel remove")
+commands=("osnadmin channel" "osnadmin channel join" "osnadmin channel bulk-join" "osnadmin channel watch" "osnadmin channel list" "osnadmin channel remove" "osnadmin channel transfer-leader")
 generateHelpText \
         docs/source/commands/osnadminchannel.md \
         docs/wrappers/osnadmin_channel_preamble.md \
         docs/wrappers/osnadmin_channel_postscript.md \
         "${commands[@]}"
 
+commands=("osnadmin migration" "osnadmin migration status" "osnadmin migration run")
+generateHelpText \
+        docs/source/commands/osnadminmigration.md \
+        docs/wrappers/osnadmin_migration_preamble.md \
+        docs/wrappers/osnadmin_migration_postscript.md \
+        "${commands[@]}"
+
 exit